@@ -1,26 +1,79 @@
 package cmd
 
 import (
+	"encoding/json"
 	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"strings"
 
+	"github.com/BrandonThomas84/code-review-automation/internal/config"
+	"github.com/BrandonThomas84/code-review-automation/pkg/review"
 	"github.com/spf13/cobra"
 )
 
+// effectiveConfig is the `config show --json` document
+type effectiveConfig struct {
+	ConfigFile     string                 `json:"config_file"`
+	Settings       []effectiveSetting     `json:"settings"`
+	IgnorePatterns []review.IgnorePattern `json:"ignore_patterns"`
+	EnabledRules   []string               `json:"enabled_rules"`
+	DisabledRules  []string               `json:"disabled_rules"`
+	// LanguageDisabledRules are rule IDs disabled only for files of the keyed language
+	LanguageDisabledRules map[string][]string `json:"language_disabled_rules"`
+}
+
+type effectiveSetting struct {
+	Key    string        `json:"key"`
+	Value  string        `json:"value"`
+	Source config.Source `json:"source"`
+}
+
 func NewConfigCommand() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "config",
 		Short: "Manage configuration",
 	}
 
-	cmd.AddCommand(&cobra.Command{
+	var showJSON bool
+	showCmd := &cobra.Command{
 		Use:   "show",
-		Short: "Show current configuration",
+		Short: "Show the effective configuration and where each value came from",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			fmt.Println("Configuration:")
-			fmt.Println("  Target Branch: (set via -t flag)")
-			fmt.Println("  Output Directory: review_reports")
-			fmt.Println("  Full Scan: false (set via --full-scan flag)")
-			fmt.Println("  Email: (set via --email flag)")
+			effective, err := resolveEffectiveConfig(cmd)
+			if err != nil {
+				return err
+			}
+
+			if showJSON {
+				encoder := json.NewEncoder(os.Stdout)
+				encoder.SetIndent("", "  ")
+				return encoder.Encode(effective)
+			}
+
+			printEffectiveConfig(effective)
+			return nil
+		},
+	}
+	showCmd.Flags().BoolVar(&showJSON, "json", false, "Output the effective configuration as JSON")
+	cmd.AddCommand(showCmd)
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "path",
+		Short: "Print the path of the loaded config file",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			repoPath, err := os.Getwd()
+			if err != nil {
+				return exitError(ExitSetup, fmt.Errorf("failed to get current directory: %w", err))
+			}
+
+			path := config.FindFile(repoPath)
+			if path == "" {
+				return fmt.Errorf("no config file found (looked for %s)", strings.Join(config.FileNames, ", "))
+			}
+
+			fmt.Println(path)
 			return nil
 		},
 	})
@@ -28,3 +81,88 @@ func NewConfigCommand() *cobra.Command {
 	return cmd
 }
 
+func resolveEffectiveConfig(cmd *cobra.Command) (*effectiveConfig, error) {
+	repoPath, err := os.Getwd()
+	if err != nil {
+		return nil, exitError(ExitSetup, fmt.Errorf("failed to get current directory: %w", err))
+	}
+
+	cfg, err := loadConfig(cmd, repoPath)
+	if err != nil {
+		return nil, err
+	}
+
+	effective := &effectiveConfig{
+		ConfigFile:    cfg.Path,
+		DisabledRules: []string{},
+		EnabledRules:  []string{},
+	}
+
+	for _, key := range config.Keys() {
+		effective.Settings = append(effective.Settings, effectiveSetting{
+			Key:    key,
+			Value:  cfg.Value(key),
+			Source: cfg.Sources[key],
+		})
+	}
+
+	effective.IgnorePatterns = review.IgnorePatterns(reviewOptions(repoPath, cfg))
+
+	disabled := map[string]bool{}
+	for _, rule := range cfg.Rules.Disabled {
+		disabled[rule] = true
+		effective.DisabledRules = append(effective.DisabledRules, rule)
+	}
+	for _, rule := range review.IssueTypes() {
+		if !disabled[rule] {
+			effective.EnabledRules = append(effective.EnabledRules, rule)
+		}
+	}
+
+	effective.LanguageDisabledRules = cfg.Rules.LanguageDisabled()
+
+	return effective, nil
+}
+
+func printEffectiveConfig(effective *effectiveConfig) {
+	fmt.Println("Configuration:")
+	if effective.ConfigFile != "" {
+		fmt.Printf("  Config file: %s\n", effective.ConfigFile)
+	} else {
+		fmt.Printf("  Config file: (none found, looked for %s)\n", strings.Join(config.FileNames, ", "))
+	}
+
+	fmt.Println()
+	fmt.Println("Settings:")
+	for _, setting := range effective.Settings {
+		value := setting.Value
+		if value == "" {
+			value = "(not set)"
+		}
+		fmt.Printf("  %-14s %-30s [%s]\n", setting.Key+":", value, setting.Source)
+	}
+
+	fmt.Println()
+	fmt.Println("Ignore patterns:")
+	if len(effective.IgnorePatterns) == 0 {
+		fmt.Println("  (none)")
+	}
+	for _, pattern := range effective.IgnorePatterns {
+		fmt.Printf("  %-30s [%s]\n", pattern.Pattern, pattern.Source)
+	}
+
+	fmt.Println()
+	fmt.Println("Rules:")
+	fmt.Printf("  Enabled:  %s\n", listOrNone(effective.EnabledRules))
+	fmt.Printf("  Disabled: %s\n", listOrNone(effective.DisabledRules))
+	for _, language := range slices.Sorted(maps.Keys(effective.LanguageDisabledRules)) {
+		fmt.Printf("  Disabled for %s: %s\n", language, listOrNone(effective.LanguageDisabledRules[language]))
+	}
+}
+
+func listOrNone(items []string) string {
+	if len(items) == 0 {
+		return "(none)"
+	}
+	return strings.Join(items, ", ")
+}
@@ -0,0 +1,48 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/BrandonThomas84/code-review-automation/pkg/review"
+	"github.com/spf13/cobra"
+)
+
+func NewDiffReportsCommand() *cobra.Command {
+	var diffFormat string
+
+	cmd := &cobra.Command{
+		Use:   "diff-reports <old.json> <new.json>",
+		Short: "Compare two saved JSON reports",
+		Long: `Compare two reports saved with --json (or review_report.json) and print
+which findings were added, removed or left unchanged. Works offline on
+report artifacts, e.g. to comment "this change introduced 2 new high findings".`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			previous, err := review.LoadReport(args[0])
+			if err != nil {
+				return err
+			}
+			current, err := review.LoadReport(args[1])
+			if err != nil {
+				return err
+			}
+
+			diff := review.DiffReports(previous, current)
+
+			switch diffFormat {
+			case "text":
+				return diff.WriteText(os.Stdout)
+			case "markdown", "md":
+				return diff.WriteMarkdown(os.Stdout)
+			case "json":
+				return diff.WriteJSON(os.Stdout)
+			}
+			return fmt.Errorf("unknown format %q (available: text, markdown, json)", diffFormat)
+		},
+	}
+
+	cmd.Flags().StringVarP(&diffFormat, "format", "f", "text", "Output format (text, markdown, json)")
+
+	return cmd
+}
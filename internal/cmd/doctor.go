@@ -0,0 +1,348 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/BrandonThomas84/code-review-automation/internal/config"
+	"github.com/BrandonThomas84/code-review-automation/internal/email"
+	"github.com/BrandonThomas84/code-review-automation/pkg/review"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+// doctorTimeout bounds each network diagnostic
+const doctorTimeout = 10 * time.Second
+
+// doctorStatus is the outcome of a diagnostic
+type doctorStatus string
+
+const (
+	doctorPass doctorStatus = "pass"
+	doctorWarn doctorStatus = "warn"
+	doctorFail doctorStatus = "fail"
+)
+
+// doctorResult is the outcome of one diagnostic and what it found
+type doctorResult struct {
+	Check  string
+	Status doctorStatus
+	Detail string
+}
+
+// doctorEnv is what the diagnostics inspect. Tests replace lookPath to run
+// them as if git were not installed.
+type doctorEnv struct {
+	repoPath string
+	// cfg is nil when the config file could not be loaded
+	cfg          *config.Config
+	configErr    error
+	slackWebhook string
+	lookPath     func(file string) (string, error)
+	httpClient   *http.Client
+}
+
+// errGitMissing is returned by doctorEnv.git when git is not on the PATH
+var errGitMissing = errors.New("git is not installed or not on the PATH")
+
+// git runs git in the repository and returns its trimmed output
+func (e *doctorEnv) git(ctx context.Context, args ...string) (string, error) {
+	path, err := e.lookPath("git")
+	if err != nil {
+		return "", errGitMissing
+	}
+	cmd := exec.CommandContext(ctx, path, args...)
+	cmd.Dir = e.repoPath
+	out, err := cmd.Output()
+	return strings.TrimSpace(string(out)), err
+}
+
+// doctorChecks are run in order. Each reads only the environment, so a failing
+// one does not stop the rest from running.
+var doctorChecks = []func(context.Context, *doctorEnv) doctorResult{
+	checkGitAvailable,
+	checkRepository,
+	checkConfigFile,
+	checkTargetBranch,
+	checkOutputDir,
+	checkEmailConfig,
+	checkTokens,
+	checkSlackWebhook,
+}
+
+func NewDoctorCommand() *cobra.Command {
+	var slackWebhook string
+
+	cmd := &cobra.Command{
+		Use:   "doctor",
+		Short: "Check that the environment is set up to run reviews",
+		Long: `Run a series of independent diagnostics and print pass, warn or fail for each:
+git is installed, the current directory is a repository, the config file loads,
+the target branch resolves, the output directory is writable, the SMTP settings
+for --email are complete and the server is reachable, API tokens are present and
+the Slack webhook is reachable.
+
+Exits with status 3 when any diagnostic fails; warnings are for optional
+features that are not set up.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			repoPath, err := os.Getwd()
+			if err != nil {
+				return exitError(ExitSetup, fmt.Errorf("failed to get current directory: %w", err))
+			}
+
+			if slackWebhook == "" {
+				slackWebhook = os.Getenv("SLACK_WEBHOOK_URL")
+			}
+			env := &doctorEnv{
+				repoPath:     repoPath,
+				slackWebhook: slackWebhook,
+				lookPath:     exec.LookPath,
+				httpClient:   &http.Client{Timeout: doctorTimeout},
+			}
+			env.cfg, env.configErr = loadConfig(cmd, repoPath)
+
+			// Failed diagnostics are not about how the command was invoked
+			cmd.SilenceUsage = true
+			results := runDoctor(cmd.Context(), env)
+			printDoctorResults(cmd, results)
+
+			failed := 0
+			for _, result := range results {
+				if result.Status == doctorFail {
+					failed++
+				}
+			}
+			if failed > 0 {
+				return exitError(ExitSetup, fmt.Errorf("%d of %d checks failed", failed, len(results)))
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&slackWebhook, "slack-webhook", "", "Slack incoming webhook URL to check is reachable (default: SLACK_WEBHOOK_URL); nothing is posted")
+
+	return cmd
+}
+
+// runDoctor runs every diagnostic against env
+func runDoctor(ctx context.Context, env *doctorEnv) []doctorResult {
+	results := make([]doctorResult, 0, len(doctorChecks))
+	for _, check := range doctorChecks {
+		results = append(results, check(ctx, env))
+	}
+	return results
+}
+
+func printDoctorResults(cmd *cobra.Command, results []doctorResult) {
+	w := cmd.OutOrStdout()
+	width := 0
+	for _, result := range results {
+		width = max(width, len(result.Check))
+	}
+	for _, result := range results {
+		mark := color.GreenString("✓")
+		switch result.Status {
+		case doctorWarn:
+			mark = color.YellowString("!")
+		case doctorFail:
+			mark = color.RedString("✗")
+		}
+		fmt.Fprintf(w, "  %s %-*s  %s\n", mark, width, result.Check, result.Detail)
+	}
+}
+
+// checkGitAvailable reports the installed git version
+func checkGitAvailable(ctx context.Context, env *doctorEnv) doctorResult {
+	result := doctorResult{Check: "git"}
+	version, err := env.git(ctx, "--version")
+	if err != nil {
+		result.Status, result.Detail = doctorFail, err.Error()
+		return result
+	}
+	result.Status, result.Detail = doctorPass, version
+	return result
+}
+
+// checkRepository reports whether the current directory is inside a work tree,
+// and on which branch
+func checkRepository(ctx context.Context, env *doctorEnv) doctorResult {
+	result := doctorResult{Check: "repository"}
+	top, err := env.git(ctx, "rev-parse", "--show-toplevel")
+	switch {
+	case errors.Is(err, errGitMissing):
+		result.Status, result.Detail = doctorFail, "cannot check without git"
+		return result
+	case err != nil:
+		result.Status, result.Detail = doctorFail, fmt.Sprintf("%s is not inside a git repository", env.repoPath)
+		return result
+	}
+
+	// Fails on a detached HEAD, as CI checkouts usually are
+	branch, err := env.git(ctx, "symbolic-ref", "--short", "HEAD")
+	if err != nil {
+		result.Status, result.Detail = doctorWarn, fmt.Sprintf("%s on a detached HEAD; email and Slack summaries will not name a branch", top)
+		return result
+	}
+	result.Status, result.Detail = doctorPass, fmt.Sprintf("%s on branch %s", top, branch)
+	return result
+}
+
+// checkConfigFile reports which config file was loaded
+func checkConfigFile(ctx context.Context, env *doctorEnv) doctorResult {
+	result := doctorResult{Check: "config"}
+	switch {
+	case env.configErr != nil:
+		result.Status, result.Detail = doctorFail, env.configErr.Error()
+	case env.cfg.Path == "":
+		result.Status, result.Detail = doctorPass, fmt.Sprintf("no config file (looked for %s), using defaults", strings.Join(config.FileNames, ", "))
+	default:
+		result.Status, result.Detail = doctorPass, "loaded "+env.cfg.Path
+	}
+	return result
+}
+
+// checkTargetBranch resolves the target branch the way a review would, fetching
+// it unless --offline is set
+func checkTargetBranch(ctx context.Context, env *doctorEnv) doctorResult {
+	result := doctorResult{Check: "target branch"}
+	switch {
+	case env.cfg == nil:
+		result.Status, result.Detail = doctorFail, "cannot check without a valid config"
+		return result
+	case env.cfg.TargetBranch == "":
+		result.Status, result.Detail = doctorWarn, fmt.Sprintf("not set; pass --target or set %s (only --full-scan works without one)", config.KeyTargetBranch)
+		return result
+	}
+
+	opts := reviewOptions(env.repoPath, env.cfg)
+	opts.FullScan = false
+	opts.LogLevel = review.LogQuiet
+	plan, err := review.Plan(ctx, opts)
+	if err != nil {
+		result.Status, result.Detail = doctorFail, err.Error()
+		return result
+	}
+	result.Status, result.Detail = doctorPass, fmt.Sprintf("%s resolves to %s", env.cfg.TargetBranch, plan.TargetRef)
+	return result
+}
+
+// checkOutputDir writes a file to the output directory, or to the nearest
+// existing parent it would be created in, without creating it
+func checkOutputDir(ctx context.Context, env *doctorEnv) doctorResult {
+	result := doctorResult{Check: "output directory"}
+	if env.cfg == nil {
+		result.Status, result.Detail = doctorFail, "cannot check without a valid config"
+		return result
+	}
+
+	dir := env.cfg.OutputDir
+	existing := dir
+	for {
+		info, err := os.Stat(existing)
+		if err == nil && !info.IsDir() {
+			result.Status, result.Detail = doctorFail, fmt.Sprintf("%s is a file, not a directory", existing)
+			return result
+		}
+		if err == nil || filepath.Dir(existing) == existing {
+			break
+		}
+		existing = filepath.Dir(existing)
+	}
+
+	probe, err := os.CreateTemp(existing, ".doctor-*")
+	if err != nil {
+		result.Status, result.Detail = doctorFail, fmt.Sprintf("%s is not writable: %v", existing, err)
+		return result
+	}
+	probe.Close()
+	os.Remove(probe.Name())
+
+	result.Status, result.Detail = doctorPass, dir+" is writable"
+	if existing != dir {
+		result.Detail = fmt.Sprintf("%s will be created in %s, which is writable", dir, existing)
+	}
+	return result
+}
+
+// checkEmailConfig reports missing SMTP settings, and whether the server
+// accepts connections when they are complete
+func checkEmailConfig(ctx context.Context, env *doctorEnv) doctorResult {
+	result := doctorResult{Check: "email"}
+	smtpConfig := email.ConfigFromEnv()
+	missing := smtpConfig.Missing()
+	switch {
+	case len(missing) == 4:
+		result.Status, result.Detail = doctorWarn, "not configured; set the AUTOREVIEW_SMTP_* variables to use --email"
+		return result
+	case len(missing) > 0:
+		result.Status, result.Detail = doctorFail, "incomplete; missing "+strings.Join(missing, ", ")
+		return result
+	}
+
+	addr := net.JoinHostPort(smtpConfig.SMTPHost, strconv.Itoa(smtpConfig.SMTPPort))
+	dialer := net.Dialer{Timeout: doctorTimeout}
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		result.Status, result.Detail = doctorFail, fmt.Sprintf("cannot reach %s: %v", addr, err)
+		return result
+	}
+	conn.Close()
+	result.Status, result.Detail = doctorPass, addr+" is reachable"
+	return result
+}
+
+// checkTokens reports which API tokens are set
+func checkTokens(ctx context.Context, env *doctorEnv) doctorResult {
+	result := doctorResult{Check: "tokens"}
+	var set []string
+	for _, name := range []string{"GITHUB_TOKEN", "GITLAB_TOKEN", "JIRA_API_TOKEN"} {
+		if os.Getenv(name) != "" {
+			set = append(set, name)
+		}
+	}
+	if len(set) == 0 {
+		result.Status, result.Detail = doctorWarn, "none of GITHUB_TOKEN, GITLAB_TOKEN or JIRA_API_TOKEN is set; pull request comments and --check-todo-tickets need them"
+		return result
+	}
+	result.Status, result.Detail = doctorPass, strings.Join(set, ", ")+" set"
+	return result
+}
+
+// checkSlackWebhook sends a GET to the webhook, which Slack answers without
+// posting anything, to check it is reachable and has not been revoked
+func checkSlackWebhook(ctx context.Context, env *doctorEnv) doctorResult {
+	result := doctorResult{Check: "slack webhook"}
+	if env.slackWebhook == "" {
+		result.Status, result.Detail = doctorWarn, "not configured; pass --slack-webhook or set SLACK_WEBHOOK_URL to check it"
+		return result
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, env.slackWebhook, nil)
+	if err != nil {
+		result.Status, result.Detail = doctorFail, "invalid URL"
+		return result
+	}
+	resp, err := env.httpClient.Do(req)
+	if err != nil {
+		// The URL is a secret, so only the host is named
+		result.Status, result.Detail = doctorFail, fmt.Sprintf("cannot reach %s", req.URL.Host)
+		return result
+	}
+	resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusGone {
+		result.Status, result.Detail = doctorFail, fmt.Sprintf("%s answered %s; the webhook may have been revoked", req.URL.Host, resp.Status)
+		return result
+	}
+	result.Status, result.Detail = doctorPass, req.URL.Host+" is reachable"
+	return result
+}
@@ -0,0 +1,72 @@
+package cmd
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/BrandonThomas84/code-review-automation/internal/email"
+	"github.com/BrandonThomas84/code-review-automation/pkg/review"
+)
+
+// reportSender delivers a rendered report, see email.Sender
+type reportSender interface {
+	SendReportWithContext(report *review.Report, toEmail, repoName, branchName string, prNumber int, prTitle string) error
+}
+
+// newReportSender builds the sender used for --email; tests replace it to avoid real SMTP
+var newReportSender = func() reportSender {
+	return email.NewSenderFromEnv()
+}
+
+// sendEmailReport emails the report to emailTo, headed with the repository and
+// branch it was generated for
+func sendEmailReport(repoPath string, report *review.Report, emailTo string) error {
+	repoName, branchName := gitContext(repoPath)
+	return newReportSender().SendReportWithContext(report, emailTo, repoName, branchName, 0, "")
+}
+
+// writeHTMLReport saves the report as review_report.html in outputDir, using the
+// email's layout and header, and returns the file's path
+func writeHTMLReport(repoPath string, report *review.Report, outputDir string) (string, error) {
+	repoName, branchName := gitContext(repoPath)
+	content := email.NewFormatter().WithRepo(repoName).WithBranch(branchName).FormatHTML(report)
+
+	path := filepath.Join(outputDir, "review_report.html")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+// gitContext returns the repository name and current branch for the email
+// header, preferring the GitHub Actions environment and then git itself.
+// Either is empty when it cannot be determined, e.g. outside a repository.
+func gitContext(repoPath string) (repoName, branchName string) {
+	repoName = os.Getenv("GITHUB_REPOSITORY")
+	if repoName == "" {
+		if top := gitOutput(repoPath, "rev-parse", "--show-toplevel"); top != "" {
+			repoName = filepath.Base(top)
+		}
+	}
+
+	// GITHUB_HEAD_REF is the pull request's branch; Actions checks out a detached merge commit
+	branchName = os.Getenv("GITHUB_HEAD_REF")
+	if branchName == "" {
+		// Fails on a detached HEAD, which has no branch to name
+		branchName = gitOutput(repoPath, "symbolic-ref", "--short", "HEAD")
+	}
+	return repoName, branchName
+}
+
+// gitOutput runs git in dir and returns its trimmed output, or "" if it fails
+func gitOutput(dir string, args ...string) string {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	out, err := cmd.Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
@@ -0,0 +1,125 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/BrandonThomas84/code-review-automation/pkg/review"
+)
+
+// Exit codes returned by code-review; see exitStatusHelp
+const (
+	ExitOK       = 0
+	ExitUsage    = 1
+	ExitFindings = 2
+	ExitSetup    = 3
+	ExitAnalysis = 4
+	ExitOutput   = 5
+)
+
+// Exit code bits set by --exit-code-bits. A failed run sets ExitBitError on top
+// of its usual code, so it cannot be mistaken for a set of severities.
+const (
+	ExitBitLow    = 1 << 0
+	ExitBitMedium = 1 << 1
+	ExitBitHigh   = 1 << 2
+	ExitBitError  = 1 << 3
+)
+
+// exitStatusHelp documents the exit codes in `code-review --help`
+const exitStatusHelp = `Exit status:
+  0  success
+  1  usage or flag error
+  2  findings at or above the --fail-on severity
+  3  environment or setup failure (not a git repository, target branch unresolvable, unreadable config file)
+  4  internal error during analysis
+  5  output or delivery failure (report could not be written, email could not be sent)
+
+With --exit-code-bits the status is a bit set instead: 1 if low issues were
+found, 2 if medium, 4 if high or critical, and 8 plus the code above on failure.`
+
+// ExitError carries the process exit code for an error returned by a command
+type ExitError struct {
+	Code int
+	Err  error
+}
+
+func (e *ExitError) Error() string { return e.Err.Error() }
+func (e *ExitError) Unwrap() error { return e.Err }
+
+func exitError(code int, err error) error {
+	return &ExitError{Code: code, Err: err}
+}
+
+// severityBitsError ends an --exit-code-bits run that found issues
+type severityBitsError struct {
+	bits int
+}
+
+func (e *severityBitsError) Error() string {
+	var found []string
+	for _, bit := range []struct {
+		bit  int
+		name string
+	}{{ExitBitHigh, "high"}, {ExitBitMedium, "medium"}, {ExitBitLow, "low"}} {
+		if e.bits&bit.bit != 0 {
+			found = append(found, bit.name)
+		}
+	}
+	return fmt.Sprintf("found %s severity issues (--exit-code-bits %d)", strings.Join(found, ", "), e.bits)
+}
+
+// severityBits returns the --exit-code-bits status for the report's issues.
+// Critical issues set the high bit; info issues set none.
+func severityBits(report *review.Report) int {
+	bits := 0
+	for _, issue := range report.Issues {
+		switch {
+		case severityAtLeast(issue, "high"):
+			bits |= ExitBitHigh
+		case severityAtLeast(issue, "medium"):
+			bits |= ExitBitMedium
+		case severityAtLeast(issue, "low"):
+			bits |= ExitBitLow
+		}
+	}
+	return bits
+}
+
+// ExitCode returns the exit code for an error returned by Execute. Errors
+// without an explicit code are cobra's flag and argument errors, so they are
+// usage errors.
+func ExitCode(err error) int {
+	if err == nil {
+		return ExitOK
+	}
+	var bitsErr *severityBitsError
+	if errors.As(err, &bitsErr) {
+		return bitsErr.bits
+	}
+
+	code := ExitUsage
+	var exitErr *ExitError
+	if errors.As(err, &exitErr) {
+		code = exitErr.Code
+	}
+	// The codes below 8 report severities, so failures move above them
+	if exitCodeBits {
+		code |= ExitBitError
+	}
+	return code
+}
+
+// reviewExitCode classifies an error returned by the review API
+func reviewExitCode(err error) int {
+	var optionsErr *review.OptionsError
+	var setupErr *review.SetupError
+	switch {
+	case errors.As(err, &optionsErr):
+		return ExitUsage
+	case errors.As(err, &setupErr):
+		return ExitSetup
+	}
+	return ExitAnalysis
+}
@@ -0,0 +1,51 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"strings"
+
+	"github.com/BrandonThomas84/code-review-automation/internal/github"
+	"github.com/BrandonThomas84/code-review-automation/pkg/review"
+)
+
+// commentPoster posts pull request comments, see github.Client
+type commentPoster interface {
+	CreateComment(ctx context.Context, pr github.PullRequest, body string) error
+}
+
+// newCommentPoster builds the client used for --compare-base-report; tests replace it
+var newCommentPoster = func() commentPoster {
+	return github.NewClientFromEnv()
+}
+
+// postNewFindingsComment comments on the pull request of the current GitHub
+// Actions run with the findings report introduced over the base report at
+// basePath. A missing base report, e.g. on the first run, lists every finding.
+// It reports whether a comment was posted.
+func postNewFindingsComment(ctx context.Context, report *review.Report, basePath string, log *review.Logger) (bool, error) {
+	pr, ok := github.PullRequestFromEnv()
+	if !ok {
+		log.Warnf("--compare-base-report is set but this is not a pull request run (GITHUB_REPOSITORY and a pull request event are needed), no comment posted")
+		return false, nil
+	}
+
+	base, err := review.LoadReport(basePath)
+	if errors.Is(err, fs.ErrNotExist) {
+		log.Warnf("Base report %s not found, the comment lists every finding", basePath)
+		base, err = nil, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	var body strings.Builder
+	if err := review.WritePRComment(&body, report, base); err != nil {
+		return false, err
+	}
+	if err := newCommentPoster().CreateComment(ctx, pr, body.String()); err != nil {
+		return false, err
+	}
+	return true, nil
+}
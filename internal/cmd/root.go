@@ -1,22 +1,59 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
+	"net/url"
 	"os"
 	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
 
-	"github.com/BrandonThomas84/code-review-automation/internal/review"
+	"github.com/BrandonThomas84/code-review-automation/internal/config"
+	"github.com/BrandonThomas84/code-review-automation/internal/github"
+	"github.com/BrandonThomas84/code-review-automation/internal/gitlab"
+	"github.com/BrandonThomas84/code-review-automation/internal/notify"
+	"github.com/BrandonThomas84/code-review-automation/pkg/review"
 	"github.com/fatih/color"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 )
 
 var (
-	targetBranch string
-	outputDir    string
-	jsonOutput   bool
-	fullScan     bool
-	emailTo      string
-	verbose      bool
+	targetBranch   string
+	outputDir      string
+	jsonOutput     bool
+	format         string
+	minSeverity    string
+	only           []string
+	include        []string
+	internalPkgs   []string
+	failOn         string
+	exitCodeBits   bool
+	maxLineLength  int
+	maxFileSize    int
+	skipSubmodules bool
+	remote         string
+	offline        bool
+	checkTickets   bool
+	showClean      bool
+	fullScan       bool
+	staged         bool
+	working        bool
+	emailTo        string
+	verbose        int
+	dryRun         bool
+	compareBase    string
+	ciSummary      string
+	outputTemplate string
+	disableRules   []string
+	baselineReport string
+	githubPR       int
+	gitlabMR       int
+	gitlabURL      string
+	slackWebhook   string
+	slackAlways    bool
 )
 
 func NewRootCommand() *cobra.Command {
@@ -25,116 +62,455 @@ func NewRootCommand() *cobra.Command {
 		Short: "Automated code review tool for multiple languages",
 		Long: `Code Review Automation - A comprehensive code review tool that analyzes
 code changes across multiple languages including Python, JavaScript, TypeScript,
-Dart, Ruby, PHP, and Java.`,
+Dart, Ruby, PHP, and Java.
+
+` + exitStatusHelp,
 		RunE: runReview,
+		// main prints the error and picks the exit code
+		SilenceErrors: true,
 	}
 
-	cmd.Flags().StringVarP(&targetBranch, "target", "t", "", "Target branch to compare against (required)")
-	cmd.Flags().StringVarP(&outputDir, "output", "o", "review_reports", "Output directory for reports")
-	cmd.Flags().BoolVarP(&jsonOutput, "json", "j", false, "Output as JSON")
-	cmd.Flags().BoolVar(&fullScan, "full-scan", false, "Scan entire codebase instead of just changed files")
-	cmd.Flags().StringVar(&emailTo, "email", "", "Email address to send report to")
-	cmd.Flags().BoolVarP(&verbose, "verbose", "v", false, "Verbose output")
+	// Persistent so `config show` can report flag overrides too
+	cmd.PersistentFlags().StringVarP(&targetBranch, "target", "t", "", "Target branch to compare against (required unless set in config)")
+	cmd.PersistentFlags().StringVar(&remote, "remote", "", "Remote to fetch the target branch from (default: origin, or the only remote)")
+	cmd.PersistentFlags().BoolVar(&offline, "offline", false, "Never fetch the target branch; only use refs already present")
+	cmd.PersistentFlags().StringVarP(&outputDir, "output", "o", "review_reports", "Output directory for reports")
+	cmd.PersistentFlags().BoolVarP(&jsonOutput, "json", "j", false, "Output as JSON (shorthand for --format json)")
+	cmd.PersistentFlags().StringVarP(&format, "format", "f", "text", "Output format ("+strings.Join(review.Formats(), ", ")+")")
+	cmd.PersistentFlags().StringVar(&minSeverity, "min-severity", "", "Only report issues at or above this severity (critical, high, medium, low, info)")
+	cmd.PersistentFlags().StringSliceVar(&only, "only", nil, "Only run these check categories ("+strings.Join(review.Categories(), ", ")+", all); repeatable")
+	cmd.PersistentFlags().StringSliceVar(&include, "include", nil, "Only analyze changed or scanned files matching these globs, e.g. 'src/api/**'; ignore patterns still apply; repeatable")
+	cmd.PersistentFlags().StringVar(&failOn, "fail-on", "none", "Exit with status 2 when an issue at or above this severity is found (none, critical, high, medium, low, info)")
+	cmd.PersistentFlags().IntVar(&maxLineLength, "max-line-length", 120, "Report lines longer than this many characters (per-language limits can be set in the config file)")
+	cmd.PersistentFlags().IntVar(&maxFileSize, "max-file-size", 1048576, "Skip quality checks on files larger than this many bytes, reporting them as generated")
+	cmd.PersistentFlags().BoolVar(&fullScan, "full-scan", false, "Scan entire codebase instead of just changed files")
+	cmd.PersistentFlags().BoolVar(&staged, "staged", false, "Review the changes staged for commit (git diff --cached) instead of a branch diff; --target is not needed")
+	cmd.PersistentFlags().BoolVar(&working, "working", false, "Review the working tree changes not yet staged (git diff) instead of a branch diff; --target is not needed")
+	cmd.PersistentFlags().BoolVar(&checkTickets, "check-todo-tickets", false, "Look up tickets referenced from TODO comments and flag those that are closed (credentials from JIRA_* or GITHUB_* env vars)")
+	cmd.PersistentFlags().StringSliceVar(&disableRules, "disable-rule", nil, "Drop findings of these issue types or rule IDs, in addition to those disabled in the config file; repeatable")
+	cmd.PersistentFlags().StringSliceVar(&internalPkgs, "internal-packages", nil, "Name prefixes of private packages; unscoped references to them are flagged as dependency confusion risks")
+	cmd.PersistentFlags().BoolVar(&showClean, "show-clean", false, "Also list the analyzed files without issues in the report")
+	cmd.PersistentFlags().BoolVar(&skipSubmodules, "skip-submodules", true, "Skip git submodules and nested repositories during a full scan")
+	cmd.PersistentFlags().StringVar(&emailTo, "email", "", "Email address to send report to")
+	cmd.PersistentFlags().CountVarP(&verbose, "verbose", "v", "Log progress to stderr: -v warnings, -vv info, -vvv debug")
 
-	cmd.MarkFlagRequired("target")
+	// Only the review itself can be previewed, so this one is not persistent
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "List the files that would be analyzed and how, without running any checks")
+	cmd.Flags().BoolVar(&exitCodeBits, "exit-code-bits", false, "Exit with a bit set of the severities found instead of --fail-on's threshold: 1 low, 2 medium, 4 high or critical, 8 on failure")
+	cmd.Flags().StringVar(&ciSummary, "ci-summary", "", "Print a final AUTOREVIEW_RESULT line with the severity counts, score and pass/fail state to stderr, or to stdout with --ci-summary=stdout")
+	cmd.Flags().Lookup("ci-summary").NoOptDefVal = "stderr"
+	cmd.Flags().StringVar(&outputTemplate, "output-template", "", "Render the report to stdout with this Go text/template file instead of --format")
+	cmd.Flags().StringVar(&baselineReport, "baseline-report", "", "Also save every finding as a JSON baseline at this path, before --min-severity narrows the printed report")
+	cmd.Flags().IntVar(&githubPR, "github-pr", 0, "Post the findings as a review on this GitHub pull request number, with an inline comment at each finding (needs GITHUB_TOKEN and GITHUB_REPOSITORY)")
+	cmd.Flags().IntVar(&gitlabMR, "gitlab-mr", 0, "Post the findings as discussions on this GitLab merge request IID, anchored at each finding's line (needs GITLAB_TOKEN and CI_PROJECT_ID)")
+	cmd.Flags().StringVar(&gitlabURL, "gitlab-url", "", "GitLab instance for --gitlab-mr, for self-hosted GitLab (default: CI_SERVER_URL, or https://gitlab.com)")
+	cmd.Flags().StringVar(&slackWebhook, "slack-webhook", "", "Post a summary of the findings to this Slack incoming webhook URL when there are any")
+	cmd.Flags().BoolVar(&slackAlways, "slack-always", false, "Post the --slack-webhook summary even when no issues are found")
+	cmd.Flags().StringVar(&compareBase, "compare-base-report", "", "Comment on the GitHub pull request with only the findings not in this earlier JSON report (needs GITHUB_TOKEN)")
 
 	cmd.AddCommand(NewVersionCommand())
 	cmd.AddCommand(NewConfigCommand())
+	cmd.AddCommand(NewDiffReportsCommand())
+	cmd.AddCommand(NewWhyCommand())
+	cmd.AddCommand(NewDoctorCommand())
 
 	return cmd
 }
 
-func runReview(cmd *cobra.Command, args []string) error {
-	if verbose {
-		color.Blue("[INFO] Starting code review analysis...")
-		color.Blue("[INFO] Target branch: %s", targetBranch)
-		color.Blue("[INFO] Full scan: %v", fullScan)
-		color.Blue("[INFO] Output directory: %s", outputDir)
-		color.Blue("[INFO] JSON output: %v", jsonOutput)
-		color.Blue("[INFO] Email: %s", emailTo)
+// flagKeys maps command-line flags to the config settings they override
+var flagKeys = map[string]string{
+	"target":             config.KeyTargetBranch,
+	"output":             config.KeyOutputDir,
+	"remote":             config.KeyRemote,
+	"offline":            config.KeyOffline,
+	"json":               config.KeyJSON,
+	"format":             config.KeyFormat,
+	"min-severity":       config.KeyMinSeverity,
+	"only":               config.KeyOnly,
+	"include":            config.KeyInclude,
+	"fail-on":            config.KeyFailOn,
+	"max-line-length":    config.KeyMaxLineLength,
+	"max-file-size":      config.KeyMaxFileSize,
+	"full-scan":          config.KeyFullScan,
+	"skip-submodules":    config.KeySkipSubmodules,
+	"check-todo-tickets": config.KeyCheckTodoTickets,
+	"show-clean":         config.KeyShowClean,
+	"internal-packages":  config.KeyInternalPackages,
+	"email":              config.KeyEmail,
+	"verbose":            config.KeyVerbose,
+}
 
-		color.Blue("[INFO] creating output directory: %s", outputDir)
+// loadConfig resolves the effective configuration: defaults, the repository
+// config file, environment variables, then any flags set on the command line
+func loadConfig(cmd *cobra.Command, repoPath string) (*config.Config, error) {
+	cfg, err := config.Load(repoPath)
+	if err != nil {
+		return nil, exitError(ExitSetup, err)
 	}
 
-	// Create output directory
-	if err := os.MkdirAll(outputDir, 0755); err != nil {
-		return fmt.Errorf("failed to create output directory: %w", err)
+	// Look the flags up on the root so subcommand-local flags of the same name are not mistaken for overrides
+	for name, key := range flagKeys {
+		flag := cmd.Root().PersistentFlags().Lookup(name)
+		if flag == nil || !flag.Changed {
+			continue
+		}
+		value := flag.Value.String()
+		if slice, ok := flag.Value.(pflag.SliceValue); ok {
+			value = strings.Join(slice.GetSlice(), ",")
+		}
+		if err := cfg.Set(key, value, config.SourceFlag); err != nil {
+			return nil, fmt.Errorf("invalid --%s: %w", name, err)
+		}
+	}
+
+	// Disabled rules add up rather than replacing the config file's list
+	if flag := cmd.Root().PersistentFlags().Lookup("disable-rule"); flag != nil && flag.Changed {
+		cfg.DisableRules(disableRules, config.SourceFlag)
+	}
+
+	return cfg, nil
+}
+
+// reviewOptions translates the effective configuration into public API options
+func reviewOptions(repoPath string, cfg *config.Config) review.Options {
+	source := config.FileNames[0]
+	if cfg.Path != "" {
+		source = filepath.Base(cfg.Path)
 	}
 
-	if verbose {
-		color.Blue("[INFO] Getting current working directory...")
+	opts := review.Options{
+		RepoPath:              repoPath,
+		TargetBranch:          cfg.TargetBranch,
+		Remote:                cfg.Remote,
+		Offline:               cfg.Offline,
+		FullScan:              cfg.FullScan,
+		IncludeSubmodules:     !cfg.SkipSubmodules,
+		DisabledRules:         cfg.Rules.Disabled,
+		LanguageDisabledRules: cfg.Rules.LanguageDisabled(),
+		SeverityOverrides:     cfg.SeverityOverrides,
+		MaxLineLength:         cfg.MaxLineLength,
+		LanguageMaxLineLength: cfg.Rules.LanguageMaxLineLength(),
+		MaxFileSize:           cfg.MaxFileSize,
+		MinSeverity:           cfg.MinSeverity,
+		Only:                  cfg.Only,
+		IncludePatterns:       cfg.Include,
+		ShowClean:             cfg.ShowClean,
+		InternalPackages:      cfg.InternalPackages,
+		LogLevel:              review.LogLevel(cfg.Verbose),
+	}
+	// Uncommitted changes are picked per run, so they are flags rather than settings
+	switch {
+	case staged:
+		opts.DiffSource = review.DiffStaged
+	case working:
+		opts.DiffSource = review.DiffWorking
+	}
+	for _, pattern := range cfg.Ignore {
+		opts.IgnorePatterns = append(opts.IgnorePatterns, review.IgnorePattern{Pattern: pattern, Source: source})
+	}
+	for _, plugin := range cfg.Plugins {
+		opts.Plugins = append(opts.Plugins, review.Plugin{
+			Name:       plugin.Name,
+			Command:    plugin.Command,
+			Args:       plugin.Args,
+			Extensions: plugin.Extensions,
+			Timeout:    plugin.Timeout,
+		})
 	}
+	if cfg.CheckTodoTickets {
+		opts.TicketTrackers = review.TicketTrackersFromEnv()
+		if len(opts.TicketTrackers) == 0 {
+			newLogger(cfg).Warnf("--check-todo-tickets is set but no tracker is configured (set JIRA_BASE_URL or GITHUB_REPOSITORY)")
+		}
+	}
+	return opts
+}
 
+// newLogger returns the logger for the configured verbosity, writing to stderr
+// so logs never mix with a report rendered to stdout
+func newLogger(cfg *config.Config) *review.Logger {
+	return review.NewLogger(review.LogLevel(cfg.Verbose), color.Error)
+}
+
+// outputFormat returns the format to render, honouring --json as an alias for --format json
+func outputFormat(cfg *config.Config) string {
+	if cfg.JSON {
+		return "json"
+	}
+	return cfg.Format
+}
+
+func runReview(cmd *cobra.Command, args []string) error {
 	// Get current working directory
 	repoPath, err := os.Getwd()
 	if err != nil {
-		return fmt.Errorf("failed to get current directory: %w", err)
+		return exitError(ExitSetup, fmt.Errorf("failed to get current directory: %w", err))
+	}
+
+	cfg, err := loadConfig(cmd, repoPath)
+	if err != nil {
+		return err
+	}
+	if !slices.Contains(review.Formats(), outputFormat(cfg)) {
+		return fmt.Errorf("unknown format %q (available: %s)", outputFormat(cfg), strings.Join(review.Formats(), ", "))
+	}
+	if cfg.FailOn != "none" && !slices.Contains(review.Severities(), cfg.FailOn) {
+		return fmt.Errorf("unknown --fail-on severity %q (expected none, %s)", cfg.FailOn, strings.Join(review.Severities(), ", "))
+	}
+	if cfg.MinSeverity != "" && !slices.Contains(review.Severities(), cfg.MinSeverity) {
+		return fmt.Errorf("unknown --min-severity %q (expected %s)", cfg.MinSeverity, strings.Join(review.Severities(), ", "))
+	}
+
+	// A fail_on from the config file or environment is replaced by the bits; only
+	// asking for both on the command line is a mistake
+	if exitCodeBits && cmd.Flags().Changed("fail-on") {
+		return fmt.Errorf("--exit-code-bits cannot be combined with --fail-on; test the severity bits instead of a threshold")
+	}
+
+	if staged && working {
+		return fmt.Errorf("--staged and --working cannot be combined; review the staged changes, then the rest")
+	}
+
+	if ciSummary != "" && ciSummary != "stderr" && ciSummary != "stdout" {
+		return fmt.Errorf("unknown --ci-summary stream %q (expected stderr or stdout)", ciSummary)
+	}
+
+	var owner, repo string
+	if githubPR != 0 {
+		var ok bool
+		owner, repo, ok = strings.Cut(os.Getenv("GITHUB_REPOSITORY"), "/")
+		if githubPR < 0 || !ok || owner == "" || repo == "" {
+			return fmt.Errorf("--github-pr needs a pull request number and GITHUB_REPOSITORY set to owner/repo")
+		}
+	}
+
+	var projectID int
+	if gitlabMR != 0 {
+		projectID, err = strconv.Atoi(os.Getenv("CI_PROJECT_ID"))
+		if gitlabMR < 0 || err != nil || projectID < 1 {
+			return fmt.Errorf("--gitlab-mr needs a merge request IID and CI_PROJECT_ID set to the numeric project ID")
+		}
+	}
+	if gitlabURL == "" {
+		gitlabURL = os.Getenv("CI_SERVER_URL")
+	}
+
+	if slackAlways && slackWebhook == "" {
+		return fmt.Errorf("--slack-always needs --slack-webhook")
+	}
+	if u, err := url.Parse(slackWebhook); slackWebhook != "" && (err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "") {
+		return fmt.Errorf("--slack-webhook must be an http(s) URL")
+	}
+
+	var tmpl *review.ReportTemplate
+	if outputTemplate != "" {
+		if tmpl, err = review.ParseReportTemplate(outputTemplate); err != nil {
+			return err
+		}
 	}
 
-	if verbose {
-		color.Blue("[INFO] Repository path: %s", repoPath)
+	// Past this point failures are not about how the command was invoked
+	cmd.SilenceUsage = true
+
+	if dryRun {
+		return runDryRun(cmd, repoPath, cfg)
 	}
 
-	// Run the review
-	analyzer := review.NewAnalyzer(repoPath, verbose)
-	report, err := analyzer.GenerateReport(targetBranch, fullScan)
+	log := newLogger(cfg)
+	log.Infof("Starting code review analysis...")
+	log.Infof("Target branch: %s", cfg.TargetBranch)
+	log.Infof("Full scan: %v", cfg.FullScan)
+	log.Infof("Output directory: %s", cfg.OutputDir)
+	log.Infof("Output format: %s", outputFormat(cfg))
+	log.Infof("Email: %s", cfg.Email)
+
+	log.Debugf("creating output directory: %s", cfg.OutputDir)
+
+	// Create output directory
+	if err := os.MkdirAll(cfg.OutputDir, 0755); err != nil {
+		return exitError(ExitOutput, fmt.Errorf("failed to create output directory: %w", err))
+	}
+
+	log.Infof("Repository path: %s", repoPath)
+	if cfg.Path != "" {
+		log.Infof("Config file: %s", cfg.Path)
+	}
+
+	// Run the review. A baseline records every finding, so --min-severity is
+	// applied only once it has been saved.
+	opts := reviewOptions(repoPath, cfg)
+	if baselineReport != "" {
+		opts.MinSeverity = ""
+	}
+	report, err := review.Run(cmd.Context(), opts)
 	if err != nil {
-		return fmt.Errorf("review failed: %w", err)
+		return exitError(reviewExitCode(err), fmt.Errorf("review failed: %w", err))
 	}
 
-	if verbose {
-		color.Blue("[INFO] Review complete")
+	log.Infof("Review complete")
+
+	var deliveryErr error
+	if baselineReport != "" {
+		if err := report.SaveToFile(baselineReport); err != nil {
+			deliveryErr = fmt.Errorf("failed to save baseline report: %w", err)
+		} else {
+			log.Successf("Baseline saved to: %s", baselineReport)
+		}
+		if cfg.MinSeverity != "" {
+			report.FilterIssues(func(issue review.Issue) bool { return severityAtLeast(issue, cfg.MinSeverity) })
+		}
 	}
+	log.Infof("Outputting %s report...", outputFormat(cfg))
 
 	// Output results
-	if jsonOutput {
-		if verbose {
-			color.Blue("[INFO] Outputting JSON...")
+	switch {
+	case tmpl != nil:
+		err = tmpl.Execute(os.Stdout, report)
+	case outputFormat(cfg) == "html":
+		// HTML is an artifact for CI to upload rather than terminal output
+		var htmlPath string
+		if htmlPath, err = writeHTMLReport(repoPath, report, cfg.OutputDir); err == nil {
+			log.Successf("HTML report saved to: %s", htmlPath)
 		}
+	default:
+		err = review.Render(os.Stdout, outputFormat(cfg), report)
+	}
+	if err != nil {
+		return exitError(ExitOutput, fmt.Errorf("failed to output report: %w", err))
+	}
 
-		if err := report.OutputJSON(os.Stdout); err != nil {
-			return fmt.Errorf("failed to output JSON: %w", err)
+	log.Infof("Saving report to file...")
+
+	// Save report to file
+	reportPath := filepath.Join(cfg.OutputDir, "review_report.json")
+	if err := report.SaveToFile(reportPath); err != nil {
+		deliveryErr = fmt.Errorf("failed to save report: %w", err)
+	} else {
+		log.Successf("Report saved to: %s", reportPath)
+	}
+
+	log.Infof("Sending email...")
+
+	// Send email if requested
+	if cfg.Email != "" {
+		if err := sendEmailReport(repoPath, report, cfg.Email); err != nil {
+			deliveryErr = errors.Join(deliveryErr, fmt.Errorf("failed to send email: %w", err))
+		} else {
+			log.Successf("Email sent to: %s", cfg.Email)
 		}
 	} else {
-		if verbose {
-			color.Blue("[INFO] Outputting report...")
+		log.Infof("No email requested")
+	}
+
+	if compareBase != "" {
+		if posted, err := postNewFindingsComment(cmd.Context(), report, compareBase, log); err != nil {
+			deliveryErr = errors.Join(deliveryErr, fmt.Errorf("failed to comment on the pull request: %w", err))
+		} else if posted {
+			log.Successf("Pull request comment posted")
 		}
+	}
 
-		report.PrintReport()
+	if githubPR != 0 {
+		if err := github.PostReviewComments(cmd.Context(), report, owner, repo, githubPR, os.Getenv("GITHUB_TOKEN")); err != nil {
+			deliveryErr = errors.Join(deliveryErr, fmt.Errorf("failed to post the pull request review: %w", err))
+		} else {
+			log.Successf("Pull request review posted to %s/%s#%d", owner, repo, githubPR)
+		}
 	}
 
-	if verbose {
-		color.Blue("[INFO] Saving report to file...")
+	if gitlabMR != 0 {
+		if err := gitlab.PostMRDiscussions(cmd.Context(), report, projectID, gitlabMR, os.Getenv("GITLAB_TOKEN"), gitlabURL); err != nil {
+			deliveryErr = errors.Join(deliveryErr, fmt.Errorf("failed to post the merge request discussions: %w", err))
+		} else {
+			log.Successf("Merge request discussions posted to !%d", gitlabMR)
+		}
 	}
 
-	// Save report to file
-	reportPath := filepath.Join(outputDir, "review_report.json")
-	if err := report.SaveToFile(reportPath); err != nil {
-		color.Yellow("[WARNING] Failed to save report: %v", err)
-	} else if verbose {
-		color.Green("[SUCCESS] Report saved to: %s", reportPath)
+	if slackWebhook != "" {
+		repoName, branchName := gitContext(repoPath)
+		if sent, err := notify.SendSlackSummary(cmd.Context(), report, slackWebhook, repoName, branchName, slackAlways); err != nil {
+			deliveryErr = errors.Join(deliveryErr, fmt.Errorf("failed to send the Slack notification: %w", err))
+		} else if sent {
+			log.Successf("Slack notification sent")
+		} else {
+			log.Infof("No issues found, Slack notification skipped")
+		}
 	}
 
-	if verbose {
-		color.Blue("[INFO] Sending email...")
+	failOn := cfg.FailOn
+	if exitCodeBits {
+		// Any issue with a severity bit makes the run exit non-zero
+		failOn = "low"
+	}
+	failing := countAtOrAbove(report, failOn)
+	if ciSummary != "" {
+		w := cmd.ErrOrStderr()
+		if ciSummary == "stdout" {
+			w = cmd.OutOrStdout()
+		}
+		fmt.Fprintln(w, report.CISummaryLine(deliveryErr != nil || failing > 0))
 	}
 
-	// Send email if requested
-	if emailTo != "" {
-		if err := sendEmailReport(report, emailTo); err != nil {
-			color.Yellow("[WARNING] Failed to send email: %v", err)
-		} else if verbose {
-			color.Green("[SUCCESS] Email sent to: %s", emailTo)
+	// A report that could not be delivered outranks its findings
+	if deliveryErr != nil {
+		return exitError(ExitOutput, deliveryErr)
+	}
+
+	if exitCodeBits {
+		if bits := severityBits(report); bits != 0 {
+			return &severityBitsError{bits: bits}
+		}
+		return nil
+	}
+
+	if failing > 0 {
+		noun := "issues"
+		if failing == 1 {
+			noun = "issue"
 		}
-	} else if verbose {
-		color.Blue("[INFO] No email requested")
+		return exitError(ExitFindings, fmt.Errorf("found %d %s at or above %s severity (--fail-on)", failing, noun, cfg.FailOn))
 	}
 
 	return nil
 }
 
-func sendEmailReport(report *review.Report, emailTo string) error {
-	// Email functionality will be implemented in a separate module
-	color.Blue("[INFO] Email functionality coming soon")
+// countAtOrAbove counts the report's issues at or above threshold; "none" never fails
+func countAtOrAbove(report *review.Report, threshold string) int {
+	count := 0
+	for _, issue := range report.Issues {
+		if severityAtLeast(issue, threshold) {
+			count++
+		}
+	}
+	return count
+}
+
+// severityAtLeast reports whether issue is at or above the threshold severity.
+// Thresholds that are not a severity, such as "none", match no issue.
+func severityAtLeast(issue review.Issue, threshold string) bool {
+	severities := review.Severities()
+	limit := slices.Index(severities, threshold)
+	rank := slices.Index(severities, issue.Severity)
+	return limit >= 0 && rank >= 0 && rank <= limit
+}
+
+// runDryRun prints the files a review would analyze without running any checks
+func runDryRun(cmd *cobra.Command, repoPath string, cfg *config.Config) error {
+	plan, err := review.Plan(cmd.Context(), reviewOptions(repoPath, cfg))
+	if err != nil {
+		return exitError(reviewExitCode(err), fmt.Errorf("dry run failed: %w", err))
+	}
+
+	switch outputFormat(cfg) {
+	case "json":
+		err = plan.WriteJSON(os.Stdout)
+	case "text":
+		err = plan.WriteText(os.Stdout)
+	default:
+		return fmt.Errorf("--dry-run supports the text and json formats, not %q", outputFormat(cfg))
+	}
+	if err != nil {
+		return exitError(ExitOutput, fmt.Errorf("failed to output dry run: %w", err))
+	}
 	return nil
 }
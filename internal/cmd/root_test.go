@@ -0,0 +1,834 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/BrandonThomas84/code-review-automation/internal/github"
+	"github.com/BrandonThomas84/code-review-automation/pkg/review"
+)
+
+// runCLI executes the root command in dir and returns its exit code
+func runCLI(t *testing.T, dir string, args ...string) int {
+	t.Helper()
+	t.Chdir(dir)
+
+	root := NewRootCommand()
+	root.SetArgs(args)
+	root.SetOut(new(nopWriter))
+	root.SetErr(new(nopWriter))
+	return ExitCode(root.Execute())
+}
+
+type nopWriter struct{}
+
+func (nopWriter) Write(p []byte) (int, error) { return len(p), nil }
+
+func writeFile(t *testing.T, dir, name, content string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write %s: %v", name, err)
+	}
+}
+
+func TestExitCodes(t *testing.T) {
+	secrets := t.TempDir()
+	writeFile(t, secrets, "app.py", "password = \"supersecret1\"\n")
+
+	brokenConfig := t.TempDir()
+	writeFile(t, brokenConfig, ".autoreview.yml", "target_branch: [unclosed\n")
+
+	outputIsFile := t.TempDir()
+	writeFile(t, outputIsFile, "app.py", "x = 1\n")
+	writeFile(t, outputIsFile, "reports", "not a directory\n")
+
+	tests := []struct {
+		name string
+		dir  string
+		args []string
+		want int
+	}{
+		{"clean full scan", t.TempDir(), []string{"--full-scan"}, ExitOK},
+		{"findings below threshold", secrets, []string{"--full-scan", "--fail-on", "critical"}, ExitOK},
+		{"findings at threshold", secrets, []string{"--full-scan", "--fail-on", "high"}, ExitFindings},
+		{"no target branch outside a repository", t.TempDir(), nil, ExitSetup},
+		{"unknown flag", t.TempDir(), []string{"--no-such-flag"}, ExitUsage},
+		{"unknown fail-on severity", t.TempDir(), []string{"--full-scan", "--fail-on", "urgent"}, ExitUsage},
+		{"invalid category", t.TempDir(), []string{"--full-scan", "--only", "style"}, ExitUsage},
+		{"not a git repository", t.TempDir(), []string{"-t", "main"}, ExitSetup},
+		{"unreadable config file", brokenConfig, []string{"--full-scan"}, ExitSetup},
+		{"output directory not writable", outputIsFile, []string{"--full-scan", "-o", "reports"}, ExitOutput},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := runCLI(t, tt.dir, tt.args...); got != tt.want {
+				t.Errorf("exit code = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExitCode_Nil(t *testing.T) {
+	if got := ExitCode(nil); got != ExitOK {
+		t.Errorf("ExitCode(nil) = %d, want %d", got, ExitOK)
+	}
+}
+
+func TestWorkingDirectoryRemoved(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "gone")
+	if err := os.Mkdir(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Chdir(dir)
+	if err := os.Remove(dir); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Getwd(); err == nil {
+		t.Skip("the working directory can still be resolved after removal")
+	}
+
+	root := NewRootCommand()
+	root.SetArgs([]string{"--full-scan"})
+	root.SetOut(new(nopWriter))
+	root.SetErr(new(nopWriter))
+	if got := ExitCode(root.Execute()); got != ExitSetup {
+		t.Errorf("exit code = %d, want %d", got, ExitSetup)
+	}
+}
+
+func TestExitCodeBits(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "app.py", "print('hi')\npassword = \"supersecret1\"\n")
+
+	if got := runCLI(t, dir, "--full-scan", "--exit-code-bits"); got != ExitBitLow|ExitBitHigh {
+		t.Errorf("low and high issues: exit code = %d, want %d", got, ExitBitLow|ExitBitHigh)
+	}
+	if got := runCLI(t, dir, "--full-scan", "--exit-code-bits", "--min-severity", "high"); got != ExitBitHigh {
+		t.Errorf("high issues only: exit code = %d, want %d", got, ExitBitHigh)
+	}
+	if got := runCLI(t, t.TempDir(), "--full-scan", "--exit-code-bits"); got != ExitOK {
+		t.Errorf("no issues: exit code = %d, want %d", got, ExitOK)
+	}
+	if got := runCLI(t, dir, "--full-scan", "--exit-code-bits", "--fail-on", "high"); got != ExitBitError|ExitUsage {
+		t.Errorf("combined with --fail-on: exit code = %d, want %d", got, ExitBitError|ExitUsage)
+	}
+	writeFile(t, dir, ".autoreview.yml", "fail_on: critical\n")
+	if got := runCLI(t, dir, "--full-scan", "--exit-code-bits"); got != ExitBitLow|ExitBitHigh {
+		t.Errorf("fail_on in the config file: exit code = %d, want %d", got, ExitBitLow|ExitBitHigh)
+	}
+	if got := runCLI(t, t.TempDir(), "--target", "main", "--exit-code-bits"); got != ExitBitError|ExitSetup {
+		t.Errorf("not a repository: exit code = %d, want %d", got, ExitBitError|ExitSetup)
+	}
+}
+
+func TestSeverityBits(t *testing.T) {
+	tests := []struct {
+		severities []string
+		want       int
+	}{
+		{nil, 0},
+		{[]string{"info"}, 0},
+		{[]string{"low"}, ExitBitLow},
+		{[]string{"medium"}, ExitBitMedium},
+		{[]string{"high"}, ExitBitHigh},
+		{[]string{"critical"}, ExitBitHigh},
+		{[]string{"low", "medium"}, ExitBitLow | ExitBitMedium},
+		{[]string{"critical", "low", "info"}, ExitBitLow | ExitBitHigh},
+		{[]string{"high", "medium", "low", "medium"}, ExitBitLow | ExitBitMedium | ExitBitHigh},
+	}
+
+	for _, tt := range tests {
+		report := &review.Report{}
+		for _, severity := range tt.severities {
+			report.Issues = append(report.Issues, review.Issue{Severity: severity})
+		}
+		if got := severityBits(report); got != tt.want {
+			t.Errorf("severityBits(%v) = %d, want %d", tt.severities, got, tt.want)
+		}
+		if tt.want != 0 {
+			if got := ExitCode(&severityBitsError{bits: tt.want}); got != tt.want {
+				t.Errorf("ExitCode for bits %d = %d", tt.want, got)
+			}
+		}
+	}
+}
+
+// fakeSender records the reports it is asked to send instead of using SMTP
+type fakeSender struct {
+	sent     []*review.Report
+	to       []string
+	repoName string
+	branch   string
+	err      error
+}
+
+func (f *fakeSender) SendReportWithContext(report *review.Report, toEmail, repoName, branchName string, prNumber int, prTitle string) error {
+	f.sent = append(f.sent, report)
+	f.to = append(f.to, toEmail)
+	f.repoName, f.branch = repoName, branchName
+	return f.err
+}
+
+func useFakeSender(t *testing.T, sender *fakeSender) {
+	t.Helper()
+	previous := newReportSender
+	newReportSender = func() reportSender { return sender }
+	t.Cleanup(func() { newReportSender = previous })
+}
+
+func TestEmailReport(t *testing.T) {
+	t.Setenv("GITHUB_REPOSITORY", "acme/widgets")
+	t.Setenv("GITHUB_HEAD_REF", "feature/login")
+
+	dir := t.TempDir()
+	writeFile(t, dir, "app.py", "password = \"supersecret1\"\n")
+
+	sender := &fakeSender{}
+	useFakeSender(t, sender)
+
+	if got := runCLI(t, dir, "--full-scan", "--email", "dev@example.com"); got != ExitOK {
+		t.Fatalf("exit code = %d, want %d", got, ExitOK)
+	}
+	if len(sender.sent) != 1 {
+		t.Fatalf("Expected one email to be sent, got %d", len(sender.sent))
+	}
+	if sender.to[0] != "dev@example.com" {
+		t.Errorf("Expected the email to go to dev@example.com, got %q", sender.to[0])
+	}
+	if sender.sent[0].Summary.TotalIssues == 0 {
+		t.Error("Expected the emailed report to contain the findings")
+	}
+	if sender.repoName != "acme/widgets" || sender.branch != "feature/login" {
+		t.Errorf("Expected repo and branch context, got %q and %q", sender.repoName, sender.branch)
+	}
+}
+
+func TestEmailReport_NotRequested(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "app.py", "x = 1\n")
+
+	sender := &fakeSender{}
+	useFakeSender(t, sender)
+
+	if got := runCLI(t, dir, "--full-scan"); got != ExitOK {
+		t.Fatalf("exit code = %d, want %d", got, ExitOK)
+	}
+	if len(sender.sent) != 0 {
+		t.Errorf("Expected no email without --email, got %d", len(sender.sent))
+	}
+}
+
+func TestEmailReport_FailureIsOutputError(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "app.py", "x = 1\n")
+
+	useFakeSender(t, &fakeSender{err: errors.New("connection refused")})
+
+	if got := runCLI(t, dir, "--full-scan", "--email", "dev@example.com"); got != ExitOutput {
+		t.Errorf("exit code = %d, want %d", got, ExitOutput)
+	}
+}
+
+func TestGitContext(t *testing.T) {
+	t.Setenv("GITHUB_REPOSITORY", "")
+	t.Setenv("GITHUB_HEAD_REF", "")
+
+	dir := filepath.Join(t.TempDir(), "widgets")
+	if err := os.Mkdir(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if repo, branch := gitContext(dir); repo != "" || branch != "" {
+		t.Errorf("Expected no context outside a repository, got %q and %q", repo, branch)
+	}
+
+	if out, err := exec.Command("git", "init", "-q", "-b", "feature/login", dir).CombinedOutput(); err != nil {
+		t.Fatalf("git init failed: %v: %s", err, out)
+	}
+	if repo, branch := gitContext(dir); repo != "widgets" || branch != "feature/login" {
+		t.Errorf("Expected widgets on feature/login, got %q and %q", repo, branch)
+	}
+}
+
+// fakePoster records the pull request comments it is asked to post
+type fakePoster struct {
+	pr     github.PullRequest
+	bodies []string
+}
+
+func (f *fakePoster) CreateComment(ctx context.Context, pr github.PullRequest, body string) error {
+	f.pr = pr
+	f.bodies = append(f.bodies, body)
+	return nil
+}
+
+func useFakePoster(t *testing.T) *fakePoster {
+	t.Helper()
+	poster := &fakePoster{}
+	previous := newCommentPoster
+	newCommentPoster = func() commentPoster { return poster }
+	t.Cleanup(func() { newCommentPoster = previous })
+	return poster
+}
+
+func TestCompareBaseReport(t *testing.T) {
+	t.Setenv("GITHUB_REPOSITORY", "acme/widgets")
+	t.Setenv("GITHUB_EVENT_PATH", "")
+	t.Setenv("GITHUB_REF", "refs/pull/7/merge")
+
+	dir := t.TempDir()
+	writeFile(t, dir, "app.py", "password = \"supersecret1\"\nprint(password)\n")
+	writeFile(t, dir, "base.json", `{"issues": [{"type": "quality", "severity": "low", "message": "Print statement found - consider using logging", "file": "app.py", "line": 2, "rule_id": "print-statement"}]}`)
+
+	poster := useFakePoster(t)
+	if got := runCLI(t, dir, "--full-scan", "--compare-base-report", "base.json"); got != ExitOK {
+		t.Fatalf("exit code = %d, want %d", got, ExitOK)
+	}
+
+	if len(poster.bodies) != 1 {
+		t.Fatalf("Expected one comment, got %d", len(poster.bodies))
+	}
+	if poster.pr != (github.PullRequest{Repo: "acme/widgets", Number: 7}) {
+		t.Errorf("Unexpected pull request %+v", poster.pr)
+	}
+	body := poster.bodies[0]
+	if !strings.Contains(body, "hardcoded password") || strings.Contains(body, "Print statement") {
+		t.Errorf("Expected only the new finding in the comment, got:\n%s", body)
+	}
+}
+
+func TestCompareBaseReport_MissingBase(t *testing.T) {
+	t.Setenv("GITHUB_REPOSITORY", "acme/widgets")
+	t.Setenv("GITHUB_EVENT_PATH", "")
+	t.Setenv("GITHUB_REF", "refs/pull/7/merge")
+
+	dir := t.TempDir()
+	writeFile(t, dir, "app.py", "print('hi')\n")
+
+	poster := useFakePoster(t)
+	if got := runCLI(t, dir, "--full-scan", "--compare-base-report", "missing.json"); got != ExitOK {
+		t.Fatalf("exit code = %d, want %d", got, ExitOK)
+	}
+	if len(poster.bodies) != 1 || !strings.Contains(poster.bodies[0], "every finding is listed") {
+		t.Errorf("Expected a comment listing every finding, got %q", poster.bodies)
+	}
+}
+
+func TestCompareBaseReport_NotAPullRequest(t *testing.T) {
+	t.Setenv("GITHUB_REPOSITORY", "")
+
+	dir := t.TempDir()
+	writeFile(t, dir, "app.py", "x = 1\n")
+
+	poster := useFakePoster(t)
+	if got := runCLI(t, dir, "--full-scan", "--compare-base-report", "base.json"); got != ExitOK {
+		t.Fatalf("exit code = %d, want %d", got, ExitOK)
+	}
+	if len(poster.bodies) != 0 {
+		t.Errorf("Did not expect a comment outside a pull request, got %d", len(poster.bodies))
+	}
+}
+
+func TestCISummary(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "app.py", "password = \"supersecret1\"\n")
+
+	tests := []struct {
+		name       string
+		args       []string
+		wantCode   int
+		wantStderr string
+		wantStdout string
+	}{
+		{
+			name:       "failing run on stderr",
+			args:       []string{"--full-scan", "--fail-on", "high", "--ci-summary"},
+			wantCode:   ExitFindings,
+			wantStderr: "AUTOREVIEW_RESULT critical=0 high=3 medium=0 low=0 info=0 total=3 score=88 failed=true\n",
+		},
+		{
+			name:       "passing run on stdout",
+			args:       []string{"--full-scan", "--ci-summary=stdout"},
+			wantCode:   ExitOK,
+			wantStdout: "AUTOREVIEW_RESULT critical=0 high=3 medium=0 low=0 info=0 total=3 score=88 failed=false\n",
+		},
+		{
+			name:     "not requested",
+			args:     []string{"--full-scan"},
+			wantCode: ExitOK,
+		},
+		{
+			name:     "unknown stream",
+			args:     []string{"--full-scan", "--ci-summary=file"},
+			wantCode: ExitUsage,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Chdir(dir)
+			var stdout, stderr strings.Builder
+			root := NewRootCommand()
+			root.SetArgs(append(tt.args, "-o", t.TempDir()))
+			root.SetOut(&stdout)
+			root.SetErr(&stderr)
+
+			if got := ExitCode(root.Execute()); got != tt.wantCode {
+				t.Fatalf("exit code = %d, want %d", got, tt.wantCode)
+			}
+			// The report itself goes to the process's stdout, so only the summary line is captured
+			if got := lastLineWith(stderr.String(), "AUTOREVIEW_RESULT"); got != tt.wantStderr {
+				t.Errorf("stderr summary = %q, want %q", got, tt.wantStderr)
+			}
+			if got := lastLineWith(stdout.String(), "AUTOREVIEW_RESULT"); got != tt.wantStdout {
+				t.Errorf("stdout summary = %q, want %q", got, tt.wantStdout)
+			}
+		})
+	}
+}
+
+// lastLineWith returns the last line of s starting with prefix, with its newline
+func lastLineWith(s, prefix string) string {
+	found := ""
+	for _, line := range strings.SplitAfter(s, "\n") {
+		if strings.HasPrefix(line, prefix) {
+			found = line
+		}
+	}
+	return found
+}
+
+func TestFailOn_MediumIssue(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "app.py", "try:\n    x = 1\nexcept:\n    pass\n")
+
+	if got := runCLI(t, dir, "--full-scan", "--fail-on", "medium"); got != ExitFindings {
+		t.Errorf("--fail-on medium: exit code = %d, want %d", got, ExitFindings)
+	}
+	if got := runCLI(t, dir, "--full-scan", "--fail-on", "high"); got != ExitOK {
+		t.Errorf("--fail-on high: exit code = %d, want %d", got, ExitOK)
+	}
+}
+
+func TestConfigFile_SeverityOverridesAndFlagPrecedence(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "app.py", "try:\n    x = 1\nexcept:\n    pass\n")
+	writeFile(t, dir, ".autoreview.yml", "fail_on: high\nseverity_overrides:\n  bare-except: high\n")
+
+	if got := runCLI(t, dir, "--full-scan"); got != ExitFindings {
+		t.Errorf("bare-except raised to high with fail_on high: exit code = %d, want %d", got, ExitFindings)
+	}
+	if got := runCLI(t, dir, "--full-scan", "--fail-on", "critical"); got != ExitOK {
+		t.Errorf("--fail-on critical over the config file: exit code = %d, want %d", got, ExitOK)
+	}
+}
+
+func TestOutputTemplate(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "app.py", "print('hi')\n")
+	writeFile(t, dir, "good.tmpl", "{{range .Issues}}{{.File}}:{{.Line}}\n{{end}}")
+	writeFile(t, dir, "bad.tmpl", "{{range .Issues}}")
+
+	if got := runCLI(t, dir, "--full-scan", "--output-template", "good.tmpl"); got != ExitOK {
+		t.Errorf("valid template: exit code = %d, want %d", got, ExitOK)
+	}
+	if got := runCLI(t, dir, "--full-scan", "--output-template", "bad.tmpl"); got != ExitUsage {
+		t.Errorf("invalid template: exit code = %d, want %d", got, ExitUsage)
+	}
+	if got := runCLI(t, dir, "--full-scan", "--output-template", "missing.tmpl"); got != ExitUsage {
+		t.Errorf("missing template: exit code = %d, want %d", got, ExitUsage)
+	}
+}
+
+func TestDisableRule(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "app.py", "print('hi')\n")
+	writeFile(t, dir, ".autoreview.yml", "rules:\n  disabled: [todo-comment]\n")
+
+	if got := runCLI(t, dir, "--full-scan", "--fail-on", "low"); got != ExitFindings {
+		t.Errorf("without --disable-rule: exit code = %d, want %d", got, ExitFindings)
+	}
+	if got := runCLI(t, dir, "--full-scan", "--fail-on", "low", "--disable-rule", "print-statement"); got != ExitOK {
+		t.Errorf("--disable-rule print-statement: exit code = %d, want %d", got, ExitOK)
+	}
+}
+
+func TestBaselineReport(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "app.py", "password = \"supersecret1\"\nprint('hi')\n")
+	// Keep the outputs out of the scanned tree, or the second run reviews the first run's JSON
+	out := t.TempDir()
+	baseline := filepath.Join(out, "baseline.json")
+	reportDir := filepath.Join(out, "reports")
+
+	fingerprints := func(path string) []string {
+		t.Helper()
+		report, err := review.LoadReport(path)
+		if err != nil {
+			t.Fatalf("Failed to load %s: %v", path, err)
+		}
+		var keys []string
+		for _, issue := range report.Issues {
+			keys = append(keys, issue.Fingerprint())
+		}
+		slices.Sort(keys)
+		return keys
+	}
+
+	if got := runCLI(t, dir, "--full-scan", "--baseline-report", baseline, "-o", reportDir); got != ExitOK {
+		t.Fatalf("exit code = %d, want %d", got, ExitOK)
+	}
+	all := fingerprints(baseline)
+	if len(all) == 0 {
+		t.Fatal("Expected the baseline to record the current findings")
+	}
+	if report := fingerprints(filepath.Join(reportDir, "review_report.json")); !slices.Equal(report, all) {
+		t.Errorf("Report findings %v, want every baseline finding %v", report, all)
+	}
+
+	// --min-severity narrows the report but not the baseline
+	if got := runCLI(t, dir, "--full-scan", "--baseline-report", baseline, "-o", reportDir, "--min-severity", "high"); got != ExitOK {
+		t.Fatalf("--min-severity high: exit code = %d, want %d", got, ExitOK)
+	}
+	if baseline := fingerprints(baseline); !slices.Equal(baseline, all) {
+		t.Errorf("Baseline with --min-severity high has %v, want every finding %v", baseline, all)
+	}
+	report := fingerprints(filepath.Join(reportDir, "review_report.json"))
+	if len(report) == 0 || len(report) >= len(all) {
+		t.Errorf("Report with --min-severity high has %d of %d findings, want only the high ones", len(report), len(all))
+	}
+
+	if got := runCLI(t, dir, "--full-scan", "--baseline-report", baseline, "-o", reportDir, "--min-severity", "urgent"); got != ExitUsage {
+		t.Errorf("unknown --min-severity: exit code = %d, want %d", got, ExitUsage)
+	}
+}
+
+func TestStagedAndWorkingChanges(t *testing.T) {
+	dir := t.TempDir()
+	git := func(args ...string) {
+		t.Helper()
+		if out, err := exec.Command("git", append([]string{"-C", dir}, args...)...).CombinedOutput(); err != nil {
+			t.Fatalf("git %s failed: %v: %s", strings.Join(args, " "), err, out)
+		}
+	}
+	git("init", "-q", "-b", "main")
+	writeFile(t, dir, "app.py", "print('staged')\n")
+	git("add", "app.py")
+	out := t.TempDir()
+
+	printLines := func() []int {
+		t.Helper()
+		report, err := review.LoadReport(filepath.Join(out, "review_report.json"))
+		if err != nil {
+			t.Fatalf("Failed to load report: %v", err)
+		}
+		var lines []int
+		for _, issue := range report.Issues {
+			if issue.File == "app.py" && issue.RuleID == "print-statement" {
+				lines = append(lines, issue.Line)
+			}
+		}
+		return lines
+	}
+
+	// No target branch or commits are needed to review what is staged
+	if got := runCLI(t, dir, "--staged", "-o", out, "--fail-on", "low"); got != ExitFindings {
+		t.Fatalf("--staged: exit code = %d, want %d", got, ExitFindings)
+	}
+	if got := printLines(); !slices.Equal(got, []int{1}) {
+		t.Errorf("--staged reported print() on lines %v, want [1]", got)
+	}
+	if got := runCLI(t, dir, "--working", "-o", out, "--fail-on", "low"); got != ExitOK {
+		t.Errorf("--working with everything staged: exit code = %d, want %d", got, ExitOK)
+	}
+
+	// An unstaged edit is reviewed by --working, and only its own line is reported
+	writeFile(t, dir, "app.py", "print('staged')\nprint('unstaged')\n")
+	if got := runCLI(t, dir, "--working", "-o", out, "--fail-on", "low"); got != ExitFindings {
+		t.Fatalf("--working: exit code = %d, want %d", got, ExitFindings)
+	}
+	if got := printLines(); !slices.Equal(got, []int{2}) {
+		t.Errorf("--working reported print() on lines %v, want [2]", got)
+	}
+
+	if got := runCLI(t, dir, "--staged", "--working"); got != ExitUsage {
+		t.Errorf("--staged --working: exit code = %d, want %d", got, ExitUsage)
+	}
+	if got := runCLI(t, dir, "--staged", "--full-scan", "-o", out); got != ExitUsage {
+		t.Errorf("--staged --full-scan: exit code = %d, want %d", got, ExitUsage)
+	}
+}
+
+func TestGitHubPR(t *testing.T) {
+	var paths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+	t.Setenv("GITHUB_API_URL", server.URL)
+	t.Setenv("GITHUB_REPOSITORY", "acme/widgets")
+	t.Setenv("GITHUB_TOKEN", "secret-token")
+
+	dir := t.TempDir()
+	writeFile(t, dir, "app.py", "password = \"supersecret1\"\n")
+
+	if got := runCLI(t, dir, "--full-scan", "--github-pr", "7"); got != ExitOK {
+		t.Fatalf("exit code = %d, want %d", got, ExitOK)
+	}
+	if !slices.Equal(paths, []string{"/repos/acme/widgets/pulls/7/reviews"}) {
+		t.Errorf("Expected a review on pull request 7, got requests to %v", paths)
+	}
+}
+
+func TestGitHubPR_Errors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer server.Close()
+	t.Setenv("GITHUB_API_URL", server.URL)
+
+	dir := t.TempDir()
+	writeFile(t, dir, "app.py", "x = 1\n")
+
+	t.Setenv("GITHUB_REPOSITORY", "")
+	if got := runCLI(t, dir, "--full-scan", "--github-pr", "7"); got != ExitUsage {
+		t.Errorf("exit code without GITHUB_REPOSITORY = %d, want %d", got, ExitUsage)
+	}
+
+	t.Setenv("GITHUB_REPOSITORY", "acme/widgets")
+	if got := runCLI(t, dir, "--full-scan", "--github-pr", "7"); got != ExitOutput {
+		t.Errorf("exit code for a rejected review = %d, want %d", got, ExitOutput)
+	}
+}
+
+func TestGitLabMR(t *testing.T) {
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		if r.Method == http.MethodGet {
+			fmt.Fprint(w, `{"diff_refs": {"base_sha": "a", "head_sha": "c", "start_sha": "b"}}`)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+	t.Setenv("CI_SERVER_URL", "")
+	t.Setenv("CI_PROJECT_ID", "12")
+	t.Setenv("GITLAB_TOKEN", "secret-token")
+
+	dir := t.TempDir()
+	writeFile(t, dir, "app.py", "password = \"supersecret1\"\n")
+
+	if got := runCLI(t, dir, "--full-scan", "--gitlab-mr", "34", "--gitlab-url", server.URL); got != ExitOK {
+		t.Fatalf("exit code = %d, want %d", got, ExitOK)
+	}
+	if !slices.Contains(requests, "POST /api/v4/projects/12/merge_requests/34/discussions") ||
+		!slices.Contains(requests, "POST /api/v4/projects/12/merge_requests/34/notes") {
+		t.Errorf("Expected discussions and a summary note on merge request 34, got %v", requests)
+	}
+}
+
+func TestGitLabMR_MissingProject(t *testing.T) {
+	t.Setenv("CI_PROJECT_ID", "")
+
+	dir := t.TempDir()
+	writeFile(t, dir, "app.py", "x = 1\n")
+
+	if got := runCLI(t, dir, "--full-scan", "--gitlab-mr", "34"); got != ExitUsage {
+		t.Errorf("exit code without CI_PROJECT_ID = %d, want %d", got, ExitUsage)
+	}
+}
+
+func TestSlackWebhook(t *testing.T) {
+	var posts int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		posts++
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	writeFile(t, dir, "app.py", "x = 1\n")
+
+	// A clean review is only announced with --slack-always
+	if got := runCLI(t, dir, "--full-scan", "--slack-webhook", server.URL); got != ExitOK {
+		t.Fatalf("exit code = %d, want %d", got, ExitOK)
+	}
+	if posts != 0 {
+		t.Errorf("Expected no Slack message for a clean review, got %d", posts)
+	}
+	if got := runCLI(t, dir, "--full-scan", "--slack-webhook", server.URL, "--slack-always"); got != ExitOK {
+		t.Fatalf("exit code = %d, want %d", got, ExitOK)
+	}
+	if posts != 1 {
+		t.Errorf("Expected one Slack message with --slack-always, got %d", posts)
+	}
+
+	writeFile(t, dir, "app.py", "password = \"supersecret1\"\n")
+	if got := runCLI(t, dir, "--full-scan", "--slack-webhook", server.URL); got != ExitOK {
+		t.Fatalf("exit code = %d, want %d", got, ExitOK)
+	}
+	if posts != 2 {
+		t.Errorf("Expected a Slack message for the findings, got %d messages", posts)
+	}
+}
+
+func TestSlackWebhook_Errors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "no_service", http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	writeFile(t, dir, "app.py", "password = \"supersecret1\"\n")
+
+	if got := runCLI(t, dir, "--full-scan", "--slack-webhook", server.URL); got != ExitOutput {
+		t.Errorf("exit code for a rejected webhook = %d, want %d", got, ExitOutput)
+	}
+	if got := runCLI(t, dir, "--full-scan", "--slack-webhook", "hooks.slack.com/services/x"); got != ExitUsage {
+		t.Errorf("exit code for a webhook without a scheme = %d, want %d", got, ExitUsage)
+	}
+	if got := runCLI(t, dir, "--full-scan", "--slack-always"); got != ExitUsage {
+		t.Errorf("exit code for --slack-always alone = %d, want %d", got, ExitUsage)
+	}
+}
+
+func TestDoctor_GitMissing(t *testing.T) {
+	env := &doctorEnv{
+		repoPath: t.TempDir(),
+		lookPath: func(string) (string, error) { return "", exec.ErrNotFound },
+	}
+
+	if result := checkGitAvailable(context.Background(), env); result.Status != doctorFail || !strings.Contains(result.Detail, "not installed") {
+		t.Errorf("git check = %+v, want a failure saying git is not installed", result)
+	}
+	if result := checkRepository(context.Background(), env); result.Status != doctorFail || result.Detail != "cannot check without git" {
+		t.Errorf("repository check = %+v, want a failure for the missing git", result)
+	}
+}
+
+func TestDoctor_NotARepository(t *testing.T) {
+	dir := t.TempDir()
+	env := &doctorEnv{repoPath: dir, lookPath: exec.LookPath}
+
+	if result := checkGitAvailable(context.Background(), env); result.Status != doctorPass {
+		t.Errorf("git check = %+v, want a pass", result)
+	}
+	result := checkRepository(context.Background(), env)
+	if result.Status != doctorFail || !strings.Contains(result.Detail, "not inside a git repository") {
+		t.Errorf("repository check = %+v, want a failure outside a repository", result)
+	}
+
+	if out, err := exec.Command("git", "init", "-q", "-b", "feature/login", dir).CombinedOutput(); err != nil {
+		t.Fatalf("git init failed: %v: %s", err, out)
+	}
+	if result := checkRepository(context.Background(), env); result.Status != doctorPass || !strings.HasSuffix(result.Detail, "on branch feature/login") {
+		t.Errorf("repository check = %+v, want a pass on feature/login", result)
+	}
+}
+
+func TestDoctor(t *testing.T) {
+	for _, name := range []string{"AUTOREVIEW_SMTP_HOST", "SMTP_HOST", "AUTOREVIEW_SMTP_USER", "SMTP_USER", "AUTOREVIEW_SMTP_PASSWORD", "SMTP_PASSWORD", "AUTOREVIEW_FROM_EMAIL", "FROM_EMAIL", "SLACK_WEBHOOK_URL"} {
+		t.Setenv(name, "")
+	}
+
+	// Outside a repository the repository and target branch checks fail
+	dir := t.TempDir()
+	if got := runCLI(t, dir, "doctor", "--target", "main"); got != ExitSetup {
+		t.Errorf("exit code outside a repository = %d, want %d", got, ExitSetup)
+	}
+
+	// Unconfigured email and Slack are only warnings
+	if out, err := exec.Command("git", "init", "-q", "-b", "main", dir).CombinedOutput(); err != nil {
+		t.Fatalf("git init failed: %v: %s", err, out)
+	}
+	if got := runCLI(t, dir, "doctor"); got != ExitOK {
+		t.Errorf("exit code in a repository = %d, want %d", got, ExitOK)
+	}
+
+	// A half-configured SMTP server fails
+	t.Setenv("AUTOREVIEW_SMTP_HOST", "smtp.example.com")
+	if result := checkEmailConfig(context.Background(), &doctorEnv{}); result.Status != doctorFail || !strings.Contains(result.Detail, "AUTOREVIEW_SMTP_USER") {
+		t.Errorf("email check = %+v, want a failure naming the missing settings", result)
+	}
+}
+
+func TestSeverityAtLeast(t *testing.T) {
+	medium := review.Issue{Severity: "medium"}
+
+	tests := []struct {
+		threshold string
+		want      bool
+	}{
+		{"none", false},
+		{"critical", false},
+		{"high", false},
+		{"medium", true},
+		{"low", true},
+		{"info", true},
+	}
+	for _, tt := range tests {
+		if got := severityAtLeast(medium, tt.threshold); got != tt.want {
+			t.Errorf("severityAtLeast(medium, %q) = %v, want %v", tt.threshold, got, tt.want)
+		}
+	}
+}
+
+func TestHTMLReport(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "app.py", "password = \"supersecret1\"\nprint('hi')\n")
+
+	if got := runCLI(t, dir, "--full-scan", "--format", "html", "--output", "reports"); got != ExitOK {
+		t.Fatalf("exit code = %d, want %d", got, ExitOK)
+	}
+
+	content, err := os.ReadFile(filepath.Join(dir, "reports", "review_report.html"))
+	if err != nil {
+		t.Fatalf("Expected review_report.html in the output directory: %v", err)
+	}
+	html := string(content)
+	for _, want := range []string{"<!DOCTYPE html>", "🚨 Code Review", "Action Required", "High Severity (", "Low Severity (", "app.py:1"} {
+		if !strings.Contains(html, want) {
+			t.Errorf("Expected the HTML report to contain %q", want)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(dir, "reports", "review_report.json")); err != nil {
+		t.Errorf("Expected review_report.json to still be saved: %v", err)
+	}
+}
+
+func TestShowClean(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "app.py", "print('hi')\n")
+	writeFile(t, dir, "clean.py", "x = 1\n")
+	// Outside the repository, so a full scan does not pick up the saved report
+	output := t.TempDir()
+
+	for _, tt := range []struct {
+		args []string
+		want []string
+	}{
+		{nil, nil},
+		{[]string{"--show-clean"}, []string{"clean.py"}},
+	} {
+		if got := runCLI(t, dir, append([]string{"--full-scan", "--output", output}, tt.args...)...); got != ExitOK {
+			t.Fatalf("%v: exit code = %d, want %d", tt.args, got, ExitOK)
+		}
+		report, err := review.LoadReport(filepath.Join(output, "review_report.json"))
+		if err != nil {
+			t.Fatalf("Failed to load the saved report: %v", err)
+		}
+		if !slices.Equal(report.CleanFiles, tt.want) {
+			t.Errorf("%v: clean files = %v, want %v", tt.args, report.CleanFiles, tt.want)
+		}
+	}
+}
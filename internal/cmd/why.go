@@ -0,0 +1,85 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/BrandonThomas84/code-review-automation/pkg/review"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+func NewWhyCommand() *cobra.Command {
+	var whyJSON bool
+
+	cmd := &cobra.Command{
+		Use:   "why <path>",
+		Short: "Explain why a file is or isn't analyzed",
+		Long: `Walk the file selection steps for a single path: whether it is in the diff
+(or collected by --full-scan), which ignore pattern excludes it and where that
+pattern came from, whether security scanning skips it, and which analyzer and
+plugins handle it.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			repoPath, err := os.Getwd()
+			if err != nil {
+				return exitError(ExitSetup, fmt.Errorf("failed to get current directory: %w", err))
+			}
+
+			cfg, err := loadConfig(cmd, repoPath)
+			if err != nil {
+				return err
+			}
+
+			file := args[0]
+			if filepath.IsAbs(file) {
+				if file, err = filepath.Rel(repoPath, file); err != nil {
+					return fmt.Errorf("failed to resolve %s: %w", args[0], err)
+				}
+			}
+
+			selection, err := review.Explain(cmd.Context(), reviewOptions(repoPath, cfg), file)
+			if err != nil {
+				return exitError(reviewExitCode(err), err)
+			}
+
+			if whyJSON {
+				encoder := json.NewEncoder(os.Stdout)
+				encoder.SetIndent("", "  ")
+				return encoder.Encode(selection)
+			}
+
+			printSelection(selection)
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&whyJSON, "json", false, "Output the decision chain as JSON")
+
+	return cmd
+}
+
+func printSelection(selection *review.FileSelection) {
+	fmt.Printf("%s (%s mode)\n\n", selection.File, selection.Mode)
+
+	width := 0
+	for _, step := range selection.Steps {
+		width = max(width, len(step.Check))
+	}
+	for _, step := range selection.Steps {
+		mark := color.GreenString("✓")
+		if !step.Passed {
+			mark = color.YellowString("✗")
+		}
+		fmt.Printf("  %s %-*s  %s\n", mark, width, step.Check, step.Detail)
+	}
+
+	fmt.Println()
+	if selection.Selected {
+		fmt.Printf("Verdict: %s\n", selection.Verdict())
+	} else {
+		fmt.Printf("Verdict: %s\n", color.YellowString(selection.Verdict()))
+	}
+}
@@ -0,0 +1,482 @@
+package config
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// FileNames lists the config file names looked up at the repository root, in order
+var FileNames = []string{".autoreview.yml", ".autoreview.yaml"}
+
+// Source describes where an effective setting came from
+type Source string
+
+const (
+	SourceDefault Source = "default"
+	SourceFile    Source = "file"
+	SourceEnv     Source = "env"
+	SourceFlag    Source = "flag"
+)
+
+// Setting keys, shared by the config file, environment variables and flags
+const (
+	KeyTargetBranch     = "target_branch"
+	KeyOutputDir        = "output_dir"
+	KeyRemote           = "remote"
+	KeyOffline          = "offline"
+	KeyFullScan         = "full_scan"
+	KeySkipSubmodules   = "skip_submodules"
+	KeyJSON             = "json"
+	KeyFormat           = "format"
+	KeyMinSeverity      = "min_severity"
+	KeyOnly             = "only"
+	KeyFailOn           = "fail_on"
+	KeyMaxLineLength    = "max_line_length"
+	KeyMaxFileSize      = "max_file_size"
+	KeyCheckTodoTickets = "check_todo_tickets"
+	KeyShowClean        = "show_clean"
+	KeyEmail            = "email"
+	KeyVerbose          = "verbose"
+	KeyIgnore           = "ignore"
+	KeyInclude          = "include"
+	KeyInternalPackages = "internal_packages"
+	KeyRules            = "rules"
+	KeySeverities       = "severity_overrides"
+	KeyPlugins          = "plugins"
+)
+
+// envVars maps setting keys to the environment variables that override them
+var envVars = map[string]string{
+	KeyTargetBranch:     "AUTOREVIEW_TARGET_BRANCH",
+	KeyOutputDir:        "AUTOREVIEW_OUTPUT_DIR",
+	KeyRemote:           "AUTOREVIEW_REMOTE",
+	KeyOffline:          "AUTOREVIEW_OFFLINE",
+	KeyFullScan:         "AUTOREVIEW_FULL_SCAN",
+	KeySkipSubmodules:   "AUTOREVIEW_SKIP_SUBMODULES",
+	KeyJSON:             "AUTOREVIEW_JSON",
+	KeyFormat:           "AUTOREVIEW_FORMAT",
+	KeyMinSeverity:      "AUTOREVIEW_MIN_SEVERITY",
+	KeyOnly:             "AUTOREVIEW_ONLY",
+	KeyFailOn:           "AUTOREVIEW_FAIL_ON",
+	KeyMaxLineLength:    "AUTOREVIEW_MAX_LINE_LENGTH",
+	KeyMaxFileSize:      "AUTOREVIEW_MAX_FILE_SIZE",
+	KeyCheckTodoTickets: "AUTOREVIEW_CHECK_TODO_TICKETS",
+	KeyShowClean:        "AUTOREVIEW_SHOW_CLEAN",
+	KeyEmail:            "AUTOREVIEW_EMAIL",
+	KeyVerbose:          "AUTOREVIEW_VERBOSE",
+	KeyInternalPackages: "AUTOREVIEW_INTERNAL_PACKAGES",
+	KeyInclude:          "AUTOREVIEW_INCLUDE",
+}
+
+// Config is the effective configuration for a review run
+type Config struct {
+	TargetBranch     string      `yaml:"target_branch" json:"target_branch"`
+	OutputDir        string      `yaml:"output_dir" json:"output_dir"`
+	Remote           string      `yaml:"remote" json:"remote"`
+	Offline          bool        `yaml:"offline" json:"offline"`
+	FullScan         bool        `yaml:"full_scan" json:"full_scan"`
+	SkipSubmodules   bool        `yaml:"skip_submodules" json:"skip_submodules"`
+	JSON             bool        `yaml:"json" json:"json"`
+	Format           string      `yaml:"format" json:"format"`
+	MinSeverity      string      `yaml:"min_severity" json:"min_severity"`
+	Only             []string    `yaml:"only" json:"only"`
+	FailOn           string      `yaml:"fail_on" json:"fail_on"`
+	MaxLineLength    int         `yaml:"max_line_length" json:"max_line_length"`
+	MaxFileSize      int         `yaml:"max_file_size" json:"max_file_size"`
+	CheckTodoTickets bool        `yaml:"check_todo_tickets" json:"check_todo_tickets"`
+	ShowClean        bool        `yaml:"show_clean" json:"show_clean"`
+	Email            string      `yaml:"email" json:"email"`
+	Verbose          Verbosity   `yaml:"verbose" json:"verbose"`
+	Ignore           []string    `yaml:"ignore" json:"ignore"`
+	Include          []string    `yaml:"include" json:"include"`
+	InternalPackages []string    `yaml:"internal_packages" json:"internal_packages"`
+	Rules            RulesConfig `yaml:"rules" json:"rules"`
+	// SeverityOverrides maps rule IDs to the severity they are reported with
+	SeverityOverrides map[string]string `yaml:"severity_overrides" json:"severity_overrides"`
+	Plugins           []Plugin          `yaml:"plugins" json:"plugins"`
+
+	// Path is the config file that was loaded, empty when none was found
+	Path string `yaml:"-" json:"path"`
+	// Sources records where each setting's effective value came from
+	Sources map[string]Source `yaml:"-" json:"sources"`
+}
+
+// RulesConfig controls which checks are reported. Disabled applies to every
+// file; any other key is a language whose own disabled list applies only to
+// files of that language, e.g.
+//
+//	rules:
+//	  disabled: [performance]
+//	  python:
+//	    disabled: [print-statement]
+type RulesConfig struct {
+	Disabled  []string                 `yaml:"disabled" json:"disabled"`
+	Languages map[string]LanguageRules `yaml:",inline" json:"languages,omitempty"`
+}
+
+// LanguageRules controls which checks are reported for a single language
+type LanguageRules struct {
+	Disabled []string `yaml:"disabled" json:"disabled"`
+	// MaxLineLength overrides the top-level max_line_length for the language
+	MaxLineLength int `yaml:"max_line_length,omitempty" json:"max_line_length,omitempty"`
+}
+
+// LanguageDisabled returns the per-language disabled rule lists
+func (r RulesConfig) LanguageDisabled() map[string][]string {
+	disabled := map[string][]string{}
+	for language, rules := range r.Languages {
+		disabled[language] = rules.Disabled
+	}
+	return disabled
+}
+
+// LanguageMaxLineLength returns the per-language line length limits that are set
+func (r RulesConfig) LanguageMaxLineLength() map[string]int {
+	limits := map[string]int{}
+	for language, rules := range r.Languages {
+		if rules.MaxLineLength != 0 {
+			limits[language] = rules.MaxLineLength
+		}
+	}
+	return limits
+}
+
+// Verbosity is the log level from 0 (quiet) to 3 (debug). Booleans are
+// accepted for configs written when verbose was on or off: true is 2 (info).
+type Verbosity int
+
+// MaxVerbosity is the most verbose level, which logs debug messages
+const MaxVerbosity Verbosity = 3
+
+// ParseVerbosity parses a level from 0 to 3, or true or false
+func ParseVerbosity(value string) (Verbosity, error) {
+	if n, err := strconv.Atoi(value); err == nil {
+		if n < 0 || Verbosity(n) > MaxVerbosity {
+			return 0, fmt.Errorf("verbose must be between 0 and %d, got %d", MaxVerbosity, n)
+		}
+		return Verbosity(n), nil
+	}
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		return 0, fmt.Errorf("verbose must be a level from 0 to %d or a boolean, got %q", MaxVerbosity, value)
+	}
+	if b {
+		return 2, nil
+	}
+	return 0, nil
+}
+
+// UnmarshalYAML accepts a level or a boolean
+func (v *Verbosity) UnmarshalYAML(node *yaml.Node) error {
+	parsed, err := ParseVerbosity(node.Value)
+	if err != nil {
+		return err
+	}
+	*v = parsed
+	return nil
+}
+
+// Plugin declares an external rule plugin
+type Plugin struct {
+	Name       string        `yaml:"name" json:"name"`
+	Command    string        `yaml:"command" json:"command"`
+	Args       []string      `yaml:"args" json:"args,omitempty"`
+	Extensions []string      `yaml:"extensions" json:"extensions,omitempty"`
+	Timeout    time.Duration `yaml:"timeout" json:"timeout,omitempty"`
+}
+
+// Default returns the built-in configuration
+func Default() *Config {
+	cfg := &Config{
+		OutputDir:         "review_reports",
+		Format:            "text",
+		SkipSubmodules:    true,
+		FailOn:            "none",
+		MaxLineLength:     120,
+		MaxFileSize:       1048576,
+		Only:              []string{},
+		Ignore:            []string{},
+		Include:           []string{},
+		InternalPackages:  []string{},
+		Rules:             RulesConfig{Disabled: []string{}},
+		SeverityOverrides: map[string]string{},
+		Plugins:           []Plugin{},
+		Sources:           map[string]Source{},
+	}
+	for _, key := range Keys() {
+		cfg.Sources[key] = SourceDefault
+	}
+	return cfg
+}
+
+// Keys returns the setting keys in display order
+func Keys() []string {
+	return []string{KeyTargetBranch, KeyRemote, KeyOffline, KeyOutputDir, KeyFullScan, KeySkipSubmodules, KeyJSON, KeyFormat, KeyMinSeverity, KeyOnly, KeyFailOn, KeyMaxLineLength, KeyMaxFileSize, KeyCheckTodoTickets, KeyShowClean, KeyEmail, KeyVerbose, KeyIgnore, KeyInclude, KeyInternalPackages, KeyRules, KeySeverities, KeyPlugins}
+}
+
+// Load resolves defaults, the repository config file and environment overrides.
+// Flag overrides are applied afterwards by the caller via Set.
+func Load(repoPath string) (*Config, error) {
+	cfg := Default()
+
+	path := FindFile(repoPath)
+	if path != "" {
+		if err := cfg.loadFile(path); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := cfg.applyEnv(); err != nil {
+		return nil, err
+	}
+
+	return cfg, nil
+}
+
+// FindFile returns the path of the config file at the repository root, or "" if none exists
+func FindFile(repoPath string) string {
+	for _, name := range FileNames {
+		path := filepath.Join(repoPath, name)
+		if info, err := os.Stat(path); err == nil && !info.IsDir() {
+			return path
+		}
+	}
+	return ""
+}
+
+func (c *Config) loadFile(path string) error {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read config file %s: %w", path, err)
+	}
+
+	// Decode into a node first so we know which keys the file actually sets
+	var node yaml.Node
+	if err := yaml.Unmarshal(content, &node); err != nil {
+		return fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+	if len(node.Content) == 0 {
+		c.Path = path
+		return nil
+	}
+	if err := node.Decode(c); err != nil {
+		return fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+
+	// disabled_rules is accepted as a shorthand for rules.disabled
+	var shorthand struct {
+		DisabledRules []string `yaml:"disabled_rules"`
+	}
+	if err := node.Decode(&shorthand); err != nil {
+		return fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+	for _, rule := range shorthand.DisabledRules {
+		if !slices.Contains(c.Rules.Disabled, rule) {
+			c.Rules.Disabled = append(c.Rules.Disabled, rule)
+		}
+	}
+
+	root := node.Content[0]
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		key := root.Content[i].Value
+		if key == "disabled_rules" {
+			key = KeyRules
+		}
+		if _, known := c.Sources[key]; known {
+			c.Sources[key] = SourceFile
+		}
+	}
+
+	c.Path = path
+	return nil
+}
+
+func (c *Config) applyEnv() error {
+	for _, key := range Keys() {
+		name, ok := envVars[key]
+		if !ok {
+			continue
+		}
+		if value, set := os.LookupEnv(name); set && value != "" {
+			if err := c.Set(key, value, SourceEnv); err != nil {
+				return fmt.Errorf("invalid %s: %w", name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// Set overrides a single setting from a string value and records its source
+func (c *Config) Set(key, value string, source Source) error {
+	switch key {
+	case KeyTargetBranch:
+		c.TargetBranch = value
+	case KeyOutputDir:
+		c.OutputDir = value
+	case KeyRemote:
+		c.Remote = value
+	case KeyFormat:
+		c.Format = value
+	case KeyMinSeverity:
+		c.MinSeverity = value
+	case KeyFailOn:
+		c.FailOn = value
+	case KeyMaxLineLength:
+		n, err := strconv.Atoi(value)
+		if err != nil || n <= 0 {
+			return fmt.Errorf("max_line_length must be a positive number, got %q", value)
+		}
+		c.MaxLineLength = n
+	case KeyMaxFileSize:
+		n, err := strconv.Atoi(value)
+		if err != nil || n <= 0 {
+			return fmt.Errorf("max_file_size must be a positive number of bytes, got %q", value)
+		}
+		c.MaxFileSize = n
+	case KeyEmail:
+		c.Email = value
+	case KeyFullScan, KeySkipSubmodules, KeyOffline, KeyCheckTodoTickets, KeyShowClean, KeyJSON:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("%s must be a boolean, got %q", key, value)
+		}
+		switch key {
+		case KeyFullScan:
+			c.FullScan = b
+		case KeySkipSubmodules:
+			c.SkipSubmodules = b
+		case KeyOffline:
+			c.Offline = b
+		case KeyCheckTodoTickets:
+			c.CheckTodoTickets = b
+		case KeyShowClean:
+			c.ShowClean = b
+		case KeyJSON:
+			c.JSON = b
+		}
+	case KeyVerbose:
+		v, err := ParseVerbosity(value)
+		if err != nil {
+			return err
+		}
+		c.Verbose = v
+	case KeyOnly:
+		c.Only = splitList(value)
+	case KeyIgnore:
+		c.Ignore = splitList(value)
+	case KeyInclude:
+		c.Include = splitList(value)
+	case KeyInternalPackages:
+		c.InternalPackages = splitList(value)
+	case KeyRules:
+		c.Rules.Disabled = splitList(value)
+	case KeySeverities, KeyPlugins:
+		return fmt.Errorf("%s can only be set in the config file", key)
+	default:
+		return fmt.Errorf("unknown setting %q", key)
+	}
+	c.Sources[key] = source
+	return nil
+}
+
+// DisableRules adds issue types or rule IDs to the globally disabled rules,
+// keeping those already disabled, and records the source
+func (c *Config) DisableRules(rules []string, source Source) {
+	for _, rule := range rules {
+		if rule = strings.TrimSpace(rule); rule != "" && !slices.Contains(c.Rules.Disabled, rule) {
+			c.Rules.Disabled = append(c.Rules.Disabled, rule)
+		}
+	}
+	c.Sources[KeyRules] = source
+}
+
+// Value returns the display value for a setting
+func (c *Config) Value(key string) string {
+	switch key {
+	case KeyTargetBranch:
+		return c.TargetBranch
+	case KeyOutputDir:
+		return c.OutputDir
+	case KeyRemote:
+		return c.Remote
+	case KeyOffline:
+		return strconv.FormatBool(c.Offline)
+	case KeyFullScan:
+		return strconv.FormatBool(c.FullScan)
+	case KeySkipSubmodules:
+		return strconv.FormatBool(c.SkipSubmodules)
+	case KeyJSON:
+		return strconv.FormatBool(c.JSON)
+	case KeyFormat:
+		return c.Format
+	case KeyMinSeverity:
+		return c.MinSeverity
+	case KeyFailOn:
+		return c.FailOn
+	case KeyMaxLineLength:
+		return strconv.Itoa(c.MaxLineLength)
+	case KeyMaxFileSize:
+		return strconv.Itoa(c.MaxFileSize)
+	case KeyCheckTodoTickets:
+		return strconv.FormatBool(c.CheckTodoTickets)
+	case KeyShowClean:
+		return strconv.FormatBool(c.ShowClean)
+	case KeyEmail:
+		return c.Email
+	case KeyVerbose:
+		return strconv.Itoa(int(c.Verbose))
+	case KeyOnly:
+		return strings.Join(c.Only, ", ")
+	case KeyIgnore:
+		return strings.Join(c.Ignore, ", ")
+	case KeyInclude:
+		return strings.Join(c.Include, ", ")
+	case KeyInternalPackages:
+		return strings.Join(c.InternalPackages, ", ")
+	case KeyRules:
+		parts := []string{}
+		if len(c.Rules.Disabled) > 0 {
+			parts = append(parts, strings.Join(c.Rules.Disabled, ", "))
+		}
+		for _, language := range slices.Sorted(maps.Keys(c.Rules.Languages)) {
+			parts = append(parts, fmt.Sprintf("%s: %s", language, strings.Join(c.Rules.Languages[language].Disabled, ", ")))
+		}
+		return strings.Join(parts, "; ")
+	case KeySeverities:
+		overrides := make([]string, 0, len(c.SeverityOverrides))
+		for _, rule := range slices.Sorted(maps.Keys(c.SeverityOverrides)) {
+			overrides = append(overrides, rule+"="+c.SeverityOverrides[rule])
+		}
+		return strings.Join(overrides, ", ")
+	case KeyPlugins:
+		names := make([]string, 0, len(c.Plugins))
+		for _, plugin := range c.Plugins {
+			names = append(names, plugin.Name)
+		}
+		return strings.Join(names, ", ")
+	}
+	return ""
+}
+
+// EnvVar returns the environment variable that overrides a setting, if any
+func EnvVar(key string) string {
+	return envVars[key]
+}
+
+func splitList(value string) []string {
+	items := []string{}
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
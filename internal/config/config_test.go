@@ -0,0 +1,297 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+)
+
+func writeConfigFile(t *testing.T, dir, content string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, ".autoreview.yml"), []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write config file: %v", err)
+	}
+}
+
+func TestLoad_NoFileUsesDefaults(t *testing.T) {
+	cfg, err := Load(t.TempDir())
+	if err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+
+	if cfg.Path != "" {
+		t.Errorf("Expected no config path, got %q", cfg.Path)
+	}
+	if cfg.OutputDir != "review_reports" {
+		t.Errorf("Expected default output dir, got %q", cfg.OutputDir)
+	}
+	if len(cfg.Rules.Disabled) != 0 || len(cfg.SeverityOverrides) != 0 {
+		t.Errorf("Expected no disabled rules or severity overrides, got %v and %v", cfg.Rules.Disabled, cfg.SeverityOverrides)
+	}
+	for _, key := range Keys() {
+		if cfg.Sources[key] != SourceDefault {
+			t.Errorf("Expected %s to come from defaults, got %s", key, cfg.Sources[key])
+		}
+	}
+}
+
+func TestLoad_FileValuesAndSources(t *testing.T) {
+	dir := t.TempDir()
+	writeConfigFile(t, dir, `
+target_branch: develop
+full_scan: true
+ignore:
+  - dist/
+rules:
+  disabled: [performance]
+`)
+
+	cfg, err := Load(dir)
+	if err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+
+	if cfg.TargetBranch != "develop" || cfg.Sources[KeyTargetBranch] != SourceFile {
+		t.Errorf("Expected target_branch develop from file, got %q (%s)", cfg.TargetBranch, cfg.Sources[KeyTargetBranch])
+	}
+	if !cfg.FullScan {
+		t.Error("Expected full_scan from file")
+	}
+	if len(cfg.Ignore) != 1 || cfg.Ignore[0] != "dist/" {
+		t.Errorf("Expected ignore [dist/], got %v", cfg.Ignore)
+	}
+	if len(cfg.Rules.Disabled) != 1 || cfg.Rules.Disabled[0] != "performance" {
+		t.Errorf("Expected disabled rules [performance], got %v", cfg.Rules.Disabled)
+	}
+	if cfg.Sources[KeyOutputDir] != SourceDefault {
+		t.Errorf("Expected output_dir to stay default, got %s", cfg.Sources[KeyOutputDir])
+	}
+}
+
+func TestLoad_EnvOverridesFile(t *testing.T) {
+	dir := t.TempDir()
+	writeConfigFile(t, dir, "target_branch: develop\n")
+	t.Setenv("AUTOREVIEW_TARGET_BRANCH", "release")
+
+	cfg, err := Load(dir)
+	if err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+
+	if cfg.TargetBranch != "release" || cfg.Sources[KeyTargetBranch] != SourceEnv {
+		t.Errorf("Expected target_branch release from env, got %q (%s)", cfg.TargetBranch, cfg.Sources[KeyTargetBranch])
+	}
+}
+
+func TestSet_FlagOverridesEnv(t *testing.T) {
+	t.Setenv("AUTOREVIEW_FULL_SCAN", "true")
+
+	cfg, err := Load(t.TempDir())
+	if err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+	if err := cfg.Set(KeyFullScan, "false", SourceFlag); err != nil {
+		t.Fatalf("Set returned error: %v", err)
+	}
+
+	if cfg.FullScan || cfg.Sources[KeyFullScan] != SourceFlag {
+		t.Errorf("Expected full_scan false from flag, got %v (%s)", cfg.FullScan, cfg.Sources[KeyFullScan])
+	}
+}
+
+func TestLoad_InvalidEnvValue(t *testing.T) {
+	t.Setenv("AUTOREVIEW_FULL_SCAN", "sometimes")
+
+	if _, err := Load(t.TempDir()); err == nil {
+		t.Error("Expected error for non-boolean AUTOREVIEW_FULL_SCAN")
+	}
+}
+
+func TestLoad_VerbosityLevels(t *testing.T) {
+	dir := t.TempDir()
+	writeConfigFile(t, dir, "verbose: true\n")
+
+	cfg, err := Load(dir)
+	if err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+	if cfg.Verbose != 2 {
+		t.Errorf("Expected verbose: true to mean level 2, got %d", cfg.Verbose)
+	}
+
+	if err := cfg.Set(KeyVerbose, "3", SourceFlag); err != nil || cfg.Verbose != 3 {
+		t.Errorf("Expected level 3 from flag, got %d (%v)", cfg.Verbose, err)
+	}
+	if err := cfg.Set(KeyVerbose, "4", SourceFlag); err == nil {
+		t.Error("Expected error for a level above the maximum")
+	}
+}
+
+func TestLoad_MaxLineLength(t *testing.T) {
+	dir := t.TempDir()
+	writeConfigFile(t, dir, `
+max_line_length: 100
+rules:
+  python:
+    max_line_length: 79
+`)
+
+	cfg, err := Load(dir)
+	if err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+	if cfg.MaxLineLength != 100 || cfg.Sources[KeyMaxLineLength] != SourceFile {
+		t.Errorf("Expected max_line_length 100 from file, got %d (%s)", cfg.MaxLineLength, cfg.Sources[KeyMaxLineLength])
+	}
+	if limits := cfg.Rules.LanguageMaxLineLength(); len(limits) != 1 || limits["python"] != 79 {
+		t.Errorf("Expected a python limit of 79, got %v", limits)
+	}
+	if err := cfg.Set(KeyMaxLineLength, "0", SourceFlag); err == nil {
+		t.Error("Expected error for a zero line length")
+	}
+}
+
+func TestSet_MaxFileSize(t *testing.T) {
+	cfg := Default()
+	if cfg.MaxFileSize != 1048576 {
+		t.Errorf("Expected a default max_file_size of 1 MiB, got %d", cfg.MaxFileSize)
+	}
+	if err := cfg.Set(KeyMaxFileSize, "4096", SourceFlag); err != nil {
+		t.Fatalf("Set returned error: %v", err)
+	}
+	if cfg.MaxFileSize != 4096 || cfg.Value(KeyMaxFileSize) != "4096" {
+		t.Errorf("Expected max_file_size 4096, got %d", cfg.MaxFileSize)
+	}
+	if err := cfg.Set(KeyMaxFileSize, "-1", SourceFlag); err == nil {
+		t.Error("Expected error for a negative file size")
+	}
+}
+
+func TestLoad_Plugins(t *testing.T) {
+	dir := t.TempDir()
+	writeConfigFile(t, dir, `
+plugins:
+  - name: acme
+    command: ./tools/acme-check
+    args: [--strict]
+    extensions: [.py, .rb]
+    timeout: 5s
+`)
+
+	cfg, err := Load(dir)
+	if err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+
+	if len(cfg.Plugins) != 1 {
+		t.Fatalf("Expected 1 plugin, got %d", len(cfg.Plugins))
+	}
+	plugin := cfg.Plugins[0]
+	if plugin.Name != "acme" || plugin.Command != "./tools/acme-check" || plugin.Timeout != 5*time.Second {
+		t.Errorf("Unexpected plugin config: %+v", plugin)
+	}
+	if cfg.Sources[KeyPlugins] != SourceFile {
+		t.Errorf("Expected plugins to come from file, got %s", cfg.Sources[KeyPlugins])
+	}
+	if err := cfg.Set(KeyPlugins, "x", SourceEnv); err == nil {
+		t.Error("Expected plugins to be settable only from the config file")
+	}
+}
+
+func TestLoad_LanguageScopedRules(t *testing.T) {
+	dir := t.TempDir()
+	writeConfigFile(t, dir, `
+rules:
+  disabled: [performance]
+  python:
+    disabled: [print-statement]
+  typescript:
+    disabled: [line-length]
+`)
+
+	cfg, err := Load(dir)
+	if err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+
+	if len(cfg.Rules.Disabled) != 1 || cfg.Rules.Disabled[0] != "performance" {
+		t.Errorf("Expected global disabled rules to be kept, got %v", cfg.Rules.Disabled)
+	}
+	disabled := cfg.Rules.LanguageDisabled()
+	if len(disabled["python"]) != 1 || disabled["python"][0] != "print-statement" {
+		t.Errorf("Unexpected python rules: %v", disabled["python"])
+	}
+	if len(disabled["typescript"]) != 1 || disabled["typescript"][0] != "line-length" {
+		t.Errorf("Unexpected typescript rules: %v", disabled["typescript"])
+	}
+}
+
+func TestLoad_SeverityOverridesAndDisabledRules(t *testing.T) {
+	dir := t.TempDir()
+	writeConfigFile(t, dir, `
+disabled_rules: [performance, todo-comment]
+rules:
+  disabled: [performance]
+severity_overrides:
+  print-statement: high
+  bare-except: low
+`)
+
+	cfg, err := Load(dir)
+	if err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+
+	if !slices.Equal(cfg.Rules.Disabled, []string{"performance", "todo-comment"}) || cfg.Sources[KeyRules] != SourceFile {
+		t.Errorf("Expected disabled_rules merged into rules.disabled from file, got %v (%s)", cfg.Rules.Disabled, cfg.Sources[KeyRules])
+	}
+	if cfg.SeverityOverrides["print-statement"] != "high" || cfg.Sources[KeySeverities] != SourceFile {
+		t.Errorf("Expected severity overrides from file, got %v (%s)", cfg.SeverityOverrides, cfg.Sources[KeySeverities])
+	}
+	if got := cfg.Value(KeySeverities); got != "bare-except=low, print-statement=high" {
+		t.Errorf("Value(severity_overrides) = %q", got)
+	}
+	if err := cfg.Set(KeySeverities, "eval=low", SourceFlag); err == nil {
+		t.Error("Expected severity_overrides to be settable only in the config file")
+	}
+}
+
+func TestSet_FlagOverridesFile(t *testing.T) {
+	dir := t.TempDir()
+	writeConfigFile(t, dir, "target_branch: develop\nfail_on: high\nmax_line_length: 100\n")
+
+	cfg, err := Load(dir)
+	if err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+	if err := cfg.Set(KeyFailOn, "critical", SourceFlag); err != nil {
+		t.Fatalf("Set returned error: %v", err)
+	}
+
+	if cfg.FailOn != "critical" || cfg.Sources[KeyFailOn] != SourceFlag {
+		t.Errorf("Expected fail_on critical from flag, got %q (%s)", cfg.FailOn, cfg.Sources[KeyFailOn])
+	}
+	if cfg.MaxLineLength != 100 || cfg.Sources[KeyMaxLineLength] != SourceFile {
+		t.Errorf("Expected max_line_length 100 from file, got %d (%s)", cfg.MaxLineLength, cfg.Sources[KeyMaxLineLength])
+	}
+	if cfg.TargetBranch != "develop" || cfg.Sources[KeyTargetBranch] != SourceFile {
+		t.Errorf("Expected target_branch develop from file, got %q (%s)", cfg.TargetBranch, cfg.Sources[KeyTargetBranch])
+	}
+}
+
+func TestDisableRules_AddsToFile(t *testing.T) {
+	dir := t.TempDir()
+	writeConfigFile(t, dir, "rules:\n  disabled: [performance]\n")
+
+	cfg, err := Load(dir)
+	if err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+	cfg.DisableRules([]string{"todo-comment", "performance"}, SourceFlag)
+
+	if !slices.Equal(cfg.Rules.Disabled, []string{"performance", "todo-comment"}) || cfg.Sources[KeyRules] != SourceFlag {
+		t.Errorf("Expected flag rules added to the file's, got %v (%s)", cfg.Rules.Disabled, cfg.Sources[KeyRules])
+	}
+}
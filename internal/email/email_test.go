@@ -1,8 +1,15 @@
 package email
 
 import (
+	"crypto/tls"
+	"net"
+	"net/http/httptest"
+	"net/smtp"
+	"net/textproto"
 	"os"
+	"slices"
 	"strings"
+	"sync"
 	"testing"
 
 	"github.com/BrandonThomas84/code-review-automation/internal/review"
@@ -154,6 +161,63 @@ func TestFormatter_FormatHTML_WithHighSeverityIssues(t *testing.T) {
 	}
 }
 
+func TestHTMLRenderer(t *testing.T) {
+	report := review.NewReport()
+	report.AddIssue(review.Issue{
+		Type:     "security",
+		Severity: "high",
+		Message:  "SQL injection vulnerability",
+		File:     "database.py",
+		Line:     42,
+	})
+
+	var buf strings.Builder
+	if err := report.Render(&buf, "html"); err != nil {
+		t.Fatalf("Render(html) returned error: %v", err)
+	}
+	if !strings.Contains(buf.String(), "High Severity (1)") {
+		t.Errorf("Expected the html format to render the email layout, got %q", buf.String())
+	}
+}
+
+func TestFormatter_FormatHTML_EffortRollup(t *testing.T) {
+	f := NewFormatter()
+	report := review.NewReport()
+	report.AddIssue(review.Issue{Type: "quality", Severity: "low", Message: "console.log found", RuleID: "console-log"})
+	report.AddIssue(review.Issue{Type: "security", Severity: "high", Message: "SQL injection", RuleID: "sql-injection"})
+
+	html := f.FormatHTML(report)
+
+	if !strings.Contains(html, "Estimated remediation effort: <strong>35m</strong> (1 trivial, 1 moderate, 0 significant)") {
+		t.Error("Expected the effort rollup in the summary")
+	}
+	if strings.Contains(f.FormatHTML(review.NewReport()), "Estimated remediation effort") {
+		t.Error("Did not expect an effort rollup without issues")
+	}
+}
+
+func TestFormatter_FormatHTML_TopFiles(t *testing.T) {
+	f := NewFormatter()
+	report := review.NewReport()
+	report.AddIssue(review.Issue{Type: "quality", Severity: "low", Message: "print", File: "util.py"})
+	report.AddIssue(review.Issue{Type: "security", Severity: "high", Message: "SQL injection", File: "<api>.py"})
+	report.AddIssue(review.Issue{Type: "quality", Severity: "low", Message: "print", File: "<api>.py"})
+
+	html := f.FormatHTML(report)
+
+	if !strings.Contains(html, "Top Offending Files") {
+		t.Fatal("Expected a top offending files section in the summary")
+	}
+	api := strings.Index(html, "&lt;api&gt;.py</code></td><td style=\"text-align: right; color: #666;\">2</td>")
+	util := strings.Index(html, "util.py</code></td><td style=\"text-align: right; color: #666;\">1</td>")
+	if api < 0 || util < 0 || api > util {
+		t.Errorf("Expected escaped files listed worst first, got api at %d and util at %d", api, util)
+	}
+	if strings.Contains(f.FormatHTML(review.NewReport()), "Top Offending Files") {
+		t.Error("Did not expect top files without issues")
+	}
+}
+
 func TestFormatter_FormatHTML_GroupsIssuesBySeverity(t *testing.T) {
 	f := NewFormatter()
 	report := review.NewReport()
@@ -219,6 +283,24 @@ func TestFormatter_FormatHTML_WithContext(t *testing.T) {
 	}
 }
 
+func TestFormatter_FormatHTML_WithCommit(t *testing.T) {
+	report := review.NewReport()
+	report.Commit = &review.Commit{
+		SHA:         "0123456789abcdef0123456789abcdef01234567",
+		AuthorName:  "Ada",
+		AuthorEmail: "ada@example.com",
+		Subject:     "Fix <script> handling",
+	}
+	html := NewFormatter().FormatHTML(report)
+
+	if !strings.Contains(html, "<code>0123456</code> by Ada &lt;ada@example.com&gt;") {
+		t.Error("Expected the short commit SHA and author in the footer")
+	}
+	if !strings.Contains(html, "Fix &lt;script&gt; handling") {
+		t.Error("Expected the commit subject to be escaped")
+	}
+}
+
 // ============== Sender Tests ==============
 
 func TestNewSender(t *testing.T) {
@@ -244,6 +326,42 @@ func TestNewSenderFromEnv(t *testing.T) {
 	}
 }
 
+func TestNewSenderFromEnv_ReadsConfig(t *testing.T) {
+	t.Setenv("AUTOREVIEW_SMTP_HOST", "autoreview.smtp.com")
+	t.Setenv("SMTP_HOST", "legacy.smtp.com")
+	t.Setenv("AUTOREVIEW_SMTP_USER", "")
+	t.Setenv("SMTP_USER", "legacy@test.com")
+	t.Setenv("AUTOREVIEW_SMTP_PORT", "")
+	t.Setenv("SMTP_PORT", "2525")
+	t.Setenv("AUTOREVIEW_FROM_NAME", "")
+
+	config := NewSenderFromEnv().config
+
+	if config.SMTPHost != "autoreview.smtp.com" {
+		t.Errorf("Expected the AUTOREVIEW_ host to win, got '%s'", config.SMTPHost)
+	}
+	if config.SMTPUser != "legacy@test.com" {
+		t.Errorf("Expected the legacy user as fallback, got '%s'", config.SMTPUser)
+	}
+	if config.SMTPPort != 2525 {
+		t.Errorf("Expected port 2525, got %d", config.SMTPPort)
+	}
+	if config.FromName != "AutoReview Bot" {
+		t.Errorf("Expected the default sender name, got '%s'", config.FromName)
+	}
+}
+
+func TestNewSenderFromEnv_DefaultPort(t *testing.T) {
+	for _, port := range []string{"", "not-a-port"} {
+		t.Setenv("AUTOREVIEW_SMTP_PORT", port)
+		t.Setenv("SMTP_PORT", "")
+
+		if got := NewSenderFromEnv().config.SMTPPort; got != 587 {
+			t.Errorf("SMTP_PORT %q: expected default port 587, got %d", port, got)
+		}
+	}
+}
+
 func TestGetEnvWithFallback_Primary(t *testing.T) {
 	os.Setenv("TEST_PRIMARY", "primary_value")
 	os.Setenv("TEST_FALLBACK", "fallback_value")
@@ -295,6 +413,40 @@ func TestSender_SendReport_MissingConfig(t *testing.T) {
 	}
 }
 
+func TestSender_SendReportWithContext_UsesFormatter(t *testing.T) {
+	sender := NewSender(Config{SMTPHost: "smtp.example.com", SMTPPort: 2525, SMTPUser: "bot@example.com", FromEmail: "bot@example.com", FromName: "Bot"})
+	var gotAddr string
+	var gotTo []string
+	var gotMsg string
+	sender.sendMail = func(addr string, a smtp.Auth, from string, to []string, msg []byte) error {
+		gotAddr, gotTo, gotMsg = addr, to, string(msg)
+		return nil
+	}
+
+	report := review.NewReport()
+	report.AddIssue(review.Issue{Type: "security", Severity: "high", Message: "SQL injection", File: "app.py", Line: 3})
+
+	if err := sender.SendReportWithContext(report, "team@example.com", "acme/widgets", "feature/login", 12, "Add login"); err != nil {
+		t.Fatalf("SendReportWithContext returned error: %v", err)
+	}
+
+	if gotAddr != "smtp.example.com:2525" || !slices.Equal(gotTo, []string{"team@example.com"}) {
+		t.Errorf("Unexpected delivery to %s for %v", gotAddr, gotTo)
+	}
+	formatter := NewFormatter().WithRepo("acme/widgets").WithBranch("feature/login").WithPR(12, "Add login")
+	for _, want := range []string{
+		"Subject: " + formatter.FormatSubject(report) + "\r\n",
+		"Content-Type: text/html",
+		`<h1 style="color: #ffffff;`,
+		"Code Review: acme/widgets",
+		"feature/login",
+	} {
+		if !strings.Contains(gotMsg, want) {
+			t.Errorf("Expected the message to contain %q", want)
+		}
+	}
+}
+
 func TestSender_EnvVariables_AutoreviewPrefix(t *testing.T) {
 	// Set AUTOREVIEW_ prefixed variables
 	os.Setenv("AUTOREVIEW_SMTP_HOST", "autoreview.smtp.com")
@@ -314,6 +466,177 @@ func TestSender_EnvVariables_AutoreviewPrefix(t *testing.T) {
 	}
 }
 
+// ============== SMTP TLS Tests ==============
+
+// fakeSMTPServer is a minimal SMTP server that records whether the message
+// arrived over TLS
+type fakeSMTPServer struct {
+	addr string
+	port int
+
+	mu      sync.Mutex
+	overTLS bool
+	data    string
+}
+
+// startFakeSMTP serves one connection per accept, with implicit TLS or offering STARTTLS
+func startFakeSMTP(t *testing.T, implicitTLS, offerStartTLS bool) *fakeSMTPServer {
+	t.Helper()
+	certServer := httptest.NewUnstartedServer(nil)
+	certServer.StartTLS()
+	tlsConfig := &tls.Config{Certificates: certServer.TLS.Certificates}
+	certServer.Close()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	server := &fakeSMTPServer{addr: listener.Addr().String(), port: listener.Addr().(*net.TCPAddr).Port}
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			if implicitTLS {
+				conn = tls.Server(conn, tlsConfig)
+			}
+			go server.serve(conn, tlsConfig, implicitTLS, offerStartTLS)
+		}
+	}()
+	return server
+}
+
+func (s *fakeSMTPServer) serve(conn net.Conn, tlsConfig *tls.Config, secure, offerStartTLS bool) {
+	defer conn.Close()
+	text := textproto.NewConn(conn)
+	text.PrintfLine("220 localhost ESMTP")
+
+	for {
+		line, err := text.ReadLine()
+		if err != nil {
+			return
+		}
+		verb, _, _ := strings.Cut(line, " ")
+		switch strings.ToUpper(verb) {
+		case "EHLO", "HELO":
+			text.PrintfLine("250-localhost")
+			if offerStartTLS && !secure {
+				text.PrintfLine("250-STARTTLS")
+			}
+			text.PrintfLine("250 AUTH PLAIN")
+		case "STARTTLS":
+			text.PrintfLine("220 Ready to start TLS")
+			tlsConn := tls.Server(conn, tlsConfig)
+			if err := tlsConn.Handshake(); err != nil {
+				return
+			}
+			conn, text, secure = tlsConn, textproto.NewConn(tlsConn), true
+		case "AUTH":
+			text.PrintfLine("235 Authenticated")
+		case "MAIL", "RCPT":
+			text.PrintfLine("250 OK")
+		case "DATA":
+			text.PrintfLine("354 Go ahead")
+			data, err := text.ReadDotBytes()
+			if err != nil {
+				return
+			}
+			s.mu.Lock()
+			s.overTLS, s.data = secure, string(data)
+			s.mu.Unlock()
+			text.PrintfLine("250 Queued")
+		case "QUIT":
+			text.PrintfLine("221 Bye")
+			return
+		default:
+			text.PrintfLine("502 Not implemented")
+		}
+	}
+}
+
+func (s *fakeSMTPServer) received() (bool, string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.overTLS, s.data
+}
+
+func testSMTPConfig(port int) Config {
+	return Config{
+		SMTPHost:           "127.0.0.1",
+		SMTPPort:           port,
+		SMTPUser:           "bot@example.com",
+		SMTPPassword:       "secret",
+		FromEmail:          "bot@example.com",
+		FromName:           "Bot",
+		InsecureSkipVerify: true,
+	}
+}
+
+func TestSender_ImplicitTLS(t *testing.T) {
+	server := startFakeSMTP(t, true, false)
+	config := testSMTPConfig(server.port)
+	config.UseTLS = true
+
+	if err := NewSender(config).SendReport(review.NewReport(), "team@example.com"); err != nil {
+		t.Fatalf("SendReport returned error: %v", err)
+	}
+	overTLS, data := server.received()
+	if !overTLS || !strings.Contains(data, "Subject: ") {
+		t.Errorf("Expected the message to arrive over TLS, got TLS=%v data=%q", overTLS, data)
+	}
+}
+
+func TestSender_StartTLS(t *testing.T) {
+	server := startFakeSMTP(t, false, true)
+
+	if err := NewSender(testSMTPConfig(server.port)).SendReport(review.NewReport(), "team@example.com"); err != nil {
+		t.Fatalf("SendReport returned error: %v", err)
+	}
+	overTLS, data := server.received()
+	if !overTLS || !strings.Contains(data, "Subject: ") {
+		t.Errorf("Expected the message to arrive after STARTTLS, got TLS=%v data=%q", overTLS, data)
+	}
+}
+
+func TestSender_VerifiesCertificates(t *testing.T) {
+	server := startFakeSMTP(t, true, false)
+	config := testSMTPConfig(server.port)
+	config.UseTLS = true
+	config.InsecureSkipVerify = false
+
+	if err := NewSender(config).SendReport(review.NewReport(), "team@example.com"); err == nil {
+		t.Error("Expected an untrusted certificate to be rejected")
+	}
+	if _, data := server.received(); data != "" {
+		t.Error("Did not expect the message to be sent")
+	}
+}
+
+func TestConfig_TLSDefaults(t *testing.T) {
+	tests := []struct {
+		config           Config
+		implicitTLS      bool
+		requiresStartTLS bool
+	}{
+		{Config{SMTPPort: 587}, false, true},
+		{Config{SMTPPort: 465}, true, false},
+		{Config{SMTPPort: 2525, UseTLS: true}, true, false},
+		{Config{SMTPPort: 25}, false, false},
+	}
+
+	for _, tt := range tests {
+		if got := tt.config.implicitTLS(); got != tt.implicitTLS {
+			t.Errorf("%+v: implicitTLS() = %v, want %v", tt.config, got, tt.implicitTLS)
+		}
+		if got := tt.config.requiresStartTLS(); got != tt.requiresStartTLS {
+			t.Errorf("%+v: requiresStartTLS() = %v, want %v", tt.config, got, tt.requiresStartTLS)
+		}
+	}
+}
+
 // ============== Filter Tests ==============
 
 func TestFilterBySeverity(t *testing.T) {
@@ -340,3 +663,24 @@ func TestFilterBySeverity(t *testing.T) {
 		t.Errorf("Expected 2 low severity issues, got %d", len(low))
 	}
 }
+
+func TestFormatter_CriticalSeverity(t *testing.T) {
+	f := NewFormatter()
+	report := review.NewReport()
+	report.AddIssue(review.Issue{Type: "security", Severity: "critical", Message: "Private key detected in code"})
+	report.AddIssue(review.Issue{Type: "quality", Severity: "info", Message: "TODO/FIXME comment found"})
+
+	if subject := f.FormatSubject(report); !strings.Contains(subject, "🛑") {
+		t.Errorf("Expected critical emoji in subject, got %q", subject)
+	}
+
+	html := f.FormatHTML(report)
+	criticalIdx := strings.Index(html, "Critical Severity (1)")
+	infoIdx := strings.Index(html, "Info (1)")
+	if criticalIdx == -1 || infoIdx == -1 {
+		t.Fatal("Expected critical and info severity groups")
+	}
+	if criticalIdx > infoIdx {
+		t.Error("Critical should appear before info")
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"fmt"
 	"html"
+	"io"
 	"strings"
 	"time"
 
@@ -18,6 +19,15 @@ type Formatter struct {
 	PRTitle    string
 }
 
+// The email's HTML doubles as the html output format. It is registered from here
+// because the review package cannot import this one.
+func init() {
+	review.RegisterRenderer("html", func(w io.Writer, r *review.Report) error {
+		_, err := io.WriteString(w, NewFormatter().FormatHTML(r))
+		return err
+	})
+}
+
 // NewFormatter creates a new email formatter
 func NewFormatter() *Formatter {
 	return &Formatter{}
@@ -67,7 +77,7 @@ func (f *Formatter) FormatHTML(report *review.Report) string {
 	}
 
 	// Footer
-	buf.WriteString(f.footer())
+	buf.WriteString(f.footer(report))
 
 	buf.WriteString(`</table></body></html>`)
 
@@ -89,7 +99,11 @@ func (f *Formatter) headerBanner(report *review.Report) string {
 	emoji := "✅"
 	status := "All Clear"
 
-	if report.Summary.HighSeverity > 0 {
+	if report.Summary.CriticalSeverity > 0 {
+		bgColor = "#b71c1c" // dark red for critical severity
+		emoji = "🛑"
+		status = "Critical Issues - Fix Immediately"
+	} else if report.Summary.HighSeverity > 0 {
 		bgColor = "#f44336" // red for high severity
 		emoji = "🚨"
 		status = "Action Required"
@@ -97,8 +111,8 @@ func (f *Formatter) headerBanner(report *review.Report) string {
 		bgColor = "#ff9800" // orange for medium
 		emoji = "⚠️"
 		status = "Review Recommended"
-	} else if report.Summary.LowSeverity > 0 {
-		bgColor = "#2196f3" // blue for low
+	} else if report.Summary.LowSeverity > 0 || report.Summary.InfoSeverity > 0 {
+		bgColor = "#2196f3" // blue for low and info
 		emoji = "ℹ️"
 		status = "Minor Issues"
 	}
@@ -141,6 +155,10 @@ func (f *Formatter) summarySection(report *review.Report) string {
                     <div style="font-size: 28px; font-weight: bold; color: #333;">%d</div>
                     <div style="font-size: 12px; color: #666;">Files Changed</div>
                 </td>
+                <td style="text-align: center; border-right: 1px solid #ddd;">
+                    <div style="font-size: 28px; font-weight: bold; color: #b71c1c;">%d</div>
+                    <div style="font-size: 12px; color: #666;">Critical</div>
+                </td>
                 <td style="text-align: center; border-right: 1px solid #ddd;">
                     <div style="font-size: 28px; font-weight: bold; color: #f44336;">%d</div>
                     <div style="font-size: 12px; color: #666;">High</div>
@@ -149,14 +167,50 @@ func (f *Formatter) summarySection(report *review.Report) string {
                     <div style="font-size: 28px; font-weight: bold; color: #ff9800;">%d</div>
                     <div style="font-size: 12px; color: #666;">Medium</div>
                 </td>
-                <td style="text-align: center;">
+                <td style="text-align: center; border-right: 1px solid #ddd;">
                     <div style="font-size: 28px; font-weight: bold; color: #4caf50;">%d</div>
                     <div style="font-size: 12px; color: #666;">Low</div>
                 </td>
+                <td style="text-align: center;">
+                    <div style="font-size: 28px; font-weight: bold; color: #2196f3;">%d</div>
+                    <div style="font-size: 12px; color: #666;">Info</div>
+                </td>
             </tr>
         </table>
+        %s
+        %s
     </td>
-</tr>`, context, report.Summary.TotalFiles, report.Summary.HighSeverity, report.Summary.MediumSeverity, report.Summary.LowSeverity)
+</tr>`, context, report.Summary.TotalFiles, report.Summary.CriticalSeverity, report.Summary.HighSeverity,
+		report.Summary.MediumSeverity, report.Summary.LowSeverity, report.Summary.InfoSeverity, f.effortRollup(report), f.topFiles(report))
+}
+
+// topFiles lists the five files with the most issues below the summary
+func (f *Formatter) topFiles(report *review.Report) string {
+	top := report.Summary.TopFiles(5)
+	if len(top) == 0 {
+		return ""
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(`<h3 style="color: #333; margin: 15px 0 5px 0; font-size: 14px;">🔥 Top Offending Files</h3>
+        <table width="100%" cellpadding="4" cellspacing="0" style="font-size: 13px; color: #333;">`)
+	for _, count := range top {
+		buf.WriteString(fmt.Sprintf(`
+            <tr><td><code style="background-color: #f5f5f5; padding: 2px 6px; border-radius: 3px;">%s</code></td><td style="text-align: right; color: #666;">%d</td></tr>`,
+			html.EscapeString(count.File), count.Issues))
+	}
+	buf.WriteString(`</table>`)
+	return buf.String()
+}
+
+// effortRollup summarizes the estimated remediation effort below the severity counts
+func (f *Formatter) effortRollup(report *review.Report) string {
+	if report.Summary.TotalIssues == 0 {
+		return ""
+	}
+	effort := report.Summary.Effort
+	return fmt.Sprintf(`<p style="margin: 10px 0 0 0; color: #666; font-size: 13px;">⏱️ Estimated remediation effort: <strong>%s</strong> (%d trivial, %d moderate, %d significant)</p>`,
+		html.EscapeString(effort.Duration()), effort.Trivial, effort.Moderate, effort.Significant)
 }
 
 func (f *Formatter) issuesSection(report *review.Report) string {
@@ -167,26 +221,28 @@ func (f *Formatter) issuesSection(report *review.Report) string {
     <td style="padding: 0 20px 20px 20px; font-family: Arial, sans-serif;">
         <h2 style="color: #333; margin: 0 0 15px 0; font-size: 18px;">🔍 Issues Found</h2>`)
 
-	// Group issues by severity
-	highIssues := filterBySeverity(report.Issues, "high")
-	mediumIssues := filterBySeverity(report.Issues, "medium")
-	lowIssues := filterBySeverity(report.Issues, "low")
-
-	// Render high severity first
-	if len(highIssues) > 0 {
-		buf.WriteString(f.issueGroup("High Severity", "#f44336", highIssues))
-	}
-	if len(mediumIssues) > 0 {
-		buf.WriteString(f.issueGroup("Medium Severity", "#ff9800", mediumIssues))
-	}
-	if len(lowIssues) > 0 {
-		buf.WriteString(f.issueGroup("Low Severity", "#4caf50", lowIssues))
+	// Group issues by severity, most severe first
+	for _, group := range severityGroups {
+		if issues := filterBySeverity(report.Issues, group.severity); len(issues) > 0 {
+			buf.WriteString(f.issueGroup(group.title, group.color, issues))
+		}
 	}
 
 	buf.WriteString(`</td></tr>`)
 	return buf.String()
 }
 
+// severityGroups are the issue groups rendered in the email, most severe first
+var severityGroups = []struct {
+	severity, title, color string
+}{
+	{review.SeverityCritical, "Critical Severity", "#b71c1c"},
+	{review.SeverityHigh, "High Severity", "#f44336"},
+	{review.SeverityMedium, "Medium Severity", "#ff9800"},
+	{review.SeverityLow, "Low Severity", "#4caf50"},
+	{review.SeverityInfo, "Info", "#2196f3"},
+}
+
 func filterBySeverity(issues []review.Issue, severity string) []review.Issue {
 	var filtered []review.Issue
 	for _, issue := range issues {
@@ -252,23 +308,32 @@ func (f *Formatter) noIssuesSection() string {
 </tr>`
 }
 
-func (f *Formatter) footer() string {
+func (f *Formatter) footer(report *review.Report) string {
 	timestamp := time.Now().Format("January 2, 2006 at 3:04 PM")
+
+	var commit string
+	if c := report.Commit; c != nil {
+		commit = fmt.Sprintf("Commit <code>%s</code> by %s &lt;%s&gt;: %s<br>\n            ",
+			html.EscapeString(c.ShortSHA()), html.EscapeString(c.AuthorName), html.EscapeString(c.AuthorEmail), html.EscapeString(c.Subject))
+	}
+
 	return fmt.Sprintf(`
 <tr>
     <td style="padding: 20px; background-color: #f9f9f9; text-align: center; font-family: Arial, sans-serif;">
         <p style="color: #999; font-size: 12px; margin: 0;">
-            Generated on %s<br>
+            %sGenerated on %s<br>
             <a href="https://github.com/BrandonThomas84/code_review_automation" style="color: #2196f3;">Code Review Automation</a>
         </p>
     </td>
-</tr>`, timestamp)
+</tr>`, commit, timestamp)
 }
 
 // FormatSubject generates an appropriate email subject line
 func (f *Formatter) FormatSubject(report *review.Report) string {
 	var prefix string
-	if report.Summary.HighSeverity > 0 {
+	if report.Summary.CriticalSeverity > 0 {
+		prefix = "🛑 "
+	} else if report.Summary.HighSeverity > 0 {
 		prefix = "🚨 "
 	} else if report.Summary.MediumSeverity > 0 {
 		prefix = "⚠️ "
@@ -4,10 +4,20 @@ import (
 	"fmt"
 	"net/smtp"
 	"os"
+	"strconv"
 
 	"github.com/BrandonThomas84/code-review-automation/internal/review"
 )
 
+const (
+	defaultSMTPPort = 587
+	defaultFromName = "AutoReview Bot"
+	// submissionPort expects STARTTLS before authenticating
+	submissionPort = 587
+	// implicitTLSPort expects TLS from the first byte
+	implicitTLSPort = 465
+)
+
 type Config struct {
 	SMTPHost     string
 	SMTPPort     int
@@ -15,19 +25,74 @@ type Config struct {
 	SMTPPassword string
 	FromEmail    string
 	FromName     string
+	// UseTLS connects with implicit TLS, as port 465 requires. Otherwise the
+	// connection is upgraded with STARTTLS, which is mandatory on port 587.
+	UseTLS bool
+	// InsecureSkipVerify accepts any server certificate, e.g. a self-signed relay
+	InsecureSkipVerify bool
 }
 
 type Sender struct {
 	config Config
+	// sendMail delivers the message; it is sendSMTP outside tests
+	sendMail func(addr string, a smtp.Auth, from string, to []string, msg []byte) error
 }
 
 func NewSender(config Config) *Sender {
-	return &Sender{config: config}
+	s := &Sender{config: config}
+	s.sendMail = s.sendSMTP
+	return s
 }
 
-// NewSenderFromEnv creates a Sender with configuration from environment variables
+// NewSenderFromEnv creates a Sender with configuration from environment variables.
+// AUTOREVIEW_ prefixed variables (as stored in GitHub secrets) take precedence over
+// the legacy unprefixed names.
 func NewSenderFromEnv() *Sender {
-	return &Sender{config: Config{}}
+	return NewSender(configFromEnv())
+}
+
+// configFromEnv reads the SMTP configuration from the environment, defaulting the
+// port to 587 and the sender name to "AutoReview Bot"
+func configFromEnv() Config {
+	config := Config{
+		SMTPHost:     getEnvWithFallback("AUTOREVIEW_SMTP_HOST", "SMTP_HOST"),
+		SMTPPort:     defaultSMTPPort,
+		SMTPUser:     getEnvWithFallback("AUTOREVIEW_SMTP_USER", "SMTP_USER"),
+		SMTPPassword: getEnvWithFallback("AUTOREVIEW_SMTP_PASSWORD", "SMTP_PASSWORD"),
+		FromEmail:    getEnvWithFallback("AUTOREVIEW_FROM_EMAIL", "FROM_EMAIL"),
+		FromName:     getEnvWithFallback("AUTOREVIEW_FROM_NAME", ""),
+	}
+	if port, err := strconv.Atoi(getEnvWithFallback("AUTOREVIEW_SMTP_PORT", "SMTP_PORT")); err == nil && port > 0 {
+		config.SMTPPort = port
+	}
+	config.UseTLS, _ = strconv.ParseBool(getEnvWithFallback("AUTOREVIEW_SMTP_TLS", "SMTP_TLS"))
+	config.InsecureSkipVerify, _ = strconv.ParseBool(getEnvWithFallback("AUTOREVIEW_SMTP_INSECURE_SKIP_VERIFY", ""))
+	if config.FromName == "" {
+		config.FromName = defaultFromName
+	}
+	return config
+}
+
+// ConfigFromEnv returns the SMTP configuration NewSenderFromEnv uses
+func ConfigFromEnv() Config {
+	return configFromEnv()
+}
+
+// Missing lists the environment variables for the settings a Config needs to
+// send mail but lacks
+func (c Config) Missing() []string {
+	var missing []string
+	for _, setting := range []struct{ value, env string }{
+		{c.SMTPHost, "AUTOREVIEW_SMTP_HOST"},
+		{c.SMTPUser, "AUTOREVIEW_SMTP_USER"},
+		{c.SMTPPassword, "AUTOREVIEW_SMTP_PASSWORD"},
+		{c.FromEmail, "AUTOREVIEW_FROM_EMAIL"},
+	} {
+		if setting.value == "" {
+			missing = append(missing, setting.env)
+		}
+	}
+	return missing
 }
 
 // getEnvWithFallback tries the primary env var first, then falls back to the secondary
@@ -48,34 +113,34 @@ func (s *Sender) SendReport(report *review.Report, toEmail string) error {
 
 // SendReportWithContext sends a formatted email report with optional context
 func (s *Sender) SendReportWithContext(report *review.Report, toEmail, repoName, branchName string, prNumber int, prTitle string) error {
-	// Get config from environment if not provided (AUTOREVIEW_ prefixed for GitHub secrets)
+	// Fill in settings not provided from the environment (AUTOREVIEW_ prefixed for GitHub secrets)
+	env := configFromEnv()
 	if s.config.SMTPHost == "" {
-		s.config.SMTPHost = getEnvWithFallback("AUTOREVIEW_SMTP_HOST", "SMTP_HOST")
+		s.config.SMTPHost = env.SMTPHost
 	}
 	if s.config.SMTPPort == 0 {
-		s.config.SMTPPort = 587 // Default SMTP port
+		s.config.SMTPPort = env.SMTPPort
 	}
 	if s.config.SMTPUser == "" {
-		s.config.SMTPUser = getEnvWithFallback("AUTOREVIEW_SMTP_USER", "SMTP_USER")
+		s.config.SMTPUser = env.SMTPUser
 	}
 	if s.config.SMTPPassword == "" {
-		s.config.SMTPPassword = getEnvWithFallback("AUTOREVIEW_SMTP_PASSWORD", "SMTP_PASSWORD")
+		s.config.SMTPPassword = env.SMTPPassword
 	}
 	if s.config.FromEmail == "" {
-		s.config.FromEmail = getEnvWithFallback("AUTOREVIEW_FROM_EMAIL", "FROM_EMAIL")
+		s.config.FromEmail = env.FromEmail
 	}
 	if s.config.FromName == "" {
-		s.config.FromName = getEnvWithFallback("AUTOREVIEW_FROM_NAME", "")
-		if s.config.FromName == "" {
-			s.config.FromName = "AutoReview Bot"
-		}
+		s.config.FromName = env.FromName
 	}
+	s.config.UseTLS = s.config.UseTLS || env.UseTLS
+	s.config.InsecureSkipVerify = s.config.InsecureSkipVerify || env.InsecureSkipVerify
 
 	if s.config.SMTPHost == "" || s.config.SMTPUser == "" {
 		return fmt.Errorf("SMTP configuration not provided")
 	}
 
-	// Use the new formatter
+	// The formatter's header shows whichever of repo, branch and PR are given
 	formatter := NewFormatter().
 		WithRepo(repoName).
 		WithBranch(branchName).
@@ -91,5 +156,5 @@ func (s *Sender) SendReportWithContext(report *review.Report, toEmail, repoName,
 	msg := fmt.Sprintf("From: %s <%s>\r\nTo: %s\r\nSubject: %s\r\nMIME-Version: 1.0\r\nContent-Type: text/html; charset=\"UTF-8\"\r\n\r\n%s",
 		s.config.FromName, s.config.FromEmail, toEmail, subject, body)
 
-	return smtp.SendMail(addr, auth, s.config.FromEmail, []string{toEmail}, []byte(msg))
+	return s.sendMail(addr, auth, s.config.FromEmail, []string{toEmail}, []byte(msg))
 }
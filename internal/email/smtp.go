@@ -0,0 +1,100 @@
+package email
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/smtp"
+	"time"
+)
+
+// dialTimeout bounds connecting to the SMTP server
+const dialTimeout = 30 * time.Second
+
+// implicitTLS reports whether the connection starts with a TLS handshake
+func (c Config) implicitTLS() bool {
+	return c.UseTLS || c.SMTPPort == implicitTLSPort
+}
+
+// requiresStartTLS reports whether a server without STARTTLS must be refused
+// rather than sent the password in plain text
+func (c Config) requiresStartTLS() bool {
+	return !c.implicitTLS() && c.SMTPPort == submissionPort
+}
+
+// sendSMTP delivers msg over implicit TLS or STARTTLS according to the config.
+// It has the signature of smtp.SendMail, which cannot do implicit TLS.
+func (s *Sender) sendSMTP(addr string, auth smtp.Auth, from string, to []string, msg []byte) error {
+	client, err := s.dial(addr)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("AUTH"); ok && auth != nil {
+		if err := client.Auth(auth); err != nil {
+			return fmt.Errorf("SMTP authentication failed: %w", err)
+		}
+	}
+	if err := client.Mail(from); err != nil {
+		return err
+	}
+	for _, rcpt := range to {
+		if err := client.Rcpt(rcpt); err != nil {
+			return err
+		}
+	}
+	w, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(msg); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return client.Quit()
+}
+
+// dial connects to the SMTP server and secures the connection, with a TLS
+// handshake for implicit TLS or by upgrading with STARTTLS when offered
+func (s *Sender) dial(addr string) (*smtp.Client, error) {
+	tlsConfig := &tls.Config{
+		ServerName:         s.config.SMTPHost,
+		InsecureSkipVerify: s.config.InsecureSkipVerify,
+	}
+
+	if s.config.implicitTLS() {
+		conn, err := tls.DialWithDialer(&net.Dialer{Timeout: dialTimeout}, "tcp", addr, tlsConfig)
+		if err != nil {
+			return nil, fmt.Errorf("TLS connection to %s failed: %w", addr, err)
+		}
+		client, err := smtp.NewClient(conn, s.config.SMTPHost)
+		if err != nil {
+			conn.Close()
+			return nil, err
+		}
+		return client, nil
+	}
+
+	conn, err := net.DialTimeout("tcp", addr, dialTimeout)
+	if err != nil {
+		return nil, err
+	}
+	client, err := smtp.NewClient(conn, s.config.SMTPHost)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err := client.StartTLS(tlsConfig); err != nil {
+			client.Close()
+			return nil, fmt.Errorf("STARTTLS with %s failed: %w", addr, err)
+		}
+	} else if s.config.requiresStartTLS() {
+		client.Close()
+		return nil, fmt.Errorf("%s does not offer STARTTLS, which port %d requires", addr, submissionPort)
+	}
+	return client, nil
+}
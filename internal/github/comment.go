@@ -0,0 +1,136 @@
+// Package github posts review results to GitHub pull requests through the REST API.
+package github
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// requestTimeout bounds each call to the GitHub API
+const requestTimeout = 30 * time.Second
+
+// pullRefPattern matches the GITHUB_REF of pull request events, e.g. refs/pull/42/merge
+var pullRefPattern = regexp.MustCompile(`^refs/pull/(\d+)/`)
+
+// PullRequest identifies the pull request to comment on
+type PullRequest struct {
+	// Repo is the owner/name of the repository
+	Repo   string
+	Number int
+}
+
+// PullRequestFromEnv reads the pull request a GitHub Actions run belongs to from
+// GITHUB_REPOSITORY and the event payload at GITHUB_EVENT_PATH, falling back to
+// GITHUB_REF. It reports false outside a pull request run.
+func PullRequestFromEnv() (PullRequest, bool) {
+	pr := PullRequest{Repo: os.Getenv("GITHUB_REPOSITORY")}
+	if pr.Repo == "" {
+		return pr, false
+	}
+
+	if path := os.Getenv("GITHUB_EVENT_PATH"); path != "" {
+		if content, err := os.ReadFile(path); err == nil {
+			var event struct {
+				PullRequest struct {
+					Number int `json:"number"`
+				} `json:"pull_request"`
+			}
+			if json.Unmarshal(content, &event) == nil && event.PullRequest.Number > 0 {
+				pr.Number = event.PullRequest.Number
+				return pr, true
+			}
+		}
+	}
+
+	if m := pullRefPattern.FindStringSubmatch(os.Getenv("GITHUB_REF")); m != nil {
+		pr.Number, _ = strconv.Atoi(m[1])
+		return pr, true
+	}
+	return pr, false
+}
+
+// Client calls the GitHub REST API
+type Client struct {
+	// APIURL defaults to https://api.github.com
+	APIURL string
+	Token  string
+	// HTTPClient defaults to http.DefaultClient
+	HTTPClient *http.Client
+}
+
+// NewClientFromEnv returns a client authenticated with GITHUB_TOKEN, using
+// GITHUB_API_URL for GitHub Enterprise Server
+func NewClientFromEnv() *Client {
+	return &Client{
+		APIURL: os.Getenv("GITHUB_API_URL"),
+		Token:  os.Getenv("GITHUB_TOKEN"),
+	}
+}
+
+// CreateComment posts body as a comment on the pull request's conversation
+func (c *Client) CreateComment(ctx context.Context, pr PullRequest, body string) error {
+	endpoint := fmt.Sprintf("/repos/%s/issues/%d/comments", pr.Repo, pr.Number)
+	return c.post(ctx, endpoint, map[string]string{"body": body})
+}
+
+// StatusError is returned for a response outside the 2xx range
+type StatusError struct {
+	URL        string
+	StatusCode int
+	Status     string
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("%s returned %s", e.URL, e.Status)
+}
+
+// post sends payload as JSON to endpoint and checks for a successful response
+func (c *Client) post(ctx context.Context, endpoint string, payload any) error {
+	content, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	apiURL := c.APIURL
+	if apiURL == "" {
+		apiURL = "https://api.github.com"
+	}
+	url := strings.TrimSuffix(apiURL, "/") + endpoint
+
+	ctx, cancel := context.WithTimeout(ctx, requestTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(content))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("Content-Type", "application/json")
+	if c.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.Token)
+	}
+
+	client := c.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	// Comments are answered with 201 Created and reviews with 200 OK
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return &StatusError{URL: url, StatusCode: resp.StatusCode, Status: resp.Status}
+	}
+	return nil
+}
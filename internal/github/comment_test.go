@@ -0,0 +1,88 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestClient_CreateComment(t *testing.T) {
+	var gotPath, gotAuth, gotBody string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath, gotAuth = r.URL.Path, r.Header.Get("Authorization")
+		var payload struct {
+			Body string `json:"body"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Errorf("Failed to decode request: %v", err)
+		}
+		gotBody = payload.Body
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+
+	client := &Client{APIURL: server.URL, Token: "secret-token"}
+	if err := client.CreateComment(context.Background(), PullRequest{Repo: "acme/widgets", Number: 42}, "## Code Review"); err != nil {
+		t.Fatalf("CreateComment returned error: %v", err)
+	}
+
+	if gotPath != "/repos/acme/widgets/issues/42/comments" {
+		t.Errorf("Unexpected path %q", gotPath)
+	}
+	if gotAuth != "Bearer secret-token" {
+		t.Errorf("Unexpected Authorization header %q", gotAuth)
+	}
+	if gotBody != "## Code Review" {
+		t.Errorf("Unexpected comment body %q", gotBody)
+	}
+}
+
+func TestClient_CreateComment_Error(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	client := &Client{APIURL: server.URL}
+	if err := client.CreateComment(context.Background(), PullRequest{Repo: "acme/widgets", Number: 42}, "body"); err == nil {
+		t.Error("Expected an error for a 403 response")
+	}
+}
+
+func TestPullRequestFromEnv(t *testing.T) {
+	event := filepath.Join(t.TempDir(), "event.json")
+	if err := os.WriteFile(event, []byte(`{"action": "opened", "pull_request": {"number": 17}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name      string
+		repo      string
+		eventPath string
+		ref       string
+		want      PullRequest
+		wantOK    bool
+	}{
+		{"event payload", "acme/widgets", event, "refs/pull/99/merge", PullRequest{Repo: "acme/widgets", Number: 17}, true},
+		{"pull request ref", "acme/widgets", "", "refs/pull/99/merge", PullRequest{Repo: "acme/widgets", Number: 99}, true},
+		{"push event", "acme/widgets", "", "refs/heads/main", PullRequest{Repo: "acme/widgets"}, false},
+		{"outside GitHub Actions", "", "", "", PullRequest{}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("GITHUB_REPOSITORY", tt.repo)
+			t.Setenv("GITHUB_EVENT_PATH", tt.eventPath)
+			t.Setenv("GITHUB_REF", tt.ref)
+
+			got, ok := PullRequestFromEnv()
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("PullRequestFromEnv() = %+v, %v, want %+v, %v", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
@@ -0,0 +1,81 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/BrandonThomas84/code-review-automation/internal/review"
+)
+
+// ReviewComment is an inline pull request comment anchored to a line of the new
+// version of a file
+type ReviewComment struct {
+	Path string `json:"path"`
+	Line int    `json:"line"`
+	// Side is RIGHT, the head of the pull request
+	Side string `json:"side"`
+	Body string `json:"body"`
+}
+
+// CreateReview submits a COMMENT review on the pull request with body as the
+// summary and comments anchored to lines of the diff
+func (c *Client) CreateReview(ctx context.Context, pr PullRequest, body string, comments []ReviewComment) error {
+	endpoint := fmt.Sprintf("/repos/%s/pulls/%d/reviews", pr.Repo, pr.Number)
+	payload := struct {
+		Event    string          `json:"event"`
+		Body     string          `json:"body"`
+		Comments []ReviewComment `json:"comments,omitempty"`
+	}{Event: "COMMENT", Body: body, Comments: comments}
+	return c.post(ctx, endpoint, payload)
+}
+
+// PostReviewComments posts the report as a review on pull request prNumber of
+// owner/repo, using GITHUB_API_URL for GitHub Enterprise Server
+func PostReviewComments(ctx context.Context, report *review.Report, owner, repo string, prNumber int, token string) error {
+	client := &Client{APIURL: os.Getenv("GITHUB_API_URL"), Token: token}
+	return client.PostReviewComments(ctx, PullRequest{Repo: owner + "/" + repo, Number: prNumber}, report)
+}
+
+// PostReviewComments posts the report as a review with one inline comment per
+// issue. Issues without a line are listed in the review summary, and a report
+// with no line issues is posted as a single summary comment. GitHub rejects a
+// review whose comments fall outside the diff, as issues in unchanged lines of
+// a changed file do, so it is then retried with every issue in the summary.
+func (c *Client) PostReviewComments(ctx context.Context, pr PullRequest, report *review.Report) error {
+	var comments []ReviewComment
+	var unanchored []review.Issue
+	for _, issue := range report.Issues {
+		if issue.File == "" || issue.Line < 1 {
+			unanchored = append(unanchored, issue)
+			continue
+		}
+		comments = append(comments, ReviewComment{
+			Path: issue.File,
+			Line: issue.Line,
+			Side: "RIGHT",
+			Body: review.IssueComment(issue),
+		})
+	}
+
+	if len(comments) == 0 {
+		return c.CreateComment(ctx, pr, reviewSummary(report, unanchored))
+	}
+
+	err := c.CreateReview(ctx, pr, reviewSummary(report, unanchored), comments)
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) && statusErr.StatusCode == http.StatusUnprocessableEntity {
+		return c.CreateComment(ctx, pr, reviewSummary(report, report.Issues))
+	}
+	return err
+}
+
+// reviewSummary renders the review body, listing the issues without an inline comment
+func reviewSummary(report *review.Report, listed []review.Issue) string {
+	var body strings.Builder
+	review.WriteReviewSummary(&body, report, listed)
+	return body.String()
+}
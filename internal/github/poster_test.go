@@ -0,0 +1,153 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/BrandonThomas84/code-review-automation/internal/review"
+)
+
+// recordedRequest is a request seen by mockTransport
+type recordedRequest struct {
+	Path    string
+	Auth    string
+	Payload map[string]any
+}
+
+// mockTransport answers every request with the next status in statuses and
+// records the requests it was sent
+type mockTransport struct {
+	statuses []int
+	requests []recordedRequest
+}
+
+func (m *mockTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	recorded := recordedRequest{Path: req.URL.Path, Auth: req.Header.Get("Authorization")}
+	if err := json.NewDecoder(req.Body).Decode(&recorded.Payload); err != nil {
+		return nil, err
+	}
+	m.requests = append(m.requests, recorded)
+
+	status := http.StatusOK
+	if len(m.statuses) > 0 {
+		status, m.statuses = m.statuses[0], m.statuses[1:]
+	}
+	return &http.Response{
+		StatusCode: status,
+		Status:     http.StatusText(status),
+		Body:       io.NopCloser(strings.NewReader("{}")),
+		Request:    req,
+	}, nil
+}
+
+func newTestReport() *review.Report {
+	report := review.NewReport()
+	report.AddIssue(review.Issue{Type: "security", Severity: "high", Message: "Hardcoded password", File: "app.py", Line: 3, RuleID: "hardcoded-password"})
+	report.AddIssue(review.Issue{Type: "quality", Severity: "medium", Message: "Function too long", File: "app.py", Line: 10})
+	report.AddIssue(review.Issue{Type: "dependency", Severity: "low", Message: "Unpinned dependency", File: "requirements.txt"})
+	return report
+}
+
+func TestClient_PostReviewComments(t *testing.T) {
+	transport := &mockTransport{}
+	client := &Client{Token: "secret-token", HTTPClient: &http.Client{Transport: transport}}
+
+	if err := client.PostReviewComments(context.Background(), PullRequest{Repo: "acme/widgets", Number: 42}, newTestReport()); err != nil {
+		t.Fatalf("PostReviewComments returned error: %v", err)
+	}
+
+	if len(transport.requests) != 1 {
+		t.Fatalf("Expected one request, got %d", len(transport.requests))
+	}
+	req := transport.requests[0]
+	if req.Path != "/repos/acme/widgets/pulls/42/reviews" {
+		t.Errorf("Unexpected path %q", req.Path)
+	}
+	if req.Auth != "Bearer secret-token" {
+		t.Errorf("Unexpected Authorization header %q", req.Auth)
+	}
+	if req.Payload["event"] != "COMMENT" {
+		t.Errorf("Expected a COMMENT review, got %v", req.Payload["event"])
+	}
+
+	comments, _ := req.Payload["comments"].([]any)
+	if len(comments) != 2 {
+		t.Fatalf("Expected two inline comments, got %v", req.Payload["comments"])
+	}
+	first := comments[0].(map[string]any)
+	if first["path"] != "app.py" || first["line"] != float64(3) || first["side"] != "RIGHT" {
+		t.Errorf("Unexpected comment anchor %v", first)
+	}
+	if body, _ := first["body"].(string); !strings.HasPrefix(body, "🔴 **high** security: Hardcoded password") {
+		t.Errorf("Unexpected comment body %q", body)
+	}
+	if body, _ := comments[1].(map[string]any)["body"].(string); !strings.HasPrefix(body, "🟡 ") {
+		t.Errorf("Expected a medium issue to be prefixed with 🟡, got %q", body)
+	}
+
+	// The issue without a line is listed in the review summary
+	summary, _ := req.Payload["body"].(string)
+	if !strings.Contains(summary, "- 🟢 `requirements.txt` **low** dependency: Unpinned dependency") {
+		t.Errorf("Expected the unanchored issue in the summary, got:\n%s", summary)
+	}
+	if strings.Contains(summary, "Hardcoded password") {
+		t.Errorf("Did not expect inline issues in the summary, got:\n%s", summary)
+	}
+}
+
+func TestClient_PostReviewComments_NoLines(t *testing.T) {
+	transport := &mockTransport{statuses: []int{http.StatusCreated}}
+	client := &Client{HTTPClient: &http.Client{Transport: transport}}
+
+	report := review.NewReport()
+	report.AddIssue(review.Issue{Type: "dependency", Severity: "critical", Message: "Known vulnerable package", File: "go.mod"})
+
+	if err := client.PostReviewComments(context.Background(), PullRequest{Repo: "acme/widgets", Number: 42}, report); err != nil {
+		t.Fatalf("PostReviewComments returned error: %v", err)
+	}
+
+	if len(transport.requests) != 1 || transport.requests[0].Path != "/repos/acme/widgets/issues/42/comments" {
+		t.Fatalf("Expected a single summary comment, got %+v", transport.requests)
+	}
+	if body, _ := transport.requests[0].Payload["body"].(string); !strings.Contains(body, "🔴 `go.mod` **critical**") {
+		t.Errorf("Unexpected summary comment %q", body)
+	}
+}
+
+func TestClient_PostReviewComments_OutsideDiff(t *testing.T) {
+	transport := &mockTransport{statuses: []int{http.StatusUnprocessableEntity, http.StatusCreated}}
+	client := &Client{HTTPClient: &http.Client{Transport: transport}}
+
+	if err := client.PostReviewComments(context.Background(), PullRequest{Repo: "acme/widgets", Number: 42}, newTestReport()); err != nil {
+		t.Fatalf("PostReviewComments returned error: %v", err)
+	}
+
+	if len(transport.requests) != 2 {
+		t.Fatalf("Expected a review and a fallback comment, got %d requests", len(transport.requests))
+	}
+	if transport.requests[1].Path != "/repos/acme/widgets/issues/42/comments" {
+		t.Errorf("Unexpected fallback path %q", transport.requests[1].Path)
+	}
+	body, _ := transport.requests[1].Payload["body"].(string)
+	for _, want := range []string{"`app.py:3` **high**", "`app.py:10` **medium**", "`requirements.txt` **low**"} {
+		if !strings.Contains(body, want) {
+			t.Errorf("Expected %q in the fallback comment, got:\n%s", want, body)
+		}
+	}
+}
+
+func TestClient_PostReviewComments_Error(t *testing.T) {
+	transport := &mockTransport{statuses: []int{http.StatusForbidden}}
+	client := &Client{HTTPClient: &http.Client{Transport: transport}}
+
+	if err := client.PostReviewComments(context.Background(), PullRequest{Repo: "acme/widgets", Number: 42}, newTestReport()); err == nil {
+		t.Error("Expected an error for a 403 response")
+	}
+	if len(transport.requests) != 1 {
+		t.Errorf("Did not expect a fallback comment after a 403, got %d requests", len(transport.requests))
+	}
+}
@@ -0,0 +1,187 @@
+// Package gitlab posts review results to GitLab merge requests through the REST API.
+package gitlab
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/BrandonThomas84/code-review-automation/internal/review"
+)
+
+// requestTimeout bounds each call to the GitLab API
+const requestTimeout = 30 * time.Second
+
+// DefaultURL is the GitLab instance used when no URL is configured
+const DefaultURL = "https://gitlab.com"
+
+// Client calls the GitLab REST API
+type Client struct {
+	// BaseURL is the GitLab instance, e.g. https://gitlab.example.com; defaults to DefaultURL
+	BaseURL string
+	Token   string
+	// HTTPClient defaults to http.DefaultClient
+	HTTPClient *http.Client
+}
+
+// DiffRefs are the commits a merge request diff is computed from. Line comments
+// are anchored to the diff by all three.
+type DiffRefs struct {
+	BaseSHA  string `json:"base_sha"`
+	HeadSHA  string `json:"head_sha"`
+	StartSHA string `json:"start_sha"`
+}
+
+// Position anchors a discussion to a line of the new version of a file
+type Position struct {
+	PositionType string `json:"position_type"`
+	BaseSHA      string `json:"base_sha"`
+	HeadSHA      string `json:"head_sha"`
+	StartSHA     string `json:"start_sha"`
+	OldPath      string `json:"old_path"`
+	NewPath      string `json:"new_path"`
+	NewLine      int    `json:"new_line"`
+}
+
+// StatusError is returned for a response outside the 2xx range
+type StatusError struct {
+	URL        string
+	StatusCode int
+	Status     string
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("%s returned %s", e.URL, e.Status)
+}
+
+// DiffRefs returns the diff refs of the latest version of merge request mrIID
+func (c *Client) DiffRefs(ctx context.Context, projectID, mrIID int) (DiffRefs, error) {
+	var mr struct {
+		DiffRefs *DiffRefs `json:"diff_refs"`
+	}
+	endpoint := fmt.Sprintf("/projects/%d/merge_requests/%d", projectID, mrIID)
+	if err := c.do(ctx, http.MethodGet, endpoint, nil, &mr); err != nil {
+		return DiffRefs{}, err
+	}
+	if mr.DiffRefs == nil || mr.DiffRefs.HeadSHA == "" {
+		return DiffRefs{}, errors.New("merge request has no diff refs yet")
+	}
+	return *mr.DiffRefs, nil
+}
+
+// CreateDiscussion starts a discussion on the merge request, anchored to a line
+// of the diff when position is set
+func (c *Client) CreateDiscussion(ctx context.Context, projectID, mrIID int, body string, position *Position) error {
+	endpoint := fmt.Sprintf("/projects/%d/merge_requests/%d/discussions", projectID, mrIID)
+	payload := struct {
+		Body     string    `json:"body"`
+		Position *Position `json:"position,omitempty"`
+	}{Body: body, Position: position}
+	return c.do(ctx, http.MethodPost, endpoint, payload, nil)
+}
+
+// CreateNote posts body as a comment on the merge request's overview
+func (c *Client) CreateNote(ctx context.Context, projectID, mrIID int, body string) error {
+	endpoint := fmt.Sprintf("/projects/%d/merge_requests/%d/notes", projectID, mrIID)
+	return c.do(ctx, http.MethodPost, endpoint, map[string]string{"body": body}, nil)
+}
+
+// PostMRDiscussions posts the report to merge request mrIID of project
+// projectID on the GitLab instance at baseURL, which defaults to gitlab.com
+func PostMRDiscussions(ctx context.Context, report *review.Report, projectID, mrIID int, token, baseURL string) error {
+	client := &Client{BaseURL: baseURL, Token: token}
+	return client.PostMRDiscussions(ctx, projectID, mrIID, report)
+}
+
+// PostMRDiscussions starts one discussion per issue, anchored to the issue's
+// line in the merge request diff, then posts a summary note with the counts.
+// Issues that cannot be anchored, because they have no line, the diff refs
+// cannot be read or GitLab rejects the position as outside the diff, are listed
+// in the summary note instead.
+func (c *Client) PostMRDiscussions(ctx context.Context, projectID, mrIID int, report *review.Report) error {
+	var unanchored []review.Issue
+	refs, refsErr := c.DiffRefs(ctx, projectID, mrIID)
+
+	for _, issue := range report.Issues {
+		if refsErr != nil || issue.File == "" || issue.Line < 1 {
+			unanchored = append(unanchored, issue)
+			continue
+		}
+		position := &Position{
+			PositionType: "text",
+			BaseSHA:      refs.BaseSHA,
+			HeadSHA:      refs.HeadSHA,
+			StartSHA:     refs.StartSHA,
+			OldPath:      issue.File,
+			NewPath:      issue.File,
+			NewLine:      issue.Line,
+		}
+		err := c.CreateDiscussion(ctx, projectID, mrIID, review.IssueComment(issue), position)
+		var statusErr *StatusError
+		if errors.As(err, &statusErr) && statusErr.StatusCode == http.StatusBadRequest {
+			// The line is not part of the diff
+			unanchored = append(unanchored, issue)
+			continue
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	var body strings.Builder
+	review.WriteReviewSummary(&body, report, unanchored)
+	return c.CreateNote(ctx, projectID, mrIID, body.String())
+}
+
+// do sends payload as JSON to the API endpoint and decodes the response into
+// out, when set
+func (c *Client) do(ctx context.Context, method, endpoint string, payload, out any) error {
+	var content []byte
+	if payload != nil {
+		var err error
+		if content, err = json.Marshal(payload); err != nil {
+			return err
+		}
+	}
+
+	baseURL := c.BaseURL
+	if baseURL == "" {
+		baseURL = DefaultURL
+	}
+	url := strings.TrimSuffix(baseURL, "/") + "/api/v4" + endpoint
+
+	ctx, cancel := context.WithTimeout(ctx, requestTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, method, url, bytes.NewReader(content))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if c.Token != "" {
+		req.Header.Set("PRIVATE-TOKEN", c.Token)
+	}
+
+	client := c.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return &StatusError{URL: url, StatusCode: resp.StatusCode, Status: resp.Status}
+	}
+	if out != nil {
+		return json.NewDecoder(resp.Body).Decode(out)
+	}
+	return nil
+}
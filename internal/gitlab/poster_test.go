@@ -0,0 +1,167 @@
+package gitlab
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/BrandonThomas84/code-review-automation/internal/review"
+)
+
+// mockGitLab is a GitLab API that records the discussions and notes posted to it
+type mockGitLab struct {
+	mu          sync.Mutex
+	discussions []map[string]any
+	notes       []string
+	token       string
+	// refsStatus answers the merge request lookup when set
+	refsStatus int
+	// rejectLine makes discussions positioned on this line fail with 400
+	rejectLine int
+}
+
+func (m *mockGitLab) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.token = r.Header.Get("PRIVATE-TOKEN")
+
+	switch {
+	case r.Method == http.MethodGet && r.URL.Path == "/api/v4/projects/12/merge_requests/34":
+		if m.refsStatus != 0 {
+			w.WriteHeader(m.refsStatus)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]any{
+			"iid":       34,
+			"diff_refs": map[string]string{"base_sha": "aaa111", "head_sha": "ccc333", "start_sha": "bbb222"},
+		})
+	case r.Method == http.MethodPost && r.URL.Path == "/api/v4/projects/12/merge_requests/34/discussions":
+		var payload map[string]any
+		json.NewDecoder(r.Body).Decode(&payload)
+		if position, ok := payload["position"].(map[string]any); ok && position["new_line"] == float64(m.rejectLine) {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		m.discussions = append(m.discussions, payload)
+		w.WriteHeader(http.StatusCreated)
+	case r.Method == http.MethodPost && r.URL.Path == "/api/v4/projects/12/merge_requests/34/notes":
+		var payload struct {
+			Body string `json:"body"`
+		}
+		json.NewDecoder(r.Body).Decode(&payload)
+		m.notes = append(m.notes, payload.Body)
+		w.WriteHeader(http.StatusCreated)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func newTestReport() *review.Report {
+	report := review.NewReport()
+	report.AddIssue(review.Issue{Type: "security", Severity: "high", Message: "Hardcoded password", File: "app.py", Line: 3, RuleID: "hardcoded-password"})
+	report.AddIssue(review.Issue{Type: "quality", Severity: "medium", Message: "Function too long", File: "lib/util.py", Line: 10})
+	report.AddIssue(review.Issue{Type: "dependency", Severity: "low", Message: "Unpinned dependency", File: "requirements.txt"})
+	return report
+}
+
+func TestClient_PostMRDiscussions(t *testing.T) {
+	api := &mockGitLab{}
+	server := httptest.NewServer(api)
+	defer server.Close()
+
+	client := &Client{BaseURL: server.URL, Token: "secret-token"}
+	if err := client.PostMRDiscussions(context.Background(), 12, 34, newTestReport()); err != nil {
+		t.Fatalf("PostMRDiscussions returned error: %v", err)
+	}
+
+	if api.token != "secret-token" {
+		t.Errorf("Unexpected PRIVATE-TOKEN header %q", api.token)
+	}
+	if len(api.discussions) != 2 {
+		t.Fatalf("Expected two line discussions, got %d", len(api.discussions))
+	}
+
+	position, _ := api.discussions[1]["position"].(map[string]any)
+	want := map[string]any{
+		"position_type": "text",
+		"base_sha":      "aaa111",
+		"head_sha":      "ccc333",
+		"start_sha":     "bbb222",
+		"old_path":      "lib/util.py",
+		"new_path":      "lib/util.py",
+		"new_line":      float64(10),
+	}
+	for key, value := range want {
+		if position[key] != value {
+			t.Errorf("position[%q] = %v, want %v", key, position[key], value)
+		}
+	}
+	if body, _ := api.discussions[0]["body"].(string); !strings.HasPrefix(body, "🔴 **high** security: Hardcoded password") {
+		t.Errorf("Unexpected discussion body %q", body)
+	}
+
+	// The summary note lists only the issue without a line
+	if len(api.notes) != 1 {
+		t.Fatalf("Expected one summary note, got %d", len(api.notes))
+	}
+	if note := api.notes[0]; !strings.Contains(note, "🟢 `requirements.txt` **low**") || strings.Contains(note, "Hardcoded password") {
+		t.Errorf("Unexpected summary note:\n%s", note)
+	}
+}
+
+func TestClient_PostMRDiscussions_PositionRejected(t *testing.T) {
+	api := &mockGitLab{rejectLine: 10}
+	server := httptest.NewServer(api)
+	defer server.Close()
+
+	client := &Client{BaseURL: server.URL + "/"}
+	if err := client.PostMRDiscussions(context.Background(), 12, 34, newTestReport()); err != nil {
+		t.Fatalf("PostMRDiscussions returned error: %v", err)
+	}
+
+	if len(api.discussions) != 1 {
+		t.Errorf("Expected one line discussion, got %d", len(api.discussions))
+	}
+	if len(api.notes) != 1 || !strings.Contains(api.notes[0], "`lib/util.py:10` **medium**") {
+		t.Errorf("Expected the rejected issue in the summary note, got %q", api.notes)
+	}
+}
+
+func TestClient_PostMRDiscussions_NoDiffRefs(t *testing.T) {
+	api := &mockGitLab{refsStatus: http.StatusNotFound}
+	server := httptest.NewServer(api)
+	defer server.Close()
+
+	client := &Client{BaseURL: server.URL}
+	if err := client.PostMRDiscussions(context.Background(), 12, 34, newTestReport()); err != nil {
+		t.Fatalf("PostMRDiscussions returned error: %v", err)
+	}
+
+	if len(api.discussions) != 0 {
+		t.Errorf("Did not expect line discussions without diff refs, got %d", len(api.discussions))
+	}
+	if len(api.notes) != 1 {
+		t.Fatalf("Expected a single summary note, got %d", len(api.notes))
+	}
+	for _, want := range []string{"`app.py:3`", "`lib/util.py:10`", "`requirements.txt`"} {
+		if !strings.Contains(api.notes[0], want) {
+			t.Errorf("Expected %s in the summary note, got:\n%s", want, api.notes[0])
+		}
+	}
+}
+
+func TestClient_PostMRDiscussions_Error(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	client := &Client{BaseURL: server.URL}
+	if err := client.PostMRDiscussions(context.Background(), 12, 34, newTestReport()); err == nil {
+		t.Error("Expected an error for a 401 response")
+	}
+}
@@ -0,0 +1,210 @@
+// Package notify sends review summaries to chat services.
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/BrandonThomas84/code-review-automation/internal/review"
+)
+
+// requestTimeout bounds each call to a webhook
+const requestTimeout = 30 * time.Second
+
+// maxListedIssues is how many critical and high issues a Slack summary lists
+const maxListedIssues = 5
+
+// StatusError is returned for a response outside the 2xx range. The webhook
+// URL is a secret, so it is left out of the message; Slack explains rejected
+// payloads in the response body instead, e.g. invalid_blocks.
+type StatusError struct {
+	StatusCode int
+	Status     string
+	Body       string
+}
+
+func (e *StatusError) Error() string {
+	if e.Body == "" {
+		return fmt.Sprintf("webhook returned %s", e.Status)
+	}
+	return fmt.Sprintf("webhook returned %s: %s", e.Status, e.Body)
+}
+
+// slackMessage is an incoming webhook payload. The blocks are wrapped in an
+// attachment so the message gets a colored bar; Text is the notification fallback.
+type slackMessage struct {
+	Text        string            `json:"text"`
+	Attachments []slackAttachment `json:"attachments"`
+}
+
+type slackAttachment struct {
+	Color  string       `json:"color"`
+	Blocks []slackBlock `json:"blocks"`
+}
+
+// slackBlock is a Block Kit header, section or context block
+type slackBlock struct {
+	Type     string      `json:"type"`
+	Text     *slackText  `json:"text,omitempty"`
+	Fields   []slackText `json:"fields,omitempty"`
+	Elements []slackText `json:"elements,omitempty"`
+}
+
+type slackText struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+// SendSlackSummary posts the report's issue counts and its first critical and
+// high issues to a Slack incoming webhook, colored by the highest severity
+// found. A report without issues is only sent when always is set; sent reports
+// whether a message was posted.
+func SendSlackSummary(ctx context.Context, report *review.Report, webhookURL, repo, branch string, always bool) (sent bool, err error) {
+	if report.Summary.TotalIssues == 0 && !always {
+		return false, nil
+	}
+
+	content, err := json.Marshal(slackSummary(report, repo, branch))
+	if err != nil {
+		return false, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, requestTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhookURL, bytes.NewReader(content))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		// The error names the URL, which carries the webhook's secret path
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			return false, fmt.Errorf("posting to the webhook: %w", urlErr.Err)
+		}
+		return false, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return false, &StatusError{StatusCode: resp.StatusCode, Status: resp.Status, Body: strings.TrimSpace(string(body))}
+	}
+	return true, nil
+}
+
+// slackSummary builds the message for a report
+func slackSummary(report *review.Report, repo, branch string) slackMessage {
+	summary := report.Summary
+	title := fmt.Sprintf("%s Code review: %d issues found", review.SeverityMarker(maxSeverity(summary)), summary.TotalIssues)
+	if summary.TotalIssues == 0 {
+		title = "✅ Code review: no issues found"
+	}
+	if repo != "" {
+		title += " in " + repo
+	}
+
+	blocks := []slackBlock{{Type: "header", Text: &slackText{Type: "plain_text", Text: title}}}
+
+	counts := slackBlock{
+		Type: "section",
+		Fields: []slackText{
+			{Type: "mrkdwn", Text: fmt.Sprintf("*Critical*\n%d", summary.CriticalSeverity)},
+			{Type: "mrkdwn", Text: fmt.Sprintf("*High*\n%d", summary.HighSeverity)},
+			{Type: "mrkdwn", Text: fmt.Sprintf("*Medium*\n%d", summary.MediumSeverity)},
+			{Type: "mrkdwn", Text: fmt.Sprintf("*Low*\n%d", summary.LowSeverity+summary.InfoSeverity)},
+		},
+	}
+	if branch != "" {
+		counts.Text = &slackText{Type: "mrkdwn", Text: fmt.Sprintf("Branch `%s`, %d files reviewed", escapeSlack(branch), summary.TotalFiles)}
+	}
+	blocks = append(blocks, counts)
+
+	// Critical issues are listed before high ones
+	var listed []review.Issue
+	for _, severity := range []string{review.SeverityCritical, review.SeverityHigh} {
+		for _, issue := range report.Issues {
+			if issue.Severity == severity {
+				listed = append(listed, issue)
+			}
+		}
+	}
+	if len(listed) > 0 {
+		var text strings.Builder
+		text.WriteString("*Critical and high severity issues*")
+		for _, issue := range listed[:min(len(listed), maxListedIssues)] {
+			fmt.Fprintf(&text, "\n• `%s` %s", escapeSlack(issueLocation(issue)), escapeSlack(issue.Message))
+		}
+		blocks = append(blocks, slackBlock{Type: "section", Text: &slackText{Type: "mrkdwn", Text: text.String()}})
+		if more := len(listed) - maxListedIssues; more > 0 {
+			blocks = append(blocks, slackBlock{
+				Type:     "context",
+				Elements: []slackText{{Type: "mrkdwn", Text: fmt.Sprintf("and %d more critical or high severity issues in the full report", more)}},
+			})
+		}
+	}
+
+	return slackMessage{
+		Text:        title,
+		Attachments: []slackAttachment{{Color: severityColor(maxSeverity(summary)), Blocks: blocks}},
+	}
+}
+
+// maxSeverity returns the highest severity with issues, or "" for none
+func maxSeverity(summary review.Summary) string {
+	switch {
+	case summary.CriticalSeverity > 0:
+		return review.SeverityCritical
+	case summary.HighSeverity > 0:
+		return review.SeverityHigh
+	case summary.MediumSeverity > 0:
+		return review.SeverityMedium
+	case summary.LowSeverity > 0:
+		return review.SeverityLow
+	case summary.InfoSeverity > 0:
+		return review.SeverityInfo
+	}
+	return ""
+}
+
+// severityColor returns the attachment bar color for a severity, matching the
+// email report's header
+func severityColor(severity string) string {
+	switch severity {
+	case review.SeverityCritical:
+		return "#b71c1c"
+	case review.SeverityHigh:
+		return "#f44336"
+	case review.SeverityMedium:
+		return "#ff9800"
+	case review.SeverityLow, review.SeverityInfo:
+		return "#2196f3"
+	}
+	return "#4caf50"
+}
+
+// issueLocation formats an issue as file:line, or just the file for issues
+// without a line
+func issueLocation(issue review.Issue) string {
+	if issue.Line < 1 {
+		return issue.File
+	}
+	return fmt.Sprintf("%s:%d", issue.File, issue.Line)
+}
+
+// escapeSlack escapes the characters Slack's mrkdwn reads as markup for links
+// and mentions
+func escapeSlack(text string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace(text)
+}
@@ -0,0 +1,123 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/BrandonThomas84/code-review-automation/internal/review"
+)
+
+// newWebhook starts a webhook that records the payloads posted to it
+func newWebhook(t *testing.T, status int) (*httptest.Server, *[]slackMessage) {
+	t.Helper()
+	var received []slackMessage
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var message slackMessage
+		if err := json.NewDecoder(r.Body).Decode(&message); err != nil {
+			t.Errorf("Invalid payload: %v", err)
+		}
+		received = append(received, message)
+		w.WriteHeader(status)
+		if status != http.StatusOK {
+			fmt.Fprint(w, "invalid_blocks")
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server, &received
+}
+
+func TestSendSlackSummary(t *testing.T) {
+	server, received := newWebhook(t, http.StatusOK)
+
+	report := review.NewReport()
+	report.AddIssue(review.Issue{Type: "quality", Severity: "medium", Message: "Function too long", File: "lib/util.py", Line: 10})
+	for i := range 6 {
+		report.AddIssue(review.Issue{Type: "security", Severity: "high", Message: "Hardcoded password <admin>", File: "app.py", Line: i + 1})
+	}
+	report.AddIssue(review.Issue{Type: "security", Severity: "critical", Message: "Private key committed", File: "deploy/key.pem"})
+
+	sent, err := SendSlackSummary(context.Background(), report, server.URL+"/services/T0/B0/secret", "acme/shop", "feature/login", false)
+	if err != nil {
+		t.Fatalf("SendSlackSummary returned error: %v", err)
+	}
+	if !sent || len(*received) != 1 {
+		t.Fatalf("Expected one message to be sent, got sent=%v and %d messages", sent, len(*received))
+	}
+
+	message := (*received)[0]
+	if len(message.Attachments) != 1 || message.Attachments[0].Color != "#b71c1c" {
+		t.Fatalf("Expected one attachment colored for critical severity, got %+v", message.Attachments)
+	}
+	blocks := message.Attachments[0].Blocks
+	if len(blocks) != 4 {
+		t.Fatalf("Expected header, counts, issues and context blocks, got %d blocks", len(blocks))
+	}
+	if header := blocks[0].Text.Text; header != "🔴 Code review: 8 issues found in acme/shop" {
+		t.Errorf("Unexpected header %q", header)
+	}
+	if got := blocks[1].Fields[1].Text; got != "*High*\n6" {
+		t.Errorf("Unexpected high count field %q", got)
+	}
+	if got := blocks[1].Text.Text; !strings.Contains(got, "`feature/login`") {
+		t.Errorf("Expected the branch in the counts block, got %q", got)
+	}
+
+	issues := strings.Split(blocks[2].Text.Text, "\n")
+	if len(issues) != 6 {
+		t.Fatalf("Expected a title and five issues, got:\n%s", blocks[2].Text.Text)
+	}
+	if issues[1] != "• `deploy/key.pem` Private key committed" {
+		t.Errorf("Expected the critical issue first, got %q", issues[1])
+	}
+	if issues[2] != "• `app.py:1` Hardcoded password &lt;admin&gt;" {
+		t.Errorf("Unexpected issue line %q", issues[2])
+	}
+	if got := blocks[3].Elements[0].Text; !strings.Contains(got, "and 2 more") {
+		t.Errorf("Unexpected context %q", got)
+	}
+}
+
+func TestSendSlackSummary_NoIssues(t *testing.T) {
+	server, received := newWebhook(t, http.StatusOK)
+	report := review.NewReport()
+
+	sent, err := SendSlackSummary(context.Background(), report, server.URL, "acme/shop", "main", false)
+	if err != nil || sent || len(*received) != 0 {
+		t.Fatalf("Expected a clean report to be skipped, got sent=%v err=%v and %d messages", sent, err, len(*received))
+	}
+
+	sent, err = SendSlackSummary(context.Background(), report, server.URL, "acme/shop", "main", true)
+	if err != nil || !sent || len(*received) != 1 {
+		t.Fatalf("Expected a clean report to be sent with always, got sent=%v err=%v and %d messages", sent, err, len(*received))
+	}
+	attachment := (*received)[0].Attachments[0]
+	if attachment.Color != "#4caf50" || len(attachment.Blocks) != 2 {
+		t.Errorf("Expected a green summary without issues, got %+v", attachment)
+	}
+}
+
+func TestSendSlackSummary_Error(t *testing.T) {
+	server, _ := newWebhook(t, http.StatusBadRequest)
+	report := review.NewReport()
+	report.AddIssue(review.Issue{Type: "quality", Severity: "low", Message: "Unused import", File: "app.py", Line: 1})
+
+	_, err := SendSlackSummary(context.Background(), report, server.URL+"/services/T0/B0/secret", "", "", false)
+	if err == nil {
+		t.Fatal("Expected an error for a 400 response")
+	}
+	if !strings.Contains(err.Error(), "invalid_blocks") || strings.Contains(err.Error(), "secret") {
+		t.Errorf("Expected Slack's reason without the webhook URL, got %q", err)
+	}
+
+	// Connection errors leave the URL out too
+	server.Close()
+	_, err = SendSlackSummary(context.Background(), report, server.URL+"/services/T0/B0/secret", "", "", false)
+	if err == nil || strings.Contains(err.Error(), "secret") {
+		t.Errorf("Expected a connection error without the webhook URL, got %v", err)
+	}
+}
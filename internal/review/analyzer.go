@@ -1,28 +1,141 @@
 package review
 
 import (
+	"context"
 	"fmt"
+	"maps"
 	"os"
-	"os/exec"
 	"path/filepath"
+	"slices"
 	"strings"
 
 	"github.com/fatih/color"
 )
 
+// IgnorePatternFile is the per-repository ignore file name
+const IgnorePatternFile = ".autoreview-ignore"
+
+// IgnorePattern is a file ignore pattern and where it was loaded from
+type IgnorePattern struct {
+	Pattern string `json:"pattern"`
+	Source  string `json:"source"`
+}
+
+// languageExtensions maps the languages rules can be scoped to onto their file extensions
+var languageExtensions = map[string][]string{
+	"python":     {".py"},
+	"javascript": {".js", ".jsx"},
+	"typescript": {".ts", ".tsx"},
+	"ruby":       {".rb"},
+	"dart":       {".dart"},
+	"php":        {".php"},
+	"java":       {".java"},
+	"kotlin":     {".kt"},
+	"go":         {".go"},
+	"rust":       {".rs"},
+	"shell":      {".sh", ".bash"},
+	"cpp":        {".c", ".cc", ".cpp", ".h", ".hpp"},
+	"scala":      {".scala"},
+	"swift":      {".swift"},
+	"elixir":     {".ex", ".exs"},
+	"powershell": {".ps1", ".psm1"},
+	"groovy":     {".groovy", ".gradle"},
+	"r":          {".r"},
+	"vue":        {".vue"},
+	"svelte":     {".svelte"},
+	"notebook":   {".ipynb"},
+	"proto":      {".proto"},
+	"graphql":    {".graphql", ".gql"},
+	"solidity":   {".sol"},
+	"csharp":     {".cs"},
+	"template":   {".html", ".erb", ".ejs", ".jinja", ".j2", ".hbs", ".handlebars", ".mustache", ".twig"},
+	"terraform":  {".tf", ".tfvars"},
+	"config":     {".json", ".yaml", ".yml", ".properties", ".toml", ".ini", ".env"},
+}
+
+// Languages returns the language names rules can be scoped to, sorted
+func Languages() []string {
+	return slices.Sorted(maps.Keys(languageExtensions))
+}
+
+// LanguageForFile returns the language of a file based on its extension, or "" if unknown
+func LanguageForFile(file string) string {
+	if isConfigFile(file) {
+		return "config"
+	}
+	if isJenkinsfile(file) {
+		return "groovy"
+	}
+	ext := strings.ToLower(filepath.Ext(file))
+	for language, extensions := range languageExtensions {
+		if slices.Contains(extensions, ext) {
+			return language
+		}
+	}
+	return ""
+}
+
 type Analyzer struct {
+	ctx            context.Context
 	repoPath       string
-	ignorePatterns []string
-	verbose        bool
-	targetBranch   string // Store for use in security checks
+	ignorePatterns []IgnorePattern
+	ignoreRules    []ignoreRule
+	// includeRules limit the run to matching files; empty includes every file
+	includeRules  []ignoreRule
+	disabledRules map[string]bool
+	// languageDisabledRules holds rules disabled only for files of a given language
+	languageDisabledRules map[string]map[string]bool
+	// severityOverrides maps rule IDs to the severity they are reported with
+	severityOverrides map[string]string
+	// lineLengthLimit is the longest line accepted; 0 uses DefaultLineLengthLimit
+	lineLengthLimit int
+	// languageLineLengthLimits override lineLengthLimit for files of a given language
+	languageLineLengthLimits map[string]int
+	// maxFileSize is the largest file quality checks run on; 0 uses DefaultMaxFileSize
+	maxFileSize int
+	categories  map[string]bool
+	plugins     []Plugin
+	// ticketTrackers look up tickets referenced from TODO comments; empty skips the check
+	ticketTrackers []TicketTracker
+	// internalPackages are name prefixes of private packages, for the dependency confusion check
+	internalPackages []string
+	// includeSubmodules makes full scans descend into submodules and nested repositories
+	includeSubmodules bool
+	log               *Logger
+	targetBranch      string // Store for use in security checks
+	// remote is the remote target branches are fetched from; empty detects it
+	remote string
+	// offline disables fetching the target branch
+	offline bool
+	// diffSource reviews uncommitted changes instead of the target branch diff:
+	// DiffStaged, DiffWorking, or empty
+	diffSource string
+	// target caches the resolved target branch for the run
+	target *targetResolution
+	// files caches file content, so each file is read once per run
+	files *fileCache
+	// diffs caches each file's changed lines, so each file is diffed once per run
+	diffs *diffCache
+	// runGit runs git in the repository; tests replace it to count invocations
+	runGit func(args ...string) (string, error)
+}
+
+// NewAnalyzer creates an analyzer that logs progress up to level to stderr
+func NewAnalyzer(repoPath string, level LogLevel) *Analyzer {
+	return newAnalyzer(repoPath, NewLogger(level, color.Error))
 }
 
-func NewAnalyzer(repoPath string, verbose bool) *Analyzer {
+func newAnalyzer(repoPath string, log *Logger) *Analyzer {
 	analyzer := &Analyzer{
+		ctx:            context.Background(),
 		repoPath:       repoPath,
-		ignorePatterns: []string{},
-		verbose:        verbose,
+		ignorePatterns: []IgnorePattern{},
+		disabledRules:  map[string]bool{},
+		log:            log,
+		files:          newFileCache(),
+		diffs:          newDiffCache(),
 	}
+	analyzer.runGit = analyzer.execGit
 	// Load ignore patterns from .autoreview-ignore file
 	analyzer.loadIgnorePatterns()
 	return analyzer
@@ -30,20 +143,16 @@ func NewAnalyzer(repoPath string, verbose bool) *Analyzer {
 
 // loadIgnorePatterns reads the .autoreview-ignore file and loads patterns
 func (a *Analyzer) loadIgnorePatterns() {
-	if a.verbose {
-		color.Blue("[INFO] Loading ignore patterns...")
-	}
+	a.log.Infof("Loading ignore patterns...")
 
-	ignoreFilePath := filepath.Join(a.repoPath, ".autoreview-ignore")
+	ignoreFilePath := filepath.Join(a.repoPath, IgnorePatternFile)
 	content, err := os.ReadFile(ignoreFilePath)
 	if err != nil {
 		// File doesn't exist or can't be read, which is fine
 		return
 	}
 
-	if a.verbose {
-		color.Blue("[INFO] Found ignore file")
-	}
+	a.log.Infof("Found ignore file")
 
 	lines := strings.Split(string(content), "\n")
 	for _, line := range lines {
@@ -51,238 +160,540 @@ func (a *Analyzer) loadIgnorePatterns() {
 		line = strings.TrimSpace(line)
 		// Skip empty lines and comments
 		if line != "" && !strings.HasPrefix(line, "#") {
-			a.ignorePatterns = append(a.ignorePatterns, line)
+			a.addIgnorePattern(IgnorePattern{Pattern: line, Source: IgnorePatternFile})
 		}
 	}
 }
 
-// shouldIgnoreFile checks if a file matches any ignore patterns
-func (a *Analyzer) shouldIgnoreFile(filePath string) bool {
-	if a.verbose {
-		color.Blue("[INFO] Checking if file should be ignored: %s", filePath)
+// AddIgnorePatterns adds extra ignore patterns, recording where they came from
+func (a *Analyzer) AddIgnorePatterns(patterns []string, source string) {
+	for _, pattern := range patterns {
+		if pattern = strings.TrimSpace(pattern); pattern != "" {
+			a.addIgnorePattern(IgnorePattern{Pattern: pattern, Source: source})
+		}
 	}
+}
 
-	for _, pattern := range a.ignorePatterns {
-		// Check for exact match
-		if filePath == pattern {
-			if a.verbose {
-				color.Blue("[INFO] File matches ignore pattern: %s", pattern)
-			}
-			return true
-		}
-		// Check if pattern matches using filepath.Match (supports wildcards)
-		if matched, err := filepath.Match(pattern, filePath); err == nil && matched {
-			if a.verbose {
-				color.Blue("[INFO] File matches ignore pattern: %s", pattern)
-			}
-			return true
+// addIgnorePattern records a pattern and compiles it for matching
+func (a *Analyzer) addIgnorePattern(ignore IgnorePattern) {
+	a.ignorePatterns = append(a.ignorePatterns, ignore)
+	a.ignoreRules = append(a.ignoreRules, compileIgnorePattern(ignore))
+}
+
+// SetIncludePatterns limits a run to files matching one of the globs, which use
+// the ignore pattern syntax: a pattern without a / matches at any depth, and a
+// pattern matching a directory selects everything inside it. Empty includes all files.
+func (a *Analyzer) SetIncludePatterns(patterns []string) {
+	a.includeRules = nil
+	for _, pattern := range patterns {
+		if pattern = strings.TrimSpace(pattern); pattern != "" {
+			a.includeRules = append(a.includeRules, compileIgnorePattern(IgnorePattern{Pattern: pattern, Source: "include"}))
 		}
-		// Check if the file is within an ignored directory
-		if strings.HasSuffix(pattern, "/") {
-			dirPattern := strings.TrimSuffix(pattern, "/")
-			if strings.HasPrefix(filePath, dirPattern+"/") {
-				if a.verbose {
-					color.Blue("[INFO] File is within ignored directory:", pattern)
-				}
+	}
+}
+
+// matchIncludePattern reports whether a file is selected by the include
+// patterns, itself or through one of its directories. Every file is selected
+// when there are none.
+func (a *Analyzer) matchIncludePattern(filePath string) bool {
+	if len(a.includeRules) == 0 {
+		return true
+	}
+	parts := strings.Split(strings.Trim(filePath, "/"), "/")
+	for i := range parts {
+		path := strings.Join(parts[:i+1], "/")
+		isDir := i < len(parts)-1
+		for _, rule := range a.includeRules {
+			if (!rule.dirOnly || isDir) && rule.re.MatchString(path) {
 				return true
 			}
 		}
 	}
+	return false
+}
 
-	if a.verbose {
-		color.Blue("[INFO] File should NOT be ignored")
+// IgnorePatterns returns the ignore patterns in the order they are applied
+func (a *Analyzer) IgnorePatterns() []IgnorePattern {
+	return append([]IgnorePattern(nil), a.ignorePatterns...)
+}
+
+// SetDisabledRules disables reporting for the given issue types or rule IDs
+func (a *Analyzer) SetDisabledRules(rules []string) {
+	a.disabledRules = map[string]bool{}
+	for _, rule := range rules {
+		a.disabledRules[rule] = true
 	}
+}
 
-	return false
+// SetSeverityOverrides reports the given rule IDs with a different severity
+func (a *Analyzer) SetSeverityOverrides(overrides map[string]string) {
+	a.severityOverrides = map[string]string{}
+	for rule, severity := range overrides {
+		a.severityOverrides[rule] = strings.ToLower(severity)
+	}
 }
 
-func (a *Analyzer) GenerateReport(targetBranch string, fullScan bool) (*Report, error) {
-	if a.verbose {
-		color.Blue("[INFO] Generating report...")
+// SetLanguageDisabledRules disables rule IDs for files of the given languages only
+func (a *Analyzer) SetLanguageDisabledRules(rules map[string][]string) {
+	a.languageDisabledRules = map[string]map[string]bool{}
+	for language, ruleIDs := range rules {
+		disabled := map[string]bool{}
+		for _, rule := range ruleIDs {
+			disabled[rule] = true
+		}
+		a.languageDisabledRules[strings.ToLower(language)] = disabled
+	}
+}
+
+// DefaultLineLengthLimit is the longest line accepted when no limit is configured
+const DefaultLineLengthLimit = 120
+
+// SetLineLengthLimits sets the longest line accepted by the line-length rule, for every
+// file and for files of the given languages; a limit of 0 keeps the default
+func (a *Analyzer) SetLineLengthLimits(limit int, languages map[string]int) {
+	a.lineLengthLimit = limit
+	a.languageLineLengthLimits = map[string]int{}
+	for language, languageLimit := range languages {
+		a.languageLineLengthLimits[strings.ToLower(language)] = languageLimit
+	}
+}
+
+// lineLengthLimitFor returns the longest line accepted in a file
+func (a *Analyzer) lineLengthLimitFor(file string) int {
+	if limit := a.languageLineLengthLimits[LanguageForFile(file)]; limit > 0 {
+		return limit
+	}
+	if a.lineLengthLimit > 0 {
+		return a.lineLengthLimit
+	}
+	return DefaultLineLengthLimit
+}
+
+// SetMaxFileSize sets the largest file, in bytes, quality checks run on; a limit of 0
+// keeps the default
+func (a *Analyzer) SetMaxFileSize(limit int) {
+	a.maxFileSize = limit
+}
+
+// maxFileSizeLimit returns the largest file quality checks run on
+func (a *Analyzer) maxFileSizeLimit() int {
+	if a.maxFileSize > 0 {
+		return a.maxFileSize
+	}
+	return DefaultMaxFileSize
+}
+
+// applyDisabledRules drops issues whose type or rule ID has been disabled,
+// globally or for the language of the file the issue was reported in
+func (a *Analyzer) applyDisabledRules(report *Report) {
+	if len(a.disabledRules) == 0 && len(a.languageDisabledRules) == 0 {
+		return
+	}
+	report.FilterIssues(func(issue Issue) bool {
+		if a.disabledRules[issue.Type] || (issue.RuleID != "" && a.disabledRules[issue.RuleID]) {
+			return false
+		}
+		if issue.RuleID == "" {
+			return true
+		}
+		return !a.languageDisabledRules[LanguageForFile(issue.File)][issue.RuleID]
+	})
+}
+
+// applySeverityOverrides changes the severity of issues whose rule ID has an override
+func (a *Analyzer) applySeverityOverrides(report *Report) {
+	if len(a.severityOverrides) == 0 {
+		return
+	}
+	report.UpdateIssues(func(issue *Issue) {
+		if severity, ok := a.severityOverrides[issue.RuleID]; ok && issue.RuleID != "" {
+			issue.Severity = severity
+		}
+	})
+}
+
+// shouldIgnoreFile checks if a file matches any ignore patterns
+func (a *Analyzer) shouldIgnoreFile(filePath string) bool {
+	_, ignored := a.matchIgnorePattern(filePath)
+	return ignored
+}
+
+// matchIgnorePattern returns the ignore pattern that excludes a file, following
+// .gitignore rules: the last matching pattern wins, so a later !pattern
+// re-includes the file, but nothing inside an excluded directory can be
+// re-included. A path ending in / is matched as a directory.
+func (a *Analyzer) matchIgnorePattern(filePath string) (IgnorePattern, bool) {
+	a.log.Debugf("Checking if file should be ignored: %s", filePath)
+
+	isDir := strings.HasSuffix(filePath, "/")
+	parts := strings.Split(strings.Trim(filePath, "/"), "/")
+	for i := range parts {
+		path := strings.Join(parts[:i+1], "/")
+		rule, matched := lastIgnoreMatch(a.ignoreRules, path, isDir || i < len(parts)-1)
+		if matched && !rule.negate {
+			a.log.Debugf("File matches ignore pattern: %s", rule.Pattern)
+			return rule.IgnorePattern, true
+		}
 	}
 
+	a.log.Debugf("File should NOT be ignored")
+
+	return IgnorePattern{}, false
+}
+
+func (a *Analyzer) GenerateReport(targetBranch string, fullScan bool) (*Report, error) {
+	a.log.Infof("Generating report...")
+
 	// Store target branch for use in security checks
 	a.targetBranch = targetBranch
 
+	// Every pass reads files through the cache; start from the files on disk and
+	// release their content once the report is built
+	a.files.reset()
+	defer a.files.reset()
+	a.diffs.reset()
+	defer a.diffs.reset()
+
 	report := NewReport()
+	report.Commit = a.headCommit()
 
 	if fullScan {
-		if a.verbose {
-			color.Blue("[INFO] Full scan requested")
-		}
+		a.log.Infof("Full scan requested")
 
 		if err := a.analyzeFullCodebase(report); err != nil {
 			return nil, fmt.Errorf("full codebase analysis failed: %w", err)
 		}
-		// Full scan uses old security checks (scans whole files)
-		a.runSecurityChecks(report)
 	} else {
-		if a.verbose {
-			color.Blue("[INFO] Analyzing git diff")
-		}
+		a.log.Infof("Analyzing git diff")
 
 		if err := a.analyzeGitDiff(targetBranch, report); err != nil {
 			return nil, fmt.Errorf("git diff analysis failed: %w", err)
 		}
-		// Diff mode uses improved security checks (changed lines only)
-		a.RunSecurityChecksV2(report, targetBranch)
 	}
 
-	// Run quality checks
-	a.runQualityChecks(report)
-
-	return report, nil
-}
-
-func (a *Analyzer) analyzeGitDiff(targetBranch string, report *Report) error {
-	// Fetch the target branch
-	cmd := exec.Command("git", "fetch", "origin", targetBranch)
-	cmd.Dir = a.repoPath
-	cmd.Run() // Ignore error, branch might be local
+	report.Categories = a.ranCategories()
 
-	if a.verbose {
-		color.Blue("[INFO] Getting changed files...")
+	if a.runsCategory(CategorySecurity) {
+		if fullScan {
+			// Full scan uses old security checks (scans whole files)
+			a.runSecurityChecks(report)
+		} else {
+			// Diff mode uses improved security checks (changed lines only)
+			a.RunSecurityChecksV2(report, targetBranch)
+		}
+		for _, file := range report.ChangedFiles {
+			if _, verdict := a.selectFile(file); !verdict.security {
+				continue
+			}
+			// Like the secret patterns, diff mode only reports the lines the diff adds
+			var changed map[int]bool
+			if !fullScan {
+				lines, err := a.getChangedLines(targetBranch, file)
+				if err != nil {
+					a.log.Warnf("Could not get changed lines for %s: %v", file, err)
+					continue
+				}
+				changed = lineSet(lines)
+			}
+			a.checkDependencyConfusion(file, changed, report)
+			a.checkSecretsInComments(file, changed, report)
+		}
+		a.tagCategory(report, 0, func(Issue) string { return CategorySecurity })
 	}
 
-	// Get changed files
-	cmd = exec.Command("git", "diff", "--name-only", fmt.Sprintf("origin/%s..HEAD", targetBranch))
+	// The language analyzers produce both quality and performance findings
+	if a.runsCategory(CategoryQuality) || a.runsCategory(CategoryPerformance) {
+		from := len(report.Issues)
 
-	if a.verbose {
-		color.Blue("[INFO] Git command: %s\n", cmd.String())
-	}
+		// Run quality checks; in diff mode only the changed lines are reported
+		var changedLines map[string]map[int]bool
+		if !fullScan {
+			changedLines = a.changedLineSets(report)
+		}
+		a.runQualityChecks(report, changedLines)
 
-	cmd.Dir = a.repoPath
-	output, err := cmd.Output()
-	if err != nil {
-		// Fallback without origin
-		cmd = exec.Command("git", "diff", "--name-only", fmt.Sprintf("%s..HEAD", targetBranch))
-		cmd.Dir = a.repoPath
-		output, err = cmd.Output()
-		if err != nil {
-			return fmt.Errorf("failed to get changed files: %w", err)
+		// Run external plugins
+		if a.runsCategory(CategoryQuality) {
+			a.runPlugins(report, fullScan)
+			a.checkTodoTickets(report)
 		}
+
+		a.tagCategory(report, from, languageCheckCategory)
+	} else {
+		a.log.Infof("Skipping language analyzers (categories: %s)", strings.Join(report.Categories, ", "))
 	}
 
-	if a.verbose {
-		color.Blue("[INFO] Found changed files")
+	a.applySuppressions(report)
+	a.applyDisabledRules(report)
+	a.applySeverityOverrides(report)
+
+	return report, nil
+}
+
+func (a *Analyzer) analyzeGitDiff(targetBranch string, report *Report) error {
+	files, err := a.diffFiles(targetBranch)
+	if err != nil {
+		return err
 	}
 
-	files := strings.Split(strings.TrimSpace(string(output)), "\n")
 	for _, f := range files {
-		if f != "" && !a.shouldIgnoreFile(f) {
+		if pattern, ignored := a.matchIgnorePattern(f); ignored {
+			report.markSkipped(f, fmt.Sprintf("ignored by %q from %s", pattern.Pattern, pattern.Source))
+		} else if a.matchIncludePattern(f) {
+			// Files outside the include patterns are out of scope, not skipped
 			report.ChangedFiles = append(report.ChangedFiles, f)
 		}
 	}
 
-	if a.verbose {
-		color.Blue("[INFO] Done analyzing git diff")
-	}
+	a.log.Infof("Done analyzing git diff")
 
 	return nil
 }
 
-func (a *Analyzer) analyzeFullCodebase(report *Report) error {
-	codeExtensions := []string{".py", ".js", ".ts", ".jsx", ".tsx", ".dart", ".rb", ".php", ".java", ".kt"}
-
-	if a.verbose {
-		color.Blue("[INFO] Analyzing full codebase")
-		color.Blue("[INFO] Searching for files with extensions:", codeExtensions)
-	}
-
-	for _, ext := range codeExtensions {
-		cmd := exec.Command("find", ".", "-name", fmt.Sprintf("*%s", ext), "-type", "f")
-		cmd.Dir = a.repoPath
-		output, err := cmd.Output()
-		if err == nil {
-			files := strings.Split(strings.TrimSpace(string(output)), "\n")
-			for _, f := range files {
-				if f != "" && f != "." && !a.shouldIgnoreFile(f) {
-					report.ChangedFiles = append(report.ChangedFiles, f)
-				}
-			}
-		}
+// diffFiles returns every file changed between the target branch and HEAD, or in
+// the index or working tree when reviewing uncommitted changes, before ignore patterns
+func (a *Analyzer) diffFiles(targetBranch string) ([]string, error) {
+	target, err := a.resolveTarget(targetBranch)
+	if err != nil {
+		return nil, err
 	}
 
-	if a.verbose {
-		color.Blue("[INFO] Done analyzing full codebase")
+	a.log.Infof("Getting files changed %s...", target.description)
+
+	output, err := a.git(append([]string{"diff", "--name-only"}, target.diffArgs...)...)
+	if err != nil {
+		return nil, &SetupError{Err: fmt.Errorf("failed to get changed files: %w", err)}
 	}
 
-	return nil
+	a.log.Infof("Found changed files")
+
+	files := []string{}
+	for _, f := range strings.Split(strings.TrimSpace(output), "\n") {
+		if f != "" {
+			files = append(files, f)
+		}
+	}
+	return files, nil
 }
 
-func (a *Analyzer) runSecurityChecks(report *Report) {
-	if a.verbose {
-		color.Blue("[INFO] Running security checks")
+// fullScanExtensions are the file extensions collected by a full scan
+var fullScanExtensions = []string{".py", ".js", ".ts", ".jsx", ".tsx", ".dart", ".rb", ".php", ".java", ".kt", ".rs", ".sh", ".bash", ".c", ".cc", ".cpp", ".h", ".hpp", ".scala", ".swift", ".ex", ".exs", ".ps1", ".psm1", ".groovy", ".gradle", ".r", ".vue", ".svelte", ".ipynb", ".proto", ".graphql", ".gql", ".sol", ".cs", ".html", ".erb", ".ejs", ".jinja", ".j2", ".hbs", ".handlebars", ".mustache", ".twig", ".tf", ".tfvars", ".json", ".yaml", ".yml", ".properties", ".toml", ".ini", ".env"}
+
+func (a *Analyzer) analyzeFullCodebase(report *Report) error {
+	files, err := a.fullScanFiles()
+	if err != nil {
+		return err
 	}
 
-	// Check for common security issues
-	patterns := map[string]string{
-		"password":    "Hardcoded password detected",
-		"api_key":     "Hardcoded API key detected",
-		"secret":      "Hardcoded secret detected",
-		"private_key": "Private key in code",
-		"aws_access":  "AWS credentials in code",
+	for _, f := range files {
+		if pattern, ignored := a.matchIgnorePattern(f); ignored {
+			report.markSkipped(f, fmt.Sprintf("ignored by %q from %s", pattern.Pattern, pattern.Source))
+		} else if a.matchIncludePattern(f) {
+			// Files outside the include patterns are out of scope, not skipped
+			report.ChangedFiles = append(report.ChangedFiles, f)
+		}
 	}
 
-	if a.verbose {
-		color.Blue("[INFO] Checking for security issues...")
+	a.log.Infof("Done analyzing full codebase")
+
+	return nil
+}
+
+func (a *Analyzer) runSecurityChecks(report *Report) {
+	a.log.Infof("Running security checks")
+
+	// Check for common security issues, keyed by pattern with the message, rule ID and severity to report
+	patterns := map[string]struct{ message, ruleID, severity string }{
+		"password":    {"Hardcoded password detected", "hardcoded-password", SeverityHigh},
+		"api_key":     {"Hardcoded API key detected", "hardcoded-api-key", SeverityHigh},
+		"secret":      {"Hardcoded secret detected", "hardcoded-secret", SeverityHigh},
+		"private_key": {"Private key in code", "private-key", SeverityCritical},
+		"aws_access":  {"AWS credentials in code", "aws-credentials", SeverityCritical},
 	}
 
+	a.log.Infof("Checking for security issues...")
+
 	for _, file := range report.ChangedFiles {
-		if a.verbose {
-			color.Blue("[INFO] Checking file for security issues: %s", file)
+		a.log.Debugf("Checking file for security issues: %s", file)
+
+		if _, verdict := a.selectFile(file); !verdict.security {
+			report.markSkipped(file, verdict.securitySkip)
+			continue
 		}
 
-		filePath := filepath.Join(a.repoPath, file)
-		content, err := os.ReadFile(filePath)
+		content, err := a.readFile(file)
 		if err != nil {
 			continue
 		}
+		report.markAnalyzed(file)
+
+		// Config files are mostly keys like secrets.NPM_TOKEN or private_key_path, so
+		// they get the diff mode patterns, which check for a value, on every line
+		if isConfigFile(file) {
+			lines := []changedLine{}
+			for i, line := range strings.Split(string(content), "\n") {
+				lines = append(lines, changedLine{LineNum: i + 1, Content: line})
+			}
+			a.checkSecurityPatterns(file, lines, GetSecurityPatterns(), report)
+			continue
+		}
 
 		contentStr := strings.ToLower(string(content))
-		for pattern, message := range patterns {
+		for pattern, check := range patterns {
 			if strings.Contains(contentStr, pattern) {
 				report.AddIssue(Issue{
 					Type:     "security",
-					Severity: "high",
-					Message:  message,
+					Severity: check.severity,
+					Message:  check.message,
 					File:     file,
+					RuleID:   check.ruleID,
 				})
 			}
 		}
 
-		if a.verbose {
-			color.Blue("[INFO] Done checking for security issues in file: %s", file)
-		}
+		a.log.Debugf("Done checking for security issues in file: %s", file)
 	}
 
-	if a.verbose {
-		color.Blue("[INFO] Done running security checks")
-	}
+	a.log.Infof("Done running security checks")
 }
 
-func (a *Analyzer) runQualityChecks(report *Report) {
-	if a.verbose {
-		color.Blue("[INFO] Running quality checks")
-	}
+// runQualityChecks runs the language analyzer of each changed file. When
+// changedLines has an entry for a file, only issues on those lines, or about the
+// file as a whole, are kept; a nil map keeps every issue, as in a full scan.
+func (a *Analyzer) runQualityChecks(report *Report, changedLines map[string]map[int]bool) {
+	a.log.Infof("Running quality checks")
 
 	// Check for code quality issues
 	for _, file := range report.ChangedFiles {
-		switch {
-		case strings.HasSuffix(file, ".py"):
-			a.checkPythonQuality(file, report)
-		case strings.HasSuffix(file, ".js"), strings.HasSuffix(file, ".jsx"):
-			a.checkJavaScriptQuality(file, report)
-		case strings.HasSuffix(file, ".ts"), strings.HasSuffix(file, ".tsx"):
-			a.checkTypeScriptQuality(file, report)
-		case strings.HasSuffix(file, ".rb"):
-			a.checkRubyQuality(file, report)
-		case strings.HasSuffix(file, ".dart"):
-			a.checkDartQuality(file, report)
-		case strings.HasSuffix(file, ".php"):
-			a.checkPHPQuality(file, report)
-		case strings.HasSuffix(file, ".java"), strings.HasSuffix(file, ".kt"):
-			a.checkJavaKotlinQuality(file, report)
+		_, verdict := a.selectFile(file)
+		check := verdict.check
+		if verdict.reportSkip {
+			a.reportSkippedFile(file, verdict.qualitySkip, report)
+			continue
+		}
+		if check == nil {
+			report.markSkipped(file, verdict.qualitySkip)
+			continue
+		}
+		from := len(report.Issues)
+		check(file, report)
+		report.markAnalyzed(file)
+		if changed, ok := changedLines[file]; ok {
+			// Only the issues this check just added are narrowed to the diff
+			n := 0
+			report.FilterIssues(func(issue Issue) bool {
+				n++
+				return n <= from || issue.Line < 1 || changed[issue.Line] || diffRules[issue.RuleID]
+			})
+		}
+	}
+}
+
+// diffRules compare a file with its target branch version and report what the
+// change removed, so their issues point at lines the diff may not touch
+var diffRules = map[string]bool{
+	"proto-unreserved-removed-field": true,
+}
+
+// changedLineSets returns the lines added or modified in each changed file that
+// has a quality check. Files whose diff cannot be read are left out, so all of
+// their issues are kept.
+func (a *Analyzer) changedLineSets(report *Report) map[string]map[int]bool {
+	sets := map[string]map[int]bool{}
+	for _, file := range report.ChangedFiles {
+		if _, check := a.qualityCheckFor(file); check == nil {
+			continue
 		}
+		changedLines, err := a.getChangedLines(a.targetBranch, file)
+		if err != nil {
+			a.log.Warnf("Could not get changed lines for %s, reporting the whole file: %v", file, err)
+			continue
+		}
+		sets[file] = lineSet(changedLines)
+	}
+	return sets
+}
+
+// lineSet returns the numbers of the changed lines
+func lineSet(lines []changedLine) map[int]bool {
+	set := map[int]bool{}
+	for _, line := range lines {
+		set[line.LineNum] = true
+	}
+	return set
+}
+
+// qualityCheckFor returns the name and quality check of the analyzer handling a file,
+// or a nil check when no analyzer recognizes its extension or location
+func (a *Analyzer) qualityCheckFor(file string) (string, func(string, *Report)) {
+	// Full scans collect extensions case-insensitively, so dispatch the same way
+	file = strings.ToLower(file)
+	switch {
+	case isManifestFile(file):
+		// Manifests are matched by name, so check them before Gradle scripts and JSON
+		return "manifest", a.checkManifestQuality
+	case strings.HasSuffix(file, ".py"):
+		return "python", a.checkPythonQuality
+	case strings.HasSuffix(file, ".js"), strings.HasSuffix(file, ".jsx"):
+		return "javascript", a.checkJavaScriptQuality
+	case strings.HasSuffix(file, ".ts"), strings.HasSuffix(file, ".tsx"):
+		return "typescript", a.checkTypeScriptQuality
+	case strings.HasSuffix(file, ".rb"):
+		return "ruby", a.checkRubyQuality
+	case strings.HasSuffix(file, ".dart"):
+		return "dart", a.checkDartQuality
+	case strings.HasSuffix(file, ".php"):
+		return "php", a.checkPHPQuality
+	case strings.HasSuffix(file, ".java"), strings.HasSuffix(file, ".kt"):
+		return "java/kotlin", a.checkJavaKotlinQuality
+	case strings.HasSuffix(file, ".go"):
+		return "go", a.checkGoQuality
+	case strings.HasSuffix(file, ".rs"):
+		return "rust", a.checkRustQuality
+	case strings.HasSuffix(file, ".sh"), strings.HasSuffix(file, ".bash"):
+		return "shell", a.checkShellQuality
+	case strings.HasSuffix(file, ".c"), strings.HasSuffix(file, ".cc"), strings.HasSuffix(file, ".cpp"),
+		strings.HasSuffix(file, ".h"), strings.HasSuffix(file, ".hpp"):
+		return "cpp", a.checkCppQuality
+	case strings.HasSuffix(file, ".scala"):
+		return "scala", a.checkScalaQuality
+	case strings.HasSuffix(file, ".swift"):
+		return "swift", a.checkSwiftQuality
+	case strings.HasSuffix(file, ".ex"), strings.HasSuffix(file, ".exs"):
+		return "elixir", a.checkElixirQuality
+	case strings.HasSuffix(file, ".ps1"), strings.HasSuffix(file, ".psm1"):
+		return "powershell", a.checkPowerShellQuality
+	case strings.HasSuffix(file, ".groovy"), strings.HasSuffix(file, ".gradle"), isJenkinsfile(file):
+		// Jenkinsfiles have no extension and are matched by name
+		return "groovy", a.checkGroovyQuality
+	case strings.HasSuffix(file, ".r"):
+		return "r", a.checkRQuality
+	case strings.HasSuffix(file, ".vue"):
+		return "vue", a.checkVueQuality
+	case strings.HasSuffix(file, ".svelte"):
+		return "svelte", a.checkSvelteQuality
+	case strings.HasSuffix(file, ".proto"):
+		return "proto", a.checkProtoQuality
+	case strings.HasSuffix(file, ".graphql"), strings.HasSuffix(file, ".gql"):
+		return "graphql", a.checkGraphQLQuality
+	case strings.HasSuffix(file, ".sol"):
+		return "solidity", a.checkSolidityQuality
+	case strings.HasSuffix(file, ".cs"):
+		return "csharp", a.checkCSharpQuality
+	case strings.HasSuffix(file, ".ipynb"):
+		return "notebook", a.checkNotebookQuality
+	case strings.HasSuffix(file, ".html"), strings.HasSuffix(file, ".erb"), strings.HasSuffix(file, ".ejs"),
+		strings.HasSuffix(file, ".jinja"), strings.HasSuffix(file, ".j2"), strings.HasSuffix(file, ".hbs"),
+		strings.HasSuffix(file, ".handlebars"), strings.HasSuffix(file, ".mustache"), strings.HasSuffix(file, ".twig"):
+		return "template", a.checkTemplateQuality
+	case strings.HasSuffix(file, ".tf"), strings.HasSuffix(file, ".tfvars"):
+		return "terraform", a.checkTerraformQuality
+	case isWorkflowFile(file):
+		// Workflows are matched by directory, so check them before other YAML
+		return "github-actions", a.checkWorkflowQuality
+	case isConfigFile(file) && securitySkipReason(file) == "":
+		// Lockfiles and generated files are not hand-written config
+		return "config", a.checkConfigQuality
 	}
+	return "", nil
 }
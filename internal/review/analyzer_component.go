@@ -0,0 +1,64 @@
+package review
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	// <script> blocks, capturing their attributes and contents
+	componentScriptPattern = regexp.MustCompile(`(?is)<script\b([^>]*)>(.*?)</script\s*>`)
+	// <style> blocks, which hold CSS rather than markup
+	componentStylePattern = regexp.MustCompile(`(?is)<style\b[^>]*>.*?</style\s*>`)
+	// lang="ts" or lang="tsx" on a <script> block
+	componentTypeScriptLangPattern = regexp.MustCompile(`(?i)\blang\s*=\s*["']tsx?["']`)
+)
+
+// componentScript returns the <script> blocks of a Vue or Svelte component with
+// everything else blanked out, keeping newlines so line numbers still match the
+// component file, and whether any block is TypeScript
+func componentScript(content string) (string, bool) {
+	var script strings.Builder
+	typeScript := false
+	last := 0
+	for _, m := range componentScriptPattern.FindAllStringSubmatchIndex(content, -1) {
+		script.WriteString(strings.Repeat("\n", strings.Count(content[last:m[4]], "\n")))
+		script.WriteString(content[m[4]:m[5]])
+		if componentTypeScriptLangPattern.MatchString(content[m[2]:m[3]]) {
+			typeScript = true
+		}
+		last = m[5]
+	}
+	script.WriteString(strings.Repeat("\n", strings.Count(content[last:], "\n")))
+	return script.String(), typeScript
+}
+
+// componentMarkup returns a component with its <script> and <style> blocks
+// blanked out, keeping newlines so line numbers still match the component file
+func componentMarkup(content string) string {
+	blank := func(block string) string { return strings.Repeat("\n", strings.Count(block, "\n")) }
+	content = componentScriptPattern.ReplaceAllStringFunc(content, blank)
+	return componentStylePattern.ReplaceAllStringFunc(content, blank)
+}
+
+// checkComponentScript runs the JavaScript or TypeScript checks on the <script>
+// blocks of a component. The script checks pick their rules by extension, so
+// they run under a name with the script's extension and the issues are moved
+// back to the component file.
+func (a *Analyzer) checkComponentScript(file string, content string, report *Report) {
+	script, typeScript := componentScript(content)
+	scriptReport := NewReport()
+	if typeScript {
+		a.checkTypeScriptSource(file+".ts", script, scriptReport)
+	} else {
+		a.checkJavaScriptSource(file+".js", script, scriptReport)
+	}
+	for _, issue := range scriptReport.Issues {
+		// Component scripts are always compiled as ES modules
+		if issue.RuleID == "use-strict" {
+			continue
+		}
+		issue.File = file
+		report.AddIssue(issue)
+	}
+}
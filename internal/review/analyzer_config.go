@@ -0,0 +1,87 @@
+package review
+
+import (
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+var (
+	// Plain-HTTP URLs and their host
+	insecureURLPattern = regexp.MustCompile(`http://([^\s"'/:?#,}\]]+)`)
+	// Keys whose http:// values are identifiers rather than endpoints (JSON schema, XML namespaces)
+	configURLIdentifierPattern = regexp.MustCompile(`(?i)["']?(\$schema|\$id|xmlns(:\w+)?|namespace)["']?\s*[:=]`)
+)
+
+// isConfigFile reports whether file is a JSON, YAML, Java properties, TOML, INI or dotenv config file
+func isConfigFile(file string) bool {
+	base := strings.ToLower(filepath.Base(file))
+	if base == ".env" || strings.HasPrefix(base, ".env.") {
+		return true
+	}
+	switch filepath.Ext(base) {
+	case ".json", ".yaml", ".yml", ".properties", ".toml", ".ini", ".env":
+		return true
+	}
+	return false
+}
+
+// isLocalHost reports whether host only resolves to the local machine or a test domain
+func isLocalHost(host string) bool {
+	host = strings.ToLower(host)
+	if host == "localhost" || host == "0.0.0.0" || strings.HasPrefix(host, "127.") || strings.HasPrefix(host, "[::1]") {
+		return true
+	}
+	for _, suffix := range []string{".localhost", ".local", ".test", ".internal"} {
+		if strings.HasSuffix(host, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// checkConfigQuality analyzes JSON, YAML, properties, TOML, INI and .env config files for security issues
+func (a *Analyzer) checkConfigQuality(file string, report *Report) {
+	content, err := a.readFile(file)
+	if err != nil {
+		return
+	}
+
+	lines := strings.Split(string(content), "\n")
+	// code holds the lines that are not comments
+	code := make([]string, len(lines))
+
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		// INI files also comment with ;
+		if strings.HasPrefix(trimmed, "#") || strings.HasPrefix(trimmed, "//") || strings.HasPrefix(trimmed, ";") {
+			continue
+		}
+		code[i] = line
+
+		// SECURITY: Check for outbound integrations configured over plain HTTP
+		if configURLIdentifierPattern.MatchString(line) {
+			continue
+		}
+		for _, match := range insecureURLPattern.FindAllStringSubmatch(line, -1) {
+			if isLocalHost(match[1]) {
+				continue
+			}
+			report.AddIssue(Issue{
+				Type:     "security",
+				Severity: "medium",
+				Message:  "Insecure HTTP URL in config - use HTTPS for outbound integrations",
+				File:     file,
+				Line:     i + 1,
+				RuleID:   "insecure-http",
+			})
+			break
+		}
+	}
+
+	a.checkBrokerAuth(file, code, report)
+	a.checkLoggingLevelOff(file, code, report)
+	if isSecretsConfigFile(file) {
+		a.checkConfigSecrets(file, code, report)
+	}
+}
@@ -0,0 +1,129 @@
+package review
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	// String functions that write without a bounds check; snprintf and friends do not match
+	cppUnsafeStringPattern = regexp.MustCompile(`\b(strcpy|strcat|sprintf|vsprintf|gets)\s*\(`)
+	// Calls that run a command through the shell
+	cppShellPattern = regexp.MustCompile(`\b(system|popen)\s*\(`)
+	// Leftover printf and iostream debugging
+	cppDebugOutputPattern = regexp.MustCompile(`(^|[^\w.>])printf\s*\(|fprintf\s*\(\s*stderr\b|\bstd::(cout|cerr)\s*<<`)
+	// Heap allocations and the calls that release them
+	cppAllocPattern = regexp.MustCompile(`\b(malloc|calloc|realloc)\s*\(`)
+	cppFreePattern  = regexp.MustCompile(`\bfree\s*\(`)
+	// rand() and srand() from the C standard library
+	cppRandPattern = regexp.MustCompile(`\b(s?rand)\s*\(`)
+	// Names suggesting a random value is used for security
+	cppSecretContextPattern = regexp.MustCompile(`(?i)token|secret|passw|salt|nonce|session|otp|crypt|\bkey|_key|\biv\b`)
+	// Credentials defined as string literals, e.g. #define API_KEY "abc123"
+	cppDefineCredentialPattern = regexp.MustCompile(`(?i)^\s*#\s*define\s+\w*(password|passwd|secret|api_?key|token|private_?key)\w*\s+"[^"]+"`)
+)
+
+// checkCppQuality analyzes C and C++ files for quality and security issues
+func (a *Analyzer) checkCppQuality(file string, report *Report) {
+	content, err := a.readFile(file)
+	if err != nil {
+		return
+	}
+
+	contentStr := string(content)
+	lines := strings.Split(contentStr, "\n")
+
+	// Allocations are only reported when nothing in the file frees memory
+	freesMemory := cppFreePattern.MatchString(contentStr)
+
+	// Pattern checks see only code, so commented-out code is not reported
+	code := codeLines(lines, LanguageForFile(file))
+
+	for i, raw := range lines {
+		line := code[i]
+
+		// Check for TODO/FIXME comments
+		if rawLower := strings.ToLower(raw); strings.Contains(rawLower, "todo") || strings.Contains(rawLower, "fixme") {
+			report.AddIssue(Issue{
+				Type:     "quality",
+				Severity: "info",
+				Message:  "TODO/FIXME comment found",
+				File:     file,
+				Line:     i + 1,
+				RuleID:   "todo-comment",
+			})
+		}
+
+		// Check for printf debugging
+		if cppDebugOutputPattern.MatchString(line) {
+			report.AddIssue(Issue{
+				Type:     "quality",
+				Severity: "low",
+				Message:  "Debug output (printf/std::cout) found - use a logging library or remove before production",
+				File:     file,
+				Line:     i + 1,
+				RuleID:   "debug-output",
+			})
+		}
+
+		// Check for allocations that are never freed
+		if !freesMemory && cppAllocPattern.MatchString(line) {
+			report.AddIssue(Issue{
+				Type:     "quality",
+				Severity: "medium",
+				Message:  "Memory allocated but never freed in this file - potential memory leak (heuristic)",
+				File:     file,
+				Line:     i + 1,
+				RuleID:   "malloc-without-free",
+			})
+		}
+
+		// SECURITY: Check for unbounded string functions
+		if m := cppUnsafeStringPattern.FindStringSubmatch(line); m != nil {
+			report.AddIssue(Issue{
+				Type:     "security",
+				Severity: "high",
+				Message:  m[1] + "() does not check buffer bounds - potential buffer overflow, use strncpy/strncat/snprintf/fgets",
+				File:     file,
+				Line:     i + 1,
+				RuleID:   "unsafe-string-function",
+			})
+		}
+
+		// SECURITY: Check for commands run through the shell
+		if m := cppShellPattern.FindStringSubmatch(line); m != nil {
+			report.AddIssue(Issue{
+				Type:     "security",
+				Severity: "medium",
+				Message:  m[1] + "() runs a shell command - ensure input is sanitized to prevent command injection, or use execve",
+				File:     file,
+				Line:     i + 1,
+				RuleID:   "command-injection",
+			})
+		}
+
+		// SECURITY: Check for rand() used for secrets
+		if cppRandPattern.MatchString(line) && cppSecretContextPattern.MatchString(line) {
+			report.AddIssue(Issue{
+				Type:     "security",
+				Severity: "medium",
+				Message:  "rand() is not cryptographically secure - use getrandom(), arc4random() or a CSPRNG for security-sensitive values",
+				File:     file,
+				Line:     i + 1,
+				RuleID:   "insecure-random",
+			})
+		}
+
+		// SECURITY: Check for credentials in #define macros
+		if cppDefineCredentialPattern.MatchString(line) {
+			report.AddIssue(Issue{
+				Type:     "security",
+				Severity: "high",
+				Message:  "Potential hardcoded credential in #define - load it from configuration or the environment",
+				File:     file,
+				Line:     i + 1,
+				RuleID:   "hardcoded-credential",
+			})
+		}
+	}
+}
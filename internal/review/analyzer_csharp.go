@@ -0,0 +1,34 @@
+package review
+
+import "strings"
+
+// checkCSharpQuality analyzes C# files for XML parsers that resolve external
+// entities, unsafe deserializers and TODO/FIXME comments
+func (a *Analyzer) checkCSharpQuality(file string, report *Report) {
+	content, err := a.readFile(file)
+	if err != nil {
+		return
+	}
+
+	lines := strings.Split(string(content), "\n")
+
+	// Pattern checks see only code, so commented-out code is not reported
+	code := codeLines(lines, LanguageForFile(file))
+
+	for i, raw := range lines {
+		// Check for TODO/FIXME comments
+		if rawLower := strings.ToLower(raw); strings.Contains(rawLower, "todo") || strings.Contains(rawLower, "fixme") {
+			report.AddIssue(Issue{
+				Type:     "quality",
+				Severity: "info",
+				Message:  "TODO/FIXME comment found",
+				File:     file,
+				Line:     i + 1,
+				RuleID:   "todo-comment",
+			})
+		}
+	}
+
+	a.checkInsecureXML(file, code, report)
+	a.checkUnsafeDeserialization(file, code, report)
+}
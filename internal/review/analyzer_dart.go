@@ -1,15 +1,13 @@
 package review
 
 import (
-	"os"
-	"path/filepath"
+	"fmt"
 	"strings"
 )
 
 // checkDartQuality analyzes Dart files for quality and security issues
 func (a *Analyzer) checkDartQuality(file string, report *Report) {
-	filePath := filepath.Join(a.repoPath, file)
-	content, err := os.ReadFile(filePath)
+	content, err := a.readFile(file)
 	if err != nil {
 		return
 	}
@@ -17,17 +15,23 @@ func (a *Analyzer) checkDartQuality(file string, report *Report) {
 	contentStr := string(content)
 	lines := strings.Split(contentStr, "\n")
 
-	for i, line := range lines {
+	// Pattern checks see only code, so commented-out code is not reported
+	code := codeLines(lines, LanguageForFile(file))
+	lineLimit := a.lineLengthLimitFor(file)
+
+	for i, raw := range lines {
+		line := code[i]
 		lineLower := strings.ToLower(line)
 
 		// Line length check (Dart style guide recommends 80, but 120 is common)
-		if len(line) > 120 {
+		if len(raw) > lineLimit {
 			report.AddIssue(Issue{
 				Type:     "quality",
-				Severity: "low",
-				Message:  "Line too long (>120 characters)",
+				Severity: "info",
+				Message:  fmt.Sprintf("Line too long (>%d characters)", lineLimit),
 				File:     file,
 				Line:     i + 1,
+				RuleID:   "line-length",
 			})
 		}
 
@@ -39,6 +43,7 @@ func (a *Analyzer) checkDartQuality(file string, report *Report) {
 				Message:  "print() statement found - remove before production",
 				File:     file,
 				Line:     i + 1,
+				RuleID:   "print-statement",
 			})
 		}
 
@@ -50,17 +55,19 @@ func (a *Analyzer) checkDartQuality(file string, report *Report) {
 				Message:  "debugPrint() statement found - remove before production",
 				File:     file,
 				Line:     i + 1,
+				RuleID:   "debug-print",
 			})
 		}
 
 		// Check for TODO/FIXME comments
-		if strings.Contains(lineLower, "todo") || strings.Contains(lineLower, "fixme") {
+		if rawLower := strings.ToLower(raw); strings.Contains(rawLower, "todo") || strings.Contains(rawLower, "fixme") {
 			report.AddIssue(Issue{
 				Type:     "quality",
-				Severity: "low",
+				Severity: "info",
 				Message:  "TODO/FIXME comment found",
 				File:     file,
 				Line:     i + 1,
+				RuleID:   "todo-comment",
 			})
 		}
 
@@ -72,17 +79,19 @@ func (a *Analyzer) checkDartQuality(file string, report *Report) {
 				Message:  "Avoid using 'dynamic' type - use specific types instead",
 				File:     file,
 				Line:     i + 1,
+				RuleID:   "dynamic-type",
 			})
 		}
 
 		// Check for ignore directives
-		if strings.Contains(line, "// ignore:") || strings.Contains(line, "// ignore_for_file:") {
+		if strings.Contains(raw, "// ignore:") || strings.Contains(raw, "// ignore_for_file:") {
 			report.AddIssue(Issue{
 				Type:     "quality",
 				Severity: "medium",
 				Message:  "Dart ignore directive found - consider fixing the issue",
 				File:     file,
 				Line:     i + 1,
+				RuleID:   "ignore-directive",
 			})
 		}
 
@@ -94,6 +103,7 @@ func (a *Analyzer) checkDartQuality(file string, report *Report) {
 				Message:  "Hardcoded API URL - consider using environment configuration",
 				File:     file,
 				Line:     i + 1,
+				RuleID:   "hardcoded-api-url",
 			})
 		}
 
@@ -106,6 +116,7 @@ func (a *Analyzer) checkDartQuality(file string, report *Report) {
 					Message:  "Potential hardcoded credential - use secure storage",
 					File:     file,
 					Line:     i + 1,
+					RuleID:   "hardcoded-credential",
 				})
 			}
 		}
@@ -118,17 +129,19 @@ func (a *Analyzer) checkDartQuality(file string, report *Report) {
 				Message:  "Insecure HTTP URL - use HTTPS for production",
 				File:     file,
 				Line:     i + 1,
+				RuleID:   "insecure-http",
 			})
 		}
 
 		// SECURITY: Check for disabled SSL certificate verification
-		if strings.Contains(line, "badCertificateCallback") {
+		if strings.Contains(line, "badCertificateCallback") && !dartTrustAllCallbackPattern.MatchString(line) {
 			report.AddIssue(Issue{
 				Type:     "security",
 				Severity: "high",
 				Message:  "Custom certificate callback - ensure SSL verification is not disabled",
 				File:     file,
 				Line:     i + 1,
+				RuleID:   "certificate-callback",
 			})
 		}
 
@@ -142,9 +155,11 @@ func (a *Analyzer) checkDartQuality(file string, report *Report) {
 					Message:  "Force unwrap (!) used - consider null safety patterns",
 					File:     file,
 					Line:     i + 1,
+					RuleID:   "force-unwrap",
 				})
 			}
 		}
 	}
-}
 
+	a.checkTrustAllCertificates(file, code, report)
+}
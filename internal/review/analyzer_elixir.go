@@ -0,0 +1,131 @@
+package review
+
+import (
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+var (
+	// IO.inspect and IO.puts left over from debugging, called directly or piped into
+	elixirDebugPattern = regexp.MustCompile(`\bIO\.(inspect|puts)\b`)
+	// Evaluating strings or quoted expressions at runtime
+	elixirEvalPattern = regexp.MustCompile(`\bCode\.(eval_string|eval_quoted|eval_file)\b`)
+	// Shell commands with interpolated arguments
+	elixirCommandPattern = regexp.MustCompile(`(:os\.cmd|\bSystem\.(cmd|shell))\b.*#\{`)
+	// Atoms are never garbage collected, so atoms built from input exhaust the atom table
+	elixirToAtomPattern = regexp.MustCompile(`\b(String|List)\.to_atom\b`)
+	// Ecto fragments and raw queries built with string interpolation
+	elixirSQLInterpolationPattern = regexp.MustCompile(`\b(fragment|query!?)\s*\(\s*(\w+(\.\w+)*\s*,\s*)?"[^"]*#\{`)
+	// Secret-looking config keys set to a string literal
+	elixirConfigSecretPattern = regexp.MustCompile(`(?i)\b\w*(secret|password|api_key|token|signing_salt|private_key)\w*:\s*"([^"#]{8,})"`)
+)
+
+// isElixirConfig reports whether file is a Mix config script, e.g. config/prod.exs
+func isElixirConfig(file string) bool {
+	file = filepath.ToSlash(file)
+	return strings.HasSuffix(file, ".exs") && (strings.HasPrefix(file, "config/") || strings.Contains(file, "/config/"))
+}
+
+// checkElixirQuality analyzes Elixir files for quality and security issues
+func (a *Analyzer) checkElixirQuality(file string, report *Report) {
+	content, err := a.readFile(file)
+	if err != nil {
+		return
+	}
+
+	contentStr := string(content)
+	lines := strings.Split(contentStr, "\n")
+	isConfig := isElixirConfig(file)
+
+	// Pattern checks see only code, so commented-out code is not reported
+	code := codeLines(lines, LanguageForFile(file))
+
+	for i, raw := range lines {
+		line := code[i]
+
+		// Check for TODO/FIXME comments
+		if rawLower := strings.ToLower(raw); strings.Contains(rawLower, "todo") || strings.Contains(rawLower, "fixme") {
+			report.AddIssue(Issue{
+				Type:     "quality",
+				Severity: "info",
+				Message:  "TODO/FIXME comment found",
+				File:     file,
+				Line:     i + 1,
+				RuleID:   "todo-comment",
+			})
+		}
+
+		// Check for IO.inspect/IO.puts debugging
+		if elixirDebugPattern.MatchString(line) {
+			report.AddIssue(Issue{
+				Type:     "quality",
+				Severity: "low",
+				Message:  "IO.inspect/IO.puts found - remove debug output or use Logger",
+				File:     file,
+				Line:     i + 1,
+				RuleID:   "debug-output",
+			})
+		}
+
+		// SECURITY: Check for runtime code evaluation
+		if elixirEvalPattern.MatchString(line) {
+			report.AddIssue(Issue{
+				Type:     "security",
+				Severity: "high",
+				Message:  "Code.eval_* executes arbitrary code - never evaluate input",
+				File:     file,
+				Line:     i + 1,
+				RuleID:   "eval",
+			})
+		}
+
+		// SECURITY: Check for shell commands built with interpolation
+		if elixirCommandPattern.MatchString(line) {
+			report.AddIssue(Issue{
+				Type:     "security",
+				Severity: "high",
+				Message:  "Shell command built with #{} interpolation - potential command injection, pass arguments as a list to System.cmd",
+				File:     file,
+				Line:     i + 1,
+				RuleID:   "command-injection",
+			})
+		}
+
+		// SECURITY: Check for atoms created at runtime
+		if elixirToAtomPattern.MatchString(line) {
+			report.AddIssue(Issue{
+				Type:     "security",
+				Severity: "medium",
+				Message:  "to_atom on input can exhaust the atom table and crash the VM - use String.to_existing_atom",
+				File:     file,
+				Line:     i + 1,
+				RuleID:   "atom-exhaustion",
+			})
+		}
+
+		// SECURITY: Check for SQL built with string interpolation
+		if elixirSQLInterpolationPattern.MatchString(line) {
+			report.AddIssue(Issue{
+				Type:     "security",
+				Severity: "high",
+				Message:  "Potential SQL injection - #{} interpolated into an Ecto fragment or raw query, use ? placeholders",
+				File:     file,
+				Line:     i + 1,
+				RuleID:   "sql-injection",
+			})
+		}
+
+		// SECURITY: Check for secrets hardcoded in Mix config
+		if isConfig && elixirConfigSecretPattern.MatchString(line) {
+			report.AddIssue(Issue{
+				Type:     "security",
+				Severity: "high",
+				Message:  "Potential hardcoded secret in config - read it with System.fetch_env! in config/runtime.exs",
+				File:     file,
+				Line:     i + 1,
+				RuleID:   "hardcoded-secret",
+			})
+		}
+	}
+}
@@ -0,0 +1,25 @@
+package review
+
+import (
+	"strings"
+)
+
+// checkGoQuality analyzes Go files for security issues
+func (a *Analyzer) checkGoQuality(file string, report *Report) {
+	content, err := a.readFile(file)
+	if err != nil {
+		return
+	}
+
+	contentStr := string(content)
+	lines := strings.Split(contentStr, "\n")
+
+	// Pattern checks see only code, so commented-out code is not reported
+	code := codeLines(lines, LanguageForFile(file))
+	a.checkInsecureTransport(file, code, report)
+	a.checkBrokerAuth(file, code, report)
+	a.checkCloudIdentifiers(file, code, report)
+	a.checkInputLimits(file, contentStr, code, report)
+	a.checkHardcodedSalts(file, code, report)
+	a.checkCommandLineSecrets(file, code, report)
+}
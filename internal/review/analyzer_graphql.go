@@ -0,0 +1,178 @@
+package review
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var (
+	// A type definition or extension header, capturing extend, the kind and the name
+	graphqlTypePattern = regexp.MustCompile(`^\s*(extend\s+)?(type|interface|input|enum|union|scalar)\s+(\w+)`)
+	// A field definition, capturing its name and return type, e.g. createUser(input: NewUser!): User!
+	graphqlFieldPattern = regexp.MustCompile(`^\s*(\w+)\s*(?:\([^)]*\))?\s*:\s*([\w\[\]!\s]+)`)
+	// A field whose arguments continue on the next lines, capturing its name
+	graphqlFieldArgsPattern = regexp.MustCompile(`^\s*(\w+)\s*\([^)]*$`)
+	// The end of a multi-line argument list, capturing the return type
+	graphqlArgsEndPattern = regexp.MustCompile(`^[^)]*\)\s*:\s*([\w\[\]!\s]+)`)
+	// The root mutation type set in a schema definition
+	graphqlSchemaMutationPattern = regexp.MustCompile(`\bmutation\s*:\s*(\w+)`)
+	// @deprecated without arguments or with an empty reason
+	graphqlDeprecatedPattern = regexp.MustCompile(`@deprecated\b(\s*\(\s*reason\s*:\s*""\s*\))?`)
+	// Field names for credentials; pagination tokens are not secrets
+	graphqlSensitiveFieldPattern = regexp.MustCompile(`(?i)(password|passwd|secret|token)(hash|digest)?$`)
+	graphqlPageTokenPattern      = regexp.MustCompile(`(?i)(page|cursor|continuation)token$`)
+)
+
+// graphqlMutationRoot returns the name of the schema's mutation type, which is
+// Mutation unless a schema definition names another
+func graphqlMutationRoot(code []string) string {
+	inSchema := false
+	for _, line := range code {
+		if strings.HasPrefix(strings.TrimSpace(line), "schema") && strings.Contains(line, "{") {
+			inSchema = true
+		}
+		if inSchema {
+			if m := graphqlSchemaMutationPattern.FindStringSubmatch(line); m != nil {
+				return m[1]
+			}
+			if strings.Contains(line, "}") {
+				inSchema = false
+			}
+		}
+	}
+	return "Mutation"
+}
+
+// checkGraphQLQuality analyzes GraphQL schemas: mutations that report errors as
+// a plain String, credential fields exposed on object types, types without a
+// description and deprecations without a reason.
+func (a *Analyzer) checkGraphQLQuality(file string, report *Report) {
+	content, err := a.readFile(file)
+	if err != nil {
+		return
+	}
+
+	lines := strings.Split(string(content), "\n")
+
+	// Pattern checks see only code, so commented-out fields are not reported
+	code := codeLines(lines, LanguageForFile(file))
+	mutationRoot := graphqlMutationRoot(code)
+
+	// kind and name are those of the type whose fields are being read, or "" between types
+	var kind, name string
+	// described is set when the previous definition line ended a description string
+	described := false
+	inDescription := false
+	// argsField is the field whose multi-line arguments are being read, and argsLine its index
+	argsField, argsLine := "", 0
+
+	// checkField reports the issues of a field with its return type
+	checkField := func(field, returnType string, line int) {
+		// SECURITY: Check for credentials exposed on object types; mutations such
+		// as resetPassword are operations, not data
+		if (kind == "type" || kind == "interface") && name != mutationRoot && graphqlSensitiveFieldPattern.MatchString(field) && !graphqlPageTokenPattern.MatchString(field) {
+			report.AddIssue(Issue{
+				Type:     "security",
+				Severity: "high",
+				Message:  fmt.Sprintf("Field %s.%s exposes a credential - any client that can query %s can read it, remove the field or return only whether it is set", name, field, name),
+				File:     file,
+				Line:     line + 1,
+				RuleID:   "graphql-sensitive-field",
+			})
+		}
+
+		// Check for mutations that return errors as a String
+		if kind == "type" && name == mutationRoot && strings.Trim(returnType, "[]! \t") == "String" {
+			report.AddIssue(Issue{
+				Type:     "quality",
+				Severity: "medium",
+				Message:  fmt.Sprintf("Mutation %s returns String - clients cannot tell a result from an error message, return a payload type with the result and typed errors", field),
+				File:     file,
+				Line:     line + 1,
+				RuleID:   "graphql-mutation-string-result",
+			})
+		}
+	}
+
+	for i, raw := range lines {
+		// Check for TODO/FIXME comments
+		if rawLower := strings.ToLower(raw); strings.Contains(rawLower, "todo") || strings.Contains(rawLower, "fixme") {
+			report.AddIssue(Issue{
+				Type:     "quality",
+				Severity: "info",
+				Message:  "TODO/FIXME comment found",
+				File:     file,
+				Line:     i + 1,
+				RuleID:   "todo-comment",
+			})
+		}
+
+		// Descriptions are strings before a definition; block strings span lines
+		trimmed := strings.TrimSpace(raw)
+		if n := strings.Count(trimmed, `"""`); inDescription || n > 0 {
+			if n%2 == 1 {
+				inDescription = !inDescription
+			}
+			described = !inDescription
+			continue
+		}
+
+		line := strings.TrimSpace(code[i])
+		if line == "" {
+			continue
+		}
+
+		// A single-line description
+		if strings.HasPrefix(line, `"`) {
+			described = true
+			continue
+		}
+
+		// Check for deprecations without a reason
+		if m := graphqlDeprecatedPattern.FindStringSubmatchIndex(line); m != nil && (m[2] >= 0 || !strings.HasPrefix(strings.TrimSpace(line[m[1]:]), "(")) {
+			report.AddIssue(Issue{
+				Type:     "quality",
+				Severity: "low",
+				Message:  "@deprecated without a reason - say what to use instead with @deprecated(reason: \"Use ... instead\")",
+				File:     file,
+				Line:     i + 1,
+				RuleID:   "graphql-deprecated-no-reason",
+			})
+		}
+
+		switch m := graphqlTypePattern.FindStringSubmatch(line); {
+		case argsField != "":
+			if end := graphqlArgsEndPattern.FindStringSubmatch(line); end != nil {
+				checkField(argsField, end[1], argsLine)
+				argsField = ""
+			}
+		case m != nil:
+			// Check for public types without a description
+			if m[1] == "" && !described && !strings.HasPrefix(m[3], "__") {
+				report.AddIssue(Issue{
+					Type:     "quality",
+					Severity: "low",
+					Message:  fmt.Sprintf("%s %s has no description - add a \"\"\"docstring\"\"\" above it so it is documented in the schema", strings.ToUpper(m[2][:1])+m[2][1:], m[3]),
+					File:     file,
+					Line:     i + 1,
+					RuleID:   "graphql-missing-description",
+				})
+			}
+			if m[2] != "union" && m[2] != "scalar" {
+				kind, name = m[2], m[3]
+			}
+		case kind != "":
+			if m := graphqlFieldArgsPattern.FindStringSubmatch(line); m != nil {
+				argsField, argsLine = m[1], i
+			} else if m := graphqlFieldPattern.FindStringSubmatch(line); m != nil {
+				checkField(m[1], m[2], i)
+			}
+		}
+
+		if strings.Contains(line, "}") && argsField == "" {
+			kind, name = "", ""
+		}
+		described = false
+	}
+}
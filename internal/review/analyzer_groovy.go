@@ -0,0 +1,174 @@
+package review
+
+import (
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+var (
+	// sh, bat and powershell pipeline steps given a double-quoted GString
+	groovyShellStepPattern = regexp.MustCompile(`\b(sh|bat|powershell)\s*\(?\s*(script\s*:\s*)?"`)
+	// Build parameters interpolated into a GString, e.g. ${params.BRANCH} or $params.BRANCH
+	groovyParamsInterpolationPattern = regexp.MustCompile(`\$\{?\s*params\.`)
+	// Evaluating strings as Groovy code at runtime
+	groovyEvalPattern = regexp.MustCompile(`\bEval\.(me|x|xy|xyz)\s*\(|\bnew\s+GroovyShell\b|\bGroovyShell\s*\([^)]*\)\s*\.\s*evaluate\s*\(`)
+	// println debugging
+	groovyPrintlnPattern = regexp.MustCompile(`(^|[^\w.])println\b`)
+	// The start of a pipeline environment block
+	groovyEnvironmentPattern = regexp.MustCompile(`^\s*environment\s*\{`)
+	// Secret-looking variables set to a literal; credentials('id') lookups do not match
+	groovyEnvCredentialPattern = regexp.MustCompile(`(?i)^\s*\w*(password|passwd|secret|token|api_?key|access_?key|credential)\w*\s*=\s*["']([^"'$]{4,})["']`)
+	// @Grab annotations, capturing their arguments
+	groovyGrabPattern = regexp.MustCompile(`@Grab\s*\(([^)]*)\)`)
+	// The version of a @Grab written with named arguments
+	groovyGrabVersionPattern = regexp.MustCompile(`\bversion\s*=\s*["']([^"']*)["']`)
+	// The coordinates of a @Grab written as 'group:module:version'
+	groovyGrabCoordinatesPattern = regexp.MustCompile(`^\s*(value\s*=\s*)?["']([^"']+)["']`)
+)
+
+// isJenkinsfile reports whether file is a Jenkins pipeline, which is recognized by
+// its name rather than its extension, e.g. Jenkinsfile or Jenkinsfile.release
+func isJenkinsfile(file string) bool {
+	base := strings.ToLower(filepath.Base(file))
+	return base == "jenkinsfile" || strings.HasPrefix(base, "jenkinsfile.")
+}
+
+// unpinnedGrabVersion reports whether a @Grab's arguments leave the version open:
+// missing, a wildcard, a range, latest.* or a SNAPSHOT
+func unpinnedGrabVersion(args string) bool {
+	version := ""
+	if m := groovyGrabVersionPattern.FindStringSubmatch(args); m != nil {
+		version = m[1]
+	} else if m := groovyGrabCoordinatesPattern.FindStringSubmatch(args); m != nil {
+		if parts := strings.Split(m[2], ":"); len(parts) >= 3 {
+			version = parts[2]
+		}
+	}
+	version = strings.TrimSpace(version)
+	return version == "" || strings.ContainsAny(version, "*+[](),") ||
+		strings.HasPrefix(version, "latest.") || strings.HasSuffix(version, "-SNAPSHOT")
+}
+
+// checkGroovyQuality analyzes Groovy, Gradle and Jenkinsfile scripts for quality and security issues
+func (a *Analyzer) checkGroovyQuality(file string, report *Report) {
+	content, err := a.readFile(file)
+	if err != nil {
+		return
+	}
+
+	contentStr := string(content)
+	lines := strings.Split(contentStr, "\n")
+
+	// Pattern checks see only code, so commented-out code is not reported
+	code := codeLines(lines, LanguageForFile(file))
+
+	// inShellScript is set inside a sh """...""" step spanning several lines
+	inShellScript := false
+	// envDepth is the brace depth of the environment block being read, or 0
+	envDepth, depth := 0, 0
+
+	for i, raw := range lines {
+		line := code[i]
+
+		// Check for TODO/FIXME comments
+		if rawLower := strings.ToLower(raw); strings.Contains(rawLower, "todo") || strings.Contains(rawLower, "fixme") {
+			report.AddIssue(Issue{
+				Type:     "quality",
+				Severity: "info",
+				Message:  "TODO/FIXME comment found",
+				File:     file,
+				Line:     i + 1,
+				RuleID:   "todo-comment",
+			})
+		}
+
+		// Multi-line scripts are matched on the raw line, as their contents are not Groovy
+		interpolated := false
+		switch {
+		case inShellScript:
+			interpolated = groovyParamsInterpolationPattern.MatchString(raw)
+			inShellScript = !strings.Contains(raw, `"""`)
+		case groovyShellStepPattern.MatchString(line):
+			_, script, _ := strings.Cut(raw, `"`)
+			interpolated = groovyParamsInterpolationPattern.MatchString(script)
+			if rest, ok := strings.CutPrefix(script, `""`); ok && !strings.Contains(rest, `"""`) {
+				inShellScript = true
+			}
+		}
+
+		// SECURITY: Check for build parameters interpolated into shell steps
+		if interpolated {
+			report.AddIssue(Issue{
+				Type:     "security",
+				Severity: "high",
+				Message:  "Build parameter interpolated into a shell step with a GString - potential command injection, pass it through withEnv and reference it as a shell variable in single quotes",
+				File:     file,
+				Line:     i + 1,
+				RuleID:   "command-injection",
+			})
+		}
+		if inShellScript {
+			continue
+		}
+
+		// SECURITY: Check for runtime code evaluation
+		if groovyEvalPattern.MatchString(line) {
+			report.AddIssue(Issue{
+				Type:     "security",
+				Severity: "high",
+				Message:  "Eval.me/GroovyShell.evaluate executes arbitrary code - never evaluate input",
+				File:     file,
+				Line:     i + 1,
+				RuleID:   "eval",
+			})
+		}
+
+		// Check for println debugging
+		if groovyPrintlnPattern.MatchString(line) {
+			report.AddIssue(Issue{
+				Type:     "quality",
+				Severity: "low",
+				Message:  "println found - use echo in pipelines or a logger in build scripts",
+				File:     file,
+				Line:     i + 1,
+				RuleID:   "println",
+			})
+		}
+
+		// SECURITY: Check for credentials hardcoded in environment blocks
+		if envDepth > 0 && groovyEnvCredentialPattern.MatchString(line) {
+			report.AddIssue(Issue{
+				Type:     "security",
+				Severity: "high",
+				Message:  "Hardcoded credential in an environment block - store it in Jenkins and bind it with credentials('id')",
+				File:     file,
+				Line:     i + 1,
+				RuleID:   "hardcoded-credential",
+			})
+		}
+
+		// SECURITY: Check for @Grab dependencies without a fixed version
+		for _, m := range groovyGrabPattern.FindAllStringSubmatch(line, -1) {
+			if unpinnedGrabVersion(m[1]) {
+				report.AddIssue(Issue{
+					Type:     "security",
+					Severity: "medium",
+					Message:  "@Grab without a pinned version - every run may fetch different code, pin an exact release",
+					File:     file,
+					Line:     i + 1,
+					RuleID:   "unpinned-dependency",
+				})
+				break
+			}
+		}
+
+		if envDepth == 0 && groovyEnvironmentPattern.MatchString(line) {
+			envDepth = depth + 1
+		}
+		depth += strings.Count(line, "{") - strings.Count(line, "}")
+		if envDepth > 0 && depth < envDepth {
+			envDepth = 0
+		}
+	}
+}
@@ -1,15 +1,13 @@
 package review
 
 import (
-	"os"
-	"path/filepath"
+	"fmt"
 	"strings"
 )
 
 // checkJavaKotlinQuality analyzes Java and Kotlin files for quality and security issues
 func (a *Analyzer) checkJavaKotlinQuality(file string, report *Report) {
-	filePath := filepath.Join(a.repoPath, file)
-	content, err := os.ReadFile(filePath)
+	content, err := a.readFile(file)
 	if err != nil {
 		return
 	}
@@ -18,18 +16,24 @@ func (a *Analyzer) checkJavaKotlinQuality(file string, report *Report) {
 	lines := strings.Split(contentStr, "\n")
 	isKotlin := strings.HasSuffix(file, ".kt")
 
-	for i, line := range lines {
+	// Pattern checks see only code, so commented-out code is not reported
+	code := codeLines(lines, LanguageForFile(file))
+	lineLimit := a.lineLengthLimitFor(file)
+
+	for i, raw := range lines {
+		line := code[i]
 		lineLower := strings.ToLower(line)
 		trimmed := strings.TrimSpace(line)
 
 		// Line length check
-		if len(line) > 120 {
+		if len(raw) > lineLimit {
 			report.AddIssue(Issue{
 				Type:     "quality",
-				Severity: "low",
-				Message:  "Line too long (>120 characters)",
+				Severity: "info",
+				Message:  fmt.Sprintf("Line too long (>%d characters)", lineLimit),
 				File:     file,
 				Line:     i + 1,
+				RuleID:   "line-length",
 			})
 		}
 
@@ -41,6 +45,7 @@ func (a *Analyzer) checkJavaKotlinQuality(file string, report *Report) {
 				Message:  "System.out.println found - use proper logging instead",
 				File:     file,
 				Line:     i + 1,
+				RuleID:   "system-out-println",
 			})
 		}
 
@@ -52,17 +57,19 @@ func (a *Analyzer) checkJavaKotlinQuality(file string, report *Report) {
 				Message:  "printStackTrace() found - use proper logging instead",
 				File:     file,
 				Line:     i + 1,
+				RuleID:   "print-stack-trace",
 			})
 		}
 
 		// Check for TODO/FIXME comments
-		if strings.Contains(lineLower, "todo") || strings.Contains(lineLower, "fixme") {
+		if rawLower := strings.ToLower(raw); strings.Contains(rawLower, "todo") || strings.Contains(rawLower, "fixme") {
 			report.AddIssue(Issue{
 				Type:     "quality",
-				Severity: "low",
+				Severity: "info",
 				Message:  "TODO/FIXME comment found",
 				File:     file,
 				Line:     i + 1,
+				RuleID:   "todo-comment",
 			})
 		}
 
@@ -78,6 +85,7 @@ func (a *Analyzer) checkJavaKotlinQuality(file string, report *Report) {
 						Message:  "Empty catch block - handle or log the exception",
 						File:     file,
 						Line:     i + 1,
+						RuleID:   "empty-catch",
 					})
 				}
 			}
@@ -91,6 +99,7 @@ func (a *Analyzer) checkJavaKotlinQuality(file string, report *Report) {
 				Message:  "Process execution detected - ensure input is sanitized",
 				File:     file,
 				Line:     i + 1,
+				RuleID:   "process-exec",
 			})
 		}
 
@@ -103,6 +112,7 @@ func (a *Analyzer) checkJavaKotlinQuality(file string, report *Report) {
 					Message:  "Potential SQL injection - use PreparedStatement with parameterized queries",
 					File:     file,
 					Line:     i + 1,
+					RuleID:   "sql-injection",
 				})
 			}
 		}
@@ -115,6 +125,7 @@ func (a *Analyzer) checkJavaKotlinQuality(file string, report *Report) {
 				Message:  "Potential hardcoded password - use secure configuration",
 				File:     file,
 				Line:     i + 1,
+				RuleID:   "hardcoded-password",
 			})
 		}
 
@@ -126,6 +137,7 @@ func (a *Analyzer) checkJavaKotlinQuality(file string, report *Report) {
 				Message:  "Weak cryptographic algorithm - use SHA-256 or stronger",
 				File:     file,
 				Line:     i + 1,
+				RuleID:   "weak-crypto",
 			})
 		}
 
@@ -137,6 +149,7 @@ func (a *Analyzer) checkJavaKotlinQuality(file string, report *Report) {
 				Message:  "SSL verification disabled - vulnerable to man-in-the-middle attacks",
 				File:     file,
 				Line:     i + 1,
+				RuleID:   "ssl-verification-disabled",
 			})
 		}
 
@@ -149,6 +162,7 @@ func (a *Analyzer) checkJavaKotlinQuality(file string, report *Report) {
 					Message:  "XML parser without secure features - potential XXE vulnerability",
 					File:     file,
 					Line:     i + 1,
+					RuleID:   "xxe",
 				})
 			}
 		}
@@ -158,6 +172,14 @@ func (a *Analyzer) checkJavaKotlinQuality(file string, report *Report) {
 			a.checkKotlinSpecific(file, line, i, report)
 		}
 	}
+
+	a.checkHardcodedSalts(file, code, report)
+	a.checkInsecureJWT(file, code, report)
+	a.checkBlockingInAsync(file, code, report)
+	a.checkTrustAllCertificates(file, code, report)
+	a.checkBrokerAuth(file, code, report)
+	a.checkCloudIdentifiers(file, code, report)
+	a.checkAnyHTTPMethod(file, code, report)
 }
 
 // checkKotlinSpecific contains Kotlin-specific quality checks
@@ -170,6 +192,7 @@ func (a *Analyzer) checkKotlinSpecific(file string, line string, lineNum int, re
 			Message:  "Force unwrap (!!) used - consider safe call (?.) or null check",
 			File:     file,
 			Line:     lineNum + 1,
+			RuleID:   "force-unwrap",
 		})
 	}
 
@@ -181,6 +204,7 @@ func (a *Analyzer) checkKotlinSpecific(file string, line string, lineNum int, re
 			Message:  "println() found - use proper logging instead",
 			File:     file,
 			Line:     lineNum + 1,
+			RuleID:   "println",
 		})
 	}
 }
@@ -1,33 +1,41 @@
 package review
 
 import (
-	"os"
-	"path/filepath"
+	"fmt"
 	"strings"
 )
 
 // checkJavaScriptQuality analyzes JavaScript files for quality and security issues
 func (a *Analyzer) checkJavaScriptQuality(file string, report *Report) {
-	filePath := filepath.Join(a.repoPath, file)
-	content, err := os.ReadFile(filePath)
+	content, err := a.readFile(file)
 	if err != nil {
 		return
 	}
 
-	contentStr := string(content)
+	a.checkJavaScriptSource(file, string(content), report)
+}
+
+// checkJavaScriptSource analyzes JavaScript source read from file, which may be a
+// script block extracted from a component file
+func (a *Analyzer) checkJavaScriptSource(file string, contentStr string, report *Report) {
 	lines := strings.Split(contentStr, "\n")
 
-	for i, line := range lines {
-		lineLower := strings.ToLower(line)
+	// Pattern checks see only code, so commented-out code is not reported
+	code := codeLines(lines, LanguageForFile(file))
+	lineLimit := a.lineLengthLimitFor(file)
+
+	for i, raw := range lines {
+		line := code[i]
 
 		// Line length check
-		if len(line) > 120 {
+		if len(raw) > lineLimit {
 			report.AddIssue(Issue{
 				Type:     "quality",
-				Severity: "low",
-				Message:  "Line too long (>120 characters)",
+				Severity: "info",
+				Message:  fmt.Sprintf("Line too long (>%d characters)", lineLimit),
 				File:     file,
 				Line:     i + 1,
+				RuleID:   "line-length",
 			})
 		}
 
@@ -39,6 +47,7 @@ func (a *Analyzer) checkJavaScriptQuality(file string, report *Report) {
 				Message:  "console.log statement found - remove before production",
 				File:     file,
 				Line:     i + 1,
+				RuleID:   "console-log",
 			})
 		}
 
@@ -50,17 +59,19 @@ func (a *Analyzer) checkJavaScriptQuality(file string, report *Report) {
 				Message:  "debugger statement found - remove before production",
 				File:     file,
 				Line:     i + 1,
+				RuleID:   "debugger",
 			})
 		}
 
 		// Check for TODO/FIXME comments
-		if strings.Contains(lineLower, "todo") || strings.Contains(lineLower, "fixme") {
+		if rawLower := strings.ToLower(raw); strings.Contains(rawLower, "todo") || strings.Contains(rawLower, "fixme") {
 			report.AddIssue(Issue{
 				Type:     "quality",
-				Severity: "low",
+				Severity: "info",
 				Message:  "TODO/FIXME comment found",
 				File:     file,
 				Line:     i + 1,
+				RuleID:   "todo-comment",
 			})
 		}
 
@@ -72,6 +83,7 @@ func (a *Analyzer) checkJavaScriptQuality(file string, report *Report) {
 				Message:  "eval() usage detected - potential code injection vulnerability",
 				File:     file,
 				Line:     i + 1,
+				RuleID:   "eval",
 			})
 		}
 
@@ -83,6 +95,7 @@ func (a *Analyzer) checkJavaScriptQuality(file string, report *Report) {
 				Message:  "Function constructor usage - similar risks to eval()",
 				File:     file,
 				Line:     i + 1,
+				RuleID:   "function-constructor",
 			})
 		}
 
@@ -94,6 +107,7 @@ func (a *Analyzer) checkJavaScriptQuality(file string, report *Report) {
 				Message:  "innerHTML usage - potential XSS vulnerability",
 				File:     file,
 				Line:     i + 1,
+				RuleID:   "inner-html",
 			})
 		}
 
@@ -105,6 +119,7 @@ func (a *Analyzer) checkJavaScriptQuality(file string, report *Report) {
 				Message:  "document.write usage - potential XSS vulnerability",
 				File:     file,
 				Line:     i + 1,
+				RuleID:   "document-write",
 			})
 		}
 
@@ -116,6 +131,7 @@ func (a *Analyzer) checkJavaScriptQuality(file string, report *Report) {
 				Message:  "child_process/exec usage - ensure input is sanitized to prevent command injection",
 				File:     file,
 				Line:     i + 1,
+				RuleID:   "child-process",
 			})
 		}
 
@@ -127,6 +143,7 @@ func (a *Analyzer) checkJavaScriptQuality(file string, report *Report) {
 				Message:  "Math.random() is not cryptographically secure - use crypto.randomBytes() for security-sensitive operations",
 				File:     file,
 				Line:     i + 1,
+				RuleID:   "insecure-random",
 			})
 		}
 
@@ -138,6 +155,7 @@ func (a *Analyzer) checkJavaScriptQuality(file string, report *Report) {
 				Message:  "Non-literal require() - potential arbitrary code execution",
 				File:     file,
 				Line:     i + 1,
+				RuleID:   "dynamic-require",
 			})
 		}
 
@@ -149,6 +167,7 @@ func (a *Analyzer) checkJavaScriptQuality(file string, report *Report) {
 				Message:  "SSL verification disabled - vulnerable to man-in-the-middle attacks",
 				File:     file,
 				Line:     i + 1,
+				RuleID:   "ssl-verification-disabled",
 			})
 		}
 	}
@@ -160,6 +179,23 @@ func (a *Analyzer) checkJavaScriptQuality(file string, report *Report) {
 			Severity: "low",
 			Message:  "Consider adding 'use strict' or converting to ES module",
 			File:     file,
+			RuleID:   "use-strict",
 		})
 	}
+
+	a.checkUploadHandling(file, contentStr, code, report)
+	a.checkInputLimits(file, contentStr, code, report)
+	a.checkResponseContentType(file, code, report)
+	a.checkInsecureTransport(file, code, report)
+	a.checkBrokerAuth(file, code, report)
+	a.checkCloudIdentifiers(file, code, report)
+	a.checkAuthRateLimiting(file, contentStr, code, expressAuthRateLimit, report)
+	a.checkObjectOwnership(file, code, expressObjectOwnership, report)
+	a.checkHardcodedSalts(file, code, report)
+	a.checkInsecureJWT(file, code, report)
+	a.checkOpenRedirects(file, code, report)
+	a.checkAnyHTTPMethod(file, code, report)
+	a.checkBlockingInAsync(file, code, report)
+	a.checkSuppressedLogging(file, code, report)
+	a.checkCommandLineSecrets(file, code, report)
 }
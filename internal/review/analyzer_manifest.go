@@ -0,0 +1,257 @@
+package review
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+var (
+	// Gradle coordinates with a version, e.g. 'com.acme:core:1.2.0', capturing the module and version
+	gradleCoordinatesPattern = regexp.MustCompile(`["']([\w.-]+:[\w.-]+):([^"':@\s]+)(?:@\w+)?["']`)
+	// Gradle map-style dependencies, e.g. group: 'com.acme', name: 'core', version: '+'
+	gradleMapDependencyPattern = regexp.MustCompile(`\bname\s*[:=]\s*["']([\w.-]+)["'].*\bversion\s*[:=]\s*["']([^"']+)["']`)
+	// The start of a Gradle repositories block
+	gradleRepositoriesPattern = regexp.MustCompile(`^\s*repositories\s*\{`)
+	// A Gradle repository URL, e.g. url 'http://...', url = uri("http://...")
+	gradleRepositoryURLPattern = regexp.MustCompile(`\burl\s*(?:=\s*)?\(?\s*(?:uri\s*\(\s*)?["'](\w+://[^"']+)["']`)
+	// A pom.xml artifactId and version, capturing the value
+	pomArtifactIDPattern = regexp.MustCompile(`<artifactId>\s*([^<\s]+)\s*</artifactId>`)
+	pomVersionPattern    = regexp.MustCompile(`<version>\s*([^<\s]+)\s*</version>`)
+	// A pom.xml URL, capturing the value, and the repository elements it names a repository in
+	pomURLPattern             = regexp.MustCompile(`<url>\s*([^<\s]+)\s*</url>`)
+	pomRepositoryStartPattern = regexp.MustCompile(`<(repository|pluginRepository|snapshotRepository)>`)
+	pomRepositoryEndPattern   = regexp.MustCompile(`</(repository|pluginRepository|snapshotRepository)>`)
+	// Gemfile gem declarations, capturing the name and the rest of the arguments
+	gemfileGemPattern = regexp.MustCompile(`^\s*gem\s+["']([\w.-]+)["']\s*(.*)`)
+	// A Gemfile source or git: option, capturing the URL
+	gemfileSourcePattern = regexp.MustCompile(`^\s*source\s+["']([^"']+)["']|\b(?:git|source)\s*(?::|=>)\s*["']([^"']+)["']`)
+	// A version requirement that accepts every release
+	gemfileAnyVersionPattern = regexp.MustCompile(`^\s*,\s*["'](>=\s*0(\.0)*|\*)["']`)
+	// A requirement's name, up to its extras, version specifier or marker
+	requirementNamePattern = regexp.MustCompile(`^[A-Za-z0-9][\w.-]*`)
+)
+
+// manifestFiles are the dependency manifests recognized by name
+var manifestFiles = []string{"package.json", "build.gradle", "build.gradle.kts", "pom.xml", "Gemfile"}
+
+// isManifestFile reports whether file declares a project's dependencies
+func isManifestFile(file string) bool {
+	base := filepath.Base(file)
+	for _, name := range manifestFiles {
+		if strings.EqualFold(base, name) {
+			return true
+		}
+	}
+	return requirementsFilePattern.MatchString(strings.ToLower(base))
+}
+
+// wildcardVersion reports whether a version accepts whatever release is newest
+func wildcardVersion(version string) bool {
+	switch strings.ToLower(strings.TrimSpace(version)) {
+	case "", "*", "x", "+", "latest", "release", "latest.release", "latest.integration":
+		return true
+	}
+	return false
+}
+
+// insecureSource reports whether a dependency is fetched from a URL without TLS,
+// such as git+http:// or git://, other than from the local machine
+func insecureSource(url string) bool {
+	url = strings.TrimPrefix(strings.ToLower(strings.TrimSpace(url)), "git+")
+	if strings.HasPrefix(url, "git://") {
+		return true
+	}
+	m := insecureURLPattern.FindStringSubmatch(url)
+	return m != nil && strings.HasPrefix(url, "http://") && !isLocalHost(m[1])
+}
+
+// checkManifestQuality analyzes dependency manifests: package.json, build.gradle
+// and build.gradle.kts, pom.xml, requirements files and Gemfiles. It flags
+// dependencies that accept any version, dependencies fetched without TLS and
+// requirements without a version. Gradle build scripts are also run through the
+// Groovy analyzer.
+func (a *Analyzer) checkManifestQuality(file string, report *Report) {
+	content, err := a.readFile(file)
+	if err != nil {
+		return
+	}
+
+	lines := strings.Split(string(content), "\n")
+	base := strings.ToLower(filepath.Base(file))
+
+	// wildcard and insecure report a finding on line i
+	wildcard := func(i int, name, version string) {
+		report.AddIssue(Issue{
+			Type:     "dependency",
+			Severity: "medium",
+			Message:  fmt.Sprintf("Dependency %s accepts any version (%q) - every install may pull a different, possibly compromised release, pin a version or range", name, version),
+			File:     file,
+			Line:     i + 1,
+			RuleID:   "dependency-wildcard-version",
+		})
+	}
+	insecure := func(i int) {
+		report.AddIssue(Issue{
+			Type:     "dependency",
+			Severity: "high",
+			Message:  "Dependency fetched without TLS - anyone on the network can swap in their own code, use an https:// or ssh URL",
+			File:     file,
+			Line:     i + 1,
+			RuleID:   "dependency-insecure-source",
+		})
+	}
+
+	switch {
+	case base == "package.json":
+		a.checkPackageJSONDependencies(lines, content, wildcard, insecure)
+	case base == "build.gradle" || base == "build.gradle.kts":
+		code := codeLines(lines, "groovy")
+		// repositoriesDepth is the brace depth inside a repositories block, or 0 outside one
+		depth, repositoriesDepth := 0, 0
+		for i, line := range code {
+			if repositoriesDepth == 0 && gradleRepositoriesPattern.MatchString(line) {
+				repositoriesDepth = depth + 1
+			}
+			depth += strings.Count(line, "{") - strings.Count(line, "}")
+
+			// Check for dynamic versions such as '+' and latest.release
+			if m := gradleCoordinatesPattern.FindStringSubmatch(line); m != nil && wildcardVersion(m[2]) {
+				wildcard(i, m[1], m[2])
+			} else if m := gradleMapDependencyPattern.FindStringSubmatch(line); m != nil && wildcardVersion(m[2]) {
+				wildcard(i, m[1], m[2])
+			}
+			// SECURITY: Check for repositories without TLS; other URLs such as
+			// the project's homepage are not downloaded from
+			if m := gradleRepositoryURLPattern.FindStringSubmatch(line); m != nil && repositoriesDepth > 0 && insecureSource(m[1]) {
+				insecure(i)
+			}
+			if depth < repositoriesDepth {
+				repositoriesDepth = 0
+			}
+		}
+		if base == "build.gradle" {
+			a.checkGroovyQuality(file, report)
+		}
+	case base == "pom.xml":
+		// artifact is the last artifactId seen, which a <version> belongs to
+		artifact := ""
+		inRepository := false
+		for i, line := range lines {
+			if pomRepositoryStartPattern.MatchString(line) {
+				inRepository = true
+			}
+			if m := pomArtifactIDPattern.FindStringSubmatch(line); m != nil {
+				artifact = m[1]
+			}
+			// Check for LATEST and RELEASE, which Maven 3 no longer resolves reproducibly
+			if m := pomVersionPattern.FindStringSubmatch(line); m != nil && wildcardVersion(m[1]) {
+				wildcard(i, artifact, m[1])
+			}
+			// SECURITY: Check for repositories without TLS
+			if m := pomURLPattern.FindStringSubmatch(line); m != nil && inRepository && insecureSource(m[1]) {
+				insecure(i)
+			}
+			if pomRepositoryEndPattern.MatchString(line) {
+				inRepository = false
+			}
+		}
+	case base == "gemfile":
+		code := codeLines(lines, "ruby")
+		for i, line := range code {
+			// Check for requirements such as '>= 0' that accept every release
+			if m := gemfileGemPattern.FindStringSubmatch(line); m != nil {
+				if v := gemfileAnyVersionPattern.FindStringSubmatch(m[2]); v != nil {
+					wildcard(i, m[1], v[1])
+				}
+			}
+			// SECURITY: Check for gem sources without TLS
+			if m := gemfileSourcePattern.FindStringSubmatch(line); m != nil && insecureSource(m[1]+m[2]) {
+				insecure(i)
+			}
+		}
+	default:
+		// Requirement files
+		for i, line := range lines {
+			requirement := strings.TrimSpace(line)
+			if j := strings.Index(requirement, " #"); j >= 0 {
+				requirement = strings.TrimSpace(requirement[:j])
+			}
+			if requirement == "" || strings.HasPrefix(requirement, "#") {
+				continue
+			}
+
+			// SECURITY: Check for requirements and indexes fetched without TLS
+			for _, field := range strings.Fields(requirement) {
+				if insecureSource(strings.TrimPrefix(field, "--index-url=")) {
+					insecure(i)
+					break
+				}
+			}
+
+			// Options, URLs, paths and name @ url references are pinned some other way
+			if strings.HasPrefix(requirement, "-") || strings.Contains(requirement, "://") || strings.Contains(requirement, "@") ||
+				strings.HasPrefix(requirement, ".") || strings.HasPrefix(requirement, "/") {
+				continue
+			}
+
+			// Check for requirements without a version
+			name := requirementNamePattern.FindString(requirement)
+			specifier := strings.SplitN(requirement, ";", 2)[0]
+			if name != "" && !strings.ContainsAny(specifier, "<>=~!") {
+				report.AddIssue(Issue{
+					Type:     "dependency",
+					Severity: "low",
+					Message:  fmt.Sprintf("Requirement %s has no version - installs pick up whatever release is newest, pin it with == or a compatible range (~=)", name),
+					File:     file,
+					Line:     i + 1,
+					RuleID:   "dependency-unpinned",
+				})
+			}
+		}
+	}
+}
+
+// checkPackageJSONDependencies reports package.json dependencies that accept any
+// version or come from a URL without TLS. The file is parsed as JSON, then each
+// finding is reported on the line declaring it.
+func (a *Analyzer) checkPackageJSONDependencies(lines []string, content []byte, wildcard func(int, string, string), insecure func(int)) {
+	var manifest map[string]json.RawMessage
+	if err := json.Unmarshal(content, &manifest); err != nil {
+		return
+	}
+
+	for _, section := range []string{"dependencies", "devDependencies", "peerDependencies", "optionalDependencies"} {
+		var dependencies map[string]string
+		if err := json.Unmarshal(manifest[section], &dependencies); err != nil {
+			continue
+		}
+		for _, name := range slices.Sorted(maps.Keys(dependencies)) {
+			version := dependencies[name]
+			line := jsonEntryLine(lines, name, version)
+			if wildcardVersion(version) {
+				wildcard(line, name, version)
+			}
+			if insecureSource(version) {
+				insecure(line)
+			}
+		}
+	}
+}
+
+// jsonEntryLine returns the index of the line declaring "key": "value", or 0
+func jsonEntryLine(lines []string, key, value string) int {
+	keyJSON, _ := json.Marshal(key)
+	valueJSON, _ := json.Marshal(value)
+	entry := regexp.MustCompile(regexp.QuoteMeta(string(keyJSON)) + `\s*:\s*` + regexp.QuoteMeta(string(valueJSON)))
+	for i, line := range lines {
+		if entry.MatchString(line) {
+			return i
+		}
+	}
+	return 0
+}
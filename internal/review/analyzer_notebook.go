@@ -0,0 +1,140 @@
+package review
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var (
+	// IPython magics and shell escapes such as %matplotlib inline and !pip install,
+	// which are not Python
+	notebookMagicPattern = regexp.MustCompile(`^\s*[%!]`)
+	// Credentials and tokens printed in cell outputs: key/value pairs naming a
+	// secret, AWS access keys, GitHub, Slack and OpenAI tokens, JWTs and private keys
+	notebookOutputCredentialPattern = regexp.MustCompile(`(?i)\b(password|passwd|secret|api[_-]?key|access[_-]?token|auth[_-]?token)['"]?\s*[:=]\s*['"]?[^\s'",]{8,}` +
+		`|\b(A3T[A-Z0-9]|AKIA|ABIA|ACCA|ASIA)[A-Z0-9]{16}\b` +
+		`|\bgh[pousr]_[A-Za-z0-9]{36}\b` +
+		`|\bxox[abprs]-[A-Za-z0-9-]{10,}` +
+		`|\bsk-[A-Za-z0-9_-]{20,}` +
+		`|\beyJ[\w-]{10,}\.eyJ[\w-]{10,}\.[\w-]{10,}` +
+		`|-----BEGIN\s+(RSA|EC|DSA|OPENSSH|PGP)?\s*PRIVATE KEY-----`)
+)
+
+// notebookText is a multi-line notebook field, stored either as one string or
+// as a list of lines that each keep their trailing newline
+type notebookText string
+
+func (t *notebookText) UnmarshalJSON(data []byte) error {
+	var lines []string
+	if err := json.Unmarshal(data, &lines); err == nil {
+		*t = notebookText(strings.Join(lines, ""))
+		return nil
+	}
+	var text string
+	if err := json.Unmarshal(data, &text); err != nil {
+		return err
+	}
+	*t = notebookText(text)
+	return nil
+}
+
+// notebook is the part of the Jupyter notebook format the checks read
+type notebook struct {
+	Cells []struct {
+		CellType string       `json:"cell_type"`
+		Source   notebookText `json:"source"`
+		Outputs  []struct {
+			// Text is the output of stream outputs
+			Text notebookText `json:"text"`
+			// Data holds display and execute_result outputs keyed by MIME type
+			Data      map[string]json.RawMessage `json:"data"`
+			Traceback []string                   `json:"traceback"`
+		} `json:"outputs"`
+	} `json:"cells"`
+}
+
+// notebookOutputText returns the text MIME types of a display_data or
+// execute_result output, skipping images
+func notebookOutputText(data map[string]json.RawMessage) string {
+	var text strings.Builder
+	for mime, raw := range data {
+		if !strings.HasPrefix(mime, "text/") && mime != "application/json" {
+			continue
+		}
+		var value notebookText
+		if json.Unmarshal(raw, &value) == nil {
+			text.WriteString(string(value))
+		} else {
+			text.Write(raw)
+		}
+		text.WriteString("\n")
+	}
+	return text.String()
+}
+
+// checkNotebookQuality analyzes Jupyter notebooks. Each code cell gets the
+// Python checks, and as line numbers in the notebook JSON mean little, issues
+// are located by cell and line in the message. Cell outputs are checked for
+// credentials that were printed and committed with the notebook.
+func (a *Analyzer) checkNotebookQuality(file string, report *Report) {
+	content, err := a.readFile(file)
+	if err != nil {
+		return
+	}
+
+	var nb notebook
+	if err := json.Unmarshal(content, &nb); err != nil {
+		a.log.Debugf("Skipping notebook that is not valid JSON: %s: %v", file, err)
+		return
+	}
+
+	for n, cell := range nb.Cells {
+		if cell.CellType != "code" {
+			continue
+		}
+
+		// Blank out magics so line numbers still match the cell
+		lines := strings.Split(string(cell.Source), "\n")
+		for i, line := range lines {
+			if notebookMagicPattern.MatchString(line) {
+				lines[i] = ""
+			}
+		}
+
+		// The Python checks pick their rules by extension, so they run under a
+		// .py name and the issues are moved back to the notebook
+		cellReport := NewReport()
+		a.checkPythonSource(file+".py", strings.Join(lines, "\n"), cellReport)
+		for _, issue := range cellReport.Issues {
+			// Printing results is what notebook cells are for
+			if issue.RuleID == "print-statement" {
+				continue
+			}
+			issue.File = file
+			if issue.Line > 0 {
+				issue.Message = fmt.Sprintf("cell %d, line %d: %s", n+1, issue.Line, issue.Message)
+			} else {
+				issue.Message = fmt.Sprintf("cell %d: %s", n+1, issue.Message)
+			}
+			issue.Line = 0
+			report.AddIssue(issue)
+		}
+
+		// SECURITY: Check for credentials shown in cell outputs
+		for _, output := range cell.Outputs {
+			text := string(output.Text) + notebookOutputText(output.Data) + strings.Join(output.Traceback, "\n")
+			if notebookOutputCredentialPattern.MatchString(text) {
+				report.AddIssue(Issue{
+					Type:     "security",
+					Severity: "high",
+					Message:  fmt.Sprintf("cell %d: Output contains what looks like a credential or token - clear the outputs before committing and rotate the secret", n+1),
+					File:     file,
+					RuleID:   "notebook-output-credential",
+				})
+				break
+			}
+		}
+	}
+}
@@ -1,15 +1,21 @@
 package review
 
 import (
-	"os"
-	"path/filepath"
+	"fmt"
+	"regexp"
 	"strings"
 )
 
+var (
+	// Calls that run a SQL string directly, capturing their arguments
+	phpQueryCallPattern = regexp.MustCompile(`(->\s*(query|exec|multi_query|real_query)|\b(mysqli_query|mysql_query|pg_query))\s*\((.*)`)
+	// A variable concatenated onto a string, or interpolated into a double-quoted one
+	phpStringBuildingPattern = regexp.MustCompile(`\.\s*\$\w|\$\w+(\[[^\]]*\])?\s*\.\s*["']|"[^"]*\$[\w{][^"]*"`)
+)
+
 // checkPHPQuality analyzes PHP files for quality and security issues
 func (a *Analyzer) checkPHPQuality(file string, report *Report) {
-	filePath := filepath.Join(a.repoPath, file)
-	content, err := os.ReadFile(filePath)
+	content, err := a.readFile(file)
 	if err != nil {
 		return
 	}
@@ -17,17 +23,23 @@ func (a *Analyzer) checkPHPQuality(file string, report *Report) {
 	contentStr := string(content)
 	lines := strings.Split(contentStr, "\n")
 
-	for i, line := range lines {
+	// Pattern checks see only code, so commented-out code is not reported
+	code := codeLines(lines, LanguageForFile(file))
+	lineLimit := a.lineLengthLimitFor(file)
+
+	for i, raw := range lines {
+		line := code[i]
 		lineLower := strings.ToLower(line)
 
 		// Line length check
-		if len(line) > 120 {
+		if len(raw) > lineLimit {
 			report.AddIssue(Issue{
 				Type:     "quality",
-				Severity: "low",
-				Message:  "Line too long (>120 characters)",
+				Severity: "info",
+				Message:  fmt.Sprintf("Line too long (>%d characters)", lineLimit),
 				File:     file,
 				Line:     i + 1,
+				RuleID:   "line-length",
 			})
 		}
 
@@ -39,6 +51,7 @@ func (a *Analyzer) checkPHPQuality(file string, report *Report) {
 				Message:  "Debug output (var_dump/print_r) found - remove before production",
 				File:     file,
 				Line:     i + 1,
+				RuleID:   "debug-output",
 			})
 		}
 
@@ -50,17 +63,19 @@ func (a *Analyzer) checkPHPQuality(file string, report *Report) {
 				Message:  "die()/exit() statement found - consider proper error handling",
 				File:     file,
 				Line:     i + 1,
+				RuleID:   "die-exit",
 			})
 		}
 
 		// Check for TODO/FIXME comments
-		if strings.Contains(lineLower, "todo") || strings.Contains(lineLower, "fixme") {
+		if rawLower := strings.ToLower(raw); strings.Contains(rawLower, "todo") || strings.Contains(rawLower, "fixme") {
 			report.AddIssue(Issue{
 				Type:     "quality",
-				Severity: "low",
+				Severity: "info",
 				Message:  "TODO/FIXME comment found",
 				File:     file,
 				Line:     i + 1,
+				RuleID:   "todo-comment",
 			})
 		}
 
@@ -72,6 +87,7 @@ func (a *Analyzer) checkPHPQuality(file string, report *Report) {
 				Message:  "eval() usage detected - potential code injection vulnerability",
 				File:     file,
 				Line:     i + 1,
+				RuleID:   "eval",
 			})
 		}
 
@@ -83,20 +99,32 @@ func (a *Analyzer) checkPHPQuality(file string, report *Report) {
 				Message:  "Shell command execution detected - ensure input is sanitized",
 				File:     file,
 				Line:     i + 1,
+				RuleID:   "shell-exec",
 			})
 		}
 
 		// SECURITY: Check for SQL injection vulnerabilities
-		if strings.Contains(line, "$_GET") || strings.Contains(line, "$_POST") || strings.Contains(line, "$_REQUEST") {
-			if strings.Contains(line, "mysql_query") || strings.Contains(line, "mysqli_query") || strings.Contains(line, "->query(") {
-				report.AddIssue(Issue{
-					Type:     "security",
-					Severity: "high",
-					Message:  "Potential SQL injection - use prepared statements",
-					File:     file,
-					Line:     i + 1,
-				})
-			}
+		superglobal := strings.Contains(line, "$_GET") || strings.Contains(line, "$_POST") || strings.Contains(line, "$_REQUEST")
+		if superglobal && (strings.Contains(line, "mysql_query") || strings.Contains(line, "mysqli_query") || strings.Contains(line, "->query(")) {
+			report.AddIssue(Issue{
+				Type:       "security",
+				Severity:   "high",
+				Message:    "Potential SQL injection - use prepared statements",
+				File:       file,
+				Line:       i + 1,
+				RuleID:     "sql-injection",
+				Confidence: ConfidenceHigh,
+			})
+		} else if m := phpQueryCallPattern.FindStringSubmatch(line); m != nil && phpStringBuildingPattern.MatchString(m[4]) {
+			// Variables may carry user input from elsewhere, so this is less certain
+			report.AddIssue(Issue{
+				Type:     "security",
+				Severity: "high",
+				Message:  "SQL query built from variables - use PDO or mysqli prepared statements with bound parameters",
+				File:     file,
+				Line:     i + 1,
+				RuleID:   "sql-query-concatenation",
+			})
 		}
 
 		// Check for deprecated mysql_* functions
@@ -107,6 +135,7 @@ func (a *Analyzer) checkPHPQuality(file string, report *Report) {
 				Message:  "Deprecated mysql_* function - use mysqli or PDO instead",
 				File:     file,
 				Line:     i + 1,
+				RuleID:   "mysql-deprecated",
 			})
 		}
 
@@ -119,6 +148,7 @@ func (a *Analyzer) checkPHPQuality(file string, report *Report) {
 				Message:  "File inclusion with user input - potential LFI/RFI vulnerability",
 				File:     file,
 				Line:     i + 1,
+				RuleID:   "file-inclusion",
 			})
 		}
 
@@ -130,6 +160,7 @@ func (a *Analyzer) checkPHPQuality(file string, report *Report) {
 				Message:  "Unsafe unserialize with user input - potential object injection",
 				File:     file,
 				Line:     i + 1,
+				RuleID:   "unsafe-unserialize",
 			})
 		}
 
@@ -142,6 +173,7 @@ func (a *Analyzer) checkPHPQuality(file string, report *Report) {
 					Message:  "Potential XSS - escape output with htmlspecialchars()",
 					File:     file,
 					Line:     i + 1,
+					RuleID:   "xss",
 				})
 			}
 		}
@@ -155,9 +187,18 @@ func (a *Analyzer) checkPHPQuality(file string, report *Report) {
 					Message:  "Weak password hashing - use password_hash() instead",
 					File:     file,
 					Line:     i + 1,
+					RuleID:   "weak-password-hash",
 				})
 			}
 		}
 	}
-}
 
+	a.checkUploadHandling(file, contentStr, code, report)
+	a.checkInputLimits(file, contentStr, code, report)
+	a.checkResponseContentType(file, code, report)
+	a.checkHardcodedSalts(file, code, report)
+	a.checkOpenRedirects(file, code, report)
+	a.checkBrokerAuth(file, code, report)
+	a.checkCloudIdentifiers(file, code, report)
+	a.checkInsecureXML(file, code, report)
+}
@@ -0,0 +1,125 @@
+package review
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	// Invoke-Expression or its iex alias as a command
+	psInvokeExpressionPattern = regexp.MustCompile(`(?i)(^|[\s;|(=&{])(Invoke-Expression|iex)\b`)
+	// Downloaded content run with Invoke-Expression, piped in or passed as the argument
+	psDownloadExecutePattern = regexp.MustCompile(`(?i)(DownloadString|Invoke-WebRequest|Invoke-RestMethod|\biwr|\birm)\b.*\|\s*(Invoke-Expression|iex)\b|(^|[\s;|(=&{])(Invoke-Expression|iex)\b.*(DownloadString|Invoke-WebRequest|Invoke-RestMethod|\biwr\b|\birm\b)`)
+	// Script execution policy switched off, including the -ep and -exec abbreviations
+	psExecutionPolicyPattern = regexp.MustCompile(`(?i)(-(ExecutionPolicy|ep|exec)|Set-ExecutionPolicy)\s+(Bypass|Unrestricted)\b`)
+	// Secure strings made from plain text, and from a literal in particular
+	psPlainTextSecureStringPattern = regexp.MustCompile(`(?i)\bConvertTo-SecureString\b.*-AsPlainText`)
+	psLiteralSecureStringPattern   = regexp.MustCompile(`(?i)\bConvertTo-SecureString\s+(-String\s+)?["']`)
+	// Password-like variables assigned a string literal
+	psHardcodedPasswordPattern = regexp.MustCompile(`(?i)\$\w*(password|passwd|pwd|secret|apikey|token)\w*\s*=\s*["'][^"']+["']`)
+	// Write-Host output, which bypasses the pipeline
+	psWriteHostPattern = regexp.MustCompile(`(?i)\bWrite-Host\b`)
+)
+
+// checkPowerShellQuality analyzes PowerShell scripts and modules for quality and security issues
+func (a *Analyzer) checkPowerShellQuality(file string, report *Report) {
+	content, err := a.readFile(file)
+	if err != nil {
+		return
+	}
+
+	contentStr := string(content)
+	lines := strings.Split(contentStr, "\n")
+
+	// Pattern checks see only code, so commented-out code is not reported
+	code := codeLines(lines, LanguageForFile(file))
+
+	for i, raw := range lines {
+		line := code[i]
+
+		// Check for TODO/FIXME comments
+		if rawLower := strings.ToLower(raw); strings.Contains(rawLower, "todo") || strings.Contains(rawLower, "fixme") {
+			report.AddIssue(Issue{
+				Type:     "quality",
+				Severity: "info",
+				Message:  "TODO/FIXME comment found",
+				File:     file,
+				Line:     i + 1,
+				RuleID:   "todo-comment",
+			})
+		}
+
+		// Check for Write-Host debug output
+		if psWriteHostPattern.MatchString(line) {
+			report.AddIssue(Issue{
+				Type:     "quality",
+				Severity: "low",
+				Message:  "Write-Host found - use Write-Verbose or Write-Output so output can be redirected and silenced",
+				File:     file,
+				Line:     i + 1,
+				RuleID:   "debug-output",
+			})
+		}
+
+		// SECURITY: Check for downloaded scripts run straight away, then any other Invoke-Expression
+		if psDownloadExecutePattern.MatchString(line) {
+			report.AddIssue(Issue{
+				Type:     "security",
+				Severity: "high",
+				Message:  "Downloaded script run with Invoke-Expression - download it, verify its signature or hash, then run it",
+				File:     file,
+				Line:     i + 1,
+				RuleID:   "download-execute",
+			})
+		} else if psInvokeExpressionPattern.MatchString(line) {
+			report.AddIssue(Issue{
+				Type:     "security",
+				Severity: "high",
+				Message:  "Use of Invoke-Expression - its argument is parsed as code, a potential command injection, call the command directly with & instead",
+				File:     file,
+				Line:     i + 1,
+				RuleID:   "invoke-expression",
+			})
+		}
+
+		// SECURITY: Check for the execution policy being bypassed
+		if psExecutionPolicyPattern.MatchString(line) {
+			report.AddIssue(Issue{
+				Type:     "security",
+				Severity: "medium",
+				Message:  "Execution policy bypassed - sign the script instead of disabling policy checks",
+				File:     file,
+				Line:     i + 1,
+				RuleID:   "execution-policy-bypass",
+			})
+		}
+
+		// SECURITY: Check for secure strings built from plain text
+		if psPlainTextSecureStringPattern.MatchString(line) {
+			severity, message := "medium", "ConvertTo-SecureString -AsPlainText - the secret passed through memory and logs as plain text, use Get-Credential or a secret vault"
+			if psLiteralSecureStringPattern.MatchString(line) {
+				severity, message = "high", "ConvertTo-SecureString -AsPlainText with a literal - the secret is hardcoded in the script, use Get-Credential or a secret vault"
+			}
+			report.AddIssue(Issue{
+				Type:     "security",
+				Severity: severity,
+				Message:  message,
+				File:     file,
+				Line:     i + 1,
+				RuleID:   "plaintext-securestring",
+			})
+		}
+
+		// SECURITY: Check for hardcoded passwords
+		if psHardcodedPasswordPattern.MatchString(line) {
+			report.AddIssue(Issue{
+				Type:     "security",
+				Severity: "high",
+				Message:  "Potential hardcoded password - read it with Get-Credential or from a secret vault",
+				File:     file,
+				Line:     i + 1,
+				RuleID:   "hardcoded-password",
+			})
+		}
+	}
+}
@@ -0,0 +1,250 @@
+package review
+
+import (
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+var (
+	// A message, enum, oneof, service or extend block header, capturing its kind and name
+	protoBlockPattern = regexp.MustCompile(`^\s*(message|enum|oneof|service|extend)\s+([\w.]+)\s*\{`)
+	// A message field, capturing its label, name and number
+	protoFieldPattern = regexp.MustCompile(`^\s*(?:(optional|required|repeated)\s+)?(?:map\s*<[^>]*>|[\w.]+)\s+(\w+)\s*=\s*(\d+)`)
+	// An enum value, capturing its name and number
+	protoEnumValuePattern = regexp.MustCompile(`^\s*(\w+)\s*=\s*(-?\d+)`)
+	// A reserved statement, capturing the reserved numbers or names
+	protoReservedPattern = regexp.MustCompile(`^\s*reserved\s+([^;]+)`)
+	// A reserved number range, e.g. 9 to 11 or 100 to max
+	protoReservedRangePattern = regexp.MustCompile(`^(\d+)\s+to\s+(\d+|max)$`)
+	// Names that mark an enum's zero value as the unset default
+	protoUnknownValuePattern = regexp.MustCompile(`(?i)(UNKNOWN|UNSPECIFIED)$`)
+)
+
+// protoMessage is a message of a .proto file and the field numbers it uses or reserves
+type protoMessage struct {
+	// line is the index of the message header
+	line int
+	// fields maps field numbers to names
+	fields map[int]string
+	// reservedRanges are inclusive [from, to] number ranges
+	reservedRanges [][2]int
+}
+
+// reserves reports whether the message reserves a field number
+func (m *protoMessage) reserves(number int) bool {
+	return slices.ContainsFunc(m.reservedRanges, func(r [2]int) bool { return number >= r[0] && number <= r[1] })
+}
+
+// protoEnum is an enum of a .proto file
+type protoEnum struct {
+	name string
+	// line is the index of the enum header
+	line int
+	// zero is the name of the value numbered 0, or "" if there is none
+	zero string
+}
+
+// protoScope is an open { } block
+type protoScope struct {
+	// kind is message, enum, oneof, service or extend, or "" for other braces such as options
+	kind string
+	name string
+}
+
+// parseProto reads the messages and enums of a .proto file from its code lines.
+// Nested messages are keyed by their full name, e.g. Outer.Inner.
+func parseProto(code []string) (map[string]*protoMessage, []protoEnum) {
+	messages := map[string]*protoMessage{}
+	var enums []protoEnum
+	var scopes []protoScope
+
+	// messageName returns the full name of the innermost open message, or ""
+	messageName := func() string {
+		var names []string
+		for _, scope := range scopes {
+			if scope.kind == "message" {
+				names = append(names, scope.name)
+			}
+		}
+		return strings.Join(names, ".")
+	}
+	// innermost returns the kind of the innermost message, enum or oneof
+	innermost := func() string {
+		for i := len(scopes) - 1; i >= 0; i-- {
+			if scopes[i].kind != "" {
+				return scopes[i].kind
+			}
+		}
+		return ""
+	}
+
+	for i, line := range code {
+		header := protoBlockPattern.FindStringSubmatch(line)
+		kind := innermost()
+
+		switch {
+		case header != nil:
+		case kind == "message" || kind == "oneof":
+			message := messages[messageName()]
+			if m := protoReservedPattern.FindStringSubmatch(line); m != nil {
+				message.reserve(m[1])
+			} else if m := protoFieldPattern.FindStringSubmatch(line); m != nil {
+				number, _ := strconv.Atoi(m[3])
+				message.fields[number] = m[2]
+			}
+		case kind == "enum":
+			if m := protoEnumValuePattern.FindStringSubmatch(line); m != nil && m[2] == "0" && enums[len(enums)-1].zero == "" {
+				enums[len(enums)-1].zero = m[1]
+			}
+		}
+
+		for _, c := range line {
+			switch c {
+			case '{':
+				scope := protoScope{}
+				if header != nil {
+					scope = protoScope{kind: header[1], name: header[2]}
+					header = nil
+				}
+				scopes = append(scopes, scope)
+				switch scope.kind {
+				case "message":
+					messages[messageName()] = &protoMessage{line: i, fields: map[int]string{}}
+				case "enum":
+					enums = append(enums, protoEnum{name: scope.name, line: i})
+				}
+			case '}':
+				if len(scopes) > 0 {
+					scopes = scopes[:len(scopes)-1]
+				}
+			}
+		}
+	}
+	return messages, enums
+}
+
+// reserve records the numbers of a reserved statement; reserved names do not
+// stop a number being reused
+func (m *protoMessage) reserve(values string) {
+	for _, value := range strings.Split(values, ",") {
+		value = strings.TrimSpace(value)
+		if r := protoReservedRangePattern.FindStringSubmatch(value); r != nil {
+			from, _ := strconv.Atoi(r[1])
+			to := 536870911 // the largest field number
+			if r[2] != "max" {
+				to, _ = strconv.Atoi(r[2])
+			}
+			m.reservedRanges = append(m.reservedRanges, [2]int{from, to})
+		} else if number, err := strconv.Atoi(value); err == nil {
+			m.reservedRanges = append(m.reservedRanges, [2]int{number, number})
+		}
+	}
+}
+
+// checkProtoQuality analyzes Protocol Buffers schemas. In a diff review the
+// file is compared with its version on the target branch, as removing a field
+// without reserving its number lets it be reused with another meaning, which
+// old clients then misread.
+func (a *Analyzer) checkProtoQuality(file string, report *Report) {
+	content, err := a.readFile(file)
+	if err != nil {
+		return
+	}
+
+	lines := strings.Split(string(content), "\n")
+
+	// Pattern checks see only code, so commented-out fields are not reported
+	code := codeLines(lines, LanguageForFile(file))
+	messages, enums := parseProto(code)
+
+	for i, raw := range lines {
+		line := code[i]
+
+		// Check for TODO/FIXME comments
+		if rawLower := strings.ToLower(raw); strings.Contains(rawLower, "todo") || strings.Contains(rawLower, "fixme") {
+			report.AddIssue(Issue{
+				Type:     "quality",
+				Severity: "info",
+				Message:  "TODO/FIXME comment found",
+				File:     file,
+				Line:     i + 1,
+				RuleID:   "todo-comment",
+			})
+		}
+
+		// Check for proto2 required fields
+		if m := protoFieldPattern.FindStringSubmatch(line); m != nil && m[1] == "required" {
+			report.AddIssue(Issue{
+				Type:     "quality",
+				Severity: "medium",
+				Message:  fmt.Sprintf("Required field %s - required fields can never be removed or made optional without breaking readers, use optional and validate in code", m[2]),
+				File:     file,
+				Line:     i + 1,
+				RuleID:   "proto-required-field",
+			})
+		}
+	}
+
+	// Check for enums without an unknown zero value
+	for _, enum := range enums {
+		if enum.zero != "" && protoUnknownValuePattern.MatchString(enum.zero) {
+			continue
+		}
+		message := fmt.Sprintf("Enum %s has no zero value - add %s_UNSPECIFIED = 0 so unset and unrecognized values are not read as a real one", enum.name, protoEnumPrefix(enum.name))
+		if enum.zero != "" {
+			message = fmt.Sprintf("Enum %s uses %s as its zero value - unset and unrecognized values read as %s, add %s_UNSPECIFIED = 0 instead", enum.name, enum.zero, enum.zero, protoEnumPrefix(enum.name))
+		}
+		report.AddIssue(Issue{
+			Type:     "quality",
+			Severity: "low",
+			Message:  message,
+			File:     file,
+			Line:     enum.line + 1,
+			RuleID:   "proto-enum-zero-value",
+		})
+	}
+
+	// Check for fields removed since the target branch without reserving them
+	base, ok := a.targetVersion(file)
+	if !ok {
+		return
+	}
+	baseMessages, _ := parseProto(codeLines(strings.Split(base, "\n"), LanguageForFile(file)))
+	for _, name := range slices.Sorted(maps.Keys(baseMessages)) {
+		message, ok := messages[name]
+		if !ok {
+			continue
+		}
+		baseFields := baseMessages[name].fields
+		for _, number := range slices.Sorted(maps.Keys(baseFields)) {
+			if _, kept := message.fields[number]; kept || message.reserves(number) {
+				continue
+			}
+			field := baseFields[number]
+			report.AddIssue(Issue{
+				Type:     "quality",
+				Severity: "high",
+				Message:  fmt.Sprintf("Field %s = %d was removed from %s without reserving it - a new field reusing the number breaks wire compatibility, add reserved %d; and reserved \"%s\";", field, number, name, number, field),
+				File:     file,
+				Line:     message.line + 1,
+				RuleID:   "proto-unreserved-removed-field",
+			})
+		}
+	}
+}
+
+// protoEnumPrefix converts an enum name to the UPPER_SNAKE_CASE prefix of its values
+func protoEnumPrefix(name string) string {
+	var prefix strings.Builder
+	for i, c := range name {
+		if i > 0 && c >= 'A' && c <= 'Z' {
+			prefix.WriteByte('_')
+		}
+		prefix.WriteRune(c)
+	}
+	return strings.ToUpper(prefix.String())
+}
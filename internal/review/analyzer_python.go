@@ -1,34 +1,43 @@
 package review
 
 import (
-	"os"
-	"path/filepath"
+	"fmt"
 	"strings"
 )
 
 // checkPythonQuality analyzes Python files for quality and security issues
 func (a *Analyzer) checkPythonQuality(file string, report *Report) {
-	filePath := filepath.Join(a.repoPath, file)
-	content, err := os.ReadFile(filePath)
+	content, err := a.readFile(file)
 	if err != nil {
 		return
 	}
 
-	contentStr := string(content)
+	a.checkPythonSource(file, string(content), report)
+}
+
+// checkPythonSource analyzes Python source read from file, which may be a code
+// cell extracted from a notebook
+func (a *Analyzer) checkPythonSource(file string, contentStr string, report *Report) {
 	lines := strings.Split(contentStr, "\n")
 
-	for i, line := range lines {
+	// Pattern checks see only code, so commented-out code is not reported
+	code := codeLines(lines, LanguageForFile(file))
+	lineLimit := a.lineLengthLimitFor(file)
+
+	for i, raw := range lines {
+		line := code[i]
 		lineLower := strings.ToLower(line)
 		trimmed := strings.TrimSpace(line)
 
 		// Line length check (PEP 8 recommends 79, but 120 is common)
-		if len(line) > 120 {
+		if len(raw) > lineLimit {
 			report.AddIssue(Issue{
 				Type:     "quality",
-				Severity: "low",
-				Message:  "Line too long (>120 characters)",
+				Severity: "info",
+				Message:  fmt.Sprintf("Line too long (>%d characters)", lineLimit),
 				File:     file,
 				Line:     i + 1,
+				RuleID:   "line-length",
 			})
 		}
 
@@ -40,6 +49,7 @@ func (a *Analyzer) checkPythonQuality(file string, report *Report) {
 				Message:  "print() statement found - consider using logging instead",
 				File:     file,
 				Line:     i + 1,
+				RuleID:   "print-statement",
 			})
 		}
 
@@ -51,17 +61,19 @@ func (a *Analyzer) checkPythonQuality(file string, report *Report) {
 				Message:  "Debugger statement found - remove before production",
 				File:     file,
 				Line:     i + 1,
+				RuleID:   "debugger",
 			})
 		}
 
 		// Check for TODO/FIXME comments
-		if strings.Contains(lineLower, "todo") || strings.Contains(lineLower, "fixme") {
+		if rawLower := strings.ToLower(raw); strings.Contains(rawLower, "todo") || strings.Contains(rawLower, "fixme") {
 			report.AddIssue(Issue{
 				Type:     "quality",
-				Severity: "low",
+				Severity: "info",
 				Message:  "TODO/FIXME comment found",
 				File:     file,
 				Line:     i + 1,
+				RuleID:   "todo-comment",
 			})
 		}
 
@@ -73,6 +85,7 @@ func (a *Analyzer) checkPythonQuality(file string, report *Report) {
 				Message:  "eval()/exec() usage detected - potential code injection vulnerability",
 				File:     file,
 				Line:     i + 1,
+				RuleID:   "eval",
 			})
 		}
 
@@ -84,6 +97,7 @@ func (a *Analyzer) checkPythonQuality(file string, report *Report) {
 				Message:  "subprocess with shell=True - potential command injection risk",
 				File:     file,
 				Line:     i + 1,
+				RuleID:   "subprocess-shell",
 			})
 		}
 
@@ -95,6 +109,7 @@ func (a *Analyzer) checkPythonQuality(file string, report *Report) {
 				Message:  "os.system() usage - consider using subprocess with proper escaping",
 				File:     file,
 				Line:     i + 1,
+				RuleID:   "os-system",
 			})
 		}
 
@@ -106,17 +121,19 @@ func (a *Analyzer) checkPythonQuality(file string, report *Report) {
 				Message:  "Bare except clause - specify the exception type",
 				File:     file,
 				Line:     i + 1,
+				RuleID:   "bare-except",
 			})
 		}
 
 		// Check for type: ignore comments
-		if strings.Contains(line, "# type: ignore") {
+		if strings.Contains(raw, "# type: ignore") {
 			report.AddIssue(Issue{
 				Type:     "quality",
 				Severity: "low",
 				Message:  "Type ignore comment found - consider fixing the type error",
 				File:     file,
 				Line:     i + 1,
+				RuleID:   "type-ignore",
 			})
 		}
 
@@ -128,6 +145,7 @@ func (a *Analyzer) checkPythonQuality(file string, report *Report) {
 				Message:  "pickle.load() is unsafe - can execute arbitrary code during deserialization",
 				File:     file,
 				Line:     i + 1,
+				RuleID:   "pickle-load",
 			})
 		}
 
@@ -139,6 +157,7 @@ func (a *Analyzer) checkPythonQuality(file string, report *Report) {
 				Message:  "yaml.load() without safe Loader - use yaml.safe_load() or specify Loader=yaml.SafeLoader",
 				File:     file,
 				Line:     i + 1,
+				RuleID:   "yaml-load",
 			})
 		}
 
@@ -150,6 +169,7 @@ func (a *Analyzer) checkPythonQuality(file string, report *Report) {
 				Message:  "Potential SQL injection - use parameterized queries instead of string formatting",
 				File:     file,
 				Line:     i + 1,
+				RuleID:   "sql-injection",
 			})
 		}
 
@@ -161,8 +181,26 @@ func (a *Analyzer) checkPythonQuality(file string, report *Report) {
 				Message:  "Potential hardcoded password - use environment variables",
 				File:     file,
 				Line:     i + 1,
+				RuleID:   "hardcoded-password",
 			})
 		}
 	}
-}
 
+	a.checkUploadHandling(file, contentStr, code, report)
+	a.checkInputLimits(file, contentStr, code, report)
+	a.checkResponseContentType(file, code, report)
+	a.checkInsecureTransport(file, code, report)
+	a.checkBrokerAuth(file, code, report)
+	a.checkCloudIdentifiers(file, code, report)
+	a.checkAuthRateLimiting(file, contentStr, code, pythonAuthRateLimit, report)
+	a.checkObjectOwnership(file, code, pythonObjectOwnership, report)
+	a.checkHardcodedSalts(file, code, report)
+	a.checkInsecureJWT(file, code, report)
+	a.checkInsecureXML(file, code, report)
+	a.checkUnsafeDeserialization(file, code, report)
+	a.checkSuppressedLogging(file, code, report)
+	a.checkCommandLineSecrets(file, code, report)
+	a.checkOpenRedirects(file, code, report)
+	a.checkAnyHTTPMethod(file, code, report)
+	a.checkBlockingInAsync(file, code, report)
+}
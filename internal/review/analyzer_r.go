@@ -0,0 +1,137 @@
+package review
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	// Evaluating text as R code
+	rEvalParsePattern = regexp.MustCompile(`\beval\s*\(\s*(base::)?parse\s*\(\s*text\s*=`)
+	// Shell commands built with paste, sprintf or glue
+	rSystemPastePattern = regexp.MustCompile(`\b(system2?|shell)\s*\(.*\b(paste0?|sprintf|glue)\s*\(`)
+	// print() and cat() left over from debugging
+	rDebugPattern = regexp.MustCompile(`(^|[^\w.])(print|cat)\s*\(`)
+	// Key, token and password variables assigned a string literal
+	rCredentialPattern = regexp.MustCompile(`(?i)^\s*[\w.]*(key|token|password|passwd|secret)[\w.]*\s*(<<-|<-|=)\s*["']([^"']{8,})["']`)
+	// Packages attached with library() or require()
+	rLibraryPattern = regexp.MustCompile(`(^|[^\w.])(library|require)\s*\(`)
+	// The start of a function definition
+	rFunctionPattern = regexp.MustCompile(`\bfunction\s*\(`)
+)
+
+// checkRQuality analyzes R scripts for quality and security issues
+func (a *Analyzer) checkRQuality(file string, report *Report) {
+	content, err := a.readFile(file)
+	if err != nil {
+		return
+	}
+
+	contentStr := string(content)
+	lines := strings.Split(contentStr, "\n")
+
+	// Pattern checks see only code, so commented-out code is not reported
+	code := codeLines(lines, LanguageForFile(file))
+
+	// functions holds the brace depth of each function body being read
+	var functions []int
+	depth := 0
+	pending := false
+
+	for i, raw := range lines {
+		line := code[i]
+
+		// A function body starts at the first { after function(
+		inFunction := len(functions) > 0
+		if rFunctionPattern.MatchString(line) {
+			pending = true
+		}
+		for _, c := range asyncScopeStringPattern.ReplaceAllString(line, `""`) {
+			switch c {
+			case '{':
+				depth++
+				if pending {
+					functions = append(functions, depth)
+					inFunction, pending = true, false
+				}
+			case '}':
+				if n := len(functions); n > 0 && functions[n-1] == depth {
+					functions = functions[:n-1]
+				}
+				depth--
+			}
+		}
+
+		// Check for TODO/FIXME comments
+		if rawLower := strings.ToLower(raw); strings.Contains(rawLower, "todo") || strings.Contains(rawLower, "fixme") {
+			report.AddIssue(Issue{
+				Type:     "quality",
+				Severity: "info",
+				Message:  "TODO/FIXME comment found",
+				File:     file,
+				Line:     i + 1,
+				RuleID:   "todo-comment",
+			})
+		}
+
+		// SECURITY: Check for text evaluated as code
+		if rEvalParsePattern.MatchString(line) {
+			report.AddIssue(Issue{
+				Type:     "security",
+				Severity: "high",
+				Message:  "eval(parse(text = ...)) executes arbitrary code - use match.arg, switch or get() on known names instead",
+				File:     file,
+				Line:     i + 1,
+				RuleID:   "eval",
+			})
+		}
+
+		// SECURITY: Check for shell commands built from strings
+		if rSystemPastePattern.MatchString(line) {
+			report.AddIssue(Issue{
+				Type:     "security",
+				Severity: "high",
+				Message:  "Shell command built with paste() - potential command injection, use system2() with an args vector and shQuote()",
+				File:     file,
+				Line:     i + 1,
+				RuleID:   "command-injection",
+			})
+		}
+
+		// Check for print/cat debugging
+		if rDebugPattern.MatchString(line) {
+			report.AddIssue(Issue{
+				Type:     "quality",
+				Severity: "low",
+				Message:  "print()/cat() found - remove debug output or use message() or a logger",
+				File:     file,
+				Line:     i + 1,
+				RuleID:   "debug-output",
+			})
+		}
+
+		// SECURITY: Check for hardcoded API keys, tokens and passwords
+		if rCredentialPattern.MatchString(line) {
+			report.AddIssue(Issue{
+				Type:     "security",
+				Severity: "high",
+				Message:  "Hardcoded credential - read it with Sys.getenv() or keyring instead",
+				File:     file,
+				Line:     i + 1,
+				RuleID:   "hardcoded-credential",
+			})
+		}
+
+		// Check for packages attached inside functions
+		if inFunction && rLibraryPattern.MatchString(line) {
+			report.AddIssue(Issue{
+				Type:     "quality",
+				Severity: "low",
+				Message:  "library()/require() inside a function attaches the package globally - load it at the top of the script or call pkg::fn()",
+				File:     file,
+				Line:     i + 1,
+				RuleID:   "library-in-function",
+			})
+		}
+	}
+}
@@ -1,15 +1,13 @@
 package review
 
 import (
-	"os"
-	"path/filepath"
+	"fmt"
 	"strings"
 )
 
 // checkRubyQuality analyzes Ruby files for quality and security issues
 func (a *Analyzer) checkRubyQuality(file string, report *Report) {
-	filePath := filepath.Join(a.repoPath, file)
-	content, err := os.ReadFile(filePath)
+	content, err := a.readFile(file)
 	if err != nil {
 		return
 	}
@@ -17,18 +15,23 @@ func (a *Analyzer) checkRubyQuality(file string, report *Report) {
 	contentStr := string(content)
 	lines := strings.Split(contentStr, "\n")
 
-	for i, line := range lines {
-		lineLower := strings.ToLower(line)
+	// Pattern checks see only code, so commented-out code is not reported
+	code := codeLines(lines, LanguageForFile(file))
+	lineLimit := a.lineLengthLimitFor(file)
+
+	for i, raw := range lines {
+		line := code[i]
 		trimmed := strings.TrimSpace(line)
 
 		// Line length check (Ruby style guide recommends 80, but 120 is common)
-		if len(line) > 120 {
+		if len(raw) > lineLimit {
 			report.AddIssue(Issue{
 				Type:     "quality",
-				Severity: "low",
-				Message:  "Line too long (>120 characters)",
+				Severity: "info",
+				Message:  fmt.Sprintf("Line too long (>%d characters)", lineLimit),
 				File:     file,
 				Line:     i + 1,
+				RuleID:   "line-length",
 			})
 		}
 
@@ -42,6 +45,7 @@ func (a *Analyzer) checkRubyQuality(file string, report *Report) {
 					Message:  "Debug output (puts/p/pp) found - remove before production",
 					File:     file,
 					Line:     i + 1,
+					RuleID:   "debug-output",
 				})
 			}
 		}
@@ -54,17 +58,19 @@ func (a *Analyzer) checkRubyQuality(file string, report *Report) {
 				Message:  "Debugger statement found - remove before production",
 				File:     file,
 				Line:     i + 1,
+				RuleID:   "debugger",
 			})
 		}
 
 		// Check for TODO/FIXME comments
-		if strings.Contains(lineLower, "todo") || strings.Contains(lineLower, "fixme") {
+		if rawLower := strings.ToLower(raw); strings.Contains(rawLower, "todo") || strings.Contains(rawLower, "fixme") {
 			report.AddIssue(Issue{
 				Type:     "quality",
-				Severity: "low",
+				Severity: "info",
 				Message:  "TODO/FIXME comment found",
 				File:     file,
 				Line:     i + 1,
+				RuleID:   "todo-comment",
 			})
 		}
 
@@ -76,6 +82,7 @@ func (a *Analyzer) checkRubyQuality(file string, report *Report) {
 				Message:  "eval() usage detected - potential code injection vulnerability",
 				File:     file,
 				Line:     i + 1,
+				RuleID:   "eval",
 			})
 		}
 
@@ -87,6 +94,7 @@ func (a *Analyzer) checkRubyQuality(file string, report *Report) {
 				Message:  "Shell command execution detected - ensure input is sanitized to prevent command injection",
 				File:     file,
 				Line:     i + 1,
+				RuleID:   "shell-exec",
 			})
 		}
 
@@ -99,6 +107,7 @@ func (a *Analyzer) checkRubyQuality(file string, report *Report) {
 					Message:  "Potential SQL injection - use parameterized queries instead of string interpolation",
 					File:     file,
 					Line:     i + 1,
+					RuleID:   "sql-injection",
 				})
 			}
 		}
@@ -111,6 +120,7 @@ func (a *Analyzer) checkRubyQuality(file string, report *Report) {
 				Message:  "Potential mass assignment vulnerability - use strong parameters",
 				File:     file,
 				Line:     i + 1,
+				RuleID:   "mass-assignment",
 			})
 		}
 
@@ -122,6 +132,7 @@ func (a *Analyzer) checkRubyQuality(file string, report *Report) {
 				Message:  "Potential XSS vulnerability - html_safe/raw bypasses HTML escaping",
 				File:     file,
 				Line:     i + 1,
+				RuleID:   "html-safe",
 			})
 		}
 
@@ -133,6 +144,7 @@ func (a *Analyzer) checkRubyQuality(file string, report *Report) {
 				Message:  "Unsafe YAML.load - use YAML.safe_load to prevent code execution",
 				File:     file,
 				Line:     i + 1,
+				RuleID:   "yaml-load",
 			})
 		}
 
@@ -144,6 +156,7 @@ func (a *Analyzer) checkRubyQuality(file string, report *Report) {
 				Message:  "Unsafe deserialization with Marshal - can lead to remote code execution",
 				File:     file,
 				Line:     i + 1,
+				RuleID:   "marshal-load",
 			})
 		}
 
@@ -155,6 +168,7 @@ func (a *Analyzer) checkRubyQuality(file string, report *Report) {
 				Message:  "Generic rescue clause",
 				File:     file,
 				Line:     i + 1,
+				RuleID:   "generic-rescue",
 			})
 		}
 
@@ -166,12 +180,22 @@ func (a *Analyzer) checkRubyQuality(file string, report *Report) {
 				Message:  "Empty rescue block",
 				File:     file,
 				Line:     i + 1,
+				RuleID:   "empty-rescue",
 			})
 		}
 	}
 
 	// Continue with more security checks in a helper function
-	a.checkRubySecurityExtended(file, contentStr, lines, report)
+	a.checkRubySecurityExtended(file, contentStr, code, report)
+	a.checkUploadHandling(file, contentStr, code, report)
+	a.checkInputLimits(file, contentStr, code, report)
+	a.checkAuthRateLimiting(file, contentStr, code, railsAuthRateLimit, report)
+	a.checkHardcodedSalts(file, code, report)
+	a.checkCommandLineSecrets(file, code, report)
+	a.checkOpenRedirects(file, code, report)
+	a.checkBrokerAuth(file, code, report)
+	a.checkCloudIdentifiers(file, code, report)
+	a.checkAnyHTTPMethod(file, code, report)
 }
 
 // checkRubySecurityExtended contains additional Ruby security checks
@@ -179,17 +203,6 @@ func (a *Analyzer) checkRubySecurityExtended(file string, contentStr string, lin
 	for i, line := range lines {
 		lineLower := strings.ToLower(line)
 
-		// SECURITY: Check for open redirect vulnerabilities
-		if strings.Contains(line, "redirect_to") && (strings.Contains(line, "params[") || strings.Contains(line, "request.")) {
-			report.AddIssue(Issue{
-				Type:     "security",
-				Severity: "medium",
-				Message:  "Potential open redirect - validate redirect URLs",
-				File:     file,
-				Line:     i + 1,
-			})
-		}
-
 		// SECURITY: Check for file access with user input
 		if (strings.Contains(line, "File.read(") || strings.Contains(line, "File.open(") || strings.Contains(line, "IO.read(")) && strings.Contains(line, "params[") {
 			report.AddIssue(Issue{
@@ -198,6 +211,7 @@ func (a *Analyzer) checkRubySecurityExtended(file string, contentStr string, lin
 				Message:  "Potential path traversal - validate file paths from user input",
 				File:     file,
 				Line:     i + 1,
+				RuleID:   "path-traversal",
 			})
 		}
 
@@ -209,6 +223,7 @@ func (a *Analyzer) checkRubySecurityExtended(file string, contentStr string, lin
 				Message:  "Dangerous send with user input - can call arbitrary methods",
 				File:     file,
 				Line:     i + 1,
+				RuleID:   "dangerous-send",
 			})
 		}
 
@@ -220,6 +235,7 @@ func (a *Analyzer) checkRubySecurityExtended(file string, contentStr string, lin
 				Message:  "Dangerous constantize with user input - can instantiate arbitrary classes",
 				File:     file,
 				Line:     i + 1,
+				RuleID:   "constantize",
 			})
 		}
 
@@ -231,6 +247,7 @@ func (a *Analyzer) checkRubySecurityExtended(file string, contentStr string, lin
 				Message:  "Dynamic render path with user input - potential information disclosure",
 				File:     file,
 				Line:     i + 1,
+				RuleID:   "dynamic-render",
 			})
 		}
 
@@ -242,6 +259,7 @@ func (a *Analyzer) checkRubySecurityExtended(file string, contentStr string, lin
 				Message:  "Weak hash algorithm (MD5/SHA1) - use SHA256 or stronger",
 				File:     file,
 				Line:     i + 1,
+				RuleID:   "weak-hash",
 			})
 		}
 
@@ -253,6 +271,7 @@ func (a *Analyzer) checkRubySecurityExtended(file string, contentStr string, lin
 				Message:  "SSL verification disabled - vulnerable to man-in-the-middle attacks",
 				File:     file,
 				Line:     i + 1,
+				RuleID:   "ssl-verification-disabled",
 			})
 		}
 
@@ -264,6 +283,7 @@ func (a *Analyzer) checkRubySecurityExtended(file string, contentStr string, lin
 				Message:  "Session manipulation with user input - validate before storing",
 				File:     file,
 				Line:     i + 1,
+				RuleID:   "session-manipulation",
 			})
 		}
 
@@ -276,6 +296,7 @@ func (a *Analyzer) checkRubySecurityExtended(file string, contentStr string, lin
 					Message:  "Unscoped find - consider scoping to current user to prevent unauthorized access",
 					File:     file,
 					Line:     i + 1,
+					RuleID:   "unscoped-find",
 				})
 			}
 		}
@@ -288,6 +309,7 @@ func (a *Analyzer) checkRubySecurityExtended(file string, contentStr string, lin
 				Message:  "Basic authentication detected - ensure credentials are not hardcoded",
 				File:     file,
 				Line:     i + 1,
+				RuleID:   "basic-auth",
 			})
 		}
 
@@ -299,6 +321,7 @@ func (a *Analyzer) checkRubySecurityExtended(file string, contentStr string, lin
 				Message:  "CSRF protection disabled - ensure this is intentional and properly secured",
 				File:     file,
 				Line:     i + 1,
+				RuleID:   "csrf-disabled",
 			})
 		}
 
@@ -310,6 +333,7 @@ func (a *Analyzer) checkRubySecurityExtended(file string, contentStr string, lin
 				Message:  "Open parameters detected - use strong parameters to whitelist allowed attributes",
 				File:     file,
 				Line:     i + 1,
+				RuleID:   "permit-all",
 			})
 		}
 
@@ -321,6 +345,7 @@ func (a *Analyzer) checkRubySecurityExtended(file string, contentStr string, lin
 				Message:  "Potential N+1 query detected",
 				File:     file,
 				Line:     i + 1,
+				RuleID:   "n-plus-one",
 			})
 		}
 
@@ -332,6 +357,7 @@ func (a *Analyzer) checkRubySecurityExtended(file string, contentStr string, lin
 				Message:  "Model without validations",
 				File:     file,
 				Line:     i + 1,
+				RuleID:   "model-without-validations",
 			})
 		}
 
@@ -344,6 +370,7 @@ func (a *Analyzer) checkRubySecurityExtended(file string, contentStr string, lin
 				Message:  "Too many callbacks detected",
 				File:     file,
 				Line:     i + 1,
+				RuleID:   "too-many-callbacks",
 			})
 		}
 
@@ -355,6 +382,7 @@ func (a *Analyzer) checkRubySecurityExtended(file string, contentStr string, lin
 				Message:  "Database query inside loop",
 				File:     file,
 				Line:     i + 1,
+				RuleID:   "query-in-loop",
 			})
 		}
 
@@ -366,6 +394,7 @@ func (a *Analyzer) checkRubySecurityExtended(file string, contentStr string, lin
 				Message:  "String concatenation with +=",
 				File:     file,
 				Line:     i + 1,
+				RuleID:   "string-concat-in-loop",
 			})
 		}
 	}
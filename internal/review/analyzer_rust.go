@@ -0,0 +1,155 @@
+package review
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+var (
+	// unwrap() and expect() calls that panic on Err or None
+	rustUnwrapPattern = regexp.MustCompile(`\.(unwrap|expect)\s*\(`)
+	// Leftover debug output macros
+	rustDebugMacroPattern = regexp.MustCompile(`\b(println|eprintln|print|dbg)!\s*\(`)
+	// unsafe blocks, functions, impls and traits
+	rustUnsafePattern = regexp.MustCompile(`\bunsafe\s+(fn|impl|trait)\b|\bunsafe\s*\{`)
+	// mem::transmute calls, with or without a turbofish
+	rustTransmutePattern = regexp.MustCompile(`\btransmute\s*(::\s*<[^>]*>\s*)?\(`)
+	// Commands whose program is built with format!
+	rustCommandFormatPattern = regexp.MustCompile(`Command::new\s*\(\s*&?format!`)
+	// Variables holding a format! string, e.g. let cmd = format!("ls {}", dir);
+	rustFormatAssignPattern = regexp.MustCompile(`let\s+(?:mut\s+)?(\w+)\s*(?::\s*String\s*)?=\s*format!`)
+	// Arguments passed to Command::new or .arg/.args, capturing a plain variable
+	rustCommandArgPattern = regexp.MustCompile(`(?:Command::new|\.args?)\s*\(\s*&?\[?\s*&?(\w+)`)
+	// Shells whose arguments are interpreted as a command line
+	rustShellPattern = regexp.MustCompile(`Command::new\s*\(\s*"(sh|bash|zsh|cmd|cmd\.exe|powershell)"`)
+)
+
+// checkRustQuality analyzes Rust files for quality and security issues
+func (a *Analyzer) checkRustQuality(file string, report *Report) {
+	content, err := a.readFile(file)
+	if err != nil {
+		return
+	}
+
+	contentStr := string(content)
+	lines := strings.Split(contentStr, "\n")
+
+	// Integration tests live under tests/; unit tests follow #[cfg(test)]
+	inTests := strings.HasPrefix(filepath.ToSlash(file), "tests/") || strings.Contains(filepath.ToSlash(file), "/tests/")
+	// inShellCommand is set while a Command::new("sh") builder chain is open
+	inShellCommand := false
+	// formatted holds variables assigned from format!
+	formatted := map[string]bool{}
+
+	// Pattern checks see only code, so commented-out code is not reported
+	code := codeLines(lines, LanguageForFile(file))
+	lineLimit := a.lineLengthLimitFor(file)
+
+	for i, raw := range lines {
+		line := code[i]
+		trimmed := strings.TrimSpace(line)
+
+		if strings.HasPrefix(trimmed, "#[cfg(test)]") {
+			inTests = true
+		}
+
+		// Check for TODO/FIXME comments
+		if rawLower := strings.ToLower(raw); strings.Contains(rawLower, "todo") || strings.Contains(rawLower, "fixme") {
+			report.AddIssue(Issue{
+				Type:     "quality",
+				Severity: "info",
+				Message:  "TODO/FIXME comment found",
+				File:     file,
+				Line:     i + 1,
+				RuleID:   "todo-comment",
+			})
+		}
+
+		// Line length check (rustfmt defaults to 100, but 120 is common)
+		if len(raw) > lineLimit {
+			report.AddIssue(Issue{
+				Type:     "quality",
+				Severity: "info",
+				Message:  fmt.Sprintf("Line too long (>%d characters)", lineLimit),
+				File:     file,
+				Line:     i + 1,
+				RuleID:   "line-length",
+			})
+		}
+
+		// Check for unwrap()/expect() outside tests
+		if !inTests && rustUnwrapPattern.MatchString(line) {
+			report.AddIssue(Issue{
+				Type:     "quality",
+				Severity: "medium",
+				Message:  "unwrap()/expect() can panic - handle the error or propagate it with ?",
+				File:     file,
+				Line:     i + 1,
+				RuleID:   "rust-unwrap",
+			})
+		}
+
+		// Check for println!/dbg! debug output
+		if rustDebugMacroPattern.MatchString(line) {
+			report.AddIssue(Issue{
+				Type:     "quality",
+				Severity: "low",
+				Message:  "Debug output (println!/dbg!) found - use a logging crate or remove before production",
+				File:     file,
+				Line:     i + 1,
+				RuleID:   "debug-output",
+			})
+		}
+
+		// SECURITY: Check for unsafe code
+		if rustUnsafePattern.MatchString(line) {
+			report.AddIssue(Issue{
+				Type:     "security",
+				Severity: "medium",
+				Message:  "unsafe code - document the invariants it relies on and keep the block minimal",
+				File:     file,
+				Line:     i + 1,
+				RuleID:   "rust-unsafe",
+			})
+		}
+
+		// SECURITY: Check for mem::transmute
+		if rustTransmutePattern.MatchString(line) {
+			report.AddIssue(Issue{
+				Type:     "security",
+				Severity: "high",
+				Message:  "mem::transmute reinterprets memory without checks - prefer safe conversions such as from_ne_bytes or as casts",
+				File:     file,
+				Line:     i + 1,
+				RuleID:   "rust-transmute",
+			})
+		}
+
+		// SECURITY: Check for commands built with format! interpolation
+		if m := rustFormatAssignPattern.FindStringSubmatch(line); m != nil {
+			formatted[m[1]] = true
+		}
+		if rustShellPattern.MatchString(line) {
+			inShellCommand = true
+		}
+		interpolated := strings.Contains(line, "format!(")
+		for _, m := range rustCommandArgPattern.FindAllStringSubmatch(line, -1) {
+			interpolated = interpolated || formatted[m[1]]
+		}
+		if rustCommandFormatPattern.MatchString(line) || interpolated && (inShellCommand && strings.Contains(line, ".arg") || strings.Contains(line, "Command::new")) {
+			report.AddIssue(Issue{
+				Type:     "security",
+				Severity: "medium",
+				Message:  "Command built with format! interpolation - potential command injection, pass arguments separately with .arg()",
+				File:     file,
+				Line:     i + 1,
+				RuleID:   "command-injection",
+			})
+		}
+		if strings.Contains(line, ";") {
+			inShellCommand = false
+		}
+	}
+}
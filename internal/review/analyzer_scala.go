@@ -0,0 +1,124 @@
+package review
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	// Console output left over from debugging
+	scalaPrintlnPattern = regexp.MustCompile(`(^|[^\w.])(println|print)\s*\(`)
+	// Await.result or Await.ready without a timeout
+	scalaAwaitInfPattern = regexp.MustCompile(`\bAwait\.(result|ready)\s*\(.*Duration\.Inf\b`)
+	// null literals; comparisons against null are left alone for Java interop
+	scalaNullPattern           = regexp.MustCompile(`\bnull\b`)
+	scalaNullComparisonPattern = regexp.MustCompile(`[!=]=\s*null\b|\bnull\s*[!=]=`)
+	// .get on an Option, which throws on None; Map.get(key) and getOrElse do not match
+	scalaOptionGetPattern = regexp.MustCompile(`\.get\s*($|[^\w(\s]|\s+[^\w(\s])|\.get\s*\(\s*\)`)
+	// Catch-all cases that also swallow fatal errors
+	scalaCatchThrowablePattern = regexp.MustCompile(`\bcase\s+\w+\s*:\s*Throwable\s*=>`)
+	// Interpolated strings passed to Spark SQL or JDBC statements
+	scalaSQLInterpolationPattern = regexp.MustCompile(`\b(sql|executeQuery|executeUpdate|execute|prepareStatement|addBatch)\s*\(\s*(s|f)"+[^"]*\$`)
+)
+
+// checkScalaQuality analyzes Scala files for quality and security issues
+func (a *Analyzer) checkScalaQuality(file string, report *Report) {
+	content, err := a.readFile(file)
+	if err != nil {
+		return
+	}
+
+	contentStr := string(content)
+	lines := strings.Split(contentStr, "\n")
+
+	// Pattern checks see only code, so commented-out code is not reported
+	code := codeLines(lines, LanguageForFile(file))
+
+	for i, raw := range lines {
+		line := code[i]
+
+		// Check for TODO/FIXME comments
+		if rawLower := strings.ToLower(raw); strings.Contains(rawLower, "todo") || strings.Contains(rawLower, "fixme") {
+			report.AddIssue(Issue{
+				Type:     "quality",
+				Severity: "info",
+				Message:  "TODO/FIXME comment found",
+				File:     file,
+				Line:     i + 1,
+				RuleID:   "todo-comment",
+			})
+		}
+
+		// Check for println debugging
+		if scalaPrintlnPattern.MatchString(line) {
+			report.AddIssue(Issue{
+				Type:     "quality",
+				Severity: "low",
+				Message:  "println() found - use a logger instead",
+				File:     file,
+				Line:     i + 1,
+				RuleID:   "println",
+			})
+		}
+
+		// Check for futures awaited forever
+		if scalaAwaitInfPattern.MatchString(line) {
+			report.AddIssue(Issue{
+				Type:     "quality",
+				Severity: "medium",
+				Message:  "Await with Duration.Inf blocks forever if the future never completes - use a finite timeout",
+				File:     file,
+				Line:     i + 1,
+				RuleID:   "await-infinite",
+			})
+		}
+
+		// Check for null literals
+		if scalaNullPattern.MatchString(line) && !scalaNullComparisonPattern.MatchString(line) {
+			report.AddIssue(Issue{
+				Type:     "quality",
+				Severity: "low",
+				Message:  "null literal used - prefer Option to represent a missing value",
+				File:     file,
+				Line:     i + 1,
+				RuleID:   "null-literal",
+			})
+		}
+
+		// Check for Option.get
+		if scalaOptionGetPattern.MatchString(line) {
+			report.AddIssue(Issue{
+				Type:     "quality",
+				Severity: "medium",
+				Message:  ".get on an Option throws on None - use getOrElse, fold or pattern matching",
+				File:     file,
+				Line:     i + 1,
+				RuleID:   "option-get",
+			})
+		}
+
+		// Check for catch-all Throwable cases
+		if scalaCatchThrowablePattern.MatchString(line) {
+			report.AddIssue(Issue{
+				Type:     "quality",
+				Severity: "medium",
+				Message:  "Catching Throwable also swallows fatal errors such as OutOfMemoryError - catch NonFatal(e) instead",
+				File:     file,
+				Line:     i + 1,
+				RuleID:   "catch-throwable",
+			})
+		}
+
+		// SECURITY: Check for SQL built with string interpolation
+		if scalaSQLInterpolationPattern.MatchString(line) {
+			report.AddIssue(Issue{
+				Type:     "security",
+				Severity: "high",
+				Message:  "Potential SQL injection - interpolated string passed to spark.sql or a JDBC statement, use bind parameters or the DataFrame API",
+				File:     file,
+				Line:     i + 1,
+				RuleID:   "sql-injection",
+			})
+		}
+	}
+}
@@ -0,0 +1,225 @@
+package review
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	// Remote scripts piped or substituted straight into a shell
+	shellCurlPipePattern = regexp.MustCompile(`\b(curl|wget)\b[^|]*\|\s*(sudo\s+)?(\S*/)?(ba|z|k|da)?sh\b|\b(ba|z)?sh\s+(-c\s+)?["']?\$\(\s*(curl|wget)\b|<\(\s*(curl|wget)\b`)
+	// eval as a command, e.g. eval "$cmd" or ... && eval $cmd
+	shellEvalPattern = regexp.MustCompile(`(^|[\s;&|({])eval\s`)
+	// rm with its arguments up to the end of the command
+	shellRmPattern = regexp.MustCompile(`(^|[\s;&|({])rm\s+([^;&|]*)`)
+	// rm flags that recurse and force
+	shellRecursivePattern = regexp.MustCompile(`(^|\s)(-[a-zA-Z]*[rR][a-zA-Z]*|--recursive)\b`)
+	shellForcePattern     = regexp.MustCompile(`(^|\s)(-[a-zA-Z]*f[a-zA-Z]*|--force)\b`)
+	// ${VAR:?} expansions abort the script when VAR is empty or unset
+	shellGuardedVarPattern = regexp.MustCompile(`\$\{\w+:\?[^}]*\}`)
+	// A parameter expansion at the start of the string; ${#VAR} expands to a number
+	shellVariablePattern = regexp.MustCompile(`^\$([A-Za-z_][A-Za-z0-9_]*|[0-9@*]|\{[^}#][^}]*\})`)
+	// An assignment word, whose value is not split, e.g. dir=$1 or local path=$HOME
+	shellAssignmentPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*(\[[^]]*\])?\+?=`)
+	// The opening of a here-document, capturing its delimiter
+	shellHeredocPattern = regexp.MustCompile(`<<-?\s*["']?(\w+)["']?`)
+)
+
+// checkShellQuality analyzes shell scripts for quality and security issues
+func (a *Analyzer) checkShellQuality(file string, report *Report) {
+	content, err := a.readFile(file)
+	if err != nil {
+		return
+	}
+
+	contentStr := string(content)
+	lines := strings.Split(contentStr, "\n")
+
+	// Only executable scripts are expected to enable strict mode; files without a
+	// shebang are sourced and should not change the caller's options
+	if strings.HasPrefix(contentStr, "#!") && !shellStrictMode(lines) {
+		report.AddIssue(Issue{
+			Type:     "quality",
+			Severity: "medium",
+			Message:  "Missing set -euo pipefail at the top of the script - failed commands, unset variables and broken pipes are silently ignored",
+			File:     file,
+			RuleID:   "shell-strict-mode",
+		})
+	}
+
+	// heredocEnd is the delimiter of the here-document being skipped
+	heredocEnd := ""
+
+	for i, line := range lines {
+		lineLower := strings.ToLower(line)
+		trimmed := strings.TrimSpace(line)
+
+		// Check for TODO/FIXME comments
+		if strings.Contains(lineLower, "todo") || strings.Contains(lineLower, "fixme") {
+			report.AddIssue(Issue{
+				Type:     "quality",
+				Severity: "info",
+				Message:  "TODO/FIXME comment found",
+				File:     file,
+				Line:     i + 1,
+				RuleID:   "todo-comment",
+			})
+		}
+
+		// The remaining checks are about commands, not comments or here-document text
+		if heredocEnd != "" {
+			if trimmed == heredocEnd {
+				heredocEnd = ""
+			}
+			continue
+		}
+		if strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		if m := shellHeredocPattern.FindStringSubmatch(line); m != nil {
+			heredocEnd = m[1]
+		}
+
+		// SECURITY: Check for remote scripts piped into a shell
+		if shellCurlPipePattern.MatchString(line) {
+			report.AddIssue(Issue{
+				Type:     "security",
+				Severity: "high",
+				Message:  "Remote script piped into a shell - download it, verify its checksum or signature, then run it",
+				File:     file,
+				Line:     i + 1,
+				RuleID:   "curl-pipe-shell",
+			})
+		}
+
+		// SECURITY: Check for eval
+		if shellEvalPattern.MatchString(line) {
+			report.AddIssue(Issue{
+				Type:     "security",
+				Severity: "high",
+				Message:  "Use of eval - its arguments are parsed as code, a potential command injection",
+				File:     file,
+				Line:     i + 1,
+				RuleID:   "shell-eval",
+			})
+		}
+
+		// SECURITY: Check for rm -rf on a path built from a variable
+		if m := shellRmPattern.FindStringSubmatch(line); m != nil {
+			args := m[2]
+			unguarded := shellGuardedVarPattern.ReplaceAllString(args, "")
+			if shellRecursivePattern.MatchString(args) && shellForcePattern.MatchString(args) && strings.Contains(unguarded, "$") {
+				report.AddIssue(Issue{
+					Type:     "security",
+					Severity: "high",
+					Message:  `rm -rf with a variable path - an empty or unset variable can delete the wrong tree, use "${DIR:?}"`,
+					File:     file,
+					Line:     i + 1,
+					RuleID:   "rm-rf-variable",
+				})
+			}
+		}
+
+		// SECURITY: Check for unquoted variables in commands
+		if !strings.HasPrefix(trimmed, "case ") && !strings.HasPrefix(trimmed, "((") && shellUnquotedVariable(line) {
+			report.AddIssue(Issue{
+				Type:     "security",
+				Severity: "low",
+				Message:  `Unquoted variable expansion - word splitting and globbing can inject extra arguments, quote it as "$VAR"`,
+				File:     file,
+				Line:     i + 1,
+				RuleID:   "shell-unquoted-variable",
+			})
+		}
+	}
+
+	a.checkInsecureTransport(file, lines, report)
+	a.checkCommandLineSecrets(file, lines, report)
+}
+
+// shellStrictMode reports whether the set commands at the top of a script, before
+// its first other command, enable errexit, nounset and pipefail
+func shellStrictMode(lines []string) bool {
+	enabled := map[string]bool{}
+	for _, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		fields := strings.Fields(trimmed)
+		if fields[0] != "set" {
+			break
+		}
+		for j := 1; j < len(fields); j++ {
+			if !strings.HasPrefix(fields[j], "-") {
+				continue
+			}
+			for _, flag := range fields[j][1:] {
+				switch flag {
+				case 'e':
+					enabled["errexit"] = true
+				case 'u':
+					enabled["nounset"] = true
+				case 'o':
+					if j+1 < len(fields) {
+						j++
+						enabled[fields[j]] = true
+					}
+				}
+			}
+		}
+	}
+	return enabled["errexit"] && enabled["nounset"] && enabled["pipefail"]
+}
+
+// shellUnquotedVariable reports whether a command line expands a variable outside
+// double quotes. Assignments, [[ ]] tests and arithmetic do not split words, so
+// expansions there are not reported.
+func shellUnquotedVariable(line string) bool {
+	// quoted tracks whether each $( ) nesting level is inside double quotes
+	quoted := []bool{false}
+	testDepth := 0
+	for i := 0; i < len(line); i++ {
+		c := line[i]
+		inDouble := quoted[len(quoted)-1]
+		switch {
+		case c == '\\':
+			i++
+		case c == '\'' && !inDouble:
+			end := strings.IndexByte(line[i+1:], '\'')
+			if end < 0 {
+				return false
+			}
+			i += end + 1
+		case c == '"':
+			quoted[len(quoted)-1] = !inDouble
+		case strings.HasPrefix(line[i:], "$(("):
+			end := strings.Index(line[i:], "))")
+			if end < 0 {
+				return false
+			}
+			i += end + 1
+		case strings.HasPrefix(line[i:], "$("):
+			quoted = append(quoted, false)
+			i++
+		case c == ')' && !inDouble && len(quoted) > 1:
+			quoted = quoted[:len(quoted)-1]
+		case inDouble:
+		case c == '#' && (i == 0 || line[i-1] == ' ' || line[i-1] == '\t'):
+			// The rest of the line is a comment
+			return false
+		case strings.HasPrefix(line[i:], "[["):
+			testDepth++
+			i++
+		case strings.HasPrefix(line[i:], "]]") && testDepth > 0:
+			testDepth--
+			i++
+		case c == '$' && testDepth == 0 && shellVariablePattern.MatchString(line[i:]):
+			word := line[strings.LastIndexAny(line[:i], " \t;&|(")+1 : i]
+			if !shellAssignmentPattern.MatchString(word) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,205 @@
+package review
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var (
+	// tx.origin compared against an address, which any contract the owner calls can pass
+	solidityTxOriginPattern = regexp.MustCompile(`\btx\.origin\s*[!=]=|[!=]=\s*tx\.origin\b`)
+	// A low-level call made as a statement, so its success flag is dropped:
+	// addr.call{value: x}(""), addr.call.value(x)(), addr.send(x), payable(a).delegatecall(data)
+	solidityUncheckedCallPattern = regexp.MustCompile(`^\s*[\w.\[\]]+(?:\([^()]*\))?\.(call|send|delegatecall|staticcall)\b(?:\.value\s*\([^)]*\))?(?:\s*\{[^}]*\})?\s*\(`)
+	// A low-level call that forwards all remaining gas, letting the callee re-enter
+	solidityExternalCallPattern = regexp.MustCompile(`\.call\b(?:\.value\s*\([^)]*\))?\s*[({]`)
+	// Block values hashed or reduced modulo n to pick a random outcome
+	solidityWeakRandomPattern = regexp.MustCompile(`keccak256\s*\(.*\b(block\.(?:timestamp|difficulty|prevrandao|number|coinbase)|blockhash|now)\b|\b(block\.(?:timestamp|difficulty|prevrandao)|now)\s*%`)
+	// selfdestruct, and suicide from before Solidity 0.5
+	soliditySelfdestructPattern = regexp.MustCompile(`\b(selfdestruct|suicide)\s*\(`)
+	// A for loop bounded by an array's length, capturing the array
+	solidityLengthLoopPattern = regexp.MustCompile(`\bfor\s*\([^;]*;[^;]*<=?\s*(\w+)\.length\b`)
+	// A contract-level variable declaration, capturing its type and name
+	solidityStateVarPattern = regexp.MustCompile(`^\s*(mapping\s*\(.*\)|[\w.]+(?:\s*\[[^\]]*\])*)\s+(?:(?:public|private|internal|constant|immutable|override|transient)\s+)*(\w+)\s*(?:=[^=>]|;)`)
+	// Declarations at contract level that are not state variables
+	solidityMemberKeywordPattern = regexp.MustCompile(`^\s*(function|event|modifier|constructor|struct|enum|error|using|fallback|receive|return|emit)\b`)
+	// A function header, including fallback and receive, capturing the name
+	solidityFunctionPattern = regexp.MustCompile(`\bfunction\s+(\w+)|\b(fallback|receive)\s*\(`)
+	// An assignment, ++/--, or delete of a variable, its elements or its fields,
+	// capturing the variable
+	solidityAssignmentPattern = regexp.MustCompile(`(?:^|[^\w.])(\w+)(?:\s*\[[^\]]*\])*(?:\.\w+)*\s*(?:[-+*/%|&^]?=[^=]|\+\+|--)|\bdelete\s+(\w+)`)
+	// Modifiers that lock a function against reentrant calls
+	solidityReentrancyGuardPattern = regexp.MustCompile(`\b(nonReentrant|noReentrancy|lock)\b`)
+)
+
+// solidityStateVars returns the contract-level variables of a Solidity file, and
+// the names of those that are dynamic arrays, which can grow without bound
+func solidityStateVars(code []string) (vars, dynamicArrays map[string]bool) {
+	vars, dynamicArrays = map[string]bool{}, map[string]bool{}
+	depth := 0
+	for _, line := range code {
+		if depth == 1 && !solidityMemberKeywordPattern.MatchString(line) {
+			if m := solidityStateVarPattern.FindStringSubmatch(line); m != nil {
+				vars[m[2]] = true
+				if strings.HasSuffix(strings.ReplaceAll(m[1], " ", ""), "[]") {
+					dynamicArrays[m[2]] = true
+				}
+			}
+		}
+		depth += strings.Count(line, "{") - strings.Count(line, "}")
+	}
+	return vars, dynamicArrays
+}
+
+// solidityStateWrite reports whether a line assigns to, increments or deletes
+// one of the state variables
+func solidityStateWrite(line string, vars map[string]bool) bool {
+	for _, m := range solidityAssignmentPattern.FindAllStringSubmatch(line, -1) {
+		if vars[m[1]] || vars[m[2]] {
+			return true
+		}
+	}
+	return false
+}
+
+// checkSolidityQuality analyzes Solidity smart contracts for the weaknesses
+// that most often lose funds: tx.origin authorization, unchecked low-level
+// calls, block values used as randomness, selfdestruct, loops over arrays that
+// grow without bound and external calls made before state updates.
+func (a *Analyzer) checkSolidityQuality(file string, report *Report) {
+	content, err := a.readFile(file)
+	if err != nil {
+		return
+	}
+
+	lines := strings.Split(string(content), "\n")
+
+	// Pattern checks see only code, so commented-out code is not reported
+	code := codeLines(lines, LanguageForFile(file))
+	stateVars, dynamicArrays := solidityStateVars(code)
+
+	// The function being read: its name, whether a guard modifier was seen on
+	// its header, the brace depth of its body and the line of its first external call
+	var function struct {
+		name      string
+		guarded   bool
+		inHeader  bool
+		inBody    bool
+		bodyDepth int
+		callLine  int
+	}
+	depth := 0
+
+	for i, raw := range lines {
+		line := code[i]
+
+		// Check for TODO/FIXME comments
+		if rawLower := strings.ToLower(raw); strings.Contains(rawLower, "todo") || strings.Contains(rawLower, "fixme") {
+			report.AddIssue(Issue{
+				Type:     "quality",
+				Severity: "info",
+				Message:  "TODO/FIXME comment found",
+				File:     file,
+				Line:     i + 1,
+				RuleID:   "todo-comment",
+			})
+		}
+
+		// SECURITY: Check for tx.origin authorization
+		if solidityTxOriginPattern.MatchString(line) {
+			report.AddIssue(Issue{
+				Type:     "security",
+				Severity: "high",
+				Message:  "tx.origin used for authorization - a malicious contract the owner interacts with passes the check, compare msg.sender instead",
+				File:     file,
+				Line:     i + 1,
+				RuleID:   "sol-tx-origin",
+			})
+		}
+
+		// SECURITY: Check for low-level calls whose result is ignored
+		if m := solidityUncheckedCallPattern.FindStringSubmatch(line); m != nil {
+			report.AddIssue(Issue{
+				Type:     "security",
+				Severity: "high",
+				Message:  fmt.Sprintf("Return value of .%s is not checked - a failed call does not revert, so execution continues as if it succeeded, check the returned bool with require", m[1]),
+				File:     file,
+				Line:     i + 1,
+				RuleID:   "sol-unchecked-call",
+			})
+		}
+
+		// SECURITY: Check for block values used as randomness
+		if solidityWeakRandomPattern.MatchString(line) {
+			report.AddIssue(Issue{
+				Type:     "security",
+				Severity: "high",
+				Message:  "Block values used as a source of randomness - validators can choose or predict them, use a verifiable random function such as Chainlink VRF or commit-reveal",
+				File:     file,
+				Line:     i + 1,
+				RuleID:   "sol-weak-randomness",
+			})
+		}
+
+		// SECURITY: Check for selfdestruct
+		if m := soliditySelfdestructPattern.FindStringSubmatch(line); m != nil {
+			report.AddIssue(Issue{
+				Type:     "security",
+				Severity: "medium",
+				Message:  fmt.Sprintf("%s() found - it is deprecated and, if reachable by the wrong caller, destroys the contract and sends its balance away, disable the contract with a paused flag instead", m[1]),
+				File:     file,
+				Line:     i + 1,
+				RuleID:   "sol-selfdestruct",
+			})
+		}
+
+		// SECURITY: Check for loops over state arrays that grow without bound
+		if m := solidityLengthLoopPattern.FindStringSubmatch(line); m != nil && dynamicArrays[m[1]] {
+			report.AddIssue(Issue{
+				Type:     "security",
+				Severity: "medium",
+				Message:  fmt.Sprintf("Loop over the dynamic array %s - once it grows large enough the loop runs out of gas and the function can never succeed, paginate or use a mapping", m[1]),
+				File:     file,
+				Line:     i + 1,
+				RuleID:   "sol-unbounded-loop",
+			})
+		}
+
+		// SECURITY: Check for external calls followed by state writes in
+		// functions without a reentrancy guard (checks-effects-interactions)
+		if m := solidityFunctionPattern.FindStringSubmatch(line); m != nil && !function.inBody {
+			function.name, function.guarded, function.inHeader, function.callLine = m[1]+m[2], false, true, -1
+		}
+		if function.inHeader {
+			function.guarded = function.guarded || solidityReentrancyGuardPattern.MatchString(line)
+			if strings.Contains(line, "{") {
+				function.inHeader, function.inBody, function.bodyDepth = false, true, depth
+			} else if strings.Contains(line, ";") {
+				// An interface or abstract function has no body
+				function.inHeader = false
+			}
+		}
+		if function.inBody && !function.guarded {
+			if function.callLine < 0 && solidityExternalCallPattern.MatchString(line) {
+				function.callLine = i
+			} else if function.callLine >= 0 && solidityStateWrite(line, stateVars) {
+				report.AddIssue(Issue{
+					Type:     "security",
+					Severity: "high",
+					Message:  fmt.Sprintf("External call in %s() is followed by a state update on line %d - the callee can re-enter before the update, update state before the call or add a nonReentrant guard", function.name, i+1),
+					File:     file,
+					Line:     function.callLine + 1,
+					RuleID:   "sol-reentrancy",
+				})
+				// One report per function
+				function.guarded = true
+			}
+		}
+
+		depth += strings.Count(line, "{") - strings.Count(line, "}")
+		if function.inBody && depth <= function.bodyDepth {
+			function.inBody = false
+		}
+	}
+}
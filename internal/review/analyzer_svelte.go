@@ -0,0 +1,56 @@
+package review
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	// {@html ...} tags, which render their expression as raw HTML
+	svelteHTMLTagPattern = regexp.MustCompile(`\{@html\b`)
+	// bind:innerHTML on contenteditable elements
+	svelteBindInnerHTMLPattern = regexp.MustCompile(`\bbind:innerHTML\b`)
+)
+
+// checkSvelteQuality analyzes Svelte components. The <script> blocks get the
+// JavaScript or TypeScript checks and the markup is checked for XSS.
+func (a *Analyzer) checkSvelteQuality(file string, report *Report) {
+	content, err := a.readFile(file)
+	if err != nil {
+		return
+	}
+
+	contentStr := string(content)
+	a.checkComponentScript(file, contentStr, report)
+
+	lines := strings.Split(componentMarkup(contentStr), "\n")
+
+	// Pattern checks see only markup, so commented-out elements are not reported
+	code := codeLines(lines, LanguageForFile(file))
+
+	for i, line := range code {
+		// SECURITY: Check for raw HTML rendering
+		if svelteHTMLTagPattern.MatchString(line) {
+			report.AddIssue(Issue{
+				Type:     "security",
+				Severity: "high",
+				Message:  "{@html} renders raw HTML - potential XSS vulnerability, use a plain {expression} or sanitize the content with DOMPurify",
+				File:     file,
+				Line:     i + 1,
+				RuleID:   "raw-html",
+			})
+		}
+
+		// SECURITY: Check for HTML bound to editable elements
+		if svelteBindInnerHTMLPattern.MatchString(line) {
+			report.AddIssue(Issue{
+				Type:     "security",
+				Severity: "high",
+				Message:  "bind:innerHTML reads and writes raw HTML - potential XSS vulnerability, bind:textContent or sanitize the content with DOMPurify",
+				File:     file,
+				Line:     i + 1,
+				RuleID:   "raw-html",
+			})
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package review
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	// Console output left over from debugging
+	swiftPrintPattern = regexp.MustCompile(`(^|[^\w.])(print|debugPrint|NSLog)\s*\(`)
+	// try! crashes the app when the call throws
+	swiftForceTryPattern = regexp.MustCompile(`\btry!`)
+)
+
+// checkSwiftQuality analyzes Swift files for quality and security issues
+func (a *Analyzer) checkSwiftQuality(file string, report *Report) {
+	content, err := a.readFile(file)
+	if err != nil {
+		return
+	}
+
+	contentStr := string(content)
+	lines := strings.Split(contentStr, "\n")
+
+	// Pattern checks see only code, so commented-out code is not reported
+	code := codeLines(lines, LanguageForFile(file))
+
+	for i, raw := range lines {
+		line := code[i]
+
+		// Check for TODO/FIXME comments
+		if rawLower := strings.ToLower(raw); strings.Contains(rawLower, "todo") || strings.Contains(rawLower, "fixme") {
+			report.AddIssue(Issue{
+				Type:     "quality",
+				Severity: "info",
+				Message:  "TODO/FIXME comment found",
+				File:     file,
+				Line:     i + 1,
+				RuleID:   "todo-comment",
+			})
+		}
+
+		// Check for print debugging
+		if swiftPrintPattern.MatchString(line) {
+			report.AddIssue(Issue{
+				Type:     "quality",
+				Severity: "low",
+				Message:  "print() statement found - use os.Logger instead",
+				File:     file,
+				Line:     i + 1,
+				RuleID:   "print-statement",
+			})
+		}
+
+		// Check for try! which crashes on error
+		if swiftForceTryPattern.MatchString(line) {
+			report.AddIssue(Issue{
+				Type:     "quality",
+				Severity: "medium",
+				Message:  "try! crashes if the call throws - use do/catch or try?",
+				File:     file,
+				Line:     i + 1,
+				RuleID:   "force-try",
+			})
+		}
+	}
+
+	a.checkTrustAllCertificates(file, code, report)
+}
@@ -0,0 +1,144 @@
+package review
+
+import (
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// templateDialects are the template languages a file may be written in, keyed
+// by extension. Plain .html files are commonly Jinja, Django, EJS or Angular templates.
+var templateDialects = map[string][]string{
+	".erb":        {"erb"},
+	".ejs":        {"ejs"},
+	".jinja":      {"jinja"},
+	".j2":         {"jinja"},
+	".hbs":        {"handlebars"},
+	".handlebars": {"handlebars"},
+	".mustache":   {"handlebars"},
+	".twig":       {"twig"},
+	".html":       {"jinja", "ejs", "angular"},
+}
+
+var (
+	// Output that skips escaping, keyed by dialect: <%== and raw() or .html_safe in
+	// ERB, | safe and autoescape turned off in Jinja and Django, triple-stache and
+	// {{& in Handlebars and Mustache, | raw in Twig and [innerHTML] in Angular
+	templateUnescapedPatterns = map[string]*regexp.Regexp{
+		"erb":        regexp.MustCompile(`<%==|<%=\s*raw\b|\.html_safe\b`),
+		"jinja":      regexp.MustCompile(`\|\s*safe\b|\{%-?\s*autoescape\s+(false|off)\b`),
+		"handlebars": regexp.MustCompile(`\{\{\{|\{\{&`),
+		"twig":       regexp.MustCompile(`\|\s*raw\b|\{%-?\s*autoescape\s+false\b`),
+		"angular":    regexp.MustCompile(`\[innerHTML\]\s*=`),
+	}
+	// EJS <%- tags output unescaped, capturing the first word so includes can be told apart
+	ejsUnescapedTagPattern = regexp.MustCompile(`<%-\s*(\w*)`)
+	// Tags that output a value, keyed by dialect
+	templateOutputPatterns = map[string]*regexp.Regexp{
+		"erb":        regexp.MustCompile(`<%==?`),
+		"ejs":        regexp.MustCompile(`<%[=-]`),
+		"jinja":      regexp.MustCompile(`\{\{`),
+		"handlebars": regexp.MustCompile(`\{\{`),
+		"twig":       regexp.MustCompile(`\{\{`),
+	}
+	// Filters and helpers that encode a value for use inside a script
+	templateScriptEncodedPattern = regexp.MustCompile(`\b(tojson|escapejs|json_script|json_escape|escape_javascript|json_encode)\b|\|\s*e(scape)?\(\s*['"]js|<%=\s*j\b|\bj\s*\(`)
+	// An opening <script> tag, capturing its attributes
+	templateScriptOpenPattern = regexp.MustCompile(`(?i)<script\b([^>]*)>`)
+	// A closing </script> tag
+	templateScriptClosePattern = regexp.MustCompile(`(?i)</script\s*>`)
+	// A src attribute, which makes a <script> tag load an external file
+	templateScriptSrcPattern = regexp.MustCompile(`(?i)\bsrc\s*=`)
+)
+
+// templateScriptText returns the parts of line inside inline <script> blocks and
+// whether a block is still open at the end of the line
+func templateScriptText(line string, inScript bool) (string, bool) {
+	var script strings.Builder
+	rest := line
+	for rest != "" {
+		if inScript {
+			loc := templateScriptClosePattern.FindStringIndex(rest)
+			if loc == nil {
+				script.WriteString(rest)
+				break
+			}
+			script.WriteString(rest[:loc[0]])
+			inScript, rest = false, rest[loc[1]:]
+			continue
+		}
+		m := templateScriptOpenPattern.FindStringSubmatchIndex(rest)
+		if m == nil {
+			break
+		}
+		// Scripts loaded with src have no inline body to check
+		inScript = !templateScriptSrcPattern.MatchString(rest[m[2]:m[3]])
+		rest = rest[m[1]:]
+	}
+	return script.String(), inScript
+}
+
+// checkTemplateQuality analyzes HTML templates (ERB, EJS, Jinja or Django,
+// Handlebars, Twig and Angular) for output that bypasses HTML escaping
+func (a *Analyzer) checkTemplateQuality(file string, report *Report) {
+	content, err := a.readFile(file)
+	if err != nil {
+		return
+	}
+
+	contentStr := string(content)
+	lines := strings.Split(contentStr, "\n")
+	dialects := templateDialects[strings.ToLower(filepath.Ext(file))]
+
+	// Pattern checks see only markup, so commented-out elements are not reported
+	code := codeLines(lines, LanguageForFile(file))
+	inScript := false
+
+	for i, line := range code {
+		unescaped := false
+		interpolated := false
+		var script string
+		script, inScript = templateScriptText(line, inScript)
+
+		for _, dialect := range dialects {
+			if pattern, ok := templateUnescapedPatterns[dialect]; ok && pattern.MatchString(line) {
+				unescaped = true
+			}
+			if dialect == "ejs" {
+				for _, m := range ejsUnescapedTagPattern.FindAllStringSubmatch(line, -1) {
+					// <%- include(...) renders a partial, which escapes its own output
+					if m[1] != "include" {
+						unescaped = true
+					}
+				}
+			}
+			if pattern, ok := templateOutputPatterns[dialect]; ok && pattern.MatchString(script) {
+				interpolated = true
+			}
+		}
+
+		// SECURITY: Check for output that skips HTML escaping
+		if unescaped {
+			report.AddIssue(Issue{
+				Type:     "security",
+				Severity: "high",
+				Message:  "Template output with escaping disabled - potential XSS vulnerability, output with the escaping tag ({{ }} or <%= %>) or sanitize the HTML first",
+				File:     file,
+				Line:     i + 1,
+				RuleID:   "unescaped-output",
+			})
+		}
+
+		// SECURITY: Check for template variables interpolated into inline scripts
+		if interpolated && !templateScriptEncodedPattern.MatchString(script) {
+			report.AddIssue(Issue{
+				Type:     "security",
+				Severity: "medium",
+				Message:  "Template variable interpolated into an inline <script> - HTML escaping does not protect JavaScript, encode it with tojson/escapejs/json_escape or pass it through a data attribute",
+				File:     file,
+				Line:     i + 1,
+				RuleID:   "script-interpolation",
+			})
+		}
+	}
+}
@@ -0,0 +1,227 @@
+package review
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+)
+
+var (
+	// String literals, removed before counting braces so ${...} interpolation is ignored
+	terraformStringPattern = regexp.MustCompile(`"(?:[^"\\]|\\.)*"`)
+	// A top-level block header, e.g. resource "aws_s3_bucket" "logs" {
+	terraformBlockPattern = regexp.MustCompile(`^\s*(\w+)((?:\s+"[^"]*")*)\s*\{`)
+	// A nested block header, e.g. ingress {
+	terraformNestedBlockPattern = regexp.MustCompile(`^\s*(\w+)\s*\{`)
+	// AWS provider keys set to a literal rather than a variable or interpolation
+	terraformAWSCredentialPattern = regexp.MustCompile(`^\s*(access_key|secret_key|token)\s*=\s*"[^"$]`)
+	// CIDR ranges open to the whole internet
+	terraformOpenCIDRPattern = regexp.MustCompile(`(cidr_blocks|ipv6_cidr_blocks)\s*=\s*\[[^\]]*"(0\.0\.0\.0/0|::/0)"|cidr_ipv[46]\s*=\s*"(0\.0\.0\.0/0|::/0)"`)
+	// Security group rules that only govern outbound traffic
+	terraformEgressRulePattern = regexp.MustCompile(`(?m)^\s*type\s*=\s*"egress"`)
+	// Canned ACLs that make a bucket publicly readable or writable
+	terraformPublicACLPattern = regexp.MustCompile(`^\s*acl\s*=\s*"public-read(-write)?"`)
+	// Arguments and resources that turn on encryption at rest
+	terraformEncryptionPattern = regexp.MustCompile(`encrypted|server_side_encryption|kms_key_id`)
+	// A variable default, or a .tfvars assignment, set to a non-empty string literal
+	terraformDefaultPattern    = regexp.MustCompile(`^\s*default\s*=\s*"[^"]+"`)
+	terraformAssignmentPattern = regexp.MustCompile(`^\s*(\w+)\s*=\s*"[^"]+"`)
+	// Names that suggest a value is a secret
+	terraformSecretNamePattern = regexp.MustCompile(`(?i)password|passwd|secret|token|api_?key|private_?key|access_?key`)
+	// Marks a variable as sensitive
+	terraformSensitivePattern = regexp.MustCompile(`(?m)^\s*sensitive\s*=\s*true`)
+)
+
+// terraformUnencryptedResources are the storage resources expected to enable encryption
+var terraformUnencryptedResources = map[string]bool{"aws_s3_bucket": true, "aws_ebs_volume": true}
+
+// terraformBlock is a top-level block of a Terraform file
+type terraformBlock struct {
+	kind   string
+	labels []string
+	// start is the index of the header line
+	start int
+	// body holds the block's code with string literals kept, for argument checks
+	body []string
+	// defaultLine is the index of the variable's default argument, or -1
+	defaultLine int
+	// openCIDRLines are the indexes of internet-wide CIDRs outside egress blocks
+	openCIDRLines []int
+}
+
+// checkTerraformQuality analyzes Terraform files for security issues in the
+// infrastructure they describe
+func (a *Analyzer) checkTerraformQuality(file string, report *Report) {
+	content, err := a.readFile(file)
+	if err != nil {
+		return
+	}
+
+	contentStr := string(content)
+	lines := strings.Split(contentStr, "\n")
+	code := codeLines(lines, LanguageForFile(file))
+	isVars := strings.HasSuffix(strings.ToLower(file), ".tfvars")
+
+	// Buckets may be encrypted by a separate configuration resource
+	bucketEncryptionConfigured := strings.Contains(contentStr, "aws_s3_bucket_server_side_encryption_configuration")
+
+	var block *terraformBlock
+	// nested holds the names of the open nested blocks and nestedDepths the depth each was opened at
+	var nested []string
+	var nestedDepths []int
+	depth := 0
+
+	for i, raw := range lines {
+		line := code[i]
+
+		// Check for TODO/FIXME comments
+		if rawLower := strings.ToLower(raw); strings.Contains(rawLower, "todo") || strings.Contains(rawLower, "fixme") {
+			report.AddIssue(Issue{
+				Type:     "quality",
+				Severity: "info",
+				Message:  "TODO/FIXME comment found",
+				File:     file,
+				Line:     i + 1,
+				RuleID:   "todo-comment",
+			})
+		}
+
+		// SECURITY: Check for plaintext secrets assigned in .tfvars files
+		if m := terraformAssignmentPattern.FindStringSubmatch(line); isVars && m != nil && terraformSecretNamePattern.MatchString(m[1]) {
+			report.AddIssue(Issue{
+				Type:     "security",
+				Severity: "high",
+				Message:  "Plaintext secret in a variable value - pass it through the environment (TF_VAR_*) or a secrets manager",
+				File:     file,
+				Line:     i + 1,
+				RuleID:   "terraform-plaintext-secret",
+			})
+		}
+
+		bare := terraformStringPattern.ReplaceAllString(line, `""`)
+		if depth == 0 {
+			if m := terraformBlockPattern.FindStringSubmatch(line); m != nil {
+				block = &terraformBlock{kind: m[1], start: i, defaultLine: -1}
+				for _, label := range strings.Fields(m[2]) {
+					block.labels = append(block.labels, strings.Trim(label, `"`))
+				}
+				nested, nestedDepths = nil, nil
+			}
+		} else if m := terraformNestedBlockPattern.FindStringSubmatch(line); m != nil {
+			nested = append(nested, m[1])
+			nestedDepths = append(nestedDepths, depth)
+		}
+
+		if block != nil {
+			block.body = append(block.body, line)
+
+			// SECURITY: Check for AWS credentials hardcoded in the provider block
+			if block.kind == "provider" && block.label(0) == "aws" && terraformAWSCredentialPattern.MatchString(line) {
+				report.AddIssue(Issue{
+					Type:     "security",
+					Severity: "critical",
+					Message:  "AWS credentials hardcoded in the provider block - use environment variables, a shared credentials profile or an assumed role",
+					File:     file,
+					Line:     i + 1,
+					RuleID:   "terraform-hardcoded-credentials",
+				})
+			}
+
+			// SECURITY: Check for S3 buckets with a public canned ACL
+			if terraformPublicACLPattern.MatchString(line) {
+				report.AddIssue(Issue{
+					Type:     "security",
+					Severity: "high",
+					Message:  "Bucket ACL grants public access - keep buckets private and serve content through a CDN or signed URLs",
+					File:     file,
+					Line:     i + 1,
+					RuleID:   "terraform-public-bucket",
+				})
+			}
+
+			if terraformOpenCIDRPattern.MatchString(line) && !slices.Contains(nested, "egress") {
+				block.openCIDRLines = append(block.openCIDRLines, i)
+			}
+			if block.kind == "variable" && len(nested) == 0 && terraformDefaultPattern.MatchString(line) {
+				block.defaultLine = i
+			}
+		}
+
+		for _, c := range bare {
+			switch c {
+			case '{':
+				depth++
+			case '}':
+				depth--
+				if n := len(nested); n > 0 && depth <= nestedDepths[n-1] {
+					nested, nestedDepths = nested[:n-1], nestedDepths[:n-1]
+				}
+			}
+		}
+		if depth <= 0 && block != nil {
+			depth = 0
+			a.checkTerraformBlock(file, block, bucketEncryptionConfigured, report)
+			block = nil
+		}
+	}
+}
+
+// checkTerraformBlock reports the issues that depend on a whole top-level block
+func (a *Analyzer) checkTerraformBlock(file string, block *terraformBlock, bucketEncryptionConfigured bool, report *Report) {
+	body := strings.Join(block.body, "\n")
+	resourceType := ""
+	if block.kind == "resource" {
+		resourceType = block.label(0)
+	}
+
+	// SECURITY: Check for security group rules open to the internet. Egress
+	// rules only govern outbound traffic, so they are not reported.
+	egressOnly := resourceType == "aws_vpc_security_group_egress_rule" ||
+		resourceType == "aws_security_group_rule" && terraformEgressRulePattern.MatchString(body)
+	if !egressOnly {
+		for _, line := range block.openCIDRLines {
+			report.AddIssue(Issue{
+				Type:     "security",
+				Severity: "high",
+				Message:  "Ingress open to 0.0.0.0/0 - restrict the rule to known CIDR ranges or security groups",
+				File:     file,
+				Line:     line + 1,
+				RuleID:   "terraform-open-ingress",
+			})
+		}
+	}
+
+	// SECURITY: Check for storage created without encryption at rest (heuristic)
+	if terraformUnencryptedResources[resourceType] && !terraformEncryptionPattern.MatchString(body) &&
+		!(resourceType == "aws_s3_bucket" && bucketEncryptionConfigured) {
+		report.AddIssue(Issue{
+			Type:     "security",
+			Severity: "medium",
+			Message:  resourceType + " without encryption at rest - enable encryption or add an encryption configuration (heuristic)",
+			File:     file,
+			Line:     block.start + 1,
+			RuleID:   "terraform-unencrypted-storage",
+		})
+	}
+
+	// SECURITY: Check for secrets in variable default values
+	if block.kind == "variable" && block.defaultLine >= 0 &&
+		(terraformSecretNamePattern.MatchString(block.label(0)) || terraformSensitivePattern.MatchString(body)) {
+		report.AddIssue(Issue{
+			Type:     "security",
+			Severity: "high",
+			Message:  "Plaintext secret in a variable default - remove the default and pass the value through the environment (TF_VAR_*) or a secrets manager",
+			File:     file,
+			Line:     block.defaultLine + 1,
+			RuleID:   "terraform-plaintext-secret",
+		})
+	}
+}
+
+// label returns the block's nth label, or "" if it has fewer
+func (b *terraformBlock) label(n int) string {
+	if n < len(b.labels) {
+		return b.labels[n]
+	}
+	return ""
+}
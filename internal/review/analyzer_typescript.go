@@ -1,33 +1,42 @@
 package review
 
 import (
-	"os"
-	"path/filepath"
+	"fmt"
 	"strings"
 )
 
 // checkTypeScriptQuality analyzes TypeScript files for quality and security issues
 func (a *Analyzer) checkTypeScriptQuality(file string, report *Report) {
-	filePath := filepath.Join(a.repoPath, file)
-	content, err := os.ReadFile(filePath)
+	content, err := a.readFile(file)
 	if err != nil {
 		return
 	}
 
-	contentStr := string(content)
+	a.checkTypeScriptSource(file, string(content), report)
+}
+
+// checkTypeScriptSource analyzes TypeScript source read from file, which may be a
+// script block extracted from a component file
+func (a *Analyzer) checkTypeScriptSource(file string, contentStr string, report *Report) {
 	lines := strings.Split(contentStr, "\n")
 
-	for i, line := range lines {
+	// Pattern checks see only code, so commented-out code is not reported
+	code := codeLines(lines, LanguageForFile(file))
+	lineLimit := a.lineLengthLimitFor(file)
+
+	for i, raw := range lines {
+		line := code[i]
 		lineLower := strings.ToLower(line)
 
 		// Line length check
-		if len(line) > 120 {
+		if len(raw) > lineLimit {
 			report.AddIssue(Issue{
 				Type:     "quality",
-				Severity: "low",
-				Message:  "Line too long (>120 characters)",
+				Severity: "info",
+				Message:  fmt.Sprintf("Line too long (>%d characters)", lineLimit),
 				File:     file,
 				Line:     i + 1,
+				RuleID:   "line-length",
 			})
 		}
 
@@ -39,6 +48,7 @@ func (a *Analyzer) checkTypeScriptQuality(file string, report *Report) {
 				Message:  "console.log statement found - remove before production",
 				File:     file,
 				Line:     i + 1,
+				RuleID:   "console-log",
 			})
 		}
 
@@ -50,6 +60,7 @@ func (a *Analyzer) checkTypeScriptQuality(file string, report *Report) {
 				Message:  "debugger statement found - remove before production",
 				File:     file,
 				Line:     i + 1,
+				RuleID:   "debugger",
 			})
 		}
 
@@ -61,28 +72,31 @@ func (a *Analyzer) checkTypeScriptQuality(file string, report *Report) {
 				Message:  "Avoid using 'any' type - use specific types instead",
 				File:     file,
 				Line:     i + 1,
+				RuleID:   "any-type",
 			})
 		}
 
 		// Check for TODO/FIXME comments
-		if strings.Contains(lineLower, "todo") || strings.Contains(lineLower, "fixme") {
+		if rawLower := strings.ToLower(raw); strings.Contains(rawLower, "todo") || strings.Contains(rawLower, "fixme") {
 			report.AddIssue(Issue{
 				Type:     "quality",
-				Severity: "low",
+				Severity: "info",
 				Message:  "TODO/FIXME comment found",
 				File:     file,
 				Line:     i + 1,
+				RuleID:   "todo-comment",
 			})
 		}
 
 		// Check for @ts-ignore usage
-		if strings.Contains(line, "@ts-ignore") || strings.Contains(line, "@ts-nocheck") {
+		if strings.Contains(raw, "@ts-ignore") || strings.Contains(raw, "@ts-nocheck") {
 			report.AddIssue(Issue{
 				Type:     "quality",
 				Severity: "medium",
 				Message:  "TypeScript ignore directive found - consider fixing the type error",
 				File:     file,
 				Line:     i + 1,
+				RuleID:   "ts-ignore",
 			})
 		}
 
@@ -94,6 +108,7 @@ func (a *Analyzer) checkTypeScriptQuality(file string, report *Report) {
 				Message:  "eval() usage detected - potential code injection vulnerability",
 				File:     file,
 				Line:     i + 1,
+				RuleID:   "eval",
 			})
 		}
 
@@ -105,6 +120,7 @@ func (a *Analyzer) checkTypeScriptQuality(file string, report *Report) {
 				Message:  "Function constructor usage - similar risks to eval()",
 				File:     file,
 				Line:     i + 1,
+				RuleID:   "function-constructor",
 			})
 		}
 
@@ -116,6 +132,7 @@ func (a *Analyzer) checkTypeScriptQuality(file string, report *Report) {
 				Message:  "innerHTML/dangerouslySetInnerHTML usage - potential XSS vulnerability",
 				File:     file,
 				Line:     i + 1,
+				RuleID:   "inner-html",
 			})
 		}
 
@@ -127,6 +144,7 @@ func (a *Analyzer) checkTypeScriptQuality(file string, report *Report) {
 				Message:  "document.write usage - potential XSS vulnerability",
 				File:     file,
 				Line:     i + 1,
+				RuleID:   "document-write",
 			})
 		}
 
@@ -138,6 +156,7 @@ func (a *Analyzer) checkTypeScriptQuality(file string, report *Report) {
 				Message:  "child_process/exec usage - ensure input is sanitized to prevent command injection",
 				File:     file,
 				Line:     i + 1,
+				RuleID:   "child-process",
 			})
 		}
 
@@ -149,6 +168,7 @@ func (a *Analyzer) checkTypeScriptQuality(file string, report *Report) {
 				Message:  "Math.random() is not cryptographically secure - use crypto.randomBytes() for security-sensitive operations",
 				File:     file,
 				Line:     i + 1,
+				RuleID:   "insecure-random",
 			})
 		}
 
@@ -160,6 +180,7 @@ func (a *Analyzer) checkTypeScriptQuality(file string, report *Report) {
 				Message:  "SSL verification disabled - vulnerable to man-in-the-middle attacks",
 				File:     file,
 				Line:     i + 1,
+				RuleID:   "ssl-verification-disabled",
 			})
 		}
 
@@ -171,6 +192,7 @@ func (a *Analyzer) checkTypeScriptQuality(file string, report *Report) {
 				Message:  "Potential hardcoded JWT secret - use environment variables",
 				File:     file,
 				Line:     i + 1,
+				RuleID:   "hardcoded-jwt-secret",
 			})
 		}
 
@@ -182,6 +204,7 @@ func (a *Analyzer) checkTypeScriptQuality(file string, report *Report) {
 				Message:  "Potential path traversal - validate and sanitize file paths from user input",
 				File:     file,
 				Line:     i + 1,
+				RuleID:   "path-traversal",
 			})
 		}
 
@@ -193,6 +216,7 @@ func (a *Analyzer) checkTypeScriptQuality(file string, report *Report) {
 				Message:  "Non-literal RegExp - potential ReDoS vulnerability with user input",
 				File:     file,
 				Line:     i + 1,
+				RuleID:   "non-literal-regexp",
 			})
 		}
 
@@ -204,6 +228,7 @@ func (a *Analyzer) checkTypeScriptQuality(file string, report *Report) {
 				Message:  "Object.assign with user input - potential prototype pollution",
 				File:     file,
 				Line:     i + 1,
+				RuleID:   "prototype-pollution",
 			})
 		}
 
@@ -215,6 +240,7 @@ func (a *Analyzer) checkTypeScriptQuality(file string, report *Report) {
 				Message:  "Non-null assertion (!) used - consider proper null checking",
 				File:     file,
 				Line:     i + 1,
+				RuleID:   "non-null-assertion",
 			})
 		}
 
@@ -226,6 +252,7 @@ func (a *Analyzer) checkTypeScriptQuality(file string, report *Report) {
 				Message:  "Potential SQL injection - use parameterized queries instead of string concatenation",
 				File:     file,
 				Line:     i + 1,
+				RuleID:   "sql-injection",
 			})
 		}
 
@@ -237,7 +264,24 @@ func (a *Analyzer) checkTypeScriptQuality(file string, report *Report) {
 				Message:  "Non-literal require() - potential arbitrary code execution",
 				File:     file,
 				Line:     i + 1,
+				RuleID:   "dynamic-require",
 			})
 		}
 	}
+
+	a.checkUploadHandling(file, contentStr, code, report)
+	a.checkInputLimits(file, contentStr, code, report)
+	a.checkResponseContentType(file, code, report)
+	a.checkInsecureTransport(file, code, report)
+	a.checkBrokerAuth(file, code, report)
+	a.checkCloudIdentifiers(file, code, report)
+	a.checkAuthRateLimiting(file, contentStr, code, expressAuthRateLimit, report)
+	a.checkObjectOwnership(file, code, expressObjectOwnership, report)
+	a.checkHardcodedSalts(file, code, report)
+	a.checkInsecureJWT(file, code, report)
+	a.checkOpenRedirects(file, code, report)
+	a.checkAnyHTTPMethod(file, code, report)
+	a.checkBlockingInAsync(file, code, report)
+	a.checkSuppressedLogging(file, code, report)
+	a.checkCommandLineSecrets(file, code, report)
 }
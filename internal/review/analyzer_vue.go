@@ -0,0 +1,83 @@
+package review
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	// v-html bindings, which render their value as raw HTML
+	vueVHTMLPattern = regexp.MustCompile(`(^|\s)v-html\s*=`)
+	// Event handler attributes, capturing their value: @click, v-on:click, onclick and :onclick
+	vueEventHandlerPattern = regexp.MustCompile(`(?:^|\s)(?:@|v-on:|:?on)[\w.:-]+\s*=\s*("[^"]*"|'[^']*')`)
+)
+
+// vueTemplateLines reports which lines fall inside the component's top-level
+// <template>, which may itself contain nested <template> tags
+func vueTemplateLines(lines []string) []bool {
+	inTemplate := make([]bool, len(lines))
+	first, last := -1, -1
+	for i, line := range lines {
+		if first < 0 && strings.Contains(line, "<template") {
+			first = i
+		}
+		if strings.Contains(line, "</template") {
+			last = i
+		}
+	}
+	for i := first; first >= 0 && i <= last; i++ {
+		inTemplate[i] = true
+	}
+	return inTemplate
+}
+
+// checkVueQuality analyzes Vue single-file components. The <script> block gets
+// the JavaScript or TypeScript checks and the <template> is checked for XSS.
+func (a *Analyzer) checkVueQuality(file string, report *Report) {
+	content, err := a.readFile(file)
+	if err != nil {
+		return
+	}
+
+	contentStr := string(content)
+	a.checkComponentScript(file, contentStr, report)
+
+	lines := strings.Split(contentStr, "\n")
+
+	// Pattern checks see only markup, so commented-out elements are not reported
+	code := codeLines(lines, LanguageForFile(file))
+	inTemplate := vueTemplateLines(lines)
+
+	for i, line := range code {
+		if !inTemplate[i] {
+			continue
+		}
+
+		// SECURITY: Check for raw HTML rendering
+		if vueVHTMLPattern.MatchString(line) {
+			report.AddIssue(Issue{
+				Type:     "security",
+				Severity: "high",
+				Message:  "v-html renders raw HTML - potential XSS vulnerability, use text interpolation or sanitize the content with DOMPurify",
+				File:     file,
+				Line:     i + 1,
+				RuleID:   "v-html",
+			})
+		}
+
+		// SECURITY: Check for event handlers built from template strings
+		for _, m := range vueEventHandlerPattern.FindAllStringSubmatch(line, -1) {
+			if strings.Contains(m[1], "${") {
+				report.AddIssue(Issue{
+					Type:     "security",
+					Severity: "medium",
+					Message:  "Inline event handler built with template string interpolation - interpolated data may run as script, call a method and pass the value as an argument",
+					File:     file,
+					Line:     i + 1,
+					RuleID:   "inline-handler-interpolation",
+				})
+				break
+			}
+		}
+	}
+}
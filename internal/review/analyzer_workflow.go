@@ -0,0 +1,155 @@
+package review
+
+import (
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+var (
+	// A run: step, capturing the inline script or block scalar indicator
+	workflowRunPattern = regexp.MustCompile(`^\s*(-\s+)?run:\s*(.*)$`)
+	// Block scalar indicators that start a multi-line script, e.g. | or >-
+	workflowBlockScalarPattern = regexp.MustCompile(`^[|>][-+0-9]*\s*(#.*)?$`)
+	// Attacker-controlled event data interpolated into a script
+	workflowInjectionPattern = regexp.MustCompile(`\$\{\{\s*(github\.event\.[\w.\[\]'"*-]+|github\.head_ref)\s*\}\}`)
+	// Event fields GitHub fills with numbers or hashes, which cannot carry a payload
+	workflowSafeEventFieldPattern = regexp.MustCompile(`[._](number|id|sha)$`)
+	// Secrets printed from a script
+	workflowSecretEchoPattern = regexp.MustCompile(`\b(echo|printf)\b.*\$\{\{\s*secrets\.`)
+	// The pull_request_target trigger, which runs with secrets and a write token
+	workflowPRTargetPattern = regexp.MustCompile(`(?m)^[^#]*\bpull_request_target\b`)
+	// A checkout ref pointing at the pull request's own code
+	workflowPRHeadRefPattern = regexp.MustCompile(`^\s*ref:\s*["']?[^#]*(\$\{\{\s*(github\.event\.pull_request\.head\.(sha|ref)|github\.head_ref)\s*\}\}|refs/pull/)`)
+	// A uses: step referencing an action at a ref
+	workflowUsesPattern = regexp.MustCompile(`^\s*(-\s+)?uses:\s*["']?([^@\s"']+)@([^\s"'#]+)`)
+	// Full commit SHAs, the only immutable action reference
+	workflowSHAPattern = regexp.MustCompile(`^[0-9a-f]{40}$`)
+	// Version tags such as v4 or 1.2.3
+	workflowVersionTagPattern = regexp.MustCompile(`^v?\d+(\.\d+)*$`)
+)
+
+// isWorkflowFile reports whether file is a GitHub Actions workflow, which is
+// recognized by its directory rather than its extension
+func isWorkflowFile(file string) bool {
+	file = filepath.ToSlash(strings.ToLower(file))
+	switch filepath.Ext(file) {
+	case ".yml", ".yaml":
+		return strings.HasPrefix(file, ".github/workflows/") || strings.Contains(file, "/.github/workflows/")
+	}
+	return false
+}
+
+// checkWorkflowQuality analyzes GitHub Actions workflows for script injection and
+// supply chain issues, then runs the config checks every YAML file gets
+func (a *Analyzer) checkWorkflowQuality(file string, report *Report) {
+	content, err := a.readFile(file)
+	if err != nil {
+		return
+	}
+
+	contentStr := string(content)
+	lines := strings.Split(contentStr, "\n")
+	prTarget := workflowPRTargetPattern.MatchString(contentStr)
+
+	// runIndent is the indentation of the run: key whose block script is being read, or -1
+	runIndent := -1
+
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		indent := len(line) - len(strings.TrimLeft(line, " "))
+
+		if runIndent >= 0 {
+			if trimmed == "" {
+				continue
+			}
+			if indent > runIndent {
+				a.checkWorkflowScript(file, i, line, report)
+				continue
+			}
+			runIndent = -1
+		}
+
+		if strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+
+		if m := workflowRunPattern.FindStringSubmatch(line); m != nil {
+			if workflowBlockScalarPattern.MatchString(m[2]) {
+				runIndent = indent
+			} else {
+				a.checkWorkflowScript(file, i, m[2], report)
+			}
+		}
+
+		// SECURITY: Check for pull_request_target workflows running the pull request's code
+		if prTarget && workflowPRHeadRefPattern.MatchString(line) {
+			report.AddIssue(Issue{
+				Type:     "security",
+				Severity: "high",
+				Message:  "pull_request_target workflow checks out the pull request head - untrusted code runs with secrets and a write token",
+				File:     file,
+				Line:     i + 1,
+				RuleID:   "workflow-pr-target-checkout",
+			})
+		}
+
+		// SECURITY: Check for actions not pinned to a commit SHA
+		if m := workflowUsesPattern.FindStringSubmatch(line); m != nil && !strings.HasPrefix(m[2], "./") && !strings.HasPrefix(m[2], "docker://") {
+			switch ref := m[3]; {
+			case workflowSHAPattern.MatchString(ref):
+			case workflowVersionTagPattern.MatchString(ref):
+				report.AddIssue(Issue{
+					Type:     "security",
+					Severity: "low",
+					Message:  "Action " + m[2] + " pinned to tag " + ref + " - tags can be moved, pin to a full commit SHA",
+					File:     file,
+					Line:     i + 1,
+					RuleID:   "workflow-unpinned-action",
+				})
+			default:
+				report.AddIssue(Issue{
+					Type:     "security",
+					Severity: "medium",
+					Message:  "Action " + m[2] + " pinned to branch " + ref + " - every push to it runs in this workflow, pin to a full commit SHA",
+					File:     file,
+					Line:     i + 1,
+					RuleID:   "workflow-unpinned-action",
+				})
+			}
+		}
+	}
+
+	a.checkConfigQuality(file, report)
+}
+
+// checkWorkflowScript checks one line of a run: step's shell script
+func (a *Analyzer) checkWorkflowScript(file string, i int, script string, report *Report) {
+	// SECURITY: Check for event data interpolated into the script
+	for _, m := range workflowInjectionPattern.FindAllStringSubmatch(script, -1) {
+		if workflowSafeEventFieldPattern.MatchString(m[1]) {
+			continue
+		}
+		report.AddIssue(Issue{
+			Type:     "security",
+			Severity: "high",
+			Message:  "${{ " + m[1] + " }} interpolated into a run step - potential script injection, pass it through an env variable instead",
+			File:     file,
+			Line:     i + 1,
+			RuleID:   "workflow-script-injection",
+		})
+		break
+	}
+
+	// SECURITY: Check for secrets written to the log
+	if workflowSecretEchoPattern.MatchString(script) {
+		report.AddIssue(Issue{
+			Type:     "security",
+			Severity: "high",
+			Message:  "Secret echoed in a run step - it may leak into logs or outputs despite masking",
+			File:     file,
+			Line:     i + 1,
+			RuleID:   "workflow-secret-echo",
+		})
+	}
+}
@@ -0,0 +1,166 @@
+package review
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	// Synchronous fs and child_process calls in Node
+	jsBlockingCallPattern = regexp.MustCompile(`\b(fs|child_process|cp)\.\w+Sync\s*\(|\b(readFileSync|writeFileSync|appendFileSync|readdirSync|statSync|execSync|execFileSync|spawnSync)\s*\(`)
+	// Functions that run on the event loop, and plain functions, arrows and methods
+	jsAsyncFunctionPattern = regexp.MustCompile(`\basync\s+(function\b|\(|\w+\s*=>|\*?\s*\w+\s*\()`)
+	jsFunctionPattern      = regexp.MustCompile(`\bfunction\b|=>|^\s*(static\s+)?(get\s+|set\s+)?\*?(\w+)\s*\([^)]*\)\s*(:\s*[^{]+)?\{`)
+	// Calls that block the thread in Python asyncio code
+	pythonBlockingCallPattern = regexp.MustCompile(`\btime\.sleep\s*\(|\brequests\.(get|post|put|patch|delete|head|request)\s*\(|\burlopen\s*\(|\bsubprocess\.(run|call|check_call|check_output)\s*\(`)
+	pythonFunctionPattern     = regexp.MustCompile(`^\s*(async\s+)?def\s+\w+`)
+	// Blocking JDBC calls and block() in Reactor handlers
+	javaBlockingCallPattern   = regexp.MustCompile(`\.(executeQuery|executeUpdate|execute)\s*\(|\bjdbcTemplate\.\w+\s*\(|DriverManager\.getConnection\s*\(|\.block(First|Last)?\s*\(\s*\)`)
+	javaReactiveMethodPattern = regexp.MustCompile(`\b(Mono|Flux)\s*<[^>]*>+\s+\w+\s*\(`)
+	javaMethodPattern         = regexp.MustCompile(`^\s*(public|private|protected|static|final|\s)*[\w<>\[\], ?]+\s+\w+\s*\([^;]*$`)
+	// String literals, removed before counting braces
+	asyncScopeStringPattern = regexp.MustCompile("\"(?:[^\"\\\\]|\\\\.)*\"|'(?:[^'\\\\]|\\\\.)*'|`(?:[^`\\\\]|\\\\.)*`")
+)
+
+// jsControlKeywords look like method declarations to jsFunctionPattern
+var jsControlKeywords = map[string]bool{"if": true, "for": true, "while": true, "switch": true, "catch": true, "with": true, "function": true}
+
+// checkBlockingInAsync flags blocking calls made inside async functions, where they
+// stall the event loop or a reactive scheduler thread for every other task
+func (a *Analyzer) checkBlockingInAsync(file string, lines []string, report *Report) {
+	var blocking *regexp.Regexp
+	var inAsync []bool
+	switch LanguageForFile(file) {
+	case "javascript", "typescript":
+		blocking = jsBlockingCallPattern
+		inAsync = braceAsyncScopes(lines, jsFunctionHeader)
+	case "python":
+		blocking = pythonBlockingCallPattern
+		inAsync = pythonAsyncScopes(lines)
+	case "java":
+		blocking = javaBlockingCallPattern
+		inAsync = braceAsyncScopes(lines, javaMethodHeader)
+	default:
+		return
+	}
+
+	for i, line := range lines {
+		// Check for blocking calls on the event loop
+		if inAsync[i] && blocking.MatchString(line) {
+			report.AddIssue(Issue{
+				Type:     "quality",
+				Severity: "medium",
+				Message:  "Blocking call inside async code - it stalls the event loop, use the async API or run it in an executor",
+				File:     file,
+				Line:     i + 1,
+				RuleID:   "blocking-call-in-async",
+			})
+		}
+	}
+}
+
+// jsFunctionHeader reports whether a line starts a function and whether it is async
+func jsFunctionHeader(line string) (bool, bool) {
+	if jsAsyncFunctionPattern.MatchString(line) {
+		return true, true
+	}
+	m := jsFunctionPattern.FindStringSubmatch(line)
+	if m == nil || jsControlKeywords[m[3]] {
+		return false, false
+	}
+	return true, false
+}
+
+// javaMethodHeader reports whether a line declares a method and whether it returns a Mono or Flux
+func javaMethodHeader(line string) (bool, bool) {
+	if javaReactiveMethodPattern.MatchString(line) {
+		return true, true
+	}
+	trimmed := strings.TrimSpace(line)
+	for _, keyword := range []string{"if", "for", "while", "switch", "catch", "return", "new", "else"} {
+		if strings.HasPrefix(trimmed, keyword+" ") || strings.HasPrefix(trimmed, keyword+"(") {
+			return false, false
+		}
+	}
+	return javaMethodPattern.MatchString(line), false
+}
+
+// braceAsyncScopes reports for each line whether it runs inside an async function.
+// The innermost function decides, so a plain callback inside an async function is
+// not async. A function's body starts at the first { after its header.
+func braceAsyncScopes(lines []string, header func(string) (isFunc, isAsync bool)) []bool {
+	type scope struct {
+		depth int
+		async bool
+	}
+	inAsync := make([]bool, len(lines))
+	var scopes []scope
+	depth := 0
+	pending, pendingAsync := false, false
+
+	for i, line := range lines {
+		isFunc, isAsync := header(line)
+		switch {
+		case isFunc:
+			// The header line holds the parameters and, for one-line functions, the body
+			inAsync[i] = isAsync
+			pending, pendingAsync = true, isAsync
+		case len(scopes) > 0:
+			inAsync[i] = scopes[len(scopes)-1].async
+		}
+
+		for _, c := range asyncScopeStringPattern.ReplaceAllString(line, `""`) {
+			switch c {
+			case '{':
+				depth++
+				if pending {
+					scopes = append(scopes, scope{depth: depth, async: pendingAsync})
+					pending = false
+				}
+			case '}':
+				if n := len(scopes); n > 0 && scopes[n-1].depth == depth {
+					scopes = scopes[:n-1]
+				}
+				depth--
+			}
+		}
+		// An arrow function with an expression body ends with its line
+		if _, body, arrow := strings.Cut(line, "=>"); isFunc && pending && arrow && strings.TrimSpace(body) != "" {
+			pending = false
+		}
+	}
+	return inAsync
+}
+
+// pythonAsyncScopes reports for each line whether it runs inside an async def,
+// following indentation. The innermost def decides.
+func pythonAsyncScopes(lines []string) []bool {
+	type scope struct {
+		indent int
+		async  bool
+	}
+	inAsync := make([]bool, len(lines))
+	var scopes []scope
+
+	for i, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			if len(scopes) > 0 {
+				inAsync[i] = scopes[len(scopes)-1].async
+			}
+			continue
+		}
+		indent := len(line) - len(strings.TrimLeft(line, " \t"))
+		for len(scopes) > 0 && scopes[len(scopes)-1].indent >= indent {
+			scopes = scopes[:len(scopes)-1]
+		}
+
+		if m := pythonFunctionPattern.FindStringSubmatch(line); m != nil {
+			scopes = append(scopes, scope{indent: indent, async: m[1] != ""})
+			continue
+		}
+		if len(scopes) > 0 {
+			inAsync[i] = scopes[len(scopes)-1].async
+		}
+	}
+	return inAsync
+}
@@ -0,0 +1,100 @@
+package review
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Check categories that can be selected with --only
+const (
+	// CategorySecurity is the repository-wide secret and credential scan
+	CategorySecurity = "security"
+	// CategoryQuality is the per-language analyzers and plugins
+	CategoryQuality = "quality"
+	// CategoryPerformance is the performance checks of the per-language analyzers
+	CategoryPerformance = "performance"
+	// CategoryAll selects every category
+	CategoryAll = "all"
+)
+
+// Categories lists the check categories in the order they run
+var Categories = []string{CategorySecurity, CategoryQuality, CategoryPerformance}
+
+// ParseCategories validates and normalizes --only values. Values may be
+// comma-separated; "all" or no values selects every category.
+func ParseCategories(only []string) ([]string, error) {
+	selected := map[string]bool{}
+	for _, value := range only {
+		for _, category := range strings.Split(value, ",") {
+			category = strings.ToLower(strings.TrimSpace(category))
+			switch {
+			case category == "":
+				continue
+			case category == CategoryAll:
+				for _, c := range Categories {
+					selected[c] = true
+				}
+			case slices.Contains(Categories, category):
+				selected[category] = true
+			default:
+				return nil, fmt.Errorf("unknown category %q (expected %s or %s)", category, strings.Join(Categories, ", "), CategoryAll)
+			}
+		}
+	}
+
+	if len(selected) == 0 {
+		return append([]string(nil), Categories...), nil
+	}
+	categories := []string{}
+	for _, category := range Categories {
+		if selected[category] {
+			categories = append(categories, category)
+		}
+	}
+	return categories, nil
+}
+
+// SetCategories restricts which check categories run; empty runs all of them
+func (a *Analyzer) SetCategories(categories []string) {
+	a.categories = map[string]bool{}
+	for _, category := range categories {
+		a.categories[category] = true
+	}
+}
+
+// runsCategory reports whether checks in a category should run
+func (a *Analyzer) runsCategory(category string) bool {
+	return len(a.categories) == 0 || a.categories[category]
+}
+
+// ranCategories lists the categories a run executes, in order
+func (a *Analyzer) ranCategories() []string {
+	ran := []string{}
+	for _, category := range Categories {
+		if a.runsCategory(category) {
+			ran = append(ran, category)
+		}
+	}
+	return ran
+}
+
+// tagCategory records the category on issues added since index from, dropping
+// issues whose category was not selected
+func (a *Analyzer) tagCategory(report *Report, from int, category func(Issue) string) {
+	for i := from; i < len(report.Issues); i++ {
+		report.Issues[i].Category = category(report.Issues[i])
+	}
+	report.FilterIssues(func(issue Issue) bool {
+		return a.runsCategory(issue.Category)
+	})
+}
+
+// languageCheckCategory puts performance findings of the language analyzers in
+// the performance category and everything else in quality
+func languageCheckCategory(issue Issue) string {
+	if issue.Type == "performance" {
+		return CategoryPerformance
+	}
+	return CategoryQuality
+}
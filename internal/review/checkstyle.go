@@ -0,0 +1,83 @@
+package review
+
+import (
+	"cmp"
+	"encoding/xml"
+	"io"
+	"path/filepath"
+	"slices"
+)
+
+// checkstyleResult is the root of a Checkstyle XML report, as reviewdog and
+// Jenkins warnings-ng read it
+type checkstyleResult struct {
+	XMLName xml.Name         `xml:"checkstyle"`
+	Version string           `xml:"version,attr"`
+	Files   []checkstyleFile `xml:"file"`
+}
+
+type checkstyleFile struct {
+	Name   string            `xml:"name,attr"`
+	Errors []checkstyleError `xml:"error"`
+}
+
+type checkstyleError struct {
+	Line     int    `xml:"line,attr,omitempty"`
+	Severity string `xml:"severity,attr"`
+	Message  string `xml:"message,attr"`
+	Source   string `xml:"source,attr"`
+}
+
+// checkstyleSeverity maps a severity onto Checkstyle's error, warning and info
+func checkstyleSeverity(severity string) string {
+	switch severity {
+	case SeverityCritical, SeverityHigh:
+		return "error"
+	case SeverityMedium:
+		return "warning"
+	}
+	return "info"
+}
+
+// OutputCheckstyle writes the report as Checkstyle XML: one file element per
+// file, in path order, with an error element for each of its issues. The
+// source is the issue's rule ID, or its type when it has none.
+func (r *Report) OutputCheckstyle(w io.Writer) error {
+	files := map[string]*checkstyleFile{}
+	for _, issue := range r.Issues {
+		name := filepath.ToSlash(filepath.Clean(issue.File))
+		file, ok := files[name]
+		if !ok {
+			file = &checkstyleFile{Name: name}
+			files[name] = file
+		}
+
+		source := issue.RuleID
+		if source == "" {
+			source = issue.Type
+		}
+		file.Errors = append(file.Errors, checkstyleError{
+			Line:     issue.Line,
+			Severity: checkstyleSeverity(issue.Severity),
+			Message:  issue.Message,
+			Source:   source,
+		})
+	}
+
+	root := checkstyleResult{Version: "4.3", Files: []checkstyleFile{}}
+	for _, file := range files {
+		root.Files = append(root.Files, *file)
+	}
+	slices.SortFunc(root.Files, func(a, b checkstyleFile) int { return cmp.Compare(a.Name, b.Name) })
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(root); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
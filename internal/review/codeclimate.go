@@ -0,0 +1,83 @@
+package review
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"path/filepath"
+	"strconv"
+)
+
+// codeClimateSeverities maps our severities to the Code Climate levels GitLab displays
+var codeClimateSeverities = map[string]string{
+	"critical": "blocker",
+	"high":     "major",
+	"medium":   "minor",
+	"low":      "info",
+	"info":     "info",
+}
+
+// codeClimateIssue is one entry of a Code Climate report as GitLab's Code Quality widget reads it
+type codeClimateIssue struct {
+	Type        string              `json:"type"`
+	CheckName   string              `json:"check_name"`
+	Description string              `json:"description"`
+	Fingerprint string              `json:"fingerprint"`
+	Severity    string              `json:"severity"`
+	Location    codeClimateLocation `json:"location"`
+}
+
+type codeClimateLocation struct {
+	Path  string `json:"path"`
+	Lines struct {
+		Begin int `json:"begin"`
+	} `json:"lines"`
+}
+
+// codeClimateFingerprint is the fingerprint GitLab uses to match an issue
+// between pipelines: a hex SHA-256 of the issue's Fingerprint. GitLab drops
+// entries sharing a fingerprint, so repeats of the same finding in a file are
+// told apart by their occurrence, counted from 0, which stays put when code moves.
+func codeClimateFingerprint(issue Issue, occurrence int) string {
+	key := issue.Fingerprint()
+	if occurrence > 0 {
+		key += "\x00" + strconv.Itoa(occurrence)
+	}
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// OutputCodeClimate writes the report as a Code Climate JSON array, the format
+// GitLab's merge request Code Quality widget ingests
+func (r *Report) OutputCodeClimate(w io.Writer) error {
+	issues := make([]codeClimateIssue, 0, len(r.Issues))
+	occurrences := map[string]int{}
+	for _, issue := range r.Issues {
+		checkName := issue.RuleID
+		if checkName == "" {
+			checkName = issue.Type
+		}
+		severity, ok := codeClimateSeverities[issue.Severity]
+		if !ok {
+			severity = "info"
+		}
+
+		entry := codeClimateIssue{
+			Type:        "issue",
+			CheckName:   checkName,
+			Description: issue.Message,
+			Fingerprint: codeClimateFingerprint(issue, occurrences[issue.Fingerprint()]),
+			Severity:    severity,
+		}
+		entry.Location.Path = filepath.ToSlash(filepath.Clean(issue.File))
+		// Code Climate lines start at 1; whole-file findings point at the first line
+		entry.Location.Lines.Begin = max(issue.Line, 1)
+		issues = append(issues, entry)
+		occurrences[issue.Fingerprint()]++
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(issues)
+}
@@ -0,0 +1,113 @@
+package review
+
+import (
+	"fmt"
+	"io"
+	"slices"
+)
+
+// SeverityMarker is the colored circle review comments mark a severity with,
+// matching the text report
+func SeverityMarker(severity string) string {
+	switch severity {
+	case SeverityCritical, SeverityHigh:
+		return "🔴"
+	case SeverityMedium:
+		return "🟡"
+	default:
+		return "🟢"
+	}
+}
+
+// IssueComment renders an issue as an inline review comment, e.g.
+// 🔴 **high** security: message (`rule-id`)
+func IssueComment(issue Issue) string {
+	return SeverityMarker(issue.Severity) + " " + issueText(issue)
+}
+
+// issueText describes an issue in a comment without its severity marker
+func issueText(issue Issue) string {
+	text := fmt.Sprintf("**%s** %s: %s", issue.Severity, issue.Type, issue.Message)
+	if issue.RuleID != "" {
+		text += fmt.Sprintf(" (`%s`)", issue.RuleID)
+	}
+	return text
+}
+
+// WriteReviewSummary writes the summary of a review posted as inline comments:
+// the issue counts, then the listed issues, which are those that could not be
+// anchored to a line
+func WriteReviewSummary(w io.Writer, report *Report, listed []Issue) {
+	fmt.Fprintln(w, "## Code Review")
+	fmt.Fprintln(w)
+	fmt.Fprintf(w, "%d issues found: 🔴 %d critical or high, 🟡 %d medium, 🟢 %d low or info\n",
+		report.Summary.TotalIssues,
+		report.Summary.CriticalSeverity+report.Summary.HighSeverity,
+		report.Summary.MediumSeverity,
+		report.Summary.LowSeverity+report.Summary.InfoSeverity)
+	if len(listed) == 0 {
+		return
+	}
+	fmt.Fprintln(w)
+	for _, issue := range listed {
+		location := ""
+		if issue.File != "" {
+			location = fmt.Sprintf("`%s` ", issueLocation(issue))
+		}
+		fmt.Fprintf(w, "- %s %s%s\n", SeverityMarker(issue.Severity), location, issueText(issue))
+	}
+}
+
+// WritePRComment writes a Markdown pull request comment listing the findings
+// current introduced over base and counting those it resolved, so reviewers
+// see only what the change did. Without a base report every finding is listed.
+func WritePRComment(w io.Writer, current, base *Report) error {
+	fmt.Fprintln(w, "## Code Review")
+	fmt.Fprintln(w)
+
+	findings := current.Issues
+	if base == nil {
+		fmt.Fprintln(w, "_No base report was available, so every finding is listed._")
+	} else {
+		diff := current.Diff(base)
+		findings = diff.Added
+		fmt.Fprintln(w, diff.Headline())
+		if diff.Summary.Removed > 0 {
+			fmt.Fprintln(w)
+			fmt.Fprintf(w, "✅ %s resolved.\n", pluralFindings(diff.Summary.Removed))
+		}
+	}
+
+	if len(findings) == 0 {
+		fmt.Fprintln(w)
+		fmt.Fprintln(w, "🎉 No new findings.")
+		return nil
+	}
+
+	fmt.Fprintln(w)
+	if base == nil {
+		fmt.Fprintf(w, "### Findings (%d)\n\n", len(findings))
+	} else {
+		fmt.Fprintf(w, "### New findings (%d)\n\n", len(findings))
+	}
+	fmt.Fprintln(w, "| Severity | Type | Location | Message |")
+	fmt.Fprintln(w, "| --- | --- | --- | --- |")
+	// Most severe first; the sort is stable so findings keep file order within a severity
+	findings = slices.Clone(findings)
+	slices.SortStableFunc(findings, func(a, b Issue) int {
+		return severityRank[b.Severity] - severityRank[a.Severity]
+	})
+	for _, issue := range findings {
+		fmt.Fprintf(w, "| %s | %s | `%s` | %s |\n", issue.Severity, issue.Type, issueLocation(issue), markdownEscape(issue.Message))
+	}
+	fmt.Fprintf(w, "\n⏱️ Estimated effort to fix: %s\n", summarizeEffort(findings))
+	return nil
+}
+
+// pluralFindings renders a finding count, e.g. "1 finding" or "3 findings"
+func pluralFindings(n int) string {
+	if n == 1 {
+		return "1 finding"
+	}
+	return fmt.Sprintf("%d findings", n)
+}
@@ -0,0 +1,133 @@
+package review
+
+import "strings"
+
+// commentSyntax describes how a language writes comments and string literals
+type commentSyntax struct {
+	// lineComments start a comment that runs to the end of the line
+	lineComments []string
+	// blockStart and blockEnd delimit comments that may span lines; empty if unsupported
+	blockStart, blockEnd string
+	// quotes are the characters that open and close string literals, so comment
+	// markers inside strings such as "http://" are kept
+	quotes string
+	// hashAttributes marks #[ as code, as in PHP 8 attributes such as #[Route]
+	hashAttributes bool
+}
+
+// commentSyntaxes are keyed by language. Rust and Scala only quote with " because
+// ' also starts lifetimes and symbols.
+var commentSyntaxes = map[string]commentSyntax{
+	"python":     {lineComments: []string{"#"}, quotes: `"'`},
+	"ruby":       {lineComments: []string{"#"}, quotes: `"'`},
+	"javascript": {lineComments: []string{"//"}, blockStart: "/*", blockEnd: "*/", quotes: "\"'`"},
+	"typescript": {lineComments: []string{"//"}, blockStart: "/*", blockEnd: "*/", quotes: "\"'`"},
+	"php":        {lineComments: []string{"//", "#"}, blockStart: "/*", blockEnd: "*/", quotes: `"'`, hashAttributes: true},
+	"java":       {lineComments: []string{"//"}, blockStart: "/*", blockEnd: "*/", quotes: `"'`},
+	"kotlin":     {lineComments: []string{"//"}, blockStart: "/*", blockEnd: "*/", quotes: `"'`},
+	"dart":       {lineComments: []string{"//"}, blockStart: "/*", blockEnd: "*/", quotes: `"'`},
+	"go":         {lineComments: []string{"//"}, blockStart: "/*", blockEnd: "*/", quotes: "\"'`"},
+	"rust":       {lineComments: []string{"//"}, blockStart: "/*", blockEnd: "*/", quotes: `"`},
+	"cpp":        {lineComments: []string{"//"}, blockStart: "/*", blockEnd: "*/", quotes: `"'`},
+	"scala":      {lineComments: []string{"//"}, blockStart: "/*", blockEnd: "*/", quotes: `"`},
+	"swift":      {lineComments: []string{"//"}, blockStart: "/*", blockEnd: "*/", quotes: `"`},
+	"elixir":     {lineComments: []string{"#"}, quotes: `"'`},
+	"powershell": {lineComments: []string{"#"}, blockStart: "<#", blockEnd: "#>", quotes: `"'`},
+	"groovy":     {lineComments: []string{"//"}, blockStart: "/*", blockEnd: "*/", quotes: `"'`},
+	"r":          {lineComments: []string{"#"}, quotes: `"'`},
+	"vue":        {blockStart: "<!--", blockEnd: "-->", quotes: `"'`},
+	"svelte":     {blockStart: "<!--", blockEnd: "-->", quotes: `"'`},
+	"template":   {blockStart: "<!--", blockEnd: "-->"},
+	"proto":      {lineComments: []string{"//"}, blockStart: "/*", blockEnd: "*/", quotes: `"'`},
+	"graphql":    {lineComments: []string{"#"}, quotes: `"`},
+	"solidity":   {lineComments: []string{"//"}, blockStart: "/*", blockEnd: "*/", quotes: `"'`},
+	"csharp":     {lineComments: []string{"//"}, blockStart: "/*", blockEnd: "*/", quotes: `"'`},
+	"terraform":  {lineComments: []string{"#", "//"}, blockStart: "/*", blockEnd: "*/", quotes: `"`},
+}
+
+// stripComments returns the code portion of a single line, dropping line
+// comments and block comments that open and close on the line. Languages
+// without a known comment syntax are returned unchanged.
+func stripComments(line string, lang string) string {
+	code, _ := stripLineComments(line, commentSyntaxes[lang], false)
+	return code
+}
+
+// codeLines returns lines with their comments removed, following block comments
+// across lines. The result has one entry per input line, so indexes still match
+// line numbers.
+func codeLines(lines []string, lang string) []string {
+	syntax := commentSyntaxes[lang]
+	code := make([]string, len(lines))
+	inBlock := false
+	for i, line := range lines {
+		code[i], inBlock = stripLineComments(line, syntax, inBlock)
+	}
+	return code
+}
+
+// commentLines returns the comment text of each line, the complement of
+// codeLines. Lines without a comment have an empty entry.
+func commentLines(lines []string, lang string) []string {
+	syntax := commentSyntaxes[lang]
+	comments := make([]string, len(lines))
+	inBlock := false
+	for i, line := range lines {
+		_, comments[i], inBlock = splitLineComments(line, syntax, inBlock)
+	}
+	return comments
+}
+
+// stripLineComments removes the comments from line, starting inside a block
+// comment when inBlock is set. It reports whether a block comment is still open
+// at the end of the line.
+func stripLineComments(line string, syntax commentSyntax, inBlock bool) (string, bool) {
+	code, _, inBlock := splitLineComments(line, syntax, inBlock)
+	return code, inBlock
+}
+
+// splitLineComments splits line into its code and its comment text, without the
+// comment markers. Several comments on one line are joined with a space.
+func splitLineComments(line string, syntax commentSyntax, inBlock bool) (string, string, bool) {
+	var code, comment strings.Builder
+	var quote byte
+	for i := 0; i < len(line); i++ {
+		switch {
+		case inBlock:
+			if strings.HasPrefix(line[i:], syntax.blockEnd) {
+				inBlock = false
+				i += len(syntax.blockEnd) - 1
+				// Keep the tokens on either side of the comment apart
+				code.WriteByte(' ')
+				comment.WriteByte(' ')
+				continue
+			}
+			comment.WriteByte(line[i])
+			continue
+		case quote != 0:
+			if line[i] == '\\' && i+1 < len(line) {
+				code.WriteString(line[i : i+2])
+				i++
+				continue
+			}
+			if line[i] == quote {
+				quote = 0
+			}
+		case strings.IndexByte(syntax.quotes, line[i]) >= 0:
+			quote = line[i]
+		case syntax.blockStart != "" && strings.HasPrefix(line[i:], syntax.blockStart):
+			inBlock = true
+			i += len(syntax.blockStart) - 1
+			continue
+		default:
+			for _, marker := range syntax.lineComments {
+				if strings.HasPrefix(line[i:], marker) && !(syntax.hashAttributes && strings.HasPrefix(line[i:], "#[")) {
+					comment.WriteString(line[i+len(marker):])
+					return strings.TrimRight(code.String(), " \t"), strings.TrimSpace(comment.String()), inBlock
+				}
+			}
+		}
+		code.WriteByte(line[i])
+	}
+	return code.String(), strings.TrimSpace(comment.String()), inBlock
+}
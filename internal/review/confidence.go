@@ -0,0 +1,72 @@
+package review
+
+import "slices"
+
+// Confidence levels, how likely a finding is to be a real problem rather than
+// a false positive of a pattern-based check
+const (
+	ConfidenceHigh   = "high"
+	ConfidenceMedium = "medium"
+	ConfidenceLow    = "low"
+)
+
+// Confidences lists the confidence levels from most to least certain
+var Confidences = []string{ConfidenceHigh, ConfidenceMedium, ConfidenceLow}
+
+// ruleConfidences is the confidence of rules whose checks are heuristic. Rules
+// not listed match something unambiguous, such as a known key format or a call
+// to a dangerous function, and are high confidence.
+var ruleConfidences = map[string]string{
+	// Guesses from the shape of the code that often flag correct code
+	"force-unwrap":                  ConfidenceLow,
+	"malloc-without-free":           ConfidenceLow,
+	"n-plus-one":                    ConfidenceLow,
+	"query-in-loop":                 ConfidenceLow,
+	"string-concat-in-loop":         ConfidenceLow,
+	"unscoped-find":                 ConfidenceLow,
+	"model-without-validations":     ConfidenceLow,
+	"auth-no-rate-limit":            ConfidenceLow,
+	"any-http-method":               ConfidenceLow,
+	"too-many-callbacks":            ConfidenceLow,
+	"prototype-pollution":           ConfidenceLow,
+	"terraform-unencrypted-storage": ConfidenceLow,
+	// Real when the input is attacker-controlled, which a line-by-line check cannot tell
+	"sql-injection":           ConfidenceMedium,
+	"sql-query-concatenation": ConfidenceMedium,
+	"xss":                     ConfidenceMedium,
+	"path-traversal":          ConfidenceMedium,
+	"mass-assignment":         ConfidenceMedium,
+	"open-redirect":           ConfidenceMedium,
+	"reflected-content-type":  ConfidenceMedium,
+	"unescaped-html-response": ConfidenceMedium,
+	"non-literal-regexp":      ConfidenceMedium,
+	"upload-no-allowlist":     ConfidenceMedium,
+	"unbounded-body-read":     ConfidenceMedium,
+	"unbounded-read-loop":     ConfidenceMedium,
+	"blocking-call-in-async":  ConfidenceMedium,
+	"script-interpolation":    ConfidenceMedium,
+	"insecure-random":         ConfidenceMedium,
+	"dependency-confusion":    ConfidenceMedium,
+	// Secrets matched by variable name rather than by a known key format
+	"hardcoded-password":   ConfidenceMedium,
+	"hardcoded-secret":     ConfidenceMedium,
+	"hardcoded-api-key":    ConfidenceMedium,
+	"hardcoded-credential": ConfidenceMedium,
+	"hardcoded-salt":       ConfidenceMedium,
+	"secret-in-comment":    ConfidenceMedium,
+	"hardcoded-api-url":    ConfidenceMedium,
+}
+
+// ValidConfidence reports whether s is a known confidence level
+func ValidConfidence(s string) bool {
+	return slices.Contains(Confidences, s)
+}
+
+// ConfidenceFor returns the confidence of an issue's rule; unlisted rules,
+// including plugin rules, are high confidence
+func ConfidenceFor(issue Issue) string {
+	if confidence, ok := ruleConfidences[issue.RuleID]; ok {
+		return confidence
+	}
+	return ConfidenceHigh
+}
@@ -0,0 +1,101 @@
+package review
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"slices"
+	"unicode/utf8"
+)
+
+// binarySniffLength is how much of a file is inspected to decide whether it is binary,
+// matching the heuristic git uses
+const binarySniffLength = 8000
+
+// binaryFileReason is the skip reason given for files containing NUL bytes
+const binaryFileReason = "binary file"
+
+// DefaultMaxFileSize is the largest file, in bytes, quality checks run on when no
+// limit is configured
+const DefaultMaxFileSize = 1 << 20
+
+// minifiedLineLength is the line length, in characters, above which a file is taken
+// to be minified or generated rather than written by hand
+const minifiedLineLength = 5000
+
+// SkippedFile is a file no check read, with the reason it was passed over
+type SkippedFile struct {
+	File   string `json:"file"`
+	Reason string `json:"reason"`
+}
+
+// markAnalyzed records that a check read file, replacing any earlier skip reason
+func (r *Report) markAnalyzed(file string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if !slices.Contains(r.AnalyzedFiles, file) {
+		r.AnalyzedFiles = append(r.AnalyzedFiles, file)
+	}
+	r.SkippedFiles = slices.DeleteFunc(r.SkippedFiles, func(s SkippedFile) bool { return s.File == file })
+}
+
+// markSkipped records why a check passed over file. A file read by another check
+// stays analyzed, and the first reason given for a file is kept.
+func (r *Report) markSkipped(file, reason string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if slices.Contains(r.AnalyzedFiles, file) || slices.ContainsFunc(r.SkippedFiles, func(s SkippedFile) bool { return s.File == file }) {
+		return
+	}
+	r.SkippedFiles = append(r.SkippedFiles, SkippedFile{File: file, Reason: reason})
+}
+
+// unreadableReason explains why a file's content cannot be analyzed: it was
+// deleted, cannot be opened or is binary. It returns "" for readable text files.
+// The content is read through the file cache, so the checks that follow reuse it.
+func (a *Analyzer) unreadableReason(file string) string {
+	content, err := a.readFile(file)
+	if errors.Is(err, fs.ErrNotExist) {
+		return "deleted"
+	}
+	if err != nil {
+		return "unreadable: " + err.Error()
+	}
+	if bytes.IndexByte(content[:min(len(content), binarySniffLength)], 0) >= 0 {
+		return binaryFileReason
+	}
+	return ""
+}
+
+// generatedReason explains why a readable text file looks generated or minified:
+// it is larger than the file size limit or has a line longer than
+// minifiedLineLength. It returns "" for files worth running quality checks on.
+func (a *Analyzer) generatedReason(file string) string {
+	content, err := a.readFile(file)
+	if err != nil {
+		return ""
+	}
+	if limit := a.maxFileSizeLimit(); len(content) > limit {
+		return fmt.Sprintf("larger than %d bytes", limit)
+	}
+	for line := range bytes.SplitSeq(content, []byte("\n")) {
+		if len(line) > minifiedLineLength && utf8.RuneCount(line) > minifiedLineLength {
+			return fmt.Sprintf("line longer than %d characters", minifiedLineLength)
+		}
+	}
+	return ""
+}
+
+// reportSkippedFile records that quality checks passed over a generated, minified
+// or binary file, with an informational issue so the gap shows up in the report
+func (a *Analyzer) reportSkippedFile(file, reason string, report *Report) {
+	report.markSkipped(file, reason)
+	report.AddIssue(Issue{
+		Type:     "quality",
+		Severity: "info",
+		Message:  fmt.Sprintf("File skipped: appears generated, minified or binary (%s)", reason),
+		File:     file,
+		RuleID:   "file-skipped",
+	})
+}
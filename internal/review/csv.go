@@ -0,0 +1,34 @@
+package review
+
+import (
+	"encoding/csv"
+	"io"
+	"path/filepath"
+	"strconv"
+)
+
+// csvHeader names the columns written by OutputCSV
+var csvHeader = []string{"severity", "type", "rule", "file", "line", "message"}
+
+// OutputCSV writes the report as CSV for triage in a spreadsheet: a header row,
+// then one row per issue. The line is left empty for issues without one.
+func (r *Report) OutputCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(csvHeader); err != nil {
+		return err
+	}
+
+	for _, issue := range r.Issues {
+		line := ""
+		if issue.Line > 0 {
+			line = strconv.Itoa(issue.Line)
+		}
+		row := []string{issue.Severity, issue.Type, issue.RuleID, filepath.ToSlash(filepath.Clean(issue.File)), line, issue.Message}
+		if err := writer.Write(row); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
@@ -0,0 +1,270 @@
+package review
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+)
+
+// ReportDiff describes how the findings of two reports differ
+type ReportDiff struct {
+	Added     []Issue     `json:"added"`
+	Removed   []Issue     `json:"removed"`
+	Unchanged []Issue     `json:"unchanged"`
+	Summary   DiffSummary `json:"summary"`
+}
+
+// DiffSummary holds the finding deltas between two reports
+type DiffSummary struct {
+	// Categories are the check categories both reports ran; findings in other
+	// categories are left out of the comparison
+	Categories        []string       `json:"categories"`
+	Added             int            `json:"added"`
+	Removed           int            `json:"removed"`
+	Unchanged         int            `json:"unchanged"`
+	AddedBySeverity   map[string]int `json:"added_by_severity"`
+	RemovedBySeverity map[string]int `json:"removed_by_severity"`
+}
+
+// Fingerprint identifies an issue across reports by its rule, or its type when it
+// has no rule ID, file and message. Line numbers are left out so findings that only
+// moved because of edits elsewhere in the file still match, and severity so a
+// finding whose severity was overridden or remapped is not read as new.
+func (i Issue) Fingerprint() string {
+	rule := i.RuleID
+	if rule == "" {
+		rule = i.Type
+	}
+	return strings.Join([]string{rule, i.File, i.Message}, "\x00")
+}
+
+// LoadReport reads a report previously written with SaveToFile or --json
+func LoadReport(path string) (*Report, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read report %s: %w", path, err)
+	}
+
+	report := &Report{}
+	if err := json.Unmarshal(content, report); err != nil {
+		return nil, fmt.Errorf("failed to parse report %s: %w", path, err)
+	}
+	if report.Issues == nil {
+		report.Issues = []Issue{}
+	}
+	return report, nil
+}
+
+// Diff compares r against an earlier report. Issues only in r are added,
+// issues only in previous are removed. Repeated findings are matched one to one.
+// Only categories both reports ran are compared, so a security-only report is
+// not read as having fixed every quality finding.
+func (r *Report) Diff(previous *Report) *ReportDiff {
+	categories := []string{}
+	for _, category := range previous.ranCategories() {
+		if slices.Contains(r.ranCategories(), category) {
+			categories = append(categories, category)
+		}
+	}
+	compared := func(issue Issue) bool {
+		return issue.Category == "" || slices.Contains(categories, issue.Category)
+	}
+
+	remaining := map[string]int{}
+	for _, issue := range previous.Issues {
+		if compared(issue) {
+			remaining[issue.Fingerprint()]++
+		}
+	}
+
+	diff := &ReportDiff{
+		Added:     []Issue{},
+		Removed:   []Issue{},
+		Unchanged: []Issue{},
+	}
+
+	for _, issue := range r.Issues {
+		if !compared(issue) {
+			continue
+		}
+		key := issue.Fingerprint()
+		if remaining[key] > 0 {
+			remaining[key]--
+			diff.Unchanged = append(diff.Unchanged, issue)
+		} else {
+			diff.Added = append(diff.Added, issue)
+		}
+	}
+
+	// Whatever was not matched by the newer report has been fixed
+	for _, issue := range previous.Issues {
+		if !compared(issue) {
+			continue
+		}
+		key := issue.Fingerprint()
+		if remaining[key] > 0 {
+			remaining[key]--
+			diff.Removed = append(diff.Removed, issue)
+		}
+	}
+
+	diff.Summary = DiffSummary{
+		Categories:        categories,
+		Added:             len(diff.Added),
+		Removed:           len(diff.Removed),
+		Unchanged:         len(diff.Unchanged),
+		AddedBySeverity:   countBySeverity(diff.Added),
+		RemovedBySeverity: countBySeverity(diff.Removed),
+	}
+
+	return diff
+}
+
+// ranCategories returns the categories the report ran; reports without
+// recorded categories ran all of them
+func (r *Report) ranCategories() []string {
+	if len(r.Categories) == 0 {
+		return Categories
+	}
+	return r.Categories
+}
+
+func countBySeverity(issues []Issue) map[string]int {
+	counts := map[string]int{}
+	for _, issue := range issues {
+		counts[issue.Severity]++
+	}
+	return counts
+}
+
+// Headline summarizes the diff in one sentence, e.g.
+// "This change introduced 2 new high findings and fixed 1 finding."
+func (d *ReportDiff) Headline() string {
+	if d.Summary.Added == 0 && d.Summary.Removed == 0 {
+		return "This change did not introduce or fix any findings."
+	}
+
+	parts := []string{}
+	if d.Summary.Added > 0 {
+		parts = append(parts, "introduced "+severityBreakdown(d.Summary.AddedBySeverity, "new "))
+	}
+	if d.Summary.Removed > 0 {
+		parts = append(parts, "fixed "+severityBreakdown(d.Summary.RemovedBySeverity, ""))
+	}
+	return "This change " + strings.Join(parts, " and ") + "."
+}
+
+// severityBreakdown renders counts like "2 new high and 1 new low findings"
+func severityBreakdown(counts map[string]int, prefix string) string {
+	parts := []string{}
+	total := 0
+	for _, severity := range Severities {
+		if counts[severity] > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s%s", counts[severity], prefix, severity))
+			total += counts[severity]
+		}
+	}
+	// Keep any severity we don't know about rather than silently dropping it
+	for _, severity := range slices.Sorted(maps.Keys(counts)) {
+		if !slices.Contains(Severities, severity) && counts[severity] > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s%s", counts[severity], prefix, severity))
+			total += counts[severity]
+		}
+	}
+
+	noun := "findings"
+	if total == 1 {
+		noun = "finding"
+	}
+
+	switch len(parts) {
+	case 0:
+		return "0 " + noun
+	case 1:
+		return parts[0] + " " + noun
+	}
+	return strings.Join(parts[:len(parts)-1], ", ") + " and " + parts[len(parts)-1] + " " + noun
+}
+
+// WriteText writes the diff as plain text
+func (d *ReportDiff) WriteText(w io.Writer) error {
+	fmt.Fprintln(w, d.Headline())
+	fmt.Fprintf(w, "\nAdded: %d  Removed: %d  Unchanged: %d\n", d.Summary.Added, d.Summary.Removed, d.Summary.Unchanged)
+	if len(d.Summary.Categories) < len(Categories) {
+		fmt.Fprintf(w, "Compared categories: %s\n", strings.Join(d.Summary.Categories, ", "))
+	}
+
+	writeSection := func(title, marker string, issues []Issue) {
+		if len(issues) == 0 {
+			return
+		}
+		fmt.Fprintf(w, "\n%s:\n", title)
+		for _, issue := range issues {
+			fmt.Fprintf(w, "  %s [%s] %s\n", marker, issue.Severity, issue.Message)
+			fmt.Fprintf(w, "      %s\n", issueLocation(issue))
+		}
+	}
+	writeSection("NEW FINDINGS", "+", d.Added)
+	writeSection("FIXED FINDINGS", "-", d.Removed)
+
+	return nil
+}
+
+// WriteMarkdown writes the diff as Markdown suitable for a PR comment
+func (d *ReportDiff) WriteMarkdown(w io.Writer) error {
+	fmt.Fprintln(w, "## Code Review Diff")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, d.Headline())
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "| | Count |")
+	fmt.Fprintln(w, "| --- | --- |")
+	fmt.Fprintf(w, "| 🆕 Added | %d |\n", d.Summary.Added)
+	fmt.Fprintf(w, "| ✅ Removed | %d |\n", d.Summary.Removed)
+	fmt.Fprintf(w, "| ➖ Unchanged | %d |\n", d.Summary.Unchanged)
+	if len(d.Added) > 0 {
+		fmt.Fprintf(w, "\n⏱️ Estimated effort to fix the new findings: %s\n", summarizeEffort(d.Added))
+	}
+	if len(d.Summary.Categories) < len(Categories) {
+		fmt.Fprintf(w, "\n_Only %s checks were compared._\n", strings.Join(d.Summary.Categories, ", "))
+	}
+
+	writeTable := func(title string, issues []Issue) {
+		if len(issues) == 0 {
+			return
+		}
+		fmt.Fprintf(w, "\n### %s\n\n", title)
+		fmt.Fprintln(w, "| Severity | Type | Location | Message |")
+		fmt.Fprintln(w, "| --- | --- | --- | --- |")
+		for _, issue := range issues {
+			fmt.Fprintf(w, "| %s | %s | `%s` | %s |\n", issue.Severity, issue.Type, issueLocation(issue), markdownEscape(issue.Message))
+		}
+	}
+	writeTable("New findings", d.Added)
+	writeTable("Fixed findings", d.Removed)
+
+	return nil
+}
+
+// WriteJSON writes the diff as indented JSON
+func (d *ReportDiff) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(d)
+}
+
+func issueLocation(issue Issue) string {
+	if issue.Line > 0 {
+		return fmt.Sprintf("%s:%d", issue.File, issue.Line)
+	}
+	return issue.File
+}
+
+// markdownEscape keeps messages from breaking out of a table cell
+func markdownEscape(s string) string {
+	s = strings.ReplaceAll(s, "|", `\|`)
+	return strings.ReplaceAll(s, "\n", " ")
+}
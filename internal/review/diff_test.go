@@ -0,0 +1,198 @@
+package review
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func loadDiffFixtures(t *testing.T) (*Report, *Report) {
+	t.Helper()
+	previous, err := LoadReport(filepath.Join("testdata", "diff", "old.json"))
+	if err != nil {
+		t.Fatalf("Failed to load old fixture: %v", err)
+	}
+	current, err := LoadReport(filepath.Join("testdata", "diff", "new.json"))
+	if err != nil {
+		t.Fatalf("Failed to load new fixture: %v", err)
+	}
+	return previous, current
+}
+
+func TestReportDiff_Fixtures(t *testing.T) {
+	previous, current := loadDiffFixtures(t)
+	diff := current.Diff(previous)
+
+	if diff.Summary.Added != 3 || diff.Summary.Removed != 2 || diff.Summary.Unchanged != 1 {
+		t.Fatalf("Expected 3 added, 2 removed, 1 unchanged, got %+v", diff.Summary)
+	}
+	if diff.Summary.AddedBySeverity["high"] != 2 || diff.Summary.AddedBySeverity["medium"] != 1 {
+		t.Errorf("Unexpected added severity counts: %v", diff.Summary.AddedBySeverity)
+	}
+	if diff.Summary.RemovedBySeverity["high"] != 1 || diff.Summary.RemovedBySeverity["low"] != 1 {
+		t.Errorf("Unexpected removed severity counts: %v", diff.Summary.RemovedBySeverity)
+	}
+
+	// The remaining print statement moved from line 4/9 to line 6 but is still the same finding
+	if diff.Unchanged[0].File != "src/api.py" || diff.Unchanged[0].Line != 6 {
+		t.Errorf("Expected moved print finding to be unchanged, got %+v", diff.Unchanged[0])
+	}
+
+	want := "This change introduced 2 new high and 1 new medium findings and fixed 1 high and 1 low findings."
+	if got := diff.Headline(); got != want {
+		t.Errorf("Headline() = %q, want %q", got, want)
+	}
+}
+
+func TestReportDiff_IdenticalReports(t *testing.T) {
+	previous, _ := loadDiffFixtures(t)
+	diff := previous.Diff(previous)
+
+	if diff.Summary.Added != 0 || diff.Summary.Removed != 0 || diff.Summary.Unchanged != 3 {
+		t.Errorf("Expected only unchanged findings, got %+v", diff.Summary)
+	}
+	if !strings.Contains(diff.Headline(), "did not introduce or fix") {
+		t.Errorf("Unexpected headline: %s", diff.Headline())
+	}
+}
+
+func TestReportDiff_SeverityChangeIsUnchanged(t *testing.T) {
+	previous := NewReport()
+	previous.AddIssue(Issue{Type: "security", Severity: "high", Message: "Hardcoded password", File: "app.py", Line: 3, RuleID: "hardcoded-password"})
+	current := NewReport()
+	current.AddIssue(Issue{Type: "security", Severity: "critical", Message: "Hardcoded password", File: "app.py", Line: 3, RuleID: "hardcoded-password"})
+
+	diff := current.Diff(previous)
+
+	if diff.Summary.Added != 0 || diff.Summary.Removed != 0 || diff.Summary.Unchanged != 1 {
+		t.Errorf("Expected a finding whose severity changed to be unchanged, got %+v", diff.Summary)
+	}
+}
+
+func TestReportDiff_WriteMarkdown(t *testing.T) {
+	previous, current := loadDiffFixtures(t)
+	current.Issues[1].Message = "uses a | pipe"
+
+	var buf bytes.Buffer
+	if err := current.Diff(previous).WriteMarkdown(&buf); err != nil {
+		t.Fatalf("WriteMarkdown returned error: %v", err)
+	}
+	out := buf.String()
+
+	for _, want := range []string{"## Code Review Diff", "### New findings", "### Fixed findings", "`src/upload.py:8`", `uses a \| pipe`, "Estimated effort to fix the new findings"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Expected markdown to contain %q, got:\n%s", want, out)
+		}
+	}
+}
+
+func TestLoadReport_InvalidJSON(t *testing.T) {
+	tmpDir := t.TempDir()
+	path := createTestFile(t, tmpDir, "broken.json", "{not json")
+
+	if _, err := LoadReport(path); err == nil {
+		t.Error("Expected an error for invalid JSON")
+	}
+}
+
+func TestReportDiff_SkipsCategoriesNotRunByBoth(t *testing.T) {
+	previous := NewReport()
+	previous.Categories = []string{CategorySecurity, CategoryQuality, CategoryPerformance}
+	previous.AddIssue(Issue{Type: "quality", Severity: "low", Message: "Print statement", File: "app.py", Category: CategoryQuality})
+	previous.AddIssue(Issue{Type: "security", Severity: "high", Message: "Hardcoded password", File: "app.py", Category: CategorySecurity})
+
+	current := NewReport()
+	current.Categories = []string{CategorySecurity}
+
+	diff := current.Diff(previous)
+	if diff.Summary.Removed != 1 || diff.Removed[0].Category != CategorySecurity {
+		t.Errorf("Expected only the security finding to be removed, got %+v", diff.Removed)
+	}
+	if strings.Join(diff.Summary.Categories, ",") != CategorySecurity {
+		t.Errorf("Expected only security to be compared, got %v", diff.Summary.Categories)
+	}
+}
+
+func TestReportDiff_RemovedSkipsCategoriesNotRunByBoth(t *testing.T) {
+	// The same message in a category the newer report did not run comes first,
+	// so it must not be picked as the removed finding
+	previous := NewReport()
+	previous.Categories = []string{CategorySecurity, CategoryQuality, CategoryPerformance}
+	previous.AddIssue(Issue{Type: "security", Severity: "medium", Message: "Unbounded read", File: "app.py", Category: CategoryPerformance})
+	previous.AddIssue(Issue{Type: "security", Severity: "medium", Message: "Unbounded read", File: "app.py", Category: CategorySecurity})
+	current := NewReport()
+	current.Categories = []string{CategorySecurity}
+
+	diff := current.Diff(previous)
+
+	if len(diff.Removed) != 1 || diff.Removed[0].Category != CategorySecurity {
+		t.Errorf("Expected only the security finding to be removed, got %+v", diff.Removed)
+	}
+}
+
+func TestWritePRComment_OnlyNewFindings(t *testing.T) {
+	previous, current := loadDiffFixtures(t)
+
+	var buf bytes.Buffer
+	if err := WritePRComment(&buf, current, previous); err != nil {
+		t.Fatalf("WritePRComment returned error: %v", err)
+	}
+	out := buf.String()
+
+	for _, want := range []string{
+		"## Code Review",
+		"This change introduced 2 new high and 1 new medium findings",
+		"✅ 2 findings resolved.",
+		"### New findings (3)",
+		"| high | security | `src/api.py:20` |",
+		"| high | security | `src/upload.py:8` |",
+		"| medium | error_handling | `src/upload.py:14` |",
+		"Estimated effort to fix",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Expected comment to contain %q, got:\n%s", want, out)
+		}
+	}
+	// The print statement was already in the base report
+	if strings.Contains(out, "Print statement") {
+		t.Errorf("Did not expect unchanged findings in the comment, got:\n%s", out)
+	}
+	// Most severe first
+	if strings.Index(out, "src/upload.py:14") < strings.Index(out, "src/upload.py:8") {
+		t.Errorf("Expected high findings before medium ones, got:\n%s", out)
+	}
+}
+
+func TestWritePRComment_NoBaseReport(t *testing.T) {
+	_, current := loadDiffFixtures(t)
+
+	var buf bytes.Buffer
+	if err := WritePRComment(&buf, current, nil); err != nil {
+		t.Fatalf("WritePRComment returned error: %v", err)
+	}
+	out := buf.String()
+
+	for _, want := range []string{"every finding is listed", "### Findings (4)", "Print statement found"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Expected comment to contain %q, got:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "resolved") {
+		t.Errorf("Did not expect a resolved count without a base report, got:\n%s", out)
+	}
+}
+
+func TestWritePRComment_NothingNew(t *testing.T) {
+	_, current := loadDiffFixtures(t)
+
+	var buf bytes.Buffer
+	if err := WritePRComment(&buf, current, current); err != nil {
+		t.Fatalf("WritePRComment returned error: %v", err)
+	}
+	out := buf.String()
+
+	if !strings.Contains(out, "No new findings") || strings.Contains(out, "| Severity |") {
+		t.Errorf("Expected a comment without a findings table, got:\n%s", out)
+	}
+}
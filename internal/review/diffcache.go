@@ -0,0 +1,53 @@
+package review
+
+import "sync"
+
+// diffCache holds the lines each file's diff adds, keyed by diff range and file,
+// so the security scan, plugins and any other pass that needs a file's changed
+// lines share one git diff per file. It is safe for concurrent use: goroutines
+// asking for the same diff wait for a single git invocation.
+type diffCache struct {
+	mu      sync.Mutex
+	entries map[diffKey]*cachedDiff
+}
+
+// diffKey identifies a file's diff over a range, e.g. "origin/main..HEAD" or "--cached"
+type diffKey struct {
+	diffRange, file string
+}
+
+// cachedDiff is the result of diffing one file
+type cachedDiff struct {
+	once  sync.Once
+	lines []changedLine
+	err   error
+}
+
+func newDiffCache() *diffCache {
+	return &diffCache{entries: map[diffKey]*cachedDiff{}}
+}
+
+// get returns the changed lines of file over diffRange, calling diff on first use.
+// The lines are shared between callers and must not be modified.
+func (c *diffCache) get(diffRange, file string, diff func() ([]changedLine, error)) ([]changedLine, error) {
+	key := diffKey{diffRange, file}
+	c.mu.Lock()
+	entry, ok := c.entries[key]
+	if !ok {
+		entry = &cachedDiff{}
+		c.entries[key] = entry
+	}
+	c.mu.Unlock()
+
+	entry.once.Do(func() {
+		entry.lines, entry.err = diff()
+	})
+	return entry.lines, entry.err
+}
+
+// reset drops every cached diff, so the next run sees the commits as they are now
+func (c *diffCache) reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	clear(c.entries)
+}
@@ -0,0 +1,62 @@
+package review
+
+import (
+	"slices"
+	"sync"
+	"testing"
+)
+
+// countDiffs wraps the analyzer's git runner and counts the changed-line diffs
+// run per file
+func countDiffs(analyzer *Analyzer) map[string]int {
+	var mu sync.Mutex
+	diffs := map[string]int{}
+	run := analyzer.runGit
+	analyzer.runGit = func(args ...string) (string, error) {
+		if len(args) > 1 && args[0] == "diff" && args[1] == "-U0" {
+			mu.Lock()
+			diffs[args[len(args)-1]]++
+			mu.Unlock()
+		}
+		return run(args...)
+	}
+	return diffs
+}
+
+func TestGenerateReport_DiffsEachFileOnce(t *testing.T) {
+	dir := newLocalRepo(t)
+	commitFile(t, dir, "app.py", "password = \"supersecret1\"\n# FIXME: rotate\n")
+	plugin := createTestPlugin(t, t.TempDir(), "plugin.sh", `
+case "$(cat)" in *handshake*) echo '{"protocol_version":1}'; exit 0;; esac
+echo '{"rule":"fixme","severity":"low","message":"FIXME left in code","line":2}'
+`)
+
+	analyzer := NewAnalyzer(dir, LogQuiet)
+	analyzer.SetOffline(true)
+	analyzer.SetPlugins([]Plugin{{Name: "acme", Command: plugin, Extensions: []string{"py"}}})
+	diffs := countDiffs(analyzer)
+
+	report, err := analyzer.GenerateReport("main", false)
+	if err != nil {
+		t.Fatalf("GenerateReport returned error: %v", err)
+	}
+
+	// The security scan and the plugin both read the changed lines of app.py
+	if !hasIssue(report, "security", "high", "password") || !slices.ContainsFunc(report.Issues, func(issue Issue) bool { return issue.RuleID == "acme/fixme" }) {
+		t.Fatalf("Expected both the security scan and the plugin to report, got %+v", report.Issues)
+	}
+	for _, file := range []string{"app.py", "feature.py"} {
+		if diffs[file] != 1 {
+			t.Errorf("Expected %s to be diffed once, got %d", file, diffs[file])
+		}
+	}
+
+	// A new run diffs again, in case HEAD moved
+	commitFile(t, dir, "app.py", "password = \"supersecret2\"\n")
+	if _, err := analyzer.GenerateReport("main", false); err != nil {
+		t.Fatalf("GenerateReport returned error: %v", err)
+	}
+	if diffs["app.py"] != 2 {
+		t.Errorf("Expected app.py to be diffed again by the next run, got %d diffs", diffs["app.py"])
+	}
+}
@@ -0,0 +1,184 @@
+package review
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// ScanPlan previews what a review run would analyze without reading file contents
+type ScanPlan struct {
+	// Mode is "diff", "full-scan", or DiffStaged or DiffWorking for uncommitted changes
+	Mode         string `json:"mode"`
+	TargetBranch string `json:"target_branch,omitempty"`
+	// TargetRef is the ref HEAD is compared against, e.g. "origin/main"
+	TargetRef  string        `json:"target_ref,omitempty"`
+	Categories []string      `json:"categories"`
+	Files      []PlannedFile `json:"files"`
+	Summary    PlanSummary   `json:"summary"`
+}
+
+// PlannedFile is one file a review run would consider
+type PlannedFile struct {
+	File string `json:"file"`
+	// Selected is true when the file would end up in the report's changed files
+	Selected bool `json:"selected"`
+	// SkipReason explains why an unselected file is left out
+	SkipReason string `json:"skip_reason,omitempty"`
+	// Analyzer is the language analyzer that would handle the file, empty if none
+	Analyzer string `json:"analyzer,omitempty"`
+	// SecurityScanned is true when the security checks would read the file
+	SecurityScanned bool `json:"security_scanned"`
+	// SecuritySkipReason explains why security scanning skips a selected file
+	SecuritySkipReason string   `json:"security_skip_reason,omitempty"`
+	Plugins            []string `json:"plugins,omitempty"`
+	// ChangedLines counts the added or modified lines in diff mode
+	ChangedLines int `json:"changed_lines,omitempty"`
+}
+
+// PlanSummary holds the file counts of a ScanPlan
+type PlanSummary struct {
+	Candidates int `json:"candidates"`
+	Selected   int `json:"selected"`
+	Skipped    int `json:"skipped"`
+	// Unhandled counts selected files that no check would read
+	Unhandled int `json:"unhandled"`
+}
+
+// PlanScan resolves the files a run would analyze using the same selection as
+// GenerateReport: the git diff or full scan file list, then ignore patterns,
+// security skip rules, the analyzer dispatch and plugin extensions
+func (a *Analyzer) PlanScan(targetBranch string, fullScan bool) (*ScanPlan, error) {
+	plan := &ScanPlan{Mode: "diff", TargetBranch: targetBranch, Categories: a.ranCategories(), Files: []PlannedFile{}}
+
+	var files []string
+	if fullScan {
+		plan.Mode = "full-scan"
+		plan.TargetBranch = ""
+		scanFiles, err := a.fullScanFiles()
+		if err != nil {
+			return nil, err
+		}
+		files = scanFiles
+	} else {
+		a.targetBranch = targetBranch
+		diffFiles, err := a.diffFiles(targetBranch)
+		if err != nil {
+			return nil, err
+		}
+		files = diffFiles
+		if a.diffSource != "" {
+			plan.Mode = a.diffSource
+		}
+		plan.TargetBranch = a.target.branch
+		plan.TargetRef = a.target.ref
+	}
+
+	for _, file := range files {
+		planned := a.planFile(file)
+		if !fullScan {
+			changedLines, err := a.getChangedLines(targetBranch, file)
+			if err != nil {
+				a.log.Warnf("Could not get changed lines for %s: %v", file, err)
+			}
+			planned.ChangedLines = len(changedLines)
+		}
+		plan.Files = append(plan.Files, planned)
+
+		plan.Summary.Candidates++
+		switch {
+		case !planned.Selected:
+			plan.Summary.Skipped++
+		case planned.Analyzer == "" && !planned.SecurityScanned && len(planned.Plugins) == 0:
+			plan.Summary.Selected++
+			plan.Summary.Unhandled++
+		default:
+			plan.Summary.Selected++
+		}
+	}
+
+	return plan, nil
+}
+
+// planFile decides how a run treats a single candidate file
+func (a *Analyzer) planFile(file string) PlannedFile {
+	planned := PlannedFile{File: file}
+	if pattern, ignored := a.matchIgnorePattern(file); ignored {
+		planned.SkipReason = fmt.Sprintf("ignored by %q from %s", pattern.Pattern, pattern.Source)
+		return planned
+	}
+	if !a.matchIncludePattern(file) {
+		planned.SkipReason = "not matched by the include patterns"
+		return planned
+	}
+	planned.Selected = true
+
+	_, verdict := a.selectFile(file)
+	planned.SecurityScanned = verdict.security
+	planned.SecuritySkipReason = verdict.securitySkip
+	if verdict.check != nil {
+		planned.Analyzer = verdict.analyzer
+	}
+	planned.Plugins = verdict.plugins
+
+	return planned
+}
+
+// WriteText writes the plan as a human readable file list
+func (p *ScanPlan) WriteText(w io.Writer) error {
+	switch p.Mode {
+	case "full-scan":
+		fmt.Fprintln(w, "Dry run: full scan")
+	case DiffStaged:
+		fmt.Fprintln(w, "Dry run: staged changes")
+	case DiffWorking:
+		fmt.Fprintln(w, "Dry run: unstaged working tree changes")
+	default:
+		fmt.Fprintf(w, "Dry run: changes against %s\n", p.TargetRef)
+	}
+	fmt.Fprintf(w, "Categories: %s\n\n", strings.Join(p.Categories, ", "))
+
+	for _, file := range p.Files {
+		location := file.File
+		if p.Mode != "full-scan" {
+			location = fmt.Sprintf("%s (+%d lines)", file.File, file.ChangedLines)
+		}
+
+		if !file.Selected {
+			fmt.Fprintf(w, "  - %s  skipped: %s\n", location, file.SkipReason)
+			continue
+		}
+
+		checks := []string{}
+		if file.Analyzer != "" {
+			checks = append(checks, file.Analyzer+" analyzer")
+		}
+		if file.SecurityScanned {
+			checks = append(checks, "security scan")
+		} else if file.SecuritySkipReason != "" {
+			checks = append(checks, "no security scan ("+file.SecuritySkipReason+")")
+		}
+		if len(file.Plugins) > 0 {
+			checks = append(checks, "plugins "+strings.Join(file.Plugins, ", "))
+		}
+		if len(checks) == 0 {
+			checks = append(checks, "no check handles this file")
+		}
+		fmt.Fprintf(w, "  + %s  %s\n", location, strings.Join(checks, "; "))
+	}
+
+	fmt.Fprintf(w, "\n%d files considered: %d selected, %d skipped", p.Summary.Candidates, p.Summary.Selected, p.Summary.Skipped)
+	if p.Summary.Unhandled > 0 {
+		fmt.Fprintf(w, ", %d selected but not handled by any check", p.Summary.Unhandled)
+	}
+	fmt.Fprintln(w)
+	return nil
+}
+
+// WriteJSON writes the plan as indented JSON
+func (p *ScanPlan) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(p)
+}
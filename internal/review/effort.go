@@ -0,0 +1,139 @@
+package review
+
+import "fmt"
+
+// Remediation effort levels, from least to most work
+const (
+	EffortTrivial     = "trivial"
+	EffortModerate    = "moderate"
+	EffortSignificant = "significant"
+)
+
+// effortMinutes is the estimated time to fix one issue at each effort level
+var effortMinutes = map[string]int{
+	EffortTrivial:     5,
+	EffortModerate:    30,
+	EffortSignificant: 120,
+}
+
+// ruleEfforts is the remediation effort of each rule. Rules not listed fall back
+// to an estimate from their type and severity, see EffortFor.
+var ruleEfforts = map[string]string{
+	// Deleting or rewording a single line
+	"print-statement":          EffortTrivial,
+	"console-log":              EffortTrivial,
+	"debugger":                 EffortTrivial,
+	"debug-output":             EffortTrivial,
+	"debug-print":              EffortTrivial,
+	"println":                  EffortTrivial,
+	"system-out-println":       EffortTrivial,
+	"print-stack-trace":        EffortTrivial,
+	"die-exit":                 EffortTrivial,
+	"todo-comment":             EffortTrivial,
+	"line-length":              EffortTrivial,
+	"type-ignore":              EffortTrivial,
+	"ts-ignore":                EffortTrivial,
+	"use-strict":               EffortTrivial,
+	"yaml-load":                EffortTrivial,
+	"insecure-http":            EffortTrivial,
+	"shell-strict-mode":        EffortTrivial,
+	"shell-unquoted-variable":  EffortTrivial,
+	"workflow-unpinned-action": EffortTrivial,
+	// Code changes confined to the finding
+	"sql-injection": EffortModerate,
+	"eval":          EffortModerate,
+	"inner-html":    EffortModerate,
+	"xss":           EffortModerate,
+	"any-type":      EffortModerate,
+	"bare-except":   EffortModerate,
+	"empty-catch":   EffortModerate,
+	"option-get":    EffortModerate,
+	"rust-unwrap":   EffortModerate,
+	"weak-hash":     EffortModerate,
+	"unscoped-find": EffortModerate,
+	// Secrets must be rotated, stored data migrated or designs reworked
+	"private-key":                     EffortSignificant,
+	"aws-credentials":                 EffortSignificant,
+	"hardcoded-password":              EffortSignificant,
+	"hardcoded-api-key":               EffortSignificant,
+	"hardcoded-secret":                EffortSignificant,
+	"hardcoded-credential":            EffortSignificant,
+	"hardcoded-jwt-secret":            EffortSignificant,
+	"generic-token":                   EffortSignificant,
+	"terraform-hardcoded-credentials": EffortSignificant,
+	"terraform-plaintext-secret":      EffortSignificant,
+	"terraform-unencrypted-storage":   EffortSignificant,
+	"hardcoded-salt":                  EffortSignificant,
+	"secret-in-comment":               EffortSignificant,
+	"weak-password-hash":              EffortSignificant,
+	"dependency-confusion":            EffortSignificant,
+	"mass-assignment":                 EffortSignificant,
+	"n-plus-one":                      EffortSignificant,
+	"rust-unsafe":                     EffortSignificant,
+}
+
+// ValidEffort reports whether s is a known effort level
+func ValidEffort(s string) bool {
+	_, ok := effortMinutes[s]
+	return ok
+}
+
+// EffortFor estimates the work needed to fix an issue from its rule. Unlisted
+// rules fall back on the issue: critical security findings are significant,
+// low and info quality findings trivial, and everything else moderate.
+func EffortFor(issue Issue) string {
+	if effort, ok := ruleEfforts[issue.RuleID]; ok {
+		return effort
+	}
+	switch {
+	case issue.Type == "security" && issue.Severity == SeverityCritical:
+		return EffortSignificant
+	case issue.Type != "security" && (issue.Severity == SeverityLow || issue.Severity == SeverityInfo):
+		return EffortTrivial
+	}
+	return EffortModerate
+}
+
+// EffortSummary rolls up the remediation effort of a set of issues
+type EffortSummary struct {
+	Trivial     int `json:"trivial"`
+	Moderate    int `json:"moderate"`
+	Significant int `json:"significant"`
+	// Minutes is the estimated total time to fix every issue
+	Minutes int `json:"minutes"`
+}
+
+// summarizeEffort totals the effort of issues, estimating it for issues loaded
+// from reports written before efforts were recorded
+func summarizeEffort(issues []Issue) EffortSummary {
+	var summary EffortSummary
+	for _, issue := range issues {
+		effort := issue.Effort
+		if !ValidEffort(effort) {
+			effort = EffortFor(issue)
+		}
+		switch effort {
+		case EffortTrivial:
+			summary.Trivial++
+		case EffortModerate:
+			summary.Moderate++
+		case EffortSignificant:
+			summary.Significant++
+		}
+		summary.Minutes += effortMinutes[effort]
+	}
+	return summary
+}
+
+// Duration formats the estimated total time, e.g. "2h 35m"
+func (s EffortSummary) Duration() string {
+	if s.Minutes < 60 {
+		return fmt.Sprintf("%dm", s.Minutes)
+	}
+	return fmt.Sprintf("%dh %dm", s.Minutes/60, s.Minutes%60)
+}
+
+// String describes the rollup, e.g. "2h 35m (3 trivial, 2 moderate, 1 significant)"
+func (s EffortSummary) String() string {
+	return fmt.Sprintf("%s (%d trivial, %d moderate, %d significant)", s.Duration(), s.Trivial, s.Moderate, s.Significant)
+}
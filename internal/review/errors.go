@@ -0,0 +1,19 @@
+package review
+
+// OptionsError reports Options that failed validation
+type OptionsError struct {
+	Err error
+}
+
+func (e *OptionsError) Error() string { return e.Err.Error() }
+func (e *OptionsError) Unwrap() error { return e.Err }
+
+// SetupError reports an environment problem that keeps a run from starting,
+// such as a missing repository, git not being available or a target branch
+// that cannot be resolved
+type SetupError struct {
+	Err error
+}
+
+func (e *SetupError) Error() string { return e.Err.Error() }
+func (e *SetupError) Unwrap() error { return e.Err }
@@ -0,0 +1,59 @@
+package review
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// fileCache holds the content of the files read during a run, so the security
+// and quality passes share one read of each file. It is safe for concurrent
+// use: goroutines asking for the same file wait for a single read.
+type fileCache struct {
+	// readFile reads a file by path; tests replace it to count reads
+	readFile func(string) ([]byte, error)
+
+	mu      sync.Mutex
+	entries map[string]*cachedFile
+}
+
+// cachedFile is the result of reading one file
+type cachedFile struct {
+	once    sync.Once
+	content []byte
+	err     error
+}
+
+func newFileCache() *fileCache {
+	return &fileCache{readFile: os.ReadFile, entries: map[string]*cachedFile{}}
+}
+
+// read returns the content of the file at path, reading it on first use. The
+// content is shared between callers and must not be modified.
+func (c *fileCache) read(path string) ([]byte, error) {
+	c.mu.Lock()
+	entry, ok := c.entries[path]
+	if !ok {
+		entry = &cachedFile{}
+		c.entries[path] = entry
+	}
+	c.mu.Unlock()
+
+	entry.once.Do(func() {
+		entry.content, entry.err = c.readFile(path)
+	})
+	return entry.content, entry.err
+}
+
+// reset drops every cached file, so the next read sees the file as it is now
+func (c *fileCache) reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	clear(c.entries)
+}
+
+// readFile returns the content of a file relative to the repository, read once
+// per run and shared by every check
+func (a *Analyzer) readFile(file string) ([]byte, error) {
+	return a.files.read(filepath.Join(a.repoPath, file))
+}
@@ -0,0 +1,163 @@
+package review
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"testing"
+)
+
+// readCounter wraps the analyzer's file cache reader and counts reads per file
+type readCounter struct {
+	mu    sync.Mutex
+	reads map[string]int
+}
+
+func countReads(analyzer *Analyzer) *readCounter {
+	counter := &readCounter{reads: map[string]int{}}
+	read := analyzer.files.readFile
+	analyzer.files.readFile = func(path string) ([]byte, error) {
+		counter.mu.Lock()
+		counter.reads[path]++
+		counter.mu.Unlock()
+		return read(path)
+	}
+	return counter
+}
+
+// writeMixedRepo writes files that trigger the security, secrets-in-comments,
+// quality and suppression passes
+func writeMixedRepo(dir string, n int) error {
+	for i := range n {
+		files := map[string]string{
+			fmt.Sprintf("app%d.py", i):   "password = \"supersecret1\"\nprint(password)  # autoreview-ignore print-statement\n# TODO: rotate\n",
+			fmt.Sprintf("web%d.js", i):   "// api_key = \"abcdefghijklmnop1234\"\neval(input)\nconsole.log(1)\n",
+			fmt.Sprintf("main%d.go", i):  "package main\n\nfunc main() {\n\tpanic(\"secret\")\n}\n",
+			fmt.Sprintf("data%d.bin", i): "\x00\x01\x02",
+		}
+		for name, content := range files {
+			if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func TestGenerateReport_ReadsEachFileOnce(t *testing.T) {
+	tmpDir := t.TempDir()
+	if err := writeMixedRepo(tmpDir, 2); err != nil {
+		t.Fatal(err)
+	}
+
+	analyzer := NewAnalyzer(tmpDir, LogQuiet)
+	counter := countReads(analyzer)
+	report, err := analyzer.GenerateReport("", true)
+	if err != nil {
+		t.Fatalf("GenerateReport returned error: %v", err)
+	}
+	if len(report.Issues) == 0 {
+		t.Fatal("Expected the fixture to produce issues")
+	}
+
+	for _, file := range report.ChangedFiles {
+		if got := counter.reads[filepath.Join(tmpDir, file)]; got != 1 {
+			t.Errorf("%s read %d times, want 1", file, got)
+		}
+	}
+
+	// The cache is released once the report is built, so a second run sees edits
+	createTestFile(t, tmpDir, "app0.py", "x = 1\n")
+	if _, err := analyzer.GenerateReport("", true); err != nil {
+		t.Fatalf("GenerateReport returned error: %v", err)
+	}
+	if got := counter.reads[filepath.Join(tmpDir, "app0.py")]; got != 2 {
+		t.Errorf("app0.py read %d times over two runs, want 2", got)
+	}
+}
+
+func TestSecurityAndQualityPasses_Concurrent(t *testing.T) {
+	tmpDir := t.TempDir()
+	if err := writeMixedRepo(tmpDir, 10); err != nil {
+		t.Fatal(err)
+	}
+
+	// issueKeys lists the issues of a report in a stable order
+	issueKeys := func(report *Report) []string {
+		var keys []string
+		for _, issue := range report.Issues {
+			keys = append(keys, fmt.Sprintf("%s:%d:%s", issue.File, issue.Line, issue.RuleID))
+		}
+		slices.Sort(keys)
+		return keys
+	}
+	newScanReport := func(analyzer *Analyzer) *Report {
+		report := NewReport()
+		if err := analyzer.analyzeFullCodebase(report); err != nil {
+			t.Fatalf("analyzeFullCodebase returned error: %v", err)
+		}
+		return report
+	}
+
+	sequential := NewAnalyzer(tmpDir, LogQuiet)
+	want := newScanReport(sequential)
+	sequential.runSecurityChecks(want)
+	sequential.runQualityChecks(want, nil)
+
+	concurrent := NewAnalyzer(tmpDir, LogQuiet)
+	counter := countReads(concurrent)
+	got := newScanReport(concurrent)
+
+	// Each file gets its own security and quality goroutine, all sharing one report
+	runQualityChecks := func(report *Report) { concurrent.runQualityChecks(report, nil) }
+	var wg sync.WaitGroup
+	for _, file := range got.ChangedFiles {
+		for _, pass := range []func(*Report){concurrent.runSecurityChecks, runQualityChecks} {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				fileReport := &Report{ChangedFiles: []string{file}}
+				pass(fileReport)
+				for _, issue := range fileReport.Issues {
+					got.AddIssue(issue)
+				}
+			}()
+		}
+	}
+	wg.Wait()
+
+	if !slices.Equal(issueKeys(got), issueKeys(want)) {
+		t.Errorf("Concurrent passes found\n%v\nwant\n%v", issueKeys(got), issueKeys(want))
+	}
+	for _, file := range got.ChangedFiles {
+		if n := counter.reads[filepath.Join(tmpDir, file)]; n != 1 {
+			t.Errorf("%s read %d times by concurrent passes, want 1", file, n)
+		}
+	}
+}
+
+func BenchmarkGenerateReport_FullScan(b *testing.B) {
+	tmpDir := b.TempDir()
+	if err := writeMixedRepo(tmpDir, 25); err != nil {
+		b.Fatal(err)
+	}
+	analyzer := NewAnalyzer(tmpDir, LogQuiet)
+	counter := countReads(analyzer)
+
+	var files int
+	for b.Loop() {
+		report, err := analyzer.GenerateReport("", true)
+		if err != nil {
+			b.Fatalf("GenerateReport returned error: %v", err)
+		}
+		files += len(report.ChangedFiles)
+	}
+
+	reads := 0
+	for _, n := range counter.reads {
+		reads += n
+	}
+	b.ReportMetric(float64(reads)/float64(files), "reads/file")
+}
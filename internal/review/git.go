@@ -0,0 +1,262 @@
+package review
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// targetResolution is the ref a diff-mode run compares HEAD against
+type targetResolution struct {
+	// requested is the target branch as given, empty when it was detected
+	requested string
+	// branch is the target branch name, e.g. "main"
+	branch string
+	// ref is what git diff compares against, e.g. "origin/main" or "main"; HEAD
+	// for staged changes and empty, meaning the index, for working tree changes
+	ref string
+	// diffArgs select the changes under review in git diff, e.g. "origin/main..HEAD"
+	diffArgs []string
+	// description names the changes under review in messages, e.g. "between main and HEAD"
+	description string
+}
+
+// Diff sources review uncommitted changes in place of the target branch diff
+const (
+	// DiffStaged reviews the changes staged in the index, as git diff --cached shows them
+	DiffStaged = "staged"
+	// DiffWorking reviews the working tree changes not yet staged, as git diff shows them
+	DiffWorking = "working"
+)
+
+// newBranchTarget returns the resolution comparing HEAD against ref
+func newBranchTarget(requested, branch, ref string) *targetResolution {
+	return &targetResolution{
+		requested:   requested,
+		branch:      branch,
+		ref:         ref,
+		diffArgs:    []string{ref + "..HEAD"},
+		description: fmt.Sprintf("between %s and HEAD", ref),
+	}
+}
+
+// SetRemote sets the remote target branches are fetched from; empty detects it
+func (a *Analyzer) SetRemote(remote string) {
+	a.remote = remote
+	a.target = nil
+}
+
+// SetDiffSource reviews uncommitted changes instead of the target branch diff:
+// DiffStaged for the index, DiffWorking for unstaged changes, or empty for the
+// target branch
+func (a *Analyzer) SetDiffSource(source string) {
+	a.diffSource = source
+	a.target = nil
+}
+
+// SetOffline stops the analyzer from fetching the target branch, using only refs already present
+func (a *Analyzer) SetOffline(offline bool) {
+	a.offline = offline
+	a.target = nil
+}
+
+// resolveTarget works out which ref to diff against. An empty targetBranch
+// uses the remote's default branch. The remote-tracking branch is fetched
+// first (unless offline) and preferred; a local branch, tag or commit of the
+// same name is the fallback. The result is cached for the rest of the run.
+// Reviews of staged or working tree changes need no target branch.
+func (a *Analyzer) resolveTarget(targetBranch string) (*targetResolution, error) {
+	if a.target != nil && a.target.requested == targetBranch {
+		return a.target, nil
+	}
+
+	fail := func(format string, args ...any) error {
+		return &SetupError{Err: fmt.Errorf("could not resolve the target branch: "+format, args...)}
+	}
+
+	if _, err := a.git("rev-parse", "--git-dir"); err != nil {
+		return nil, fail("%s is not a git repository (%v)", a.repoPath, err)
+	}
+
+	switch a.diffSource {
+	case DiffStaged:
+		a.target = &targetResolution{requested: targetBranch, ref: "HEAD", diffArgs: []string{"--cached"}, description: "in the index (staged)"}
+		return a.target, nil
+	case DiffWorking:
+		a.target = &targetResolution{requested: targetBranch, description: "in the working tree (unstaged)"}
+		return a.target, nil
+	}
+
+	remote, err := a.resolveRemote()
+	if err != nil {
+		return nil, fail("%v", err)
+	}
+
+	branch := targetBranch
+	if branch == "" {
+		if remote == "" {
+			return nil, fail("no target branch given and the repository has no remote to detect a default branch from; pass --target")
+		}
+		if branch, err = a.defaultBranch(remote); err != nil {
+			return nil, fail("%v", err)
+		}
+		a.log.Infof("Using default branch of %s: %s", remote, branch)
+	}
+
+	tried := []string{}
+	var fetchErr error
+	if remote != "" {
+		if a.offline {
+			a.log.Infof("Offline: not fetching %s from %s", branch, remote)
+		} else {
+			fetchErr = a.fetchBranch(remote, branch)
+		}
+
+		remoteRef := remote + "/" + branch
+		tried = append(tried, "refs/remotes/"+remoteRef)
+		if a.refExists("refs/remotes/" + remoteRef) {
+			a.target = newBranchTarget(targetBranch, branch, remoteRef)
+			return a.target, nil
+		}
+	}
+
+	tried = append(tried, branch)
+	if a.refExists(branch) {
+		a.target = newBranchTarget(targetBranch, branch, branch)
+		return a.target, nil
+	}
+
+	hint := "check --target"
+	switch {
+	case fetchErr != nil:
+		hint = fmt.Sprintf("fetching it from %s failed: %v", remote, fetchErr)
+	case remote != "" && a.offline:
+		hint = fmt.Sprintf("run `git fetch %s %s` or drop --offline", remote, branch)
+	case remote == "":
+		hint = "the repository has no remote; check --target or add the remote"
+	}
+	return nil, fail("%q not found (tried %s); %s", branch, strings.Join(tried, ", "), hint)
+}
+
+// resolveRemote returns the configured remote, or origin, or the only remote.
+// It returns "" when the repository has no remotes.
+func (a *Analyzer) resolveRemote() (string, error) {
+	output, err := a.git("remote")
+	if err != nil {
+		return "", fmt.Errorf("failed to list remotes: %w", err)
+	}
+	remotes := strings.Fields(output)
+
+	switch {
+	case a.remote != "":
+		if !slices.Contains(remotes, a.remote) {
+			available := strings.Join(remotes, ", ")
+			if available == "" {
+				available = "none"
+			}
+			return "", fmt.Errorf("remote %q does not exist (available: %s)", a.remote, available)
+		}
+		return a.remote, nil
+	case len(remotes) == 0:
+		return "", nil
+	case slices.Contains(remotes, "origin"):
+		return "origin", nil
+	case len(remotes) > 1:
+		a.log.Warnf("Several remotes and none named origin, using %s (set --remote to choose)", remotes[0])
+	}
+	return remotes[0], nil
+}
+
+// defaultBranch reads the branch the remote's HEAD points at, asking the
+// remote when the local symbolic ref has not been set up
+func (a *Analyzer) defaultBranch(remote string) (string, error) {
+	headRef := "refs/remotes/" + remote + "/HEAD"
+	output, err := a.git("symbolic-ref", "--short", headRef)
+	if err != nil && !a.offline {
+		if _, setErr := a.git("remote", "set-head", remote, "--auto"); setErr == nil {
+			output, err = a.git("symbolic-ref", "--short", headRef)
+		}
+	}
+	if err != nil {
+		return "", fmt.Errorf("no target branch given and the default branch of %s is unknown (%s is not set); pass --target or run `git remote set-head %s --auto`", remote, headRef, remote)
+	}
+	return strings.TrimPrefix(strings.TrimSpace(output), remote+"/"), nil
+}
+
+// fetchBranch updates the remote-tracking branch for branch. Shallow clones
+// fetch only the tip, which is all the tree comparison needs.
+func (a *Analyzer) fetchBranch(remote, branch string) error {
+	args := []string{"fetch", "--no-tags", "--quiet"}
+	if shallow, err := a.git("rev-parse", "--is-shallow-repository"); err == nil && strings.TrimSpace(shallow) == "true" {
+		args = append(args, "--depth=1")
+	}
+	args = append(args, remote, "+refs/heads/"+branch+":refs/remotes/"+remote+"/"+branch)
+
+	a.log.Infof("Fetching %s from %s", branch, remote)
+	if _, err := a.git(args...); err != nil {
+		a.log.Warnf("Could not fetch %s from %s: %v", branch, remote, err)
+		return err
+	}
+	return nil
+}
+
+// headCommit describes the checked out commit, or returns nil when the
+// repository has no commits yet or is not a repository at all
+func (a *Analyzer) headCommit() *Commit {
+	output, err := a.git("log", "-1", "--format=%H%x00%an%x00%ae%x00%s")
+	if err != nil {
+		a.log.Debugf("Could not read the HEAD commit: %v", err)
+		return nil
+	}
+	fields := strings.SplitN(strings.TrimRight(output, "\n"), "\x00", 4)
+	if len(fields) != 4 || fields[0] == "" {
+		return nil
+	}
+	return &Commit{SHA: fields[0], AuthorName: fields[1], AuthorEmail: fields[2], Subject: fields[3]}
+}
+
+// refExists reports whether ref names a commit
+func (a *Analyzer) refExists(ref string) bool {
+	_, err := a.git("rev-parse", "--verify", "--quiet", ref+"^{commit}")
+	return err == nil
+}
+
+// targetVersion returns a file's content on the target branch of a diff review,
+// at HEAD when reviewing staged changes, or in the index for working tree changes.
+// It reports false in a full scan, which has no target, and for files the target
+// branch does not have.
+func (a *Analyzer) targetVersion(file string) (string, bool) {
+	if a.target == nil {
+		return "", false
+	}
+	content, err := a.git("show", a.target.ref+":"+filepath.ToSlash(file))
+	if err != nil {
+		return "", false
+	}
+	return content, true
+}
+
+// git runs a git command in the repository and returns its stdout. Errors
+// carry the first line of git's stderr, which names the problem.
+func (a *Analyzer) git(args ...string) (string, error) {
+	return a.runGit(args...)
+}
+
+// execGit is the default git runner
+func (a *Analyzer) execGit(args ...string) (string, error) {
+	cmd := exec.CommandContext(a.ctx, "git", args...)
+	cmd.Dir = a.repoPath
+	output, err := cmd.Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && len(exitErr.Stderr) > 0 {
+			reason, _, _ := strings.Cut(strings.TrimSpace(string(exitErr.Stderr)), "\n")
+			return "", fmt.Errorf("%w: %s", err, reason)
+		}
+		return "", err
+	}
+	return string(output), nil
+}
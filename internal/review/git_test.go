@@ -0,0 +1,168 @@
+package review
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// runGit runs git in dir with a fixed identity and fails the test on error
+func runGit(t *testing.T, dir string, args ...string) {
+	t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(),
+		"GIT_AUTHOR_NAME=Test", "GIT_AUTHOR_EMAIL=test@example.com",
+		"GIT_COMMITTER_NAME=Test", "GIT_COMMITTER_EMAIL=test@example.com",
+	)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("git %s failed: %v\n%s", strings.Join(args, " "), err, output)
+	}
+}
+
+// commitFile writes a file and commits it
+func commitFile(t *testing.T, dir, name, content string) {
+	t.Helper()
+	createTestFile(t, dir, name, content)
+	runGit(t, dir, "add", name)
+	runGit(t, dir, "commit", "-q", "-m", "add "+name)
+}
+
+// newLocalRepo creates a repository with a main branch and a checked out
+// feature branch that adds feature.py
+func newLocalRepo(t *testing.T) string {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	dir := t.TempDir()
+	runGit(t, dir, "init", "-q", "-b", "main")
+	commitFile(t, dir, "base.py", "x = 1\n")
+	runGit(t, dir, "checkout", "-q", "-b", "feature")
+	commitFile(t, dir, "feature.py", "y = 2\n")
+	return dir
+}
+
+// newUpstream publishes a local repository as a bare repository and returns its file URL
+func newUpstream(t *testing.T) string {
+	t.Helper()
+	bare := filepath.Join(t.TempDir(), "upstream.git")
+	runGit(t, newLocalRepo(t), "clone", "-q", "--bare", ".", bare)
+	runGit(t, bare, "symbolic-ref", "HEAD", "refs/heads/main")
+	return "file://" + filepath.ToSlash(bare)
+}
+
+func TestResolveTarget_LocalOnlyBranch(t *testing.T) {
+	dir := newLocalRepo(t)
+	analyzer := NewAnalyzer(dir, LogQuiet)
+
+	files, err := analyzer.diffFiles("main")
+	if err != nil {
+		t.Fatalf("diffFiles returned error: %v", err)
+	}
+	if analyzer.target.ref != "main" || !slices.Equal(files, []string{"feature.py"}) {
+		t.Errorf("Expected feature.py changed against local main, got %v against %q", files, analyzer.target.ref)
+	}
+
+	if _, err := analyzer.resolveTarget("develop"); err == nil || !contains(err.Error(), `"develop" not found (tried develop)`) {
+		t.Errorf("Expected error naming the refs tried, got %v", err)
+	}
+	if _, err := analyzer.resolveTarget(""); err == nil || !contains(err.Error(), "no remote") {
+		t.Errorf("Expected error explaining there is no remote to detect a default branch from, got %v", err)
+	}
+}
+
+func TestResolveTarget_RenamedRemote(t *testing.T) {
+	url := newUpstream(t)
+	dir := filepath.Join(t.TempDir(), "clone")
+	runGit(t, filepath.Dir(dir), "clone", "-q", "-o", "upstream", "-b", "main", url, dir)
+	runGit(t, dir, "checkout", "-q", "-b", "topic")
+	commitFile(t, dir, "topic.py", "z = 3\n")
+
+	analyzer := NewAnalyzer(dir, LogQuiet)
+	files, err := analyzer.diffFiles("")
+	if err != nil {
+		t.Fatalf("diffFiles returned error: %v", err)
+	}
+	if analyzer.target.branch != "main" || analyzer.target.ref != "upstream/main" {
+		t.Errorf("Expected default branch main of remote upstream, got %+v", analyzer.target)
+	}
+	if !slices.Equal(files, []string{"topic.py"}) {
+		t.Errorf("Expected only topic.py to be changed, got %v", files)
+	}
+
+	analyzer.SetRemote("origin")
+	if _, err := analyzer.resolveTarget("main"); err == nil || !contains(err.Error(), `remote "origin" does not exist (available: upstream)`) {
+		t.Errorf("Expected unknown remote error, got %v", err)
+	}
+}
+
+func TestResolveTarget_ShallowClone(t *testing.T) {
+	url := newUpstream(t)
+	dir := filepath.Join(t.TempDir(), "clone")
+	runGit(t, filepath.Dir(dir), "clone", "-q", "--depth=1", "--single-branch", "-b", "feature", url, dir)
+
+	// Like a CI checkout, the clone only knows about the branch it checked out
+	offline := NewAnalyzer(dir, LogQuiet)
+	offline.SetOffline(true)
+	_, err := offline.resolveTarget("main")
+	if err == nil || !contains(err.Error(), "refs/remotes/origin/main") || !contains(err.Error(), "--offline") {
+		t.Errorf("Expected offline resolution to name the missing ref, got %v", err)
+	}
+
+	analyzer := NewAnalyzer(dir, LogQuiet)
+	files, err := analyzer.diffFiles("main")
+	if err != nil {
+		t.Fatalf("diffFiles returned error: %v", err)
+	}
+	if analyzer.target.ref != "origin/main" || !slices.Equal(files, []string{"feature.py"}) {
+		t.Errorf("Expected the missing target to be fetched, got %v against %q", files, analyzer.target.ref)
+	}
+}
+
+func TestHeadCommit(t *testing.T) {
+	dir := newLocalRepo(t)
+	report, err := NewAnalyzer(dir, LogQuiet).GenerateReport("main", false)
+	if err != nil {
+		t.Fatalf("GenerateReport returned error: %v", err)
+	}
+
+	commit := report.Commit
+	if commit == nil {
+		t.Fatal("Expected the report to record the HEAD commit")
+	}
+	if len(commit.SHA) != 40 || commit.AuthorName != "Test" || commit.AuthorEmail != "test@example.com" || commit.Subject != "add feature.py" {
+		t.Errorf("Unexpected commit: %+v", commit)
+	}
+
+	var buf bytes.Buffer
+	report.WriteText(&buf)
+	if !contains(buf.String(), "Commit: "+commit.SHA[:7]+" add feature.py (Test <test@example.com>)") {
+		t.Errorf("Expected the commit in the text header, got:\n%s", buf.String())
+	}
+}
+
+func TestHeadCommit_NoCommits(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	dir := t.TempDir()
+	runGit(t, dir, "init", "-q")
+	createTestFile(t, dir, "app.py", "x = 1\n")
+
+	report, err := NewAnalyzer(dir, LogQuiet).GenerateReport("", true)
+	if err != nil {
+		t.Fatalf("GenerateReport returned error: %v", err)
+	}
+	if report.Commit != nil {
+		t.Errorf("Expected no commit before the first commit, got %+v", report.Commit)
+	}
+	var buf bytes.Buffer
+	if err := report.OutputJSON(&buf); err != nil || contains(buf.String(), `"commit"`) {
+		t.Errorf("Expected the commit to be omitted from JSON, got %v:\n%s", err, buf.String())
+	}
+}
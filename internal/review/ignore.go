@@ -0,0 +1,97 @@
+package review
+
+import (
+	"regexp"
+	"strings"
+)
+
+// ignoreRule is an ignore pattern compiled with .gitignore semantics
+type ignoreRule struct {
+	IgnorePattern
+	// negate is set for !patterns, which re-include paths an earlier pattern ignored
+	negate bool
+	// dirOnly is set for patterns ending in /, which only match directories
+	dirOnly bool
+	re      *regexp.Regexp
+}
+
+// compileIgnorePattern compiles a pattern the way git reads a .gitignore line:
+// a leading ! negates it, a trailing / limits it to directories, a pattern
+// containing another / is anchored to the repository root and otherwise
+// matches at any depth, and ** matches any number of directories
+func compileIgnorePattern(ignore IgnorePattern) ignoreRule {
+	rule := ignoreRule{IgnorePattern: ignore}
+	pattern := ignore.Pattern
+	if rest, ok := strings.CutPrefix(pattern, "!"); ok {
+		rule.negate = true
+		pattern = rest
+	}
+	if rest, ok := strings.CutSuffix(pattern, "/"); ok {
+		rule.dirOnly = true
+		pattern = rest
+	}
+
+	prefix := "(?:.*/)?"
+	if strings.Contains(pattern, "/") {
+		prefix = ""
+		pattern = strings.TrimPrefix(pattern, "/")
+	}
+	re, err := regexp.Compile("^" + prefix + globToRegexp(pattern) + "$")
+	if err != nil {
+		// A malformed character class such as [] is matched literally
+		re = regexp.MustCompile("^" + prefix + regexp.QuoteMeta(pattern) + "$")
+	}
+	rule.re = re
+	return rule
+}
+
+// globToRegexp translates a .gitignore glob into a regular expression. * and ?
+// do not match /, while **/ and /** match any number of directories.
+func globToRegexp(glob string) string {
+	var re strings.Builder
+	for i := 0; i < len(glob); i++ {
+		switch c := glob[i]; {
+		case strings.HasPrefix(glob[i:], "**/") && (i == 0 || glob[i-1] == '/'):
+			re.WriteString("(?:.*/)?")
+			i += 2
+		case glob[i:] == "**" && (i == 0 || glob[i-1] == '/'):
+			re.WriteString(".*")
+			i++
+		case c == '*':
+			re.WriteString("[^/]*")
+		case c == '?':
+			re.WriteString("[^/]")
+		case c == '\\' && i+1 < len(glob):
+			i++
+			re.WriteString(regexp.QuoteMeta(glob[i : i+1]))
+		case c == '[':
+			end := strings.IndexByte(glob[i+1:], ']')
+			if end < 0 {
+				re.WriteString(`\[`)
+				continue
+			}
+			class := glob[i+1 : i+1+end]
+			if rest, ok := strings.CutPrefix(class, "!"); ok {
+				class = "^" + rest
+			}
+			re.WriteString("[" + strings.ReplaceAll(class, `\`, `\\`) + "]")
+			i += end + 1
+		default:
+			re.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	return re.String()
+}
+
+// lastIgnoreMatch returns the last rule matching path, which decides whether it
+// is ignored as in .gitignore files; isDir reports whether path is a directory
+func lastIgnoreMatch(rules []ignoreRule, path string, isDir bool) (ignoreRule, bool) {
+	var last ignoreRule
+	matched := false
+	for _, rule := range rules {
+		if (!rule.dirOnly || isDir) && rule.re.MatchString(path) {
+			last, matched = rule, true
+		}
+	}
+	return last, matched
+}
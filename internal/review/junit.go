@@ -0,0 +1,92 @@
+package review
+
+import (
+	"cmp"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"path/filepath"
+	"slices"
+)
+
+// junitTestSuites is the root of a JUnit XML report as Jenkins and most CI
+// servers read it
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	TestCases []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string       `xml:"name,attr"`
+	ClassName string       `xml:"classname,attr"`
+	Failure   junitFailure `xml:"failure"`
+}
+
+// junitFailure carries the issue's message, its severity as the failure type
+// and its file:line location as the body
+type junitFailure struct {
+	Message  string `xml:"message,attr"`
+	Type     string `xml:"type,attr"`
+	Location string `xml:",chardata"`
+}
+
+// OutputJUnit writes the report as JUnit XML for CI servers that ingest test
+// reports: one test suite per issue type, with each issue a failed test case
+// named after its rule and located by file:line
+func (r *Report) OutputJUnit(w io.Writer) error {
+	suites := map[string]*junitTestSuite{}
+	for _, issue := range r.Issues {
+		suite, ok := suites[issue.Type]
+		if !ok {
+			suite = &junitTestSuite{Name: issue.Type, TestCases: []junitTestCase{}}
+			suites[issue.Type] = suite
+		}
+
+		file := filepath.ToSlash(filepath.Clean(issue.File))
+		location := file
+		if issue.Line > 0 {
+			location = fmt.Sprintf("%s:%d", file, issue.Line)
+		}
+		name := issue.RuleID
+		if name == "" {
+			name = issue.Type
+		}
+
+		suite.Tests++
+		suite.Failures++
+		suite.TestCases = append(suite.TestCases, junitTestCase{
+			Name:      fmt.Sprintf("%s at %s", name, location),
+			ClassName: file,
+			Failure:   junitFailure{Message: issue.Message, Type: issue.Severity, Location: location},
+		})
+	}
+
+	root := junitTestSuites{Name: "code-review", Suites: []junitTestSuite{}}
+	for _, suite := range suites {
+		root.Suites = append(root.Suites, *suite)
+		root.Tests += suite.Tests
+		root.Failures += suite.Failures
+	}
+	slices.SortFunc(root.Suites, func(a, b junitTestSuite) int { return cmp.Compare(a.Name, b.Name) })
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(root); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
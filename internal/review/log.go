@@ -0,0 +1,81 @@
+package review
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/fatih/color"
+)
+
+// LogLevel controls how much progress the analyzer logs. Each level includes
+// the messages of the levels below it.
+type LogLevel int
+
+const (
+	// LogQuiet logs nothing; errors are returned to the caller
+	LogQuiet LogLevel = iota
+	// LogWarn logs problems the run recovered from, e.g. a plugin that failed
+	LogWarn
+	// LogInfo also logs the stages of the run
+	LogInfo
+	// LogDebug also logs per-file and per-pattern decisions
+	LogDebug
+)
+
+// LogLevels lists the level names, from quietest to most verbose
+var LogLevels = []string{"quiet", "warn", "info", "debug"}
+
+func (l LogLevel) String() string {
+	if l < LogQuiet || int(l) >= len(LogLevels) {
+		return fmt.Sprintf("LogLevel(%d)", int(l))
+	}
+	return LogLevels[l]
+}
+
+// Logger writes leveled progress messages
+type Logger struct {
+	level LogLevel
+	out   io.Writer
+}
+
+// NewLogger returns a logger writing messages up to level to w
+func NewLogger(level LogLevel, w io.Writer) *Logger {
+	return &Logger{level: min(max(level, LogQuiet), LogDebug), out: w}
+}
+
+// Level returns the most verbose level the logger writes
+func (l *Logger) Level() LogLevel {
+	return l.level
+}
+
+// Enabled reports whether messages at level are written
+func (l *Logger) Enabled(level LogLevel) bool {
+	return level <= l.level
+}
+
+// Warnf logs a problem the run recovered from
+func (l *Logger) Warnf(format string, args ...any) {
+	l.logf(LogWarn, color.New(color.FgYellow), "[WARN] ", format, args...)
+}
+
+// Infof logs a stage of the run
+func (l *Logger) Infof(format string, args ...any) {
+	l.logf(LogInfo, color.New(color.FgBlue), "[INFO] ", format, args...)
+}
+
+// Successf logs a completed delivery step, such as a saved report
+func (l *Logger) Successf(format string, args ...any) {
+	l.logf(LogInfo, color.New(color.FgGreen), "[SUCCESS] ", format, args...)
+}
+
+// Debugf logs a per-file or per-pattern decision
+func (l *Logger) Debugf(format string, args ...any) {
+	l.logf(LogDebug, color.New(color.FgCyan), "[DEBUG] ", format, args...)
+}
+
+func (l *Logger) logf(level LogLevel, c *color.Color, prefix, format string, args ...any) {
+	if !l.Enabled(level) {
+		return
+	}
+	c.Fprintf(l.out, prefix+format+"\n", args...)
+}
@@ -0,0 +1,61 @@
+package review
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// runWithLogLevel runs a full scan whose ticket tracker is unreachable, so the
+// run logs a warning alongside its usual progress, and returns the log
+func runWithLogLevel(t *testing.T, level LogLevel) string {
+	t.Helper()
+	tmpDir := t.TempDir()
+	createTestFile(t, tmpDir, "app.py", "# TODO PROJ-1 remove\n")
+
+	server := httptest.NewServer(http.NotFoundHandler())
+	server.Close()
+
+	var buf bytes.Buffer
+	_, err := Run(context.Background(), Options{
+		RepoPath:       tmpDir,
+		FullScan:       true,
+		TicketTrackers: []TicketTracker{&JiraTracker{BaseURL: server.URL}},
+		LogLevel:       level,
+		LogOutput:      &buf,
+	})
+	if err != nil {
+		t.Fatalf("Run returned error: %v", err)
+	}
+	return buf.String()
+}
+
+func TestLogLevel_WarnOnlyLogsWarnings(t *testing.T) {
+	out := runWithLogLevel(t, LogWarn)
+
+	if !strings.Contains(out, "[WARN] Skipping jira ticket checks") {
+		t.Errorf("Expected the tracker warning at level 1, got:\n%s", out)
+	}
+	if strings.Contains(out, "[INFO]") || strings.Contains(out, "[DEBUG]") {
+		t.Errorf("Expected only warnings at level 1, got:\n%s", out)
+	}
+}
+
+func TestLogLevel_DebugLogsEverything(t *testing.T) {
+	out := runWithLogLevel(t, LogDebug)
+
+	for _, prefix := range []string{"[WARN]", "[INFO]", "[DEBUG]"} {
+		if !strings.Contains(out, prefix) {
+			t.Errorf("Expected %s messages at level 3, got:\n%s", prefix, out)
+		}
+	}
+}
+
+func TestLogLevel_QuietLogsNothing(t *testing.T) {
+	if out := runWithLogLevel(t, LogQuiet); out != "" {
+		t.Errorf("Expected no log output at level 0, got:\n%s", out)
+	}
+}
@@ -0,0 +1,220 @@
+package review
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/fatih/color"
+)
+
+// Options configures a single review run
+type Options struct {
+	// RepoPath is the repository root to analyze; defaults to the current directory
+	RepoPath string `json:"repo_path"`
+	// TargetBranch is the branch changes are compared against; empty uses the remote's default branch
+	TargetBranch string `json:"target_branch"`
+	// Remote is the remote the target branch is fetched from; empty uses origin or the only remote
+	Remote string `json:"remote,omitempty"`
+	// Offline never fetches the target branch, using only refs already present
+	Offline bool `json:"offline,omitempty"`
+	// FullScan analyzes every supported file instead of only the changed ones
+	FullScan bool `json:"full_scan"`
+	// DiffSource reviews uncommitted changes instead of comparing HEAD with the
+	// target branch: DiffStaged or DiffWorking. TargetBranch is then not needed
+	DiffSource string `json:"diff_source,omitempty"`
+	// IncludeSubmodules makes full scans descend into git submodules and nested
+	// repositories instead of skipping them
+	IncludeSubmodules bool `json:"include_submodules,omitempty"`
+	// Only restricts which check categories run (security, quality, performance
+	// or all); empty runs every category
+	Only []string `json:"only,omitempty"`
+	// IgnorePatterns are applied in addition to the repository's .autoreview-ignore file
+	IgnorePatterns []IgnorePattern `json:"ignore_patterns,omitempty"`
+	// ShowClean lists the analyzed files without issues in the report's CleanFiles
+	ShowClean bool `json:"show_clean,omitempty"`
+	// IncludePatterns, when set, limit the run to the changed or scanned files matching
+	// one of these globs, e.g. "src/api/**". Ignore patterns still win
+	IncludePatterns []string `json:"include_patterns,omitempty"`
+	// EnabledRules limits the report to these issue types; empty means all
+	EnabledRules []string `json:"enabled_rules,omitempty"`
+	// DisabledRules drops these issue types or rule IDs from the report
+	DisabledRules []string `json:"disabled_rules,omitempty"`
+	// SeverityOverrides reports the keyed rule IDs with the given severity instead of their own
+	SeverityOverrides map[string]string `json:"severity_overrides,omitempty"`
+	// MinSeverity drops issues below this severity (critical, high, medium, low or info); empty keeps all
+	MinSeverity string `json:"min_severity,omitempty"`
+	// LanguageDisabledRules drops these rule IDs only for files of the keyed language, e.g. "python"
+	LanguageDisabledRules map[string][]string `json:"language_disabled_rules,omitempty"`
+	// MaxLineLength is the longest line the line-length rule accepts; 0 uses DefaultLineLengthLimit
+	MaxLineLength int `json:"max_line_length,omitempty"`
+	// LanguageMaxLineLength overrides MaxLineLength for files of the keyed language, e.g. "python"
+	LanguageMaxLineLength map[string]int `json:"language_max_line_length,omitempty"`
+	// MaxFileSize is the largest file, in bytes, quality checks run on; 0 uses DefaultMaxFileSize
+	MaxFileSize int `json:"max_file_size,omitempty"`
+	// Plugins are external checks run after the built-in analyzers
+	Plugins []Plugin `json:"plugins,omitempty"`
+	// TicketTrackers, when set, flag TODO comments that reference closed tickets
+	TicketTrackers []TicketTracker `json:"-"`
+	// InternalPackages are name prefixes of private packages, e.g. "acme-"; unscoped
+	// references to them are flagged as dependency confusion risks. Empty skips the check
+	InternalPackages []string `json:"internal_packages,omitempty"`
+	// LogLevel controls how much progress is logged; the zero value logs nothing
+	LogLevel LogLevel `json:"log_level,omitempty"`
+	// LogOutput receives log messages; nil writes to stderr
+	LogOutput io.Writer `json:"-"`
+}
+
+// Validate checks the options for unsupported values
+func (o Options) Validate() error {
+	if _, err := ParseCategories(o.Only); err != nil {
+		return err
+	}
+	if o.DiffSource != "" && o.DiffSource != DiffStaged && o.DiffSource != DiffWorking {
+		return fmt.Errorf("unknown diff source %q (expected %s or %s)", o.DiffSource, DiffStaged, DiffWorking)
+	}
+	if o.DiffSource != "" && o.FullScan {
+		return fmt.Errorf("reviewing %s changes cannot be combined with a full scan", o.DiffSource)
+	}
+	for language := range o.LanguageDisabledRules {
+		if !slices.Contains(Languages(), strings.ToLower(language)) {
+			return fmt.Errorf("unknown language %q in rules (expected one of %s)", language, strings.Join(Languages(), ", "))
+		}
+	}
+	if o.MaxLineLength < 0 {
+		return fmt.Errorf("max line length must be positive, got %d", o.MaxLineLength)
+	}
+	for language, limit := range o.LanguageMaxLineLength {
+		if !slices.Contains(Languages(), strings.ToLower(language)) {
+			return fmt.Errorf("unknown language %q in rules (expected one of %s)", language, strings.Join(Languages(), ", "))
+		}
+		if limit < 0 {
+			return fmt.Errorf("max line length for %s must be positive, got %d", language, limit)
+		}
+	}
+	if o.MaxFileSize < 0 {
+		return fmt.Errorf("max file size must be positive, got %d", o.MaxFileSize)
+	}
+	for _, plugin := range o.Plugins {
+		if plugin.Name == "" || plugin.Command == "" {
+			return fmt.Errorf("plugins require both a name and a command")
+		}
+	}
+	for rule, severity := range o.SeverityOverrides {
+		if err := checkSeverity(strings.ToLower(severity)); err != nil {
+			return fmt.Errorf("invalid severity override for %s: %w", rule, err)
+		}
+	}
+	if o.MinSeverity != "" {
+		if err := checkSeverity(o.MinSeverity); err != nil {
+			return fmt.Errorf("invalid minimum severity: %w", err)
+		}
+	}
+	return nil
+}
+
+// NewAnalyzerFromOptions creates an analyzer with the options' ignore patterns and rules applied
+func NewAnalyzerFromOptions(opts Options) *Analyzer {
+	repoPath := opts.RepoPath
+	if repoPath == "" {
+		repoPath = "."
+	}
+
+	logOutput := opts.LogOutput
+	if logOutput == nil {
+		logOutput = color.Error
+	}
+
+	analyzer := newAnalyzer(repoPath, NewLogger(opts.LogLevel, logOutput))
+	for _, pattern := range opts.IgnorePatterns {
+		source := pattern.Source
+		if source == "" {
+			source = "options"
+		}
+		analyzer.AddIgnorePatterns([]string{pattern.Pattern}, source)
+	}
+	analyzer.SetIncludePatterns(opts.IncludePatterns)
+	analyzer.SetDisabledRules(opts.DisabledRules)
+	analyzer.SetLanguageDisabledRules(opts.LanguageDisabledRules)
+	analyzer.SetSeverityOverrides(opts.SeverityOverrides)
+	analyzer.SetLineLengthLimits(opts.MaxLineLength, opts.LanguageMaxLineLength)
+	analyzer.SetMaxFileSize(opts.MaxFileSize)
+	analyzer.SetPlugins(opts.Plugins)
+	analyzer.SetTicketTrackers(opts.TicketTrackers)
+	analyzer.SetInternalPackages(opts.InternalPackages)
+	analyzer.SetIncludeSubmodules(opts.IncludeSubmodules)
+	analyzer.SetRemote(opts.Remote)
+	analyzer.SetOffline(opts.Offline)
+	analyzer.SetDiffSource(opts.DiffSource)
+	// Invalid categories are reported by Validate; fall back to running everything
+	if categories, err := ParseCategories(opts.Only); err == nil {
+		analyzer.SetCategories(categories)
+	}
+	return analyzer
+}
+
+// Run analyzes the repository described by opts and returns the resulting report
+func Run(ctx context.Context, opts Options) (*Report, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, &OptionsError{Err: err}
+	}
+	if opts.RepoPath != "" {
+		if info, err := os.Stat(opts.RepoPath); err != nil || !info.IsDir() {
+			return nil, &SetupError{Err: fmt.Errorf("repository path %q is not a directory", opts.RepoPath)}
+		}
+	}
+
+	analyzer := NewAnalyzerFromOptions(opts)
+	analyzer.ctx = ctx
+
+	report, err := analyzer.GenerateReport(opts.TargetBranch, opts.FullScan)
+	if err != nil {
+		return nil, err
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	enabled := map[string]bool{}
+	for _, rule := range opts.EnabledRules {
+		enabled[rule] = true
+	}
+	minRank := severityRank[opts.MinSeverity]
+
+	report.FilterIssues(func(issue Issue) bool {
+		if len(enabled) > 0 && !enabled[issue.Type] {
+			return false
+		}
+		return severityRank[issue.Severity] >= minRank
+	})
+	if opts.ShowClean {
+		report.ShowCleanFiles()
+	}
+
+	return report, nil
+}
+
+// Explain reports how a run with opts would select and analyze a single file
+func Explain(ctx context.Context, opts Options, file string) (*FileSelection, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, &OptionsError{Err: err}
+	}
+
+	analyzer := NewAnalyzerFromOptions(opts)
+	analyzer.ctx = ctx
+	return analyzer.ExplainFile(opts.TargetBranch, file, opts.FullScan)
+}
+
+// Plan previews the files a run with opts would analyze without running any checks
+func Plan(ctx context.Context, opts Options) (*ScanPlan, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, &OptionsError{Err: err}
+	}
+
+	analyzer := NewAnalyzerFromOptions(opts)
+	analyzer.ctx = ctx
+	return analyzer.PlanScan(opts.TargetBranch, opts.FullScan)
+}
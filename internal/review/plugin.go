@@ -0,0 +1,277 @@
+package review
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+// PluginProtocolVersion is the plugin contract version described in docs/PLUGINS.md
+const PluginProtocolVersion = 1
+
+// DefaultPluginTimeout bounds a single plugin invocation when the plugin sets no timeout
+const DefaultPluginTimeout = 30 * time.Second
+
+// Plugin is an external executable that reports additional issues
+type Plugin struct {
+	// Name namespaces the plugin's rule IDs, e.g. "acme" yields "acme/no-fixme"
+	Name string `json:"name"`
+	// Command is the executable to run; relative paths are resolved against the repository
+	Command string `json:"command"`
+	// Args are passed to Command on every invocation
+	Args []string `json:"args,omitempty"`
+	// Extensions limits the plugin to files with these extensions; empty means all files
+	Extensions []string `json:"extensions,omitempty"`
+	// Timeout bounds each invocation; zero means DefaultPluginTimeout
+	Timeout time.Duration `json:"timeout,omitempty"`
+}
+
+// LineRange is an inclusive range of changed lines
+type LineRange struct {
+	Start int `json:"start"`
+	End   int `json:"end"`
+}
+
+// pluginRequest is written to the plugin's stdin as a single JSON document
+type pluginRequest struct {
+	Type            string      `json:"type"`
+	ProtocolVersion int         `json:"protocol_version"`
+	File            string      `json:"file,omitempty"`
+	Path            string      `json:"path,omitempty"`
+	FullScan        bool        `json:"full_scan,omitempty"`
+	ChangedLines    []LineRange `json:"changed_lines,omitempty"`
+}
+
+// pluginHandshake is the plugin's reply to a handshake request
+type pluginHandshake struct {
+	ProtocolVersion int    `json:"protocol_version"`
+	Name            string `json:"name"`
+}
+
+// pluginIssue is one JSON line of plugin output
+type pluginIssue struct {
+	Rule     string `json:"rule"`
+	Type     string `json:"type"`
+	Severity string `json:"severity"`
+	Message  string `json:"message"`
+	Line     int    `json:"line"`
+}
+
+// SetPlugins configures the external plugins run after the built-in checks
+func (a *Analyzer) SetPlugins(plugins []Plugin) {
+	a.plugins = append([]Plugin(nil), plugins...)
+}
+
+// matches reports whether the plugin wants to analyze file
+func (p Plugin) matches(file string) bool {
+	if len(p.Extensions) == 0 {
+		return true
+	}
+	ext := filepath.Ext(file)
+	for _, want := range p.Extensions {
+		if !strings.HasPrefix(want, ".") {
+			want = "." + want
+		}
+		if strings.EqualFold(ext, want) {
+			return true
+		}
+	}
+	return false
+}
+
+// runPlugins invokes every configured plugin on the matching changed files
+func (a *Analyzer) runPlugins(report *Report, fullScan bool) {
+	for _, plugin := range a.plugins {
+		a.log.Infof("Running plugin: %s", plugin.Name)
+
+		if err := a.pluginHandshake(plugin); err != nil {
+			a.log.Warnf("Skipping plugin %s: %v", plugin.Name, err)
+			continue
+		}
+
+		for _, file := range report.ChangedFiles {
+			if !plugin.matches(file) {
+				continue
+			}
+
+			request := pluginRequest{
+				Type:            "analyze",
+				ProtocolVersion: PluginProtocolVersion,
+				File:            file,
+				Path:            filepath.Join(a.repoPath, file),
+				FullScan:        fullScan,
+			}
+			if !fullScan {
+				changedLines, err := a.getChangedLines(a.targetBranch, file)
+				if err != nil {
+					a.log.Warnf("Could not get changed lines for %s: %v", file, err)
+					continue
+				}
+				if len(changedLines) == 0 {
+					// Plugins take a request without changed lines to mean the whole
+					// file, so a diff that only deletes lines is not sent at all
+					a.log.Debugf("Skipping plugin %s on %s: the diff adds no lines", plugin.Name, file)
+					continue
+				}
+				request.ChangedLines = lineRanges(changedLines)
+			}
+
+			issues, err := a.invokePlugin(plugin, request)
+			if err != nil {
+				a.log.Warnf("Plugin %s failed on %s: %v", plugin.Name, file, err)
+				continue
+			}
+			report.markAnalyzed(file)
+			for _, issue := range issues {
+				if err := report.Add(issue); err != nil {
+					a.log.Warnf("Plugin %s reported an invalid issue on %s: %v", plugin.Name, file, err)
+				}
+			}
+		}
+	}
+}
+
+// pluginHandshake checks that the plugin speaks our protocol version
+func (a *Analyzer) pluginHandshake(plugin Plugin) error {
+	output, err := a.execPlugin(plugin, pluginRequest{Type: "handshake", ProtocolVersion: PluginProtocolVersion})
+	if err != nil {
+		return err
+	}
+
+	var reply pluginHandshake
+	if err := json.Unmarshal(bytes.TrimSpace(output), &reply); err != nil {
+		return fmt.Errorf("invalid handshake reply: %w", err)
+	}
+	if reply.ProtocolVersion != PluginProtocolVersion {
+		return fmt.Errorf("plugin speaks protocol version %d, expected %d", reply.ProtocolVersion, PluginProtocolVersion)
+	}
+	return nil
+}
+
+// invokePlugin runs the plugin for a single file and parses the issues it emits
+func (a *Analyzer) invokePlugin(plugin Plugin, request pluginRequest) ([]Issue, error) {
+	output, err := a.execPlugin(plugin, request)
+	if err != nil {
+		return nil, err
+	}
+
+	issues := []Issue{}
+	scanner := bufio.NewScanner(bytes.NewReader(output))
+	for lineNum := 1; scanner.Scan(); lineNum++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		var emitted pluginIssue
+		if err := json.Unmarshal([]byte(line), &emitted); err != nil {
+			return nil, fmt.Errorf("invalid output on line %d: %w", lineNum, err)
+		}
+		issue, err := emitted.toIssue(plugin.Name, request.File)
+		if err != nil {
+			return nil, fmt.Errorf("invalid issue on line %d: %w", lineNum, err)
+		}
+		issues = append(issues, issue)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return issues, nil
+}
+
+// execPlugin runs the plugin with request on stdin and returns its stdout
+func (a *Analyzer) execPlugin(plugin Plugin, request pluginRequest) ([]byte, error) {
+	timeout := plugin.Timeout
+	if timeout <= 0 {
+		timeout = DefaultPluginTimeout
+	}
+	ctx, cancel := context.WithTimeout(a.ctx, timeout)
+	defer cancel()
+
+	input, err := json.Marshal(request)
+	if err != nil {
+		return nil, err
+	}
+
+	command := plugin.Command
+	if !filepath.IsAbs(command) && strings.ContainsRune(command, filepath.Separator) {
+		command = filepath.Join(a.repoPath, command)
+	}
+
+	cmd := exec.CommandContext(ctx, command, plugin.Args...)
+	cmd.Dir = a.repoPath
+	cmd.Stdin = bytes.NewReader(input)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	// Don't hang on grandchildren that keep stdout open after the plugin is killed
+	cmd.WaitDelay = time.Second
+
+	output, err := cmd.Output()
+	if ctx.Err() == context.DeadlineExceeded {
+		return nil, fmt.Errorf("timed out after %s", timeout)
+	}
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return nil, fmt.Errorf("exited with status %d: %s", exitErr.ExitCode(), strings.TrimSpace(stderr.String()))
+		}
+		return nil, err
+	}
+	return output, nil
+}
+
+// toIssue validates a plugin finding and namespaces its rule ID
+func (p pluginIssue) toIssue(pluginName, file string) (Issue, error) {
+	if p.Rule == "" {
+		return Issue{}, fmt.Errorf("missing rule")
+	}
+	if p.Message == "" {
+		return Issue{}, fmt.Errorf("missing message")
+	}
+	if err := checkSeverity(p.Severity); err != nil {
+		return Issue{}, err
+	}
+
+	issueType := p.Type
+	if issueType == "" {
+		issueType = "quality"
+	}
+	if !slices.Contains(IssueTypes, issueType) {
+		return Issue{}, fmt.Errorf("unknown type %q", issueType)
+	}
+
+	line := p.Line
+	if line < 0 {
+		line = 0
+	}
+
+	return Issue{
+		Type:     issueType,
+		Severity: p.Severity,
+		Message:  p.Message,
+		File:     file,
+		Line:     line,
+		RuleID:   pluginName + "/" + p.Rule,
+	}, nil
+}
+
+// lineRanges collapses changed lines into inclusive ranges
+func lineRanges(lines []changedLine) []LineRange {
+	ranges := []LineRange{}
+	for _, line := range lines {
+		if n := len(ranges); n > 0 && ranges[n-1].End+1 == line.LineNum {
+			ranges[n-1].End = line.LineNum
+			continue
+		}
+		ranges = append(ranges, LineRange{Start: line.LineNum, End: line.LineNum})
+	}
+	return ranges
+}
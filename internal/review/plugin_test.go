@@ -0,0 +1,152 @@
+package review
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// createTestPlugin writes an executable shell plugin into dir
+func createTestPlugin(t *testing.T, dir, name, script string) string {
+	t.Helper()
+	path := createTestFile(t, dir, name, "#!/bin/sh\n"+script)
+	if err := os.Chmod(path, 0755); err != nil {
+		t.Fatalf("Failed to make plugin executable: %v", err)
+	}
+	return path
+}
+
+func TestPlugins_ExamplePluginReportsNamespacedIssues(t *testing.T) {
+	tmpDir := t.TempDir()
+	createTestFile(t, tmpDir, "app.py", "x = 1  # FIXME\ny = 2\n")
+	createTestFile(t, tmpDir, "app.rb", "x = 1 # FIXME\n")
+
+	example, err := filepath.Abs(filepath.Join("testdata", "plugins", "no-fixme.sh"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	analyzer := NewAnalyzer(tmpDir, LogQuiet)
+	analyzer.SetPlugins([]Plugin{{Name: "acme", Command: example, Extensions: []string{"py"}}})
+	report := NewReport()
+	report.ChangedFiles = []string{"app.py", "app.rb"}
+
+	analyzer.runPlugins(report, true)
+
+	if len(report.Issues) != 1 {
+		t.Fatalf("Expected 1 plugin issue, got %d: %+v", len(report.Issues), report.Issues)
+	}
+	issue := report.Issues[0]
+	if issue.RuleID != "acme/fixme" || issue.File != "app.py" || issue.Line != 1 || issue.Severity != "medium" {
+		t.Errorf("Unexpected plugin issue: %+v", issue)
+	}
+}
+
+func TestPlugins_DisabledByRuleID(t *testing.T) {
+	tmpDir := t.TempDir()
+	createTestFile(t, tmpDir, "app.py", "x = 1  # FIXME\n")
+	plugin := createTestPlugin(t, tmpDir, "plugin.sh", `
+case "$(cat)" in *handshake*) echo '{"protocol_version":1}'; exit 0;; esac
+echo '{"rule":"fixme","severity":"low","message":"FIXME left in code","line":1}'
+`)
+
+	analyzer := NewAnalyzer(tmpDir, LogQuiet)
+	analyzer.SetPlugins([]Plugin{{Name: "acme", Command: plugin}})
+	analyzer.SetDisabledRules([]string{"acme/fixme"})
+	report := NewReport()
+	report.ChangedFiles = []string{"app.py"}
+
+	analyzer.runPlugins(report, true)
+	analyzer.applyDisabledRules(report)
+
+	if len(report.Issues) != 0 {
+		t.Errorf("Expected disabled plugin rule to be dropped, got %+v", report.Issues)
+	}
+}
+
+func TestPlugins_NonZeroExitDiscardsOutput(t *testing.T) {
+	tmpDir := t.TempDir()
+	createTestFile(t, tmpDir, "app.py", "x = 1\n")
+	plugin := createTestPlugin(t, tmpDir, "plugin.sh", `
+case "$(cat)" in *handshake*) echo '{"protocol_version":1}'; exit 0;; esac
+echo '{"rule":"r","severity":"low","message":"m","line":1}'
+echo "boom" >&2
+exit 3
+`)
+
+	analyzer := NewAnalyzer(tmpDir, LogQuiet)
+	_, err := analyzer.invokePlugin(Plugin{Name: "acme", Command: plugin}, pluginRequest{Type: "analyze", File: "app.py"})
+	if err == nil || !strings.Contains(err.Error(), "status 3") || !strings.Contains(err.Error(), "boom") {
+		t.Errorf("Expected exit status and stderr in error, got %v", err)
+	}
+}
+
+func TestPlugins_Timeout(t *testing.T) {
+	tmpDir := t.TempDir()
+	plugin := createTestPlugin(t, tmpDir, "plugin.sh", "exec sleep 5\n")
+
+	analyzer := NewAnalyzer(tmpDir, LogQuiet)
+	_, err := analyzer.invokePlugin(Plugin{Name: "slow", Command: plugin, Timeout: 100 * time.Millisecond}, pluginRequest{Type: "analyze"})
+	if err == nil || !strings.Contains(err.Error(), "timed out") {
+		t.Errorf("Expected timeout error, got %v", err)
+	}
+}
+
+func TestPlugins_HandshakeVersionMismatch(t *testing.T) {
+	tmpDir := t.TempDir()
+	plugin := createTestPlugin(t, tmpDir, "plugin.sh", `cat >/dev/null; echo '{"protocol_version":99}'`)
+
+	analyzer := NewAnalyzer(tmpDir, LogQuiet)
+	if err := analyzer.pluginHandshake(Plugin{Name: "future", Command: plugin}); err == nil {
+		t.Error("Expected handshake to reject an unsupported protocol version")
+	}
+}
+
+func TestPlugins_InvalidIssueRejected(t *testing.T) {
+	tmpDir := t.TempDir()
+	plugin := createTestPlugin(t, tmpDir, "plugin.sh", `cat >/dev/null; echo '{"rule":"r","severity":"urgent","message":"m"}'`)
+
+	analyzer := NewAnalyzer(tmpDir, LogQuiet)
+	if _, err := analyzer.invokePlugin(Plugin{Name: "acme", Command: plugin}, pluginRequest{Type: "analyze"}); err == nil {
+		t.Error("Expected an unknown severity to be rejected")
+	}
+}
+
+func TestPlugins_DiffWithoutAddedLinesNotSent(t *testing.T) {
+	dir := newLocalRepo(t)
+	// The feature branch only deletes base.py's line
+	commitFile(t, dir, "base.py", "")
+	plugin := createTestPlugin(t, t.TempDir(), "plugin.sh", `
+case "$(cat)" in *handshake*) echo '{"protocol_version":1}'; exit 0;; esac
+echo '{"rule":"whole-file","severity":"low","message":"checked the whole file","line":1}'
+`)
+
+	analyzer := NewAnalyzer(dir, LogQuiet)
+	analyzer.targetBranch = "main"
+	analyzer.SetPlugins([]Plugin{{Name: "acme", Command: plugin}})
+	report := NewReport()
+	report.ChangedFiles = []string{"base.py", "feature.py"}
+
+	analyzer.runPlugins(report, false)
+
+	if len(report.Issues) != 1 || report.Issues[0].File != "feature.py" {
+		t.Errorf("Expected only feature.py to be sent to the plugin, got %+v", report.Issues)
+	}
+}
+
+func TestLineRanges(t *testing.T) {
+	lines := []changedLine{{1, "a"}, {2, "b"}, {5, "c"}, {7, "d"}, {8, "e"}}
+
+	got := lineRanges(lines)
+	want := []LineRange{{1, 2}, {5, 5}, {7, 8}}
+	if len(got) != len(want) {
+		t.Fatalf("lineRanges() = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("lineRanges()[%d] = %v, want %v", i, got[i], want[i])
+		}
+	}
+}
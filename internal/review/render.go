@@ -0,0 +1,87 @@
+package review
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Renderer writes a report to w in a particular output format
+type Renderer func(w io.Writer, report *Report) error
+
+var (
+	renderersMu sync.RWMutex
+	renderers   = map[string]Renderer{}
+)
+
+func init() {
+	RegisterRenderer("text", func(w io.Writer, r *Report) error { return r.WriteText(w) })
+	RegisterRenderer("json", func(w io.Writer, r *Report) error { return r.OutputJSON(w) })
+	RegisterRenderer("json-compact", func(w io.Writer, r *Report) error { return r.OutputJSONCompact(w) })
+	RegisterRenderer("oneline", func(w io.Writer, r *Report) error { return r.WriteOneline(w) })
+	RegisterRenderer("codeclimate", func(w io.Writer, r *Report) error { return r.OutputCodeClimate(w) })
+	RegisterRenderer("junit", func(w io.Writer, r *Report) error { return r.OutputJUnit(w) })
+	RegisterRenderer("csv", func(w io.Writer, r *Report) error { return r.OutputCSV(w) })
+	RegisterRenderer("checkstyle", func(w io.Writer, r *Report) error { return r.OutputCheckstyle(w) })
+}
+
+// RegisterRenderer makes a renderer available under the given format name,
+// replacing any renderer previously registered with that name
+func RegisterRenderer(name string, renderer Renderer) {
+	renderersMu.Lock()
+	defer renderersMu.Unlock()
+	renderers[strings.ToLower(name)] = renderer
+}
+
+// LookupRenderer returns the renderer registered for a format name
+func LookupRenderer(name string) (Renderer, bool) {
+	renderersMu.RLock()
+	defer renderersMu.RUnlock()
+	renderer, ok := renderers[strings.ToLower(name)]
+	return renderer, ok
+}
+
+// RendererNames returns the registered format names in sorted order
+func RendererNames() []string {
+	renderersMu.RLock()
+	defer renderersMu.RUnlock()
+	names := make([]string, 0, len(renderers))
+	for name := range renderers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Render writes the report using the renderer registered for format
+func (r *Report) Render(w io.Writer, format string) error {
+	renderer, ok := LookupRenderer(format)
+	if !ok {
+		return fmt.Errorf("unknown format %q (available: %s)", format, strings.Join(RendererNames(), ", "))
+	}
+	return renderer(w, r)
+}
+
+// WriteOneline writes one "file:line: [severity] message" line per issue, the
+// format editors and grep-style tooling parse. Issues without a line number
+// are written as "file: [severity] message".
+func (r *Report) WriteOneline(w io.Writer) error {
+	for _, issue := range r.Issues {
+		file := filepath.ToSlash(filepath.Clean(issue.File))
+		message := strings.Join(strings.Fields(issue.Message), " ")
+
+		var err error
+		if issue.Line > 0 {
+			_, err = fmt.Fprintf(w, "%s:%d: [%s] %s\n", file, issue.Line, issue.Severity, message)
+		} else {
+			_, err = fmt.Fprintf(w, "%s: [%s] %s\n", file, issue.Severity, message)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,423 @@
+package review
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"testing"
+)
+
+func TestWriteOneline_Golden(t *testing.T) {
+	report, err := LoadReport(filepath.Join("testdata", "render", "report.json"))
+	if err != nil {
+		t.Fatalf("Failed to load fixture: %v", err)
+	}
+	want, err := os.ReadFile(filepath.Join("testdata", "render", "oneline.golden"))
+	if err != nil {
+		t.Fatalf("Failed to read golden file: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := report.Render(&buf, "oneline"); err != nil {
+		t.Fatalf("Render returned error: %v", err)
+	}
+	if buf.String() != string(want) {
+		t.Errorf("oneline output mismatch\ngot:\n%s\nwant:\n%s", buf.String(), want)
+	}
+}
+
+func TestWriteOneline_NoIssues(t *testing.T) {
+	var buf bytes.Buffer
+	if err := NewReport().WriteOneline(&buf); err != nil {
+		t.Fatalf("WriteOneline returned error: %v", err)
+	}
+	if buf.Len() != 0 {
+		t.Errorf("Expected no output for an empty report, got %q", buf.String())
+	}
+}
+
+func TestOutputJSONCompact(t *testing.T) {
+	report, err := LoadReport(filepath.Join("testdata", "render", "report.json"))
+	if err != nil {
+		t.Fatalf("Failed to load fixture: %v", err)
+	}
+
+	var pretty, compact bytes.Buffer
+	if err := report.Render(&pretty, "json"); err != nil {
+		t.Fatalf("Render json returned error: %v", err)
+	}
+	if err := report.Render(&compact, "json-compact"); err != nil {
+		t.Fatalf("Render json-compact returned error: %v", err)
+	}
+
+	if compact.Len() >= pretty.Len() {
+		t.Errorf("Expected compact output to be smaller, got %d bytes vs %d pretty", compact.Len(), pretty.Len())
+	}
+	if lines := bytes.Count(compact.Bytes(), []byte("\n")); lines != 1 {
+		t.Errorf("Expected compact output on a single line, got %d lines", lines)
+	}
+
+	var fromPretty, fromCompact any
+	if err := json.Unmarshal(pretty.Bytes(), &fromPretty); err != nil {
+		t.Fatalf("Pretty output is not valid JSON: %v", err)
+	}
+	if err := json.Unmarshal(compact.Bytes(), &fromCompact); err != nil {
+		t.Fatalf("Compact output is not valid JSON: %v", err)
+	}
+	if !reflect.DeepEqual(fromPretty, fromCompact) {
+		t.Error("Expected pretty and compact output to decode to the same structure")
+	}
+}
+
+func TestOutputJSON_Confidence(t *testing.T) {
+	report := NewReport()
+	report.AddIssue(Issue{Type: "quality", Severity: "medium", Message: "Force unwrap", File: "lib/main.dart", RuleID: "force-unwrap"})
+	report.AddIssue(Issue{Type: "security", Severity: "high", Message: "SQL injection", File: "app.py", RuleID: "sql-injection"})
+	report.AddIssue(Issue{Type: "security", Severity: "critical", Message: "AWS credentials in code", File: "config.py", RuleID: "aws-credentials"})
+	// Confidences set by plugins are kept, unknown values are replaced
+	report.AddIssue(Issue{Type: "quality", Severity: "low", Message: "Plugin finding", File: "app.py", RuleID: "lint/guess", Confidence: ConfidenceLow})
+	report.AddIssue(Issue{Type: "quality", Severity: "low", Message: "Plugin finding", File: "app.py", RuleID: "lint/sure", Confidence: "certain"})
+
+	var buf bytes.Buffer
+	if err := report.OutputJSON(&buf); err != nil {
+		t.Fatalf("OutputJSON returned error: %v", err)
+	}
+	var decoded struct {
+		Issues []struct {
+			RuleID     string `json:"rule_id"`
+			Confidence string `json:"confidence"`
+		} `json:"issues"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatalf("Output is not valid JSON: %v", err)
+	}
+
+	want := map[string]string{
+		"force-unwrap":    ConfidenceLow,
+		"sql-injection":   ConfidenceMedium,
+		"aws-credentials": ConfidenceHigh,
+		"lint/guess":      ConfidenceLow,
+		"lint/sure":       ConfidenceHigh,
+	}
+	if len(decoded.Issues) != len(want) {
+		t.Fatalf("Expected %d issues, got %d", len(want), len(decoded.Issues))
+	}
+	for _, issue := range decoded.Issues {
+		if issue.Confidence != want[issue.RuleID] {
+			t.Errorf("%s serialized with confidence %q, want %q", issue.RuleID, issue.Confidence, want[issue.RuleID])
+		}
+	}
+}
+
+func TestOutputCodeClimate(t *testing.T) {
+	report, err := LoadReport(filepath.Join("testdata", "render", "report.json"))
+	if err != nil {
+		t.Fatalf("Failed to load fixture: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := report.Render(&buf, "codeclimate"); err != nil {
+		t.Fatalf("Render returned error: %v", err)
+	}
+
+	var entries []struct {
+		Description string `json:"description"`
+		Fingerprint string `json:"fingerprint"`
+		Severity    string `json:"severity"`
+		Location    struct {
+			Path  string `json:"path"`
+			Lines struct {
+				Begin int `json:"begin"`
+			} `json:"lines"`
+		} `json:"location"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &entries); err != nil {
+		t.Fatalf("Output is not a Code Climate array: %v\n%s", err, buf.String())
+	}
+	if len(entries) != len(report.Issues) {
+		t.Fatalf("Expected %d entries, got %d", len(report.Issues), len(entries))
+	}
+
+	first := entries[0]
+	if first.Description != report.Issues[0].Message || first.Severity != "info" ||
+		first.Location.Path != "src/api.py" || first.Location.Lines.Begin != 6 {
+		t.Errorf("Unexpected first entry: %+v", first)
+	}
+	if entries[1].Severity != "major" || entries[1].Location.Lines.Begin != 1 {
+		t.Errorf("Expected a high issue without a line to be major at line 1, got %+v", entries[1])
+	}
+
+	// The fingerprint is derived from the issues alone, so it survives a save and reload
+	for i, entry := range entries {
+		if entry.Fingerprint != codeClimateFingerprint(report.Issues[i], 0) || len(entry.Fingerprint) != 64 {
+			t.Errorf("Entry %d has fingerprint %q, want the hashed issue fingerprint", i, entry.Fingerprint)
+		}
+	}
+	saved := filepath.Join(t.TempDir(), "report.json")
+	if err := report.SaveToFile(saved); err != nil {
+		t.Fatalf("SaveToFile returned error: %v", err)
+	}
+	reloaded, err := LoadReport(saved)
+	if err != nil {
+		t.Fatalf("Failed to reload report: %v", err)
+	}
+	var again bytes.Buffer
+	if err := reloaded.OutputCodeClimate(&again); err != nil {
+		t.Fatalf("OutputCodeClimate returned error: %v", err)
+	}
+	if again.String() != buf.String() {
+		t.Errorf("Expected identical output after a round trip\ngot:\n%s\nwant:\n%s", again.String(), buf.String())
+	}
+}
+
+func TestOutputCodeClimate_RepeatedFindings(t *testing.T) {
+	report := NewReport()
+	for _, line := range []int{2, 5, 9} {
+		report.AddIssue(Issue{Type: "quality", Severity: "low", Message: "print() statement found", File: "app.py", Line: line, RuleID: "print-statement"})
+	}
+
+	fingerprints := func(report *Report) []string {
+		var buf bytes.Buffer
+		if err := report.OutputCodeClimate(&buf); err != nil {
+			t.Fatalf("OutputCodeClimate returned error: %v", err)
+		}
+		var entries []struct {
+			Fingerprint string `json:"fingerprint"`
+		}
+		if err := json.Unmarshal(buf.Bytes(), &entries); err != nil {
+			t.Fatalf("Output is not a Code Climate array: %v", err)
+		}
+		fingerprints := []string{}
+		for _, entry := range entries {
+			fingerprints = append(fingerprints, entry.Fingerprint)
+		}
+		return fingerprints
+	}
+
+	got := fingerprints(report)
+	if len(slices.Compact(slices.Sorted(slices.Values(got)))) != 3 {
+		t.Fatalf("Expected repeated findings to get distinct fingerprints, got %v", got)
+	}
+
+	// Moving the findings to other lines keeps their fingerprints
+	for i := range report.Issues {
+		report.Issues[i].Line += 10
+	}
+	if moved := fingerprints(report); !slices.Equal(moved, got) {
+		t.Errorf("Expected fingerprints to survive moved lines, got %v, want %v", moved, got)
+	}
+}
+
+func TestOutputCodeClimate_NoIssues(t *testing.T) {
+	var buf bytes.Buffer
+	if err := NewReport().OutputCodeClimate(&buf); err != nil {
+		t.Fatalf("OutputCodeClimate returned error: %v", err)
+	}
+	if buf.String() != "[]\n" {
+		t.Errorf("Expected an empty array, got %q", buf.String())
+	}
+}
+
+func TestOutputJUnit(t *testing.T) {
+	report, err := LoadReport(filepath.Join("testdata", "render", "report.json"))
+	if err != nil {
+		t.Fatalf("Failed to load fixture: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := report.Render(&buf, "junit"); err != nil {
+		t.Fatalf("Render returned error: %v", err)
+	}
+
+	var suites struct {
+		Tests    int `xml:"tests,attr"`
+		Failures int `xml:"failures,attr"`
+		Suites   []struct {
+			Name      string `xml:"name,attr"`
+			Tests     int    `xml:"tests,attr"`
+			Failures  int    `xml:"failures,attr"`
+			TestCases []struct {
+				Name      string `xml:"name,attr"`
+				ClassName string `xml:"classname,attr"`
+				Failure   struct {
+					Message  string `xml:"message,attr"`
+					Type     string `xml:"type,attr"`
+					Location string `xml:",chardata"`
+				} `xml:"failure"`
+			} `xml:"testcase"`
+		} `xml:"testsuite"`
+	}
+	if err := xml.Unmarshal(buf.Bytes(), &suites); err != nil {
+		t.Fatalf("Output is not JUnit XML: %v\n%s", err, buf.String())
+	}
+	if suites.Tests != 3 || suites.Failures != 3 || len(suites.Suites) != 2 {
+		t.Fatalf("Expected 3 failures in 2 suites, got %d/%d in %d suites", suites.Tests, suites.Failures, len(suites.Suites))
+	}
+
+	quality, security := suites.Suites[0], suites.Suites[1]
+	if quality.Name != "quality" || quality.Tests != 2 || quality.Failures != 2 || len(quality.TestCases) != 2 {
+		t.Errorf("Expected a quality suite with 2 failures, got %+v", quality)
+	}
+	if security.Name != "security" || security.Tests != 1 || len(security.TestCases) != 1 {
+		t.Fatalf("Expected a security suite with 1 failure, got %+v", security)
+	}
+
+	first := quality.TestCases[0]
+	if first.ClassName != "src/api.py" || first.Failure.Location != "src/api.py:6" ||
+		first.Failure.Type != "low" || first.Failure.Message != report.Issues[0].Message {
+		t.Errorf("Unexpected first test case: %+v", first)
+	}
+	if got := quality.TestCases[1].Failure.Location; got != "lib/util.js:12" {
+		t.Errorf("Expected a cleaned file:line location, got %q", got)
+	}
+	if got := security.TestCases[0].Failure; got.Location != "config/.env" || got.Type != "high" {
+		t.Errorf("Expected a high failure located by file alone, got %+v", got)
+	}
+}
+
+func TestReportTemplate_Golden(t *testing.T) {
+	report, err := LoadReport(filepath.Join("testdata", "render", "report.json"))
+	if err != nil {
+		t.Fatalf("Failed to load fixture: %v", err)
+	}
+	tmpl, err := ParseReportTemplate(filepath.Join("testdata", "render", "custom.tmpl"))
+	if err != nil {
+		t.Fatalf("ParseReportTemplate returned error: %v", err)
+	}
+	want, err := os.ReadFile(filepath.Join("testdata", "render", "custom.golden"))
+	if err != nil {
+		t.Fatalf("Failed to read golden file: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, report); err != nil {
+		t.Fatalf("Execute returned error: %v", err)
+	}
+	if buf.String() != string(want) {
+		t.Errorf("template output mismatch\ngot:\n%s\nwant:\n%s", buf.String(), want)
+	}
+}
+
+func TestParseReportTemplate_Invalid(t *testing.T) {
+	tests := map[string]string{
+		"syntax error":  "{{range .Issues}}",
+		"unknown field": "{{.Isues}}",
+		"unknown group": `{{range groupBy "folder" .Issues}}{{end}}`,
+		"unknown level": `{{atLeast "urgent" .Issues}}`,
+		"unknown func":  `{{shout .Summary}}`,
+	}
+
+	for name, content := range tests {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "report.tmpl")
+			if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := ParseReportTemplate(path); err == nil {
+				t.Errorf("Expected %q to be rejected", content)
+			}
+		})
+	}
+}
+
+func TestOutputCSV(t *testing.T) {
+	report, err := LoadReport(filepath.Join("testdata", "render", "report.json"))
+	if err != nil {
+		t.Fatalf("Failed to load fixture: %v", err)
+	}
+	report.Issues[0].RuleID = "print-statement"
+	report.Issues[1].Message = `Hardcoded secret detected, "api_key" in .env`
+
+	var buf bytes.Buffer
+	if err := report.Render(&buf, "csv"); err != nil {
+		t.Fatalf("Render returned error: %v", err)
+	}
+
+	rows, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("Output is not valid CSV: %v", err)
+	}
+	if len(rows) != len(report.Issues)+1 {
+		t.Fatalf("Expected a header and %d rows, got %d rows", len(report.Issues), len(rows))
+	}
+	if want := []string{"severity", "type", "rule", "file", "line", "message"}; !slices.Equal(rows[0], want) {
+		t.Errorf("Expected header %v, got %v", want, rows[0])
+	}
+	if want := []string{"low", "quality", "print-statement", "src/api.py", "6", "Print statement found - consider using logging"}; !slices.Equal(rows[1], want) {
+		t.Errorf("Expected first row %v, got %v", want, rows[1])
+	}
+	if got := rows[2]; got[4] != "" || got[5] != report.Issues[1].Message {
+		t.Errorf("Expected an empty line and the message with a comma and quotes to round-trip, got %q", got)
+	}
+	if got := rows[3][5]; got != "Line too long\n(142 characters)" {
+		t.Errorf("Expected a multi-line message to round-trip, got %q", got)
+	}
+}
+
+func TestOutputCheckstyle(t *testing.T) {
+	report, err := LoadReport(filepath.Join("testdata", "render", "report.json"))
+	if err != nil {
+		t.Fatalf("Failed to load fixture: %v", err)
+	}
+	report.Issues[0].RuleID = "print-statement"
+	report.Issues = append(report.Issues, Issue{
+		Type:     "security",
+		Severity: "medium",
+		Message:  `Response built from <input name="q"> & echoed`,
+		File:     "src/api.py",
+		Line:     9,
+		RuleID:   "xss",
+	})
+
+	var buf bytes.Buffer
+	if err := report.Render(&buf, "checkstyle"); err != nil {
+		t.Fatalf("Render returned error: %v", err)
+	}
+	if bytes.Contains(buf.Bytes(), []byte(`<input name="q">`)) {
+		t.Errorf("Expected markup in messages to be escaped:\n%s", buf.String())
+	}
+
+	type checkstyleError struct {
+		Line     int    `xml:"line,attr"`
+		Severity string `xml:"severity,attr"`
+		Message  string `xml:"message,attr"`
+		Source   string `xml:"source,attr"`
+	}
+	var result struct {
+		Files []struct {
+			Name   string            `xml:"name,attr"`
+			Errors []checkstyleError `xml:"error"`
+		} `xml:"file"`
+	}
+	if err := xml.Unmarshal(buf.Bytes(), &result); err != nil {
+		t.Fatalf("Output is not Checkstyle XML: %v\n%s", err, buf.String())
+	}
+
+	var names []string
+	for _, file := range result.Files {
+		names = append(names, file.Name)
+	}
+	if want := []string{"config/.env", "lib/util.js", "src/api.py"}; !slices.Equal(names, want) {
+		t.Fatalf("Expected one file element per cleaned path in order %v, got %v", want, names)
+	}
+
+	env, util, api := result.Files[0].Errors, result.Files[1].Errors, result.Files[2].Errors
+	if want := []checkstyleError{{0, "error", "Hardcoded secret detected", "security"}}; !slices.Equal(env, want) {
+		t.Errorf("Expected high to map to error with the type as source, got %+v", env)
+	}
+	if len(util) != 1 || util[0].Severity != "info" || util[0].Line != 12 || util[0].Message != "Line too long\n(142 characters)" {
+		t.Errorf("Expected info to map to info, got %+v", util)
+	}
+	want := []checkstyleError{
+		{6, "info", "Print statement found - consider using logging", "print-statement"},
+		{9, "warning", `Response built from <input name="q"> & echoed`, "xss"},
+	}
+	if !slices.Equal(api, want) {
+		t.Errorf("Expected both src/api.py issues grouped under one file, got %+v", api)
+	}
+}
@@ -5,7 +5,9 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"slices"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/fatih/color"
@@ -17,21 +19,98 @@ type Issue struct {
 	Message  string `json:"message"`
 	File     string `json:"file"`
 	Line     int    `json:"line,omitempty"`
+	RuleID   string `json:"rule_id,omitempty"`
+	// Category is the check category that produced the issue, see Categories
+	Category string `json:"category,omitempty"`
+	// Effort is the estimated work to fix the issue, see EffortFor
+	Effort string `json:"effort,omitempty"`
+	// Confidence is how likely the issue is to be a real problem (high, medium
+	// or low), see ConfidenceFor
+	Confidence string `json:"confidence,omitempty"`
+	// CWE is the weakness a security issue detects, e.g. "CWE-89", see CWEFor
+	CWE string `json:"cwe,omitempty"`
 }
 
+// IssueTypes lists the issue categories reported by the analyzers
+var IssueTypes = []string{"security", "quality", "performance", "error_handling", "rails_structure", "dependency"}
+
 type Report struct {
-	Timestamp    time.Time `json:"timestamp"`
-	ChangedFiles []string  `json:"changed_files"`
-	Issues       []Issue   `json:"issues"`
-	Summary      Summary   `json:"summary"`
+	// mu guards the report while analyzers add issues concurrently
+	mu sync.Mutex
+
+	Timestamp time.Time `json:"timestamp"`
+	// Categories lists the check categories that ran; empty in reports
+	// written before categories were recorded, which ran all of them
+	Categories []string `json:"categories,omitempty"`
+	// Commit is the HEAD commit that was reviewed; nil outside a repository
+	// or before the first commit
+	Commit       *Commit  `json:"commit,omitempty"`
+	ChangedFiles []string `json:"changed_files"`
+	// AnalyzedFiles lists the changed files at least one check read, and
+	// SkippedFiles those no check read with the reason, so coverage is visible
+	AnalyzedFiles []string      `json:"analyzed_files,omitempty"`
+	SkippedFiles  []SkippedFile `json:"skipped_files,omitempty"`
+	// CleanFiles lists the analyzed files without issues, once ShowCleanFiles is called
+	CleanFiles []string `json:"clean_files,omitempty"`
+	Issues     []Issue  `json:"issues"`
+	Summary    Summary  `json:"summary"`
+
+	// showClean keeps CleanFiles up to date as issues are added and filtered
+	showClean bool
+}
+
+// Commit identifies the commit a report was generated for
+type Commit struct {
+	SHA         string `json:"sha"`
+	AuthorName  string `json:"author_name"`
+	AuthorEmail string `json:"author_email"`
+	Subject     string `json:"subject"`
+}
+
+// ShortSHA returns the abbreviated commit hash used in headers
+func (c *Commit) ShortSHA() string {
+	if len(c.SHA) > 7 {
+		return c.SHA[:7]
+	}
+	return c.SHA
 }
 
 type Summary struct {
-	TotalFiles     int `json:"total_files"`
-	TotalIssues    int `json:"total_issues"`
-	HighSeverity   int `json:"high_severity"`
-	MediumSeverity int `json:"medium_severity"`
-	LowSeverity    int `json:"low_severity"`
+	TotalFiles       int `json:"total_files"`
+	TotalIssues      int `json:"total_issues"`
+	CriticalSeverity int `json:"critical_severity"`
+	HighSeverity     int `json:"high_severity"`
+	MediumSeverity   int `json:"medium_severity"`
+	LowSeverity      int `json:"low_severity"`
+	InfoSeverity     int `json:"info_severity"`
+	// Effort rolls up the estimated remediation effort of the issues
+	Effort EffortSummary `json:"effort"`
+	// ByFile and ByRule count the issues in each file and of each rule ID, or
+	// of each type for issues without one
+	ByFile map[string]int `json:"by_file,omitempty"`
+	ByRule map[string]int `json:"by_rule,omitempty"`
+}
+
+// FileCount is the number of issues found in a file
+type FileCount struct {
+	File   string `json:"file"`
+	Issues int    `json:"issues"`
+}
+
+// TopFiles returns up to n files with the most issues, most first and ties in
+// file order
+func (s Summary) TopFiles(n int) []FileCount {
+	counts := make([]FileCount, 0, len(s.ByFile))
+	for file, issues := range s.ByFile {
+		counts = append(counts, FileCount{File: file, Issues: issues})
+	}
+	slices.SortFunc(counts, func(a, b FileCount) int {
+		if a.Issues != b.Issues {
+			return b.Issues - a.Issues
+		}
+		return strings.Compare(a.File, b.File)
+	})
+	return counts[:min(n, len(counts))]
 }
 
 func NewReport() *Report {
@@ -42,63 +121,211 @@ func NewReport() *Report {
 	}
 }
 
+// AddIssue records an issue. Issues with an unknown severity are dropped rather
+// than added without being counted in the summary; use Add to see the error.
 func (r *Report) AddIssue(issue Issue) {
+	_ = r.Add(issue)
+}
+
+// Add records an issue, rejecting it when its severity is unknown
+func (r *Report) Add(issue Issue) error {
+	if err := checkSeverity(issue.Severity); err != nil {
+		return fmt.Errorf("issue %q in %s: %w", issue.Message, issue.File, err)
+	}
+	if !ValidEffort(issue.Effort) {
+		issue.Effort = EffortFor(issue)
+	}
+	if !ValidConfidence(issue.Confidence) {
+		issue.Confidence = ConfidenceFor(issue)
+	}
+	if issue.CWE == "" {
+		issue.CWE = CWEFor(issue)
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
 	r.Issues = append(r.Issues, issue)
 	r.updateSummary()
+	return nil
+}
+
+// FilterIssues keeps only the issues for which keep returns true
+func (r *Report) FilterIssues(keep func(Issue) bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	filtered := []Issue{}
+	for _, issue := range r.Issues {
+		if keep(issue) {
+			filtered = append(filtered, issue)
+		}
+	}
+	r.Issues = filtered
+	r.updateSummary()
+}
+
+// UpdateIssues calls update on every issue, then recounts the summary
+func (r *Report) UpdateIssues(update func(*Issue)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i := range r.Issues {
+		update(&r.Issues[i])
+	}
+	r.updateSummary()
+}
+
+// ShowCleanFiles lists the analyzed files without issues in CleanFiles, and
+// keeps the list current as issues are added or filtered out
+func (r *Report) ShowCleanFiles() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.showClean = true
+	r.updateSummary()
 }
 
+// updateSummary recounts the summary; the caller must hold r.mu
 func (r *Report) updateSummary() {
 	r.Summary.TotalFiles = len(r.ChangedFiles)
 	r.Summary.TotalIssues = len(r.Issues)
+	r.Summary.CriticalSeverity = 0
 	r.Summary.HighSeverity = 0
 	r.Summary.MediumSeverity = 0
 	r.Summary.LowSeverity = 0
+	r.Summary.InfoSeverity = 0
+	r.Summary.ByFile = map[string]int{}
+	r.Summary.ByRule = map[string]int{}
 
 	for _, issue := range r.Issues {
+		r.Summary.ByFile[issue.File]++
+		rule := issue.RuleID
+		if rule == "" {
+			rule = issue.Type
+		}
+		r.Summary.ByRule[rule]++
+
 		switch issue.Severity {
-		case "high":
+		case SeverityCritical:
+			r.Summary.CriticalSeverity++
+		case SeverityHigh:
 			r.Summary.HighSeverity++
-		case "medium":
+		case SeverityMedium:
 			r.Summary.MediumSeverity++
-		case "low":
+		case SeverityLow:
 			r.Summary.LowSeverity++
+		case SeverityInfo:
+			r.Summary.InfoSeverity++
+		}
+	}
+	r.Summary.Effort = summarizeEffort(r.Issues)
+
+	if r.showClean {
+		r.CleanFiles = []string{}
+		for _, file := range r.AnalyzedFiles {
+			if r.Summary.ByFile[file] == 0 {
+				r.CleanFiles = append(r.CleanFiles, file)
+			}
 		}
 	}
 }
 
+// PrintReport writes the human-readable report to stdout
 func (r *Report) PrintReport() {
+	r.WriteText(color.Output)
+}
+
+// WriteText writes the human-readable report, colored when w is a terminal
+func (r *Report) WriteText(w io.Writer) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
 	// create separator string
 	equal_separator := strings.Repeat("=", 60)
-	color.Blue("\n" + equal_separator)
-	color.Blue("📋 CODE REVIEW SUMMARY")
-	color.Blue(equal_separator)
-	fmt.Printf("📁 Files changed: %d\n", r.Summary.TotalFiles)
-	fmt.Printf("🚨 Total issues: %d\n", r.Summary.TotalIssues)
-	color.Red("🔴 High severity: %d\n", r.Summary.HighSeverity)
-	color.Yellow("🟡 Medium severity: %d\n", r.Summary.MediumSeverity)
-	color.Green("🟢 Low severity: %d\n", r.Summary.LowSeverity)
+	blue := color.New(color.FgBlue)
+	blue.Fprintln(w, "\n"+equal_separator)
+	blue.Fprintln(w, "📋 CODE REVIEW SUMMARY")
+	blue.Fprintln(w, equal_separator)
+	if r.Commit != nil {
+		fmt.Fprintf(w, "🔖 Commit: %s %s (%s <%s>)\n", r.Commit.ShortSHA(), r.Commit.Subject, r.Commit.AuthorName, r.Commit.AuthorEmail)
+	}
+	fmt.Fprintf(w, "📁 Files changed: %d\n", r.Summary.TotalFiles)
+	fmt.Fprintf(w, "🚨 Total issues: %d\n", r.Summary.TotalIssues)
+	color.New(color.FgMagenta, color.Bold).Fprintf(w, "🟣 Critical severity: %d\n", r.Summary.CriticalSeverity)
+	color.New(color.FgRed).Fprintf(w, "🔴 High severity: %d\n", r.Summary.HighSeverity)
+	color.New(color.FgYellow).Fprintf(w, "🟡 Medium severity: %d\n", r.Summary.MediumSeverity)
+	color.New(color.FgGreen).Fprintf(w, "🟢 Low severity: %d\n", r.Summary.LowSeverity)
+	color.New(color.FgCyan).Fprintf(w, "🔵 Info: %d\n", r.Summary.InfoSeverity)
+	if r.Summary.TotalIssues > 0 {
+		fmt.Fprintf(w, "⏱️  Estimated remediation effort: %s\n", r.Summary.Effort)
+	}
+	if top := r.Summary.TopFiles(5); len(top) > 0 {
+		fmt.Fprintln(w, "🔥 Top offending files:")
+		for _, count := range top {
+			fmt.Fprintf(w, "   %4d  %s\n", count.Issues, count.File)
+		}
+	}
 
 	if len(r.Issues) > 0 {
 		line_separator := strings.Repeat("-", 60)
-		fmt.Println("\n" + line_separator)
-		fmt.Println("ISSUES FOUND:")
+		fmt.Fprintln(w, "\n"+line_separator)
+		fmt.Fprintln(w, "ISSUES FOUND:")
 		for i, issue := range r.Issues {
-			fmt.Printf("%d. [%s] %s\n", i+1, issue.Severity, issue.Message)
-			fmt.Printf("   File: %s", issue.File)
+			fmt.Fprintf(w, "%d. [%s] %s\n", i+1, severityColor(issue.Severity).Sprint(issue.Severity), issue.Message)
+			fmt.Fprintf(w, "   File: %s", issue.File)
 			if issue.Line > 0 {
-				fmt.Printf(" (line %d)", issue.Line)
+				fmt.Fprintf(w, " (line %d)", issue.Line)
 			}
-			fmt.Println()
+			fmt.Fprintln(w)
+		}
+	}
+
+	if len(r.CleanFiles) > 0 {
+		fmt.Fprintln(w, "\n"+strings.Repeat("-", 60))
+		fmt.Fprintln(w, "FILES WITHOUT ISSUES:")
+		for _, file := range r.CleanFiles {
+			fmt.Fprintf(w, "   ✅ %s\n", file)
+		}
+	}
+
+	if len(r.SkippedFiles) > 0 {
+		fmt.Fprintln(w, "\n"+strings.Repeat("-", 60))
+		fmt.Fprintln(w, "FILES NOT ANALYZED:")
+		for _, skipped := range r.SkippedFiles {
+			fmt.Fprintf(w, "   %s: %s\n", skipped.File, skipped.Reason)
 		}
 	}
+
+	return nil
+}
+
+// severityColor returns the console color used for a severity
+func severityColor(severity string) *color.Color {
+	switch severity {
+	case SeverityCritical:
+		return color.New(color.FgMagenta, color.Bold)
+	case SeverityHigh:
+		return color.New(color.FgRed)
+	case SeverityMedium:
+		return color.New(color.FgYellow)
+	case SeverityLow:
+		return color.New(color.FgGreen)
+	}
+	return color.New(color.FgCyan)
 }
 
 func (r *Report) OutputJSON(w io.Writer) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
 	encoder := json.NewEncoder(w)
 	encoder.SetIndent("", "  ")
 	return encoder.Encode(r)
 }
 
+// OutputJSONCompact writes the report as JSON on a single line, for machines
+// that do not need the indentation OutputJSON adds
+func (r *Report) OutputJSONCompact(w io.Writer) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return json.NewEncoder(w).Encode(r)
+}
+
 func (r *Report) SaveToFile(path string) error {
 	file, err := os.Create(path)
 	if err != nil {
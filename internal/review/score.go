@@ -0,0 +1,61 @@
+package review
+
+import (
+	"fmt"
+	"strings"
+)
+
+// scorePenalties are the points each finding takes off the score, by severity
+var scorePenalties = map[string]int{
+	SeverityCritical: 10,
+	SeverityHigh:     4,
+	SeverityMedium:   2,
+	SeverityLow:      1,
+	SeverityInfo:     0,
+}
+
+// Score rates the report from 100 for no findings down to 0. Each finding
+// costs 10 points if critical, 4 if high, 2 if medium, 1 if low and none if info.
+func (r *Report) Score() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.score()
+}
+
+func (r *Report) score() int {
+	penalty := r.Summary.CriticalSeverity*scorePenalties[SeverityCritical] +
+		r.Summary.HighSeverity*scorePenalties[SeverityHigh] +
+		r.Summary.MediumSeverity*scorePenalties[SeverityMedium] +
+		r.Summary.LowSeverity*scorePenalties[SeverityLow] +
+		r.Summary.InfoSeverity*scorePenalties[SeverityInfo]
+	return max(0, 100-penalty)
+}
+
+// CISummaryLine returns a single line for CI logs to grep, e.g.
+//
+//	AUTOREVIEW_RESULT critical=0 high=2 medium=5 low=10 info=3 total=20 score=72 failed=true
+//
+// The fields always appear in this order. failed reports whether the run
+// failed its severity threshold.
+func (r *Report) CISummaryLine(failed bool) string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	fields := []string{"AUTOREVIEW_RESULT"}
+	counts := map[string]int{
+		SeverityCritical: r.Summary.CriticalSeverity,
+		SeverityHigh:     r.Summary.HighSeverity,
+		SeverityMedium:   r.Summary.MediumSeverity,
+		SeverityLow:      r.Summary.LowSeverity,
+		SeverityInfo:     r.Summary.InfoSeverity,
+	}
+	for _, severity := range Severities {
+		fields = append(fields, fmt.Sprintf("%s=%d", severity, counts[severity]))
+	}
+	fields = append(fields,
+		fmt.Sprintf("total=%d", r.Summary.TotalIssues),
+		fmt.Sprintf("score=%d", r.score()),
+		fmt.Sprintf("failed=%t", failed),
+	)
+	return strings.Join(fields, " ")
+}
@@ -1,14 +1,10 @@
 package review
 
 import (
-	"bufio"
-	"fmt"
-	"os/exec"
 	"path/filepath"
 	"regexp"
+	"strconv"
 	"strings"
-
-	"github.com/fatih/color"
 )
 
 // SecurityPattern defines a pattern to check with exclusions
@@ -18,6 +14,8 @@ type SecurityPattern struct {
 	Exclusions  []*regexp.Regexp
 	Message     string
 	Severity    string
+	// CWE is the weakness the pattern detects, e.g. "CWE-798"
+	CWE         string
 }
 
 // Files to always skip for security scanning
@@ -64,6 +62,7 @@ func GetSecurityPatterns() []SecurityPattern {
 			},
 			Message:  "Potential hardcoded password detected",
 			Severity: "high",
+			CWE:      "CWE-798",
 		},
 		{
 			Name: "hardcoded_api_key",
@@ -81,6 +80,7 @@ func GetSecurityPatterns() []SecurityPattern {
 			},
 			Message:  "Potential hardcoded API key detected",
 			Severity: "high",
+			CWE:      "CWE-798",
 		},
 		{
 			Name: "hardcoded_secret",
@@ -99,6 +99,7 @@ func GetSecurityPatterns() []SecurityPattern {
 			},
 			Message:  "Potential hardcoded secret detected",
 			Severity: "high",
+			CWE:      "CWE-798",
 		},
 		{
 			Name: "private_key",
@@ -110,7 +111,8 @@ func GetSecurityPatterns() []SecurityPattern {
 				regexp.MustCompile(`(?i)\.sample`),
 			},
 			Message:  "Private key detected in code",
-			Severity: "high",
+			Severity: "critical",
+			CWE:      "CWE-321",
 		},
 		{
 			Name: "aws_credentials",
@@ -122,7 +124,8 @@ func GetSecurityPatterns() []SecurityPattern {
 				regexp.MustCompile(`(?i)your.?access.?key`),
 			},
 			Message:  "AWS access key detected",
-			Severity: "high",
+			Severity: "critical",
+			CWE:      "CWE-798",
 		},
 		{
 			Name: "generic_token",
@@ -138,185 +141,284 @@ func GetSecurityPatterns() []SecurityPattern {
 			},
 			Message:  "Potential hardcoded token detected",
 			Severity: "high",
+			CWE:      "CWE-798",
 		},
 	}
 }
 
-// shouldSkipFileForSecurity checks if a file should be skipped for security scanning
-func (a *Analyzer) shouldSkipFileForSecurity(filePath string) bool {
+// ruleCWEs maps the rule IDs of the per-language security checks to the
+// weakness they detect, so findings can be grouped by CWE on dashboards
+var ruleCWEs = map[string]string{
+	// Injection
+	"sql-injection":             "CWE-89",
+	"sql-query-concatenation":   "CWE-89",
+	"command-injection":         "CWE-78",
+	"os-system":                 "CWE-78",
+	"shell-exec":                "CWE-78",
+	"shell-eval":                "CWE-78",
+	"subprocess-shell":          "CWE-78",
+	"child-process":             "CWE-78",
+	"process-exec":              "CWE-78",
+	"eval":                      "CWE-95",
+	"function-constructor":      "CWE-95",
+	"invoke-expression":         "CWE-95",
+	"workflow-script-injection": "CWE-94",
+	"constantize":               "CWE-470",
+	"dangerous-send":            "CWE-470",
+	"xxe":                       "CWE-611",
+	"prototype-pollution":       "CWE-1321",
+	"non-literal-regexp":        "CWE-1333",
+	"regex-from-input":          "CWE-1333",
+	// Cross-site scripting
+	"xss":                          "CWE-79",
+	"inner-html":                   "CWE-79",
+	"document-write":               "CWE-79",
+	"html-safe":                    "CWE-79",
+	"unescaped-html-response":      "CWE-79",
+	"reflected-content-type":       "CWE-79",
+	"v-html":                       "CWE-79",
+	"raw-html":                     "CWE-79",
+	"unescaped-output":             "CWE-79",
+	"script-interpolation":         "CWE-79",
+	"inline-handler-interpolation": "CWE-79",
+	// Deserialization of untrusted data
+	"pickle-load":        "CWE-502",
+	"marshal-load":       "CWE-502",
+	"unsafe-unserialize": "CWE-502",
+	"yaml-load":          "CWE-502",
+	"unsafe-deserialization": "CWE-502",
+	// Credentials and secrets
+	"hardcoded-password":              "CWE-798",
+	"hardcoded-api-key":               "CWE-798",
+	"hardcoded-secret":                "CWE-798",
+	"hardcoded-credential":            "CWE-798",
+	"hardcoded-jwt-secret":            "CWE-798",
+	"generic-token":                   "CWE-798",
+	"aws-credentials":                 "CWE-798",
+	"terraform-hardcoded-credentials": "CWE-798",
+	"terraform-plaintext-secret":      "CWE-798",
+	"private-key":                     "CWE-321",
+	"secret-in-comment":               "CWE-615",
+	"workflow-secret-echo":            "CWE-532",
+	"notebook-output-credential":      "CWE-538",
+	"hardcoded-salt":                  "CWE-760",
+	"broker-default-credentials":      "CWE-1392",
+	"cmdline-secret":                  "CWE-214",
+	// Cryptography and transport
+	"weak-hash":                 "CWE-327",
+	"weak-crypto":               "CWE-327",
+	"weak-password-hash":        "CWE-916",
+	"insecure-random":           "CWE-338",
+	"insecure-jwt":              "CWE-347",
+	"ssl-verification-disabled": "CWE-295",
+	"trust-all-certificates":    "CWE-295",
+	"certificate-callback":      "CWE-295",
+	"insecure-http":             "CWE-319",
+	"insecure-transport":        "CWE-319",
+	"broker-plaintext":          "CWE-319",
+	"broker-no-tls":             "CWE-319",
+	// Access control, files and resources
+	"path-traversal":                "CWE-22",
+	"upload-client-filename":        "CWE-22",
+	"file-inclusion":                "CWE-98",
+	"upload-no-allowlist":           "CWE-434",
+	"upload-executed":               "CWE-434",
+	"open-redirect":                 "CWE-601",
+	"any-http-method":               "CWE-650",
+	"csrf-disabled":                 "CWE-352",
+	"mass-assignment":               "CWE-915",
+	"permit-all":                    "CWE-915",
+	"unscoped-find":                 "CWE-639",
+	"auth-no-rate-limit":            "CWE-307",
+	"broker-no-auth":                "CWE-306",
+	"logging-disabled":              "CWE-778",
+	"dotenv-committed":              "CWE-540",
+	"config-secret":                 "CWE-798",
+	"config-debug-enabled":          "CWE-489",
+	"graphql-sensitive-field":       "CWE-200",
+	"sol-tx-origin":                 "CWE-477",
+	"sol-unchecked-call":            "CWE-252",
+	"sol-weak-randomness":           "CWE-330",
+	"sol-selfdestruct":              "CWE-284",
+	"sol-unbounded-loop":            "CWE-400",
+	"sol-reentrancy":                "CWE-841",
+	"unbounded-body-read":           "CWE-400",
+	"unbounded-read-loop":           "CWE-400",
+	"atom-exhaustion":               "CWE-400",
+	"unsafe-string-function":        "CWE-120",
+	"terraform-open-ingress":        "CWE-284",
+	"terraform-public-bucket":       "CWE-732",
+	"terraform-unencrypted-storage": "CWE-311",
+	// Supply chain
+	"dependency-confusion":        "CWE-427",
+	"curl-pipe-shell":             "CWE-494",
+	"download-execute":            "CWE-494",
+	"unpinned-dependency":         "CWE-829",
+	"dependency-wildcard-version": "CWE-829",
+	"dependency-unpinned":         "CWE-829",
+	"dependency-insecure-source":  "CWE-494",
+	"workflow-unpinned-action":    "CWE-829",
+}
+
+// CWEFor returns the CWE identifier of an issue's rule, or "" for rules that
+// do not map to a weakness
+func CWEFor(issue Issue) string {
+	return ruleCWEs[issue.RuleID]
+}
+
+// securitySkipReason explains why a file is exempt from security scanning, or returns "" if it is not
+func securitySkipReason(filePath string) string {
 	baseName := filepath.Base(filePath)
 	
 	// Check exact matches
 	for _, ignore := range securityIgnoreFiles {
 		if baseName == ignore {
-			if a.verbose {
-				color.Blue("[INFO] Skipping security scan for lockfile: %s", filePath)
-			}
-			return true
+			return "lockfile " + ignore
 		}
 	}
 	
 	// Check patterns
 	for _, pattern := range securityIgnorePatterns {
 		if matched, _ := filepath.Match(pattern, filePath); matched {
-			if a.verbose {
-				color.Blue("[INFO] Skipping security scan for pattern match: %s", filePath)
-			}
-			return true
+			return "matches " + pattern
 		}
 		if matched, _ := filepath.Match(pattern, baseName); matched {
-			if a.verbose {
-				color.Blue("[INFO] Skipping security scan for pattern match: %s", filePath)
-			}
-			return true
+			return "matches " + pattern
 		}
 	}
 	
-	return false
+	return ""
 }
 
-// getChangedLines returns only the added/modified lines from a file in the diff
-func (a *Analyzer) getChangedLines(targetBranch, filePath string) ([]struct {
+// changedLine is a line added by the diff and its number in the new file
+type changedLine struct {
 	LineNum int
 	Content string
-}, error) {
-	// Get diff for specific file showing only added lines
-	cmd := exec.Command("git", "diff", "-U0", 
-		"--diff-filter=AM",  // Added or Modified
-		"origin/"+targetBranch+"..HEAD",
-		"--", filePath)
-	cmd.Dir = a.repoPath
-	
-	output, err := cmd.Output()
+}
+
+// hunkHeaderPattern captures where the new side of a hunk starts, e.g. 12 in @@ -10,2 +12,3 @@
+var hunkHeaderPattern = regexp.MustCompile(`^@@ -\d+(?:,\d+)? \+(\d+)(?:,\d+)? @@`)
+
+// getChangedLines returns only the added/modified lines from a file in the diff.
+// Each file is diffed once per run; later calls share the result.
+func (a *Analyzer) getChangedLines(targetBranch, filePath string) ([]changedLine, error) {
+	target, err := a.resolveTarget(targetBranch)
 	if err != nil {
-		// Fallback: try without origin
-		cmd = exec.Command("git", "diff", "-U0", 
-			"--diff-filter=AM",
-			targetBranch+"..HEAD",
-			"--", filePath)
-		cmd.Dir = a.repoPath
-		output, err = cmd.Output()
+		return nil, err
+	}
+
+	return a.diffs.get(strings.Join(target.diffArgs, " "), filePath, func() ([]changedLine, error) {
+		// Get diff for specific file showing only added lines
+		args := []string{"diff", "-U0",
+			"--diff-filter=AM"}  // Added or Modified
+		args = append(args, target.diffArgs...)
+		output, err := a.git(append(args, "--", filePath)...)
 		if err != nil {
 			return nil, err
 		}
-	}
-	
-	var changedLines []struct {
-		LineNum int
-		Content string
-	}
-	
-	scanner := bufio.NewScanner(strings.NewReader(string(output)))
-	currentLine := 0
+		return parseAddedLines(output), nil
+	})
+}
+
+// parseAddedLines returns the lines a unified diff adds, numbered in the new file.
+// Numbers come only from the hunk headers: each hunk restarts the count at its
+// new-side start, added and context lines advance it, and removed lines, which are
+// not in the new file, do not. File headers (diff, index, ---, +++) are only read
+// outside hunks, so an added line that starts with ++ is still an added line.
+func parseAddedLines(diff string) []changedLine {
+	var added []changedLine
+	// next is the new-file number of the next added or context line, or 0 outside a hunk
+	next := 0
 	
-	for scanner.Scan() {
-		line := scanner.Text()
-		
-		// Parse @@ -X,Y +A,B @@ to get line numbers
-		if strings.HasPrefix(line, "@@") {
-			// Extract the +A part (new file line number)
-			parts := strings.Split(line, "+")
-			if len(parts) >= 2 {
-				numPart := strings.Split(parts[1], ",")[0]
-				numPart = strings.Split(numPart, " ")[0]
-				var startLine int
-				if _, err := fmt.Sscanf(numPart, "%d", &startLine); err == nil {
-					currentLine = startLine - 1 // Will be incremented on first line
-				}
-			}
+	for _, line := range strings.Split(diff, "\n") {
+		if m := hunkHeaderPattern.FindStringSubmatch(line); m != nil {
+			next, _ = strconv.Atoi(m[1])
+			continue
+		}
+		if next == 0 {
 			continue
 		}
 		
-		// Only process added lines (starting with +, but not +++)
-		if strings.HasPrefix(line, "+") && !strings.HasPrefix(line, "+++") {
-			currentLine++
-			content := strings.TrimPrefix(line, "+")
-			changedLines = append(changedLines, struct {
-				LineNum int
-				Content string
-			}{
-				LineNum: currentLine,
-				Content: content,
-			})
-		} else if !strings.HasPrefix(line, "-") && !strings.HasPrefix(line, "\\") {
-			// Context line or header, increment counter
-			if len(line) > 0 && line[0] != '-' && line[0] != '\\' && line[0] != '@' && line[0] != 'd' {
-				currentLine++
-			}
+		switch {
+		case strings.HasPrefix(line, "+"):
+			added = append(added, changedLine{LineNum: next, Content: line[1:]})
+			next++
+		case strings.HasPrefix(line, " "):
+			next++
+		case strings.HasPrefix(line, "diff "):
+			// The next file's headers
+			next = 0
 		}
 	}
 	
-	return changedLines, nil
+	return added
 }
 
 // RunSecurityChecksV2 runs improved security checks on changed lines only
 func (a *Analyzer) RunSecurityChecksV2(report *Report, targetBranch string) {
-	if a.verbose {
-		color.Blue("[INFO] Running improved security checks (changed lines only)")
-	}
+	a.log.Infof("Running improved security checks (changed lines only)")
 	
 	patterns := GetSecurityPatterns()
 	
 	for _, file := range report.ChangedFiles {
 		// Skip files that shouldn't be security scanned
-		if a.shouldSkipFileForSecurity(file) {
+		if _, verdict := a.selectFile(file); !verdict.security {
+			a.log.Debugf("Skipping security scan (%s): %s", verdict.securitySkip, file)
+			report.markSkipped(file, verdict.securitySkip)
 			continue
 		}
 		
-		if a.verbose {
-			color.Blue("[INFO] Security scanning changed lines in: %s", file)
-		}
+		a.log.Debugf("Security scanning changed lines in: %s", file)
 		
 		// Get only changed lines
 		changedLines, err := a.getChangedLines(targetBranch, file)
 		if err != nil {
-			if a.verbose {
-				color.Yellow("[WARN] Could not get changed lines for %s: %v", file, err)
-			}
+			a.log.Warnf("Could not get changed lines for %s: %v", file, err)
+			report.markSkipped(file, "could not read changed lines")
 			continue
 		}
 		
-		if a.verbose {
-			color.Blue("[INFO] Found %d changed lines in %s", len(changedLines), file)
-		}
+		a.log.Debugf("Found %d changed lines in %s", len(changedLines), file)
+		report.markAnalyzed(file)
 		
 		// Check each changed line against patterns
-		for _, line := range changedLines {
-			for _, sp := range patterns {
-				// Check if line matches the pattern
-				if !sp.Pattern.MatchString(line.Content) {
-					continue
-				}
-				
-				// Check exclusions
-				excluded := false
-				for _, exc := range sp.Exclusions {
-					if exc.MatchString(line.Content) {
-						excluded = true
-						if a.verbose {
-							color.Blue("[INFO] Line excluded by pattern: %s", exc.String())
-						}
-						break
-					}
-				}
-				
-				if !excluded {
-					report.AddIssue(Issue{
-						Type:     "security",
-						Severity: sp.Severity,
-						Message:  sp.Message,
-						File:     file,
-						Line:     line.LineNum,
-					})
-					if a.verbose {
-						color.Yellow("[WARN] Security issue found: %s at %s:%d", sp.Message, file, line.LineNum)
-					}
+		a.checkSecurityPatterns(file, changedLines, patterns, report)
+	}
+	
+	a.log.Infof("Done running improved security checks")
+}
+
+// checkSecurityPatterns reports the lines matching a security pattern and none of its exclusions
+func (a *Analyzer) checkSecurityPatterns(file string, lines []changedLine, patterns []SecurityPattern, report *Report) {
+	for _, line := range lines {
+		for _, sp := range patterns {
+			// Check if line matches the pattern
+			if !sp.Pattern.MatchString(line.Content) {
+				continue
+			}
+			
+			// Check exclusions
+			excluded := false
+			for _, exc := range sp.Exclusions {
+				if exc.MatchString(line.Content) {
+					excluded = true
+					a.log.Debugf("Line excluded by pattern: %s", exc.String())
+					break
 				}
 			}
+			
+			if !excluded {
+				report.AddIssue(Issue{
+					Type:     "security",
+					Severity: sp.Severity,
+					Message:  sp.Message,
+					File:     file,
+					Line:     line.LineNum,
+					RuleID:   strings.ReplaceAll(sp.Name, "_", "-"),
+					CWE:      sp.CWE,
+				})
+				a.log.Debugf("Security issue found: %s at %s:%d", sp.Message, file, line.LineNum)
+			}
 		}
 	}
-	
-	if a.verbose {
-		color.Blue("[INFO] Done running improved security checks")
-	}
 }
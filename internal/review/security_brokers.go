@@ -0,0 +1,89 @@
+package review
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	// Kafka clients set to PLAINTEXT or SASL_PLAINTEXT, in properties files, client
+	// config maps, kafka-python keyword arguments and environment variables
+	kafkaPlaintextPattern = regexp.MustCompile(`(?i)security[._]protocol["']?\s*[:=]\s*["']?(SASL_)?PLAINTEXT\b`)
+	// RabbitMQ's default guest account in an AMQP URL or credentials pair
+	rabbitGuestPattern = regexp.MustCompile(`(?i)\bamqps?://guest:guest@|PlainCredentials\s*\(\s*["']guest["']\s*,\s*["']guest["']`)
+	// Redis URLs, capturing their authority; redis://:password@host carries AUTH
+	redisURLPattern = regexp.MustCompile(`\brediss?://([^\s"'/]*)`)
+	// MQTT URLs without TLS, and plain TCP to the MQTT port, capturing the host
+	mqttPlaintextPattern = regexp.MustCompile(`\bmqtt://(?:[^\s"'/@]*@)?([^\s"'/:]+)|\btcp://(?:[^\s"'/@]*@)?([^\s"'/:]+):1883\b`)
+)
+
+// checkBrokerAuth flags message broker and queue connections set up without
+// credentials or TLS: Kafka over PLAINTEXT, RabbitMQ's default guest account,
+// Redis URLs without a password and MQTT without TLS. Redis and MQTT connections
+// to local hosts are development setups and are not reported.
+func (a *Analyzer) checkBrokerAuth(file string, lines []string, report *Report) {
+	for i, line := range lines {
+		// SECURITY: Check for Kafka clients without TLS
+		if m := kafkaPlaintextPattern.FindStringSubmatch(line); m != nil {
+			issue := Issue{
+				Type:     "security",
+				Severity: "high",
+				Message:  "Kafka security.protocol is PLAINTEXT - the connection has no authentication or encryption, use SASL_SSL or SSL",
+				File:     file,
+				Line:     i + 1,
+				RuleID:   "broker-plaintext",
+			}
+			if m[1] != "" {
+				issue.Severity = "medium"
+				issue.Message = "Kafka security.protocol is SASL_PLAINTEXT - credentials and messages are sent unencrypted, use SASL_SSL"
+			}
+			report.AddIssue(issue)
+		}
+
+		// SECURITY: Check for RabbitMQ default credentials
+		if rabbitGuestPattern.MatchString(line) {
+			report.AddIssue(Issue{
+				Type:     "security",
+				Severity: "high",
+				Message:  "RabbitMQ default guest:guest credentials - create a dedicated user and read its password from the environment",
+				File:     file,
+				Line:     i + 1,
+				RuleID:   "broker-default-credentials",
+			})
+		}
+
+		// SECURITY: Check for Redis connections without AUTH
+		for _, m := range redisURLPattern.FindAllStringSubmatch(line, -1) {
+			host, _, _ := strings.Cut(m[1], ":")
+			if strings.Contains(m[1], "@") || host == "" || isLocalHost(host) {
+				continue
+			}
+			report.AddIssue(Issue{
+				Type:     "security",
+				Severity: "high",
+				Message:  "Redis URL without a password - anyone who can reach the server can read and write it, set requirepass and connect with redis://:password@host",
+				File:     file,
+				Line:     i + 1,
+				RuleID:   "broker-no-auth",
+			})
+			break
+		}
+
+		// SECURITY: Check for MQTT connections without TLS
+		for _, m := range mqttPlaintextPattern.FindAllStringSubmatch(line, -1) {
+			host := m[1] + m[2]
+			if isLocalHost(host) {
+				continue
+			}
+			report.AddIssue(Issue{
+				Type:     "security",
+				Severity: "medium",
+				Message:  "MQTT connection without TLS - credentials and messages are sent unencrypted, use mqtts:// (port 8883) or wss://",
+				File:     file,
+				Line:     i + 1,
+				RuleID:   "broker-no-tls",
+			})
+			break
+		}
+	}
+}
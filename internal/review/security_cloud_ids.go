@@ -0,0 +1,104 @@
+package review
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var (
+	// An AWS ARN with an account number, capturing the account
+	awsARNPattern = regexp.MustCompile(`\barn:aws[\w-]*:[\w-]+:[\w-]*:(\d{12}):`)
+	// A 12 digit AWS account ID assigned to an account setting, capturing the ID
+	awsAccountIDPattern = regexp.MustCompile(`(?i)account_?id["']?\s*[:=]\s*["']?(\d{12})\b`)
+	// A GCP project ID assigned to a project setting or in a resource name, capturing the ID
+	gcpProjectIDPattern = regexp.MustCompile(`(?i)\bproject_?id["']?\s*[:=]\s*["']([a-z][a-z0-9-]{4,28}[a-z0-9])["']|\bprojects/([a-z][a-z0-9-]{4,28}[a-z0-9])/(?:topics|subscriptions|locations|secrets|datasets|instances|databases|zones|regions|global|serviceAccounts|buckets)\b`)
+	// A storage bucket URL or a name assigned to a bucket setting, capturing the name
+	bucketNamePattern = regexp.MustCompile(`(?i)\b(?:s3|gs)://([a-z0-9][a-z0-9.-]{1,61}[a-z0-9])|\bbucket(?:_?name)?["']?\s*[:=(]\s*["']([a-z0-9][a-z0-9.-]{1,61}[a-z0-9])["']`)
+	// prod or production as a word of a bucket name, e.g. acme-prod-uploads
+	prodBucketPattern = regexp.MustCompile(`(?i)(^|[.-])prod(uction)?([.-]|$)`)
+	// Identifiers used in documentation and tests rather than real environments
+	cloudPlaceholderPattern = regexp.MustCompile(`(?i)example|sample|placeholder|dummy|fake|your-|my-project|changeme|xxx`)
+)
+
+// isPlaceholderAccountID reports whether a 12 digit account ID is a documentation
+// value: a run of consecutive digits such as 123456789012, or groups of four
+// repeated digits such as 111122223333 and 000000000000
+func isPlaceholderAccountID(id string) bool {
+	if strings.Contains("0123456789012345678901", id) || strings.Contains("2109876543210987654321", id) {
+		return true
+	}
+	for i := range id {
+		if id[i] != id[i-i%4] {
+			return false
+		}
+	}
+	return true
+}
+
+// checkCloudIdentifiers flags AWS account IDs, ARNs with an account, GCP project
+// IDs and production bucket names written into code. They tie the code to one
+// environment and reveal it; configuration should supply them instead.
+// Templated identifiers and documentation placeholders are not reported.
+func (a *Analyzer) checkCloudIdentifiers(file string, lines []string, report *Report) {
+	for i, line := range lines {
+		// SECURITY: Check for ARNs with a hardcoded account
+		arn := awsARNPattern.FindStringSubmatch(line)
+		if arn != nil && !isPlaceholderAccountID(arn[1]) {
+			report.AddIssue(Issue{
+				Type:     "security",
+				Severity: "medium",
+				Message:  fmt.Sprintf("ARN with hardcoded AWS account %s - the code only works in that account and reveals it, build the ARN from configuration", arn[1]),
+				File:     file,
+				Line:     i + 1,
+				RuleID:   "cloud-hardcoded-arn",
+			})
+		}
+
+		// SECURITY: Check for hardcoded AWS account IDs
+		if m := awsAccountIDPattern.FindStringSubmatch(line); m != nil && arn == nil && !isPlaceholderAccountID(m[1]) {
+			report.AddIssue(Issue{
+				Type:     "security",
+				Severity: "low",
+				Message:  fmt.Sprintf("Hardcoded AWS account ID %s - read it from configuration so the code is not tied to one environment", m[1]),
+				File:     file,
+				Line:     i + 1,
+				RuleID:   "cloud-hardcoded-account-id",
+			})
+		}
+
+		// SECURITY: Check for hardcoded GCP project IDs
+		for _, m := range gcpProjectIDPattern.FindAllStringSubmatch(line, -1) {
+			project := m[1] + m[2]
+			if cloudPlaceholderPattern.MatchString(project) {
+				continue
+			}
+			report.AddIssue(Issue{
+				Type:     "security",
+				Severity: "low",
+				Message:  fmt.Sprintf("Hardcoded GCP project ID %s - read it from configuration so the code is not tied to one environment", project),
+				File:     file,
+				Line:     i + 1,
+				RuleID:   "cloud-hardcoded-project-id",
+			})
+			break
+		}
+
+		// SECURITY: Check for hardcoded production buckets
+		for _, m := range bucketNamePattern.FindAllStringSubmatch(line, -1) {
+			bucket := m[1] + m[2]
+			if !prodBucketPattern.MatchString(bucket) || cloudPlaceholderPattern.MatchString(bucket) {
+				continue
+			}
+			report.AddIssue(Issue{
+				Type:     "security",
+				Severity: "medium",
+				Message:  fmt.Sprintf("Hardcoded production bucket %s - the same code run in test or staging reads and writes production data, read the bucket name from configuration", bucket),
+				File:     file,
+				Line:     i + 1,
+				RuleID:   "cloud-prod-bucket",
+			})
+			break
+		}
+	}
+}
@@ -0,0 +1,117 @@
+package review
+
+import (
+	"regexp"
+	"strings"
+)
+
+// cmdlineSecretCheck matches a credential handed to a program as an argument.
+// value is the index of the submatch holding the credential, which decides the
+// severity: a literal is worse than a variable, though both show up in ps.
+type cmdlineSecretCheck struct {
+	pattern *regexp.Regexp
+	value   int
+	message string
+}
+
+// cmdlineSecretChecks are the command lines that expose a credential. They run on
+// shell scripts, and on the command strings and argv lists of process calls in code.
+var cmdlineSecretChecks = []cmdlineSecretCheck{
+	{
+		// mysql -uroot -ps3cret; a bare -p prompts for the password instead
+		pattern: regexp.MustCompile(`\b(mysql|mysqldump|mysqladmin|mariadb|mariadb-dump)\b.*[\s"'](-p|--password=)["']?([^\s"',]+)`),
+		value:   3,
+		message: "Database password passed with -p on the command line",
+	},
+	{
+		// curl -u admin:s3cret; curl -u admin prompts for the password instead
+		pattern: regexp.MustCompile(`\bcurl\b.*[\s"'](-u|--user)(\s+|=|["']\s*,\s*f?["'])["']?[^\s"':,]+:([^\s"',]+)`),
+		value:   3,
+		message: "curl credentials passed with -u user:password on the command line",
+	},
+	{
+		// docker run -e DB_PASSWORD=s3cret; -e DB_PASSWORD alone copies it from the environment
+		pattern: regexp.MustCompile(`(?i)\bdocker\b.*[\s"'](-e|--env|--build-arg)(\s+|=|["']\s*,\s*f?["'])["']?\w*(password|passwd|secret|token|api_?key|access_key|private_key)\w*=([^\s"',]+)`),
+		value:   4,
+		message: "Secret set with docker -e/--build-arg on the command line",
+	},
+	{
+		// sshpass -p s3cret
+		pattern: regexp.MustCompile(`\bsshpass\b.*[\s"']-p(\s+|["']\s*,\s*f?["'])?["']?([^\s"',]+)`),
+		value:   2,
+		message: "SSH password passed with sshpass -p on the command line",
+	},
+	{
+		// --password=s3cret, --token "$TOKEN"; --password-stdin, --token-file and
+		// docker build --secret, which mounts the secret as a file, are safe
+		pattern: regexp.MustCompile(`[\s"']--(password|passwd|token|api-key|apikey|client-secret)(=|\s+|["']\s*,\s*f?["'])["']?([^\s"',-][^\s"',]*)`),
+		value:   3,
+		message: "Credential passed as a command-line option",
+	},
+}
+
+var (
+	// Calls that start a process from code, whose arguments other users can read
+	processCallPattern = regexp.MustCompile(`\bsubprocess\.\w+\s*\(|\bos\.(system|popen|exec\w*)\s*\(|\bexec\.Command(Context)?\s*\(|\b(exec|execSync|execFile|execFileSync|spawn|spawnSync|system)\s*\(|\bRuntime\.getRuntime\(\)\.exec\s*\(|\bProcessBuilder\s*\(|\bOpen3\.\w+\s*\(|\bIO\.popen\s*\(|%x[({]`)
+	// An argv element following a credential flag, e.g. "-p" + password or "--token", token
+	argvCredentialPattern = regexp.MustCompile(`["'](-p|--password|--passwd|--token|--api-key)=?["']\s*[,+]\s*([^,)\]]+)`)
+	// Names of variables holding credentials
+	credentialNamePattern = regexp.MustCompile(`(?i)pass|pwd|secret|token|api_?key|credential`)
+)
+
+// checkCommandLineSecrets flags credentials passed to programs as arguments:
+// any local user can read them with ps or /proc/<pid>/cmdline, and they end up
+// in shell history and audit logs. Shell scripts are checked line by line; in
+// other languages only lines that start a process are. A literal credential is
+// reported as high, one expanded from a variable as medium.
+func (a *Analyzer) checkCommandLineSecrets(file string, lines []string, report *Report) {
+	shell := LanguageForFile(file) == "shell"
+
+	for i, line := range lines {
+		if shell && strings.HasPrefix(strings.TrimSpace(line), "#") {
+			continue
+		}
+		if !shell && !processCallPattern.MatchString(line) {
+			continue
+		}
+
+		message, variable := "", false
+		for _, check := range cmdlineSecretChecks {
+			if m := check.pattern.FindStringSubmatch(line); m != nil {
+				// $VAR in shell, {var} in f-strings and ${var} in template literals
+				value := m[check.value]
+				variable = strings.ContainsAny(value, "${")
+				if variable || !commentPlaceholderPattern.MatchString(value) {
+					message = check.message
+				}
+				break
+			}
+		}
+		// Code also builds argv lists one element at a time; anything but a
+		// string literal is a variable or expression
+		if m := argvCredentialPattern.FindStringSubmatch(line); message == "" && !shell && m != nil {
+			expr := strings.TrimSpace(m[2])
+			if m[1] != "-p" || credentialNamePattern.MatchString(expr) {
+				message = "Credential passed as a process argument"
+				variable = !strings.HasPrefix(expr, `"`) && !strings.HasPrefix(expr, "'") || strings.ContainsAny(expr, "+{")
+			}
+		}
+		if message == "" {
+			continue
+		}
+
+		// SECURITY: Check for credentials visible in the process list
+		severity := "high"
+		if variable {
+			severity = "medium"
+		}
+		report.AddIssue(Issue{
+			Type:     "security",
+			Severity: severity,
+			Message:  message + " - other users can read it with ps, pass it through an environment variable, a config file or stdin instead",
+			File:     file,
+			Line:     i + 1,
+			RuleID:   "cmdline-secret",
+		})
+	}
+}
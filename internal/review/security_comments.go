@@ -0,0 +1,83 @@
+package review
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	// Credentials written out in prose, e.g. "temp password: hunter2" or "token is abc123"
+	commentCredentialPattern = regexp.MustCompile("(?i)\\b(password|passwd|pwd|passphrase|secret|api[ _-]?key|token)\\s*(?:is|was|=|:|->)\\s*[\"'`]?([^\\s\"'`]{6,})")
+	// Values that stand in for a credential rather than being one
+	commentPlaceholderPattern = regexp.MustCompile(`(?i)^(<.*>|\$\{?\w+\}?|\{\{.*\}\}|\*+|x+|\.+|changeme|example\w*|your\w*|redacted|\w*_here)$`)
+	// Real credentials mix in digits or symbols, unlike the words of a sentence
+	commentSecretCharPattern = regexp.MustCompile(`[0-9!@#$%^&*_+=/-]`)
+)
+
+// checkSecretsInComments flags credentials pasted into comments, e.g.
+// "// temp password: hunter2". Comments are never executed, so the code checks
+// pass them over, but they ship with the source all the same. The secret patterns
+// run over the comment text, followed by a looser match for credentials in prose.
+// When changed is non-nil only those lines are checked, as in diff mode.
+func (a *Analyzer) checkSecretsInComments(file string, changed map[int]bool, report *Report) {
+	lang := LanguageForFile(file)
+	if _, ok := commentSyntaxes[lang]; !ok {
+		return
+	}
+
+	content, err := a.readFile(file)
+	if err != nil {
+		return
+	}
+
+	patterns := GetSecurityPatterns()
+	for i, comment := range commentLines(strings.Split(string(content), "\n"), lang) {
+		if comment == "" || changed != nil && !changed[i+1] {
+			continue
+		}
+
+		message := ""
+		for _, sp := range patterns {
+			if sp.Pattern.MatchString(comment) && !excludedBy(sp.Exclusions, comment) {
+				message = sp.Message + " in a comment"
+				break
+			}
+		}
+		if message == "" && commentCredential(comment) {
+			message = "Credential written in a comment"
+		}
+
+		// SECURITY: Check for secrets pasted into comments
+		if message != "" {
+			report.AddIssue(Issue{
+				Type:     "security",
+				Severity: "high",
+				Message:  message + " - remove it and rotate the credential, it stays in the git history",
+				File:     file,
+				Line:     i + 1,
+				RuleID:   "secret-in-comment",
+			})
+		}
+	}
+}
+
+// commentCredential reports whether comment spells out a credential's value
+func commentCredential(comment string) bool {
+	for _, m := range commentCredentialPattern.FindAllStringSubmatch(comment, -1) {
+		value := strings.TrimRight(m[2], ".,;:)")
+		if len(value) >= 6 && !commentPlaceholderPattern.MatchString(value) && commentSecretCharPattern.MatchString(value) {
+			return true
+		}
+	}
+	return false
+}
+
+// excludedBy reports whether any of the exclusion patterns match s
+func excludedBy(exclusions []*regexp.Regexp, s string) bool {
+	for _, exc := range exclusions {
+		if exc.MatchString(s) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,153 @@
+package review
+
+import (
+	"fmt"
+	"math"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	// secretEntropyThreshold is the bits per character above which the value of a
+	// credential setting is taken to be a real secret rather than a word such as "postgres"
+	secretEntropyThreshold = 3.0
+	// randomEntropyThreshold and randomMinLength pick out random-looking values,
+	// such as signing keys, whatever the setting is called
+	randomEntropyThreshold = 4.5
+	randomMinLength        = 24
+)
+
+var (
+	// A key = value or key: value setting, optionally exported, capturing the key and value
+	configSettingPattern = regexp.MustCompile(`^\s*(?:export\s+)?([\w.-]+)\s*[=:]\s*(.*?)\s*$`)
+	// Setting names that hold credentials, e.g. DB_PASSWORD, STRIPE_SECRET_KEY, auth.token
+	configSecretKeyPattern = regexp.MustCompile(`(?i)(password|passwd|passphrase|secret|token|api_?key|credentials?|(^|[._-])(pwd|key))$`)
+	// Debug switches that turn on stack traces and debug pages
+	configDebugKeyPattern = regexp.MustCompile(`(?i)^(app[._])?debug$`)
+	configTruePattern     = regexp.MustCompile(`(?i)^(true|1|yes|on)$`)
+)
+
+// isSecretsConfigFile reports whether file is a key = value settings file that
+// holds environment-specific values: dotenv, Java properties, TOML or INI
+func isSecretsConfigFile(file string) bool {
+	base := strings.ToLower(filepath.Base(file))
+	if base == ".env" || strings.HasPrefix(base, ".env.") {
+		return true
+	}
+	switch filepath.Ext(base) {
+	case ".env", ".properties", ".toml", ".ini":
+		return true
+	}
+	return false
+}
+
+// shannonEntropy returns the average bits of information per character of s
+func shannonEntropy(s string) float64 {
+	counts := map[rune]int{}
+	for _, r := range s {
+		counts[r]++
+	}
+	n := float64(utf8.RuneCountInString(s))
+	entropy := 0.0
+	for _, count := range counts {
+		p := float64(count) / n
+		entropy -= p * math.Log2(p)
+	}
+	return entropy
+}
+
+// configValue returns a setting's value without its quotes or, when unquoted,
+// a trailing comment
+func configValue(value string) string {
+	if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') {
+		if end := strings.IndexByte(value[1:], value[0]); end >= 0 {
+			return value[1 : end+1]
+		}
+	}
+	if i := strings.Index(value, " #"); i >= 0 {
+		value = value[:i]
+	}
+	return strings.TrimSpace(value)
+}
+
+// configSecretEntropy reports whether value is random enough to be a real
+// credential: a credential setting's value with digits or symbols above
+// secretEntropyThreshold, or any long value above randomEntropyThreshold
+func configSecretEntropy(key, value string) bool {
+	if commentPlaceholderPattern.MatchString(value) || strings.Contains(value, "://") || strings.ContainsAny(value, " \t") {
+		return false
+	}
+	if configSecretKeyPattern.MatchString(key) {
+		return len(value) >= 8 && commentSecretCharPattern.MatchString(value) && shannonEntropy(value) >= secretEntropyThreshold
+	}
+	return len(value) >= randomMinLength && shannonEntropy(value) >= randomEntropyThreshold
+}
+
+// checkConfigSecrets flags real values committed in dotenv, properties, TOML and
+// INI files: credentials matching the secret patterns or random enough to be
+// keys, debug switches left on, and .env itself, which should never be committed.
+// The secret patterns expect quoted values, so each setting is quoted before they
+// run. code holds the file's lines with comments blanked.
+func (a *Analyzer) checkConfigSecrets(file string, code []string, report *Report) {
+	// SECURITY: Check for a committed .env; .env.example and the like are
+	// templates meant to be committed
+	if filepath.Base(file) == ".env" {
+		report.AddIssue(Issue{
+			Type:     "security",
+			Severity: "high",
+			Message:  ".env file committed - it holds local secrets, add it to .gitignore, commit a .env.example with placeholder values instead and rotate any credentials it contained",
+			File:     file,
+			RuleID:   "dotenv-committed",
+		})
+	}
+
+	patterns := GetSecurityPatterns()
+	for i, line := range code {
+		m := configSettingPattern.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		key, value := m[1], configValue(m[2])
+		if value == "" {
+			continue
+		}
+
+		// SECURITY: Check for debug mode switched on
+		if configDebugKeyPattern.MatchString(key) && configTruePattern.MatchString(value) {
+			report.AddIssue(Issue{
+				Type:     "security",
+				Severity: "medium",
+				Message:  fmt.Sprintf("%s is enabled - debug mode shows stack traces, settings and source to anyone who triggers an error, keep it off outside local development", key),
+				File:     file,
+				Line:     i + 1,
+				RuleID:   "config-debug-enabled",
+			})
+			continue
+		}
+
+		// SECURITY: Check for credentials committed as setting values
+		setting := fmt.Sprintf("%s = %q", key, value)
+		message, severity := "", "high"
+		for _, sp := range patterns {
+			if sp.Pattern.MatchString(setting) && !excludedBy(sp.Exclusions, setting) {
+				message, severity = sp.Message, sp.Severity
+				break
+			}
+		}
+		if message == "" && configSecretEntropy(key, value) {
+			message = fmt.Sprintf("High-entropy value for %s looks like a real secret", key)
+		}
+		if message != "" {
+			report.AddIssue(Issue{
+				Type:     "security",
+				Severity: severity,
+				Message:  message + " - load it from the environment or a secret manager and rotate it, it stays in the git history",
+				File:     file,
+				Line:     i + 1,
+				RuleID:   "config-secret",
+			})
+		}
+	}
+}
@@ -0,0 +1,123 @@
+package review
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	// Content-Type response headers whose value comes from the request
+	reflectedContentTypePattern = regexp.MustCompile(`(?i)header\s*\(\s*["']content-type:[^)]*\$_(GET|POST|REQUEST|COOKIE|SERVER)|res\.(type|contentType)\s*\(\s*req\.|res\.(set|setHeader|header)\s*\(\s*["']content-type["']\s*,\s*req\.|(content_type|mimetype)\s*=\s*request\.`)
+	// PHP Content-Type headers, capturing the media type
+	phpContentTypePattern = regexp.MustCompile(`(?i)header\s*\(\s*["']content-type:\s*([\w.+-]+/[\w.+-]+)`)
+	// PHP variables assigned straight from request input
+	phpTaintedAssignPattern = regexp.MustCompile(`\$(\w+)\s*=\s*[^;]*\$_(GET|POST|REQUEST|COOKIE)\b`)
+	// PHP output statements
+	phpOutputPattern = regexp.MustCompile(`\b(echo|print)\b|<\?=`)
+	// Express response helpers that set a Content-Type, capturing the type
+	expressContentTypePattern = regexp.MustCompile(`(?i)res\.(?:type|contentType)\s*\(\s*["']([^"']+)["']|res\.(?:set|setHeader|header)\s*\(\s*["']content-type["']\s*,\s*["']([^"']+)["']`)
+	// Express variables assigned or destructured from request input
+	expressTaintedAssignPattern = regexp.MustCompile(`(?:const|let|var)\s+(\w+|\{[^}]+\})\s*=\s*req\.(query|body|params)\b`)
+	// Express route handlers, which start a new response context
+	expressRoutePattern = regexp.MustCompile(`\b(app|router|server)\.(get|post|put|patch|delete|all|use)\s*\(`)
+	// Express res.send/res.end with its argument
+	expressSendPattern = regexp.MustCompile(`res(?:\.status\([^)]*\))?\.(send|end|write)\s*\((.*)\)`)
+	// PHP variable references and JavaScript identifiers
+	phpVariablePattern = regexp.MustCompile(`\$(\w+)`)
+	identifierPattern  = regexp.MustCompile(`[A-Za-z_$][\w$]*`)
+	// Calls that escape or sanitize output
+	outputEscapePattern = regexp.MustCompile(`(?i)htmlspecialchars|htmlentities|strip_tags|escape|sanitiz|encode|purify|json_encode`)
+)
+
+// isHTMLContentType reports whether a media type is rendered as HTML by browsers
+func isHTMLContentType(mediaType string) bool {
+	mediaType = strings.ToLower(mediaType)
+	return mediaType == "html" || strings.Contains(mediaType, "text/html") || strings.Contains(mediaType, "xhtml")
+}
+
+// checkResponseContentType flags responses that reflect the request's Content-Type
+// and, for PHP and Express, user input written into HTML responses without escaping.
+// PHP and Express both default to text/html, so output is only considered safe after
+// an explicit non-HTML Content-Type.
+func (a *Analyzer) checkResponseContentType(file string, lines []string, report *Report) {
+	language := LanguageForFile(file)
+	tainted := map[string]bool{}
+	htmlResponse := true
+
+	for i, line := range lines {
+		// SECURITY: Check for Content-Type headers copied from the request
+		if reflectedContentTypePattern.MatchString(line) {
+			report.AddIssue(Issue{
+				Type:     "security",
+				Severity: "high",
+				Message:  "Response Content-Type taken from the request - an attacker can serve content as text/html, use a fixed type",
+				File:     file,
+				Line:     i + 1,
+				RuleID:   "reflected-content-type",
+			})
+			continue
+		}
+
+		switch {
+		case language == "php":
+			if m := phpContentTypePattern.FindStringSubmatch(line); m != nil {
+				htmlResponse = isHTMLContentType(m[1])
+			}
+			if m := phpTaintedAssignPattern.FindStringSubmatch(line); m != nil && !outputEscapePattern.MatchString(line) {
+				tainted[m[1]] = true
+			}
+
+			// SECURITY: Check for request input echoed into an HTML response through a variable.
+			// Direct echo of $_GET and friends is reported by the xss rule.
+			if htmlResponse && phpOutputPattern.MatchString(line) && !outputEscapePattern.MatchString(line) && !strings.Contains(line, "$_") {
+				for _, m := range phpVariablePattern.FindAllStringSubmatch(line, -1) {
+					if name := m[1]; tainted[name] {
+						report.AddIssue(Issue{
+							Type:     "security",
+							Severity: "medium",
+							Message:  "Request input $" + name + " written to an HTML response without escaping - use htmlspecialchars() or a non-HTML Content-Type",
+							File:     file,
+							Line:     i + 1,
+							RuleID:   "unescaped-html-response",
+						})
+						break
+					}
+				}
+			}
+
+		case language == "javascript" || language == "typescript":
+			if expressRoutePattern.MatchString(line) {
+				htmlResponse = true
+			}
+			if m := expressContentTypePattern.FindStringSubmatch(line); m != nil {
+				htmlResponse = isHTMLContentType(m[1] + m[2])
+			}
+			if m := expressTaintedAssignPattern.FindStringSubmatch(line); m != nil {
+				for _, name := range identifierPattern.FindAllString(m[1], -1) {
+					tainted[name] = true
+				}
+			}
+
+			// SECURITY: Check for request input sent as an HTML response
+			m := expressSendPattern.FindStringSubmatch(line)
+			// Objects and arrays are sent as JSON
+			if !htmlResponse || m == nil || outputEscapePattern.MatchString(m[2]) || strings.HasPrefix(strings.TrimSpace(m[2]), "{") || strings.HasPrefix(strings.TrimSpace(m[2]), "[") {
+				continue
+			}
+			reflected := strings.Contains(m[2], "req.query") || strings.Contains(m[2], "req.body") || strings.Contains(m[2], "req.params")
+			for _, name := range identifierPattern.FindAllString(m[2], -1) {
+				reflected = reflected || tainted[name]
+			}
+			if reflected {
+				report.AddIssue(Issue{
+					Type:     "security",
+					Severity: "medium",
+					Message:  "Request input sent with res." + m[1] + "() as text/html - escape it, use res.json() or set a non-HTML Content-Type",
+					File:     file,
+					Line:     i + 1,
+					RuleID:   "unescaped-html-response",
+				})
+			}
+		}
+	}
+}
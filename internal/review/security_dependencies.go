@@ -0,0 +1,148 @@
+package review
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+var (
+	// Module specifiers of require(), import ... from and bare import statements
+	jsModuleSpecifierPattern = regexp.MustCompile(`\brequire\s*\(\s*['"]([^'"]+)['"]|\bfrom\s+['"]([^'"]+)['"]|^\s*import\s+['"]([^'"]+)['"]`)
+	// The opening of a package.json dependency map
+	packageJSONDependenciesPattern = regexp.MustCompile(`"(dev|peer|optional)?[dD]ependencies"\s*:\s*\{`)
+	// Keys of package.json dependency maps, e.g. "acme-utils": "^1.2.0"
+	packageJSONDependencyPattern = regexp.MustCompile(`^\s*"([^"]+)"\s*:\s*"`)
+	// pip install commands, capturing their arguments up to the end of the command
+	pipInstallPattern = regexp.MustCompile(`\bpip3?\s+install\s+([^;&|]*)`)
+	// Options that pin pip to a single index; --extra-index-url does not, it adds the public index
+	pipIndexPinPattern = regexp.MustCompile(`(^|\s)(--index-url|-i)(\s|=)`)
+	// pip options whose value is the next argument
+	pipValueOptions = map[string]bool{"-r": true, "--requirement": true, "-c": true, "--constraint": true, "-e": true, "--editable": true,
+		"-f": true, "--find-links": true, "--extra-index-url": true, "--trusted-host": true, "-t": true, "--target": true}
+	// The end of a requirement's name: a version specifier, extras, marker or URL reference
+	pipRequirementEndPattern = regexp.MustCompile(`[\s<>=!~;\[@]`)
+	// Requirement files, e.g. requirements.txt or requirements-dev.in
+	requirementsFilePattern = regexp.MustCompile(`^requirements[\w.-]*\.(txt|in)$`)
+)
+
+// SetInternalPackages enables the dependency confusion check for packages whose
+// names start with one of these prefixes
+func (a *Analyzer) SetInternalPackages(prefixes []string) {
+	a.internalPackages = nil
+	for _, prefix := range prefixes {
+		if prefix = strings.TrimSpace(prefix); prefix != "" {
+			a.internalPackages = append(a.internalPackages, normalizePackageName(prefix))
+		}
+	}
+}
+
+// checkDependencyConfusion flags internal packages referenced in a way that lets a
+// public registry satisfy them: unscoped npm names in imports and package.json,
+// and pip installs or requirement files that are not pinned to a single index.
+// Whether a scope or index is really private cannot be checked, so findings are
+// low confidence. When changed is non-nil only those lines are reported, as in diff mode.
+func (a *Analyzer) checkDependencyConfusion(file string, changed map[int]bool, report *Report) {
+	if len(a.internalPackages) == 0 {
+		return
+	}
+
+	content, err := a.readFile(file)
+	if err != nil {
+		return
+	}
+	contentStr := string(content)
+	lines := strings.Split(contentStr, "\n")
+	base := filepath.Base(file)
+	language := LanguageForFile(file)
+	// inDependencies is set inside a package.json dependency map
+	inDependencies := false
+
+	for i, line := range lines {
+		var names []string
+		switch {
+		case language == "javascript" || language == "typescript":
+			for _, m := range jsModuleSpecifierPattern.FindAllStringSubmatch(line, -1) {
+				names = append(names, strings.Join(m[1:], ""))
+			}
+		case base == "package.json":
+			if packageJSONDependenciesPattern.MatchString(line) {
+				inDependencies = true
+			} else if strings.Contains(line, "}") {
+				inDependencies = false
+			} else if m := packageJSONDependencyPattern.FindStringSubmatch(line); m != nil && inDependencies {
+				names = append(names, m[1])
+			}
+		case requirementsFilePattern.MatchString(base):
+			trimmed := strings.TrimSpace(line)
+			if pipIndexPinPattern.MatchString(contentStr) || trimmed == "" || strings.HasPrefix(trimmed, "#") || strings.HasPrefix(trimmed, "-") || strings.Contains(trimmed, "://") {
+				continue
+			}
+			names = append(names, pipRequirementEndPattern.Split(trimmed, 2)[0])
+		}
+		if m := pipInstallPattern.FindStringSubmatch(line); m != nil && !pipIndexPinPattern.MatchString(m[1]) {
+			names = append(names, pipInstallPackages(m[1])...)
+		}
+		// The dependency map is tracked on every line, but only changed lines are reported
+		if changed != nil && !changed[i+1] {
+			continue
+		}
+
+		for _, name := range names {
+			if !a.isUnscopedInternalPackage(name) {
+				continue
+			}
+
+			// SECURITY: Check for internal packages a public registry could satisfy
+			report.AddIssue(Issue{
+				Type:     "security",
+				Severity: "medium",
+				Message:  fmt.Sprintf("Internal package %s referenced without a scope or pinned registry - a public package of the same name could be installed instead (dependency confusion, heuristic)", name),
+				File:     file,
+				Line:     i + 1,
+				RuleID:   "dependency-confusion",
+			})
+		}
+	}
+}
+
+// isUnscopedInternalPackage reports whether a package name starts with an internal
+// prefix and carries no npm scope. Relative and absolute paths are not packages.
+func (a *Analyzer) isUnscopedInternalPackage(name string) bool {
+	if name == "" || strings.HasPrefix(name, "@") || strings.HasPrefix(name, ".") || strings.HasPrefix(name, "/") {
+		return false
+	}
+	name = normalizePackageName(strings.SplitN(name, "/", 2)[0])
+	for _, prefix := range a.internalPackages {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// pipInstallPackages returns the package names among pip install arguments
+func pipInstallPackages(args string) []string {
+	var names []string
+	fields := strings.Fields(args)
+	for i := 0; i < len(fields); i++ {
+		field := strings.Trim(fields[i], `"'`)
+		if strings.HasPrefix(field, "-") {
+			if pipValueOptions[field] {
+				i++
+			}
+			continue
+		}
+		if strings.Contains(field, "://") || strings.ContainsAny(field, "/\\") || strings.HasPrefix(field, "$") {
+			continue
+		}
+		names = append(names, pipRequirementEndPattern.Split(field, 2)[0])
+	}
+	return names
+}
+
+// normalizePackageName lowercases a name and treats _ and . like - as pip does
+func normalizePackageName(name string) string {
+	return strings.NewReplacer("_", "-", ".", "-").Replace(strings.ToLower(name))
+}
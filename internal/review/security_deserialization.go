@@ -0,0 +1,70 @@
+package review
+
+import "regexp"
+
+// deserializationCheck matches a deserializer that can run code chosen by the data
+type deserializationCheck struct {
+	pattern *regexp.Regexp
+	ruleID  string
+	message string
+}
+
+// unsafeDeserializationChecks are keyed by language. Python's pickle and
+// yaml.load are reported by the Python analyzer itself.
+var unsafeDeserializationChecks = map[string][]deserializationCheck{
+	"python": {
+		{
+			pattern: regexp.MustCompile(`\bmarshal\.loads?\s*\(`),
+			ruleID:  "marshal-load",
+			message: "marshal.load() is unsafe on untrusted data - malformed input can crash the interpreter, use json instead",
+		},
+		{
+			pattern: regexp.MustCompile(`\bshelve\.open\s*\(`),
+			ruleID:  "unsafe-deserialization",
+			message: "shelve stores values with pickle - opening an untrusted shelf can execute arbitrary code",
+		},
+		{
+			pattern: regexp.MustCompile(`\bjsonpickle\.(decode|loads)\s*\(`),
+			ruleID:  "unsafe-deserialization",
+			message: "jsonpickle.decode() creates whatever objects the data names and can execute arbitrary code - use json for untrusted data",
+		},
+	},
+	"csharp": {
+		{
+			// The formatters Microsoft documents as unsafe for any untrusted input,
+			// where they are created or declared
+			pattern: regexp.MustCompile(`\b(new\s+)?(BinaryFormatter|NetDataContractSerializer|LosFormatter|SoapFormatter|ObjectStateFormatter)(\s*\(|\s+\w+\s*[=;])`),
+			ruleID:  "unsafe-deserialization",
+			message: "BinaryFormatter and similar formatters execute code chosen by the data - use System.Text.Json or DataContractSerializer with known types",
+		},
+		{
+			// Json.NET type name handling other than None lets the payload pick the types to create
+			pattern: regexp.MustCompile(`\bTypeNameHandling\s*\.\s*(All|Auto|Objects|Arrays)\b`),
+			ruleID:  "unsafe-deserialization",
+			message: "Json.NET TypeNameHandling lets the JSON choose the types to create, which can execute code - use TypeNameHandling.None or restrict types with a SerializationBinder",
+		},
+	},
+}
+
+// checkUnsafeDeserialization flags deserializers that can instantiate arbitrary
+// types from their input, which turns untrusted data into code execution (CWE-502)
+func (a *Analyzer) checkUnsafeDeserialization(file string, lines []string, report *Report) {
+	checks := unsafeDeserializationChecks[LanguageForFile(file)]
+
+	for i, line := range lines {
+		for _, check := range checks {
+			// SECURITY: Check for unsafe deserialization
+			if check.pattern.MatchString(line) {
+				report.AddIssue(Issue{
+					Type:     "security",
+					Severity: "high",
+					Message:  check.message,
+					File:     file,
+					Line:     i + 1,
+					RuleID:   check.ruleID,
+				})
+				break
+			}
+		}
+	}
+}
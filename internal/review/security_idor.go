@@ -0,0 +1,63 @@
+package review
+
+import "regexp"
+
+// objectOwnershipCheck describes how one framework looks up records by a request
+// supplied id and how code narrows those lookups to the current user
+type objectOwnershipCheck struct {
+	// lookup matches a line that loads a record by an id taken from the request
+	lookup *regexp.Regexp
+	// scope matches a user filter or an ownership/permission check
+	scope   *regexp.Regexp
+	message string
+}
+
+// objectOwnershipWindow is how many lines after a lookup may hold its ownership check
+const objectOwnershipWindow = 5
+
+var (
+	// Django ORM and Flask-SQLAlchemy lookups keyed by request data, and the
+	// user filters and permission checks that scope them
+	pythonObjectOwnership = objectOwnershipCheck{
+		lookup:  regexp.MustCompile(`\.objects\.(get|filter|get_or_create)\([^)]*\b(pk|id)\s*=\s*(request\.|self\.kwargs)|get_object_or_404\(\s*\w+\s*,[^)]*\b(pk|id)\s*=\s*(request\.|self\.kwargs)|\.query\.(get|get_or_404)\(\s*request\.`),
+		scope:   regexp.MustCompile(`request\.user|current_user|\b(owner|user|user_id|owner_id|author|created_by)\w*\s*=|has_perm|has_object_permission|check_object_permissions`),
+		message: "Object looked up by a request id without a user filter - scope the query to request.user or check object permissions (IDOR, heuristic)",
+	}
+	// Mongoose, Sequelize and Prisma lookups keyed by request data, and the
+	// ownership checks that follow them
+	expressObjectOwnership = objectOwnershipCheck{
+		lookup:  regexp.MustCompile(`\.(findById|findByPk|findByIdAndUpdate|findByIdAndDelete|findOne|findUnique|findFirst)\s*\([^)]*\breq\.(params|query|body)\.`),
+		scope:   regexp.MustCompile(`req\.user|res\.locals\.user|\b(owner|user|userId|user_id|ownerId|owner_id|author|createdBy)\b\s*(:|===|!==|==|!=)|authorize|\.can\(`),
+		message: "Object looked up by a request id without an ownership check - compare its owner to req.user or add it to the query (IDOR, heuristic)",
+	}
+)
+
+// checkObjectOwnership flags records loaded directly by a request supplied id with
+// no user filter on the lookup and no ownership check in the lines that follow.
+// Authorization may live in middleware or a policy layer, so findings are low
+// confidence.
+func (a *Analyzer) checkObjectOwnership(file string, lines []string, check objectOwnershipCheck, report *Report) {
+	for i, line := range lines {
+		if !check.lookup.MatchString(line) {
+			continue
+		}
+
+		scoped := false
+		for j := i; j < len(lines) && j <= i+objectOwnershipWindow; j++ {
+			scoped = scoped || check.scope.MatchString(lines[j])
+		}
+		if scoped {
+			continue
+		}
+
+		// SECURITY: Check for insecure direct object references
+		report.AddIssue(Issue{
+			Type:     "security",
+			Severity: "medium",
+			Message:  check.message,
+			File:     file,
+			Line:     i + 1,
+			RuleID:   "unscoped-find",
+		})
+	}
+}
@@ -0,0 +1,113 @@
+package review
+
+import (
+	"regexp"
+	"strings"
+)
+
+// inputLimitCheck describes how one language reads request input without bounds
+type inputLimitCheck struct {
+	// regexFromInput matches a regular expression compiled from request input
+	regexFromInput *regexp.Regexp
+	// bodyRead matches reading a whole request body into memory
+	bodyRead *regexp.Regexp
+	// bodyLimit matches a size limit anywhere in the file that bounds bodyRead
+	bodyLimit *regexp.Regexp
+}
+
+var (
+	// inputLimitChecks are keyed by language. TypeScript's non-literal RegExp is
+	// reported by its own non-literal-regexp rule.
+	inputLimitChecks = map[string]inputLimitCheck{
+		"javascript": {
+			regexFromInput: regexp.MustCompile(`RegExp\s*\(\s*req\.(query|body|params)`),
+			bodyRead:       regexp.MustCompile(`req\.on\s*\(\s*["']data["']`),
+			bodyLimit:      regexp.MustCompile(`(?i)\.length\s*>|content-length|max_?(body|size|bytes)|limit`),
+		},
+		"typescript": {
+			bodyRead:  regexp.MustCompile(`req\.on\s*\(\s*["']data["']`),
+			bodyLimit: regexp.MustCompile(`(?i)\.length\s*>|content-length|max_?(body|size|bytes)|limit`),
+		},
+		"python": {
+			regexFromInput: regexp.MustCompile(`re\.(compile|match|search|fullmatch|findall|sub)\s*\(\s*request\.`),
+			bodyRead:       regexp.MustCompile(`request\.(stream\.)?read\(\s*\)|request\.get_data\(|(rfile|wsgi_input)\.read\(\s*\)`),
+			bodyLimit:      regexp.MustCompile(`(?i)max_content_length|data_upload_max_memory_size|content.length|limit`),
+		},
+		"ruby": {
+			regexFromInput: regexp.MustCompile(`Regexp\.new\s*\(\s*params\[`),
+			bodyRead:       regexp.MustCompile(`request\.body\.read\b\s*(\(\s*\))?\s*$|request\.raw_post\b`),
+			bodyLimit:      regexp.MustCompile(`(?i)content_length|max_size|limit`),
+		},
+		"php": {
+			regexFromInput: regexp.MustCompile(`preg_(match|match_all|replace|split)\s*\(\s*\$_(GET|POST|REQUEST)`),
+		},
+		"go": {
+			regexFromInput: regexp.MustCompile(`regexp\.(MustCompile|Compile)\s*\(\s*(r|req)\.(FormValue|URL\.Query|PostFormValue)`),
+			bodyRead:       regexp.MustCompile(`(io|ioutil)\.ReadAll\s*\(\s*(r|req|request)\.Body\s*\)`),
+			bodyLimit:      regexp.MustCompile(`MaxBytesReader|LimitReader`),
+		},
+	}
+	// Escaping that makes user input safe to embed in a regular expression
+	regexEscapePattern = regexp.MustCompile(`(?i)escape|QuoteMeta|preg_quote`)
+	// Infinite loops across languages
+	infiniteLoopPattern = regexp.MustCompile(`while\s*\(\s*(true|1)\s*\)|while\s+(True|1)\s*:|^\s*(loop|for)\s*\{\s*$|^\s*loop\s+do\b`)
+	// Reads from a socket, stream or standard input
+	inputReadPattern = regexp.MustCompile(`\.(read|recv|readline|readLine|ReadString|ReadBytes|Read|gets)\s*\(|\binput\s*\(|\bgets\b`)
+	// Growing a buffer with what was read
+	bufferGrowPattern = regexp.MustCompile(`\+=|append\s*\(|\.push\s*\(|\.extend\s*\(|<<|\.concat\s*\(|\.write\s*\(`)
+	// Guards that bound a buffer's size
+	sizeGuardPattern = regexp.MustCompile(`(?i)\blen\s*\(|\.length\b|\.size\b|\bsize\b|limit|max`)
+)
+
+// inputLoopWindow is how many lines after an infinite loop are treated as its body
+const inputLoopWindow = 10
+
+// checkInputLimits flags request input consumed without a size bound: regular
+// expressions compiled from input (ReDoS), whole request bodies read into
+// memory and infinite loops that keep appending input to a buffer. The checks
+// cannot see limits configured elsewhere, so findings are low confidence.
+func (a *Analyzer) checkInputLimits(file string, contentStr string, lines []string, report *Report) {
+	check := inputLimitChecks[LanguageForFile(file)]
+	bodyLimited := check.bodyLimit != nil && check.bodyLimit.MatchString(contentStr)
+
+	for i, line := range lines {
+		// SECURITY: Check for regular expressions compiled from request input (ReDoS)
+		if check.regexFromInput != nil && check.regexFromInput.MatchString(line) && !regexEscapePattern.MatchString(line) {
+			report.AddIssue(Issue{
+				Type:     "security",
+				Severity: "medium",
+				Message:  "Regular expression built from request input - potential ReDoS, escape the input or bound its length (heuristic)",
+				File:     file,
+				Line:     i + 1,
+				RuleID:   "regex-from-input",
+			})
+		}
+
+		// SECURITY: Check for request bodies read without a size limit
+		if check.bodyRead != nil && !bodyLimited && check.bodyRead.MatchString(line) {
+			report.AddIssue(Issue{
+				Type:     "security",
+				Severity: "medium",
+				Message:  "Request body read without a size limit - a large body can exhaust memory, cap it (heuristic)",
+				File:     file,
+				Line:     i + 1,
+				RuleID:   "unbounded-body-read",
+			})
+		}
+
+		// SECURITY: Check for infinite loops accumulating input without a bound
+		if infiniteLoopPattern.MatchString(line) {
+			body := strings.Join(lines[i+1:min(i+1+inputLoopWindow, len(lines))], "\n")
+			if inputReadPattern.MatchString(body) && bufferGrowPattern.MatchString(body) && !sizeGuardPattern.MatchString(body) {
+				report.AddIssue(Issue{
+					Type:     "security",
+					Severity: "medium",
+					Message:  "Infinite loop accumulates input without a size bound - potential memory exhaustion, stop at a maximum size (heuristic)",
+					File:     file,
+					Line:     i + 1,
+					RuleID:   "unbounded-read-loop",
+				})
+			}
+		}
+	}
+}
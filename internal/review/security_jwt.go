@@ -0,0 +1,79 @@
+package review
+
+import "regexp"
+
+// jwtCheck pairs a JWT misconfiguration pattern with the message reported for it
+type jwtCheck struct {
+	pattern *regexp.Regexp
+	message string
+}
+
+const (
+	jwtNoneMessage       = "JWT \"none\" algorithm accepted - unsigned tokens pass verification, allow only the algorithm you sign with"
+	jwtUnverifiedMessage = "JWT decoded without verifying its signature - anyone can forge the claims, verify with the signing key"
+	jwtConfusionMessage  = "JWT verification accepts both HMAC and public key algorithms - an attacker can sign an HS256 token with the public key (algorithm confusion)"
+)
+
+var (
+	// A quoted "none" algorithm in an algorithms allowlist or alg setting, in any case
+	jwtNoneAlgorithmPattern = regexp.MustCompile(`(?i)(\balgorithms?\b["']?\s*[:=]\s*\[?[^\]\n]*|["']alg["']\s*:\s*)["']none["']`)
+	// An algorithms allowlist mixing HS* with RS*, ES* or PS*, in either order
+	jwtMixedAlgorithmsPattern = regexp.MustCompile(`\balgorithms["']?\s*[:=]\s*\[[^\]]*(["']HS\d+["'][^\]]*["'](RS|ES|PS)\d+["']|["'](RS|ES|PS)\d+["'][^\]]*["']HS\d+["'])`)
+)
+
+// insecureJWTChecks are keyed by language. Algorithm allowlists look alike
+// across libraries; the verification switches are library specific.
+var insecureJWTChecks = map[string][]jwtCheck{
+	"python": {
+		{jwtNoneAlgorithmPattern, jwtNoneMessage},
+		{regexp.MustCompile(`["']verify_signature["']\s*:\s*False\b|\bjwt\.decode\s*\(.*\bverify\s*=\s*False\b`), jwtUnverifiedMessage},
+		{jwtMixedAlgorithmsPattern, jwtConfusionMessage},
+		// HMAC verification keyed with an RSA or EC public key
+		{regexp.MustCompile(`(?i)\bjwt\.decode\s*\(.*public_?key.*["']HS\d+["']`), jwtConfusionMessage},
+	},
+	"javascript": {
+		{jwtNoneAlgorithmPattern, jwtNoneMessage},
+		{jwtMixedAlgorithmsPattern, jwtConfusionMessage},
+		{regexp.MustCompile(`(?i)\bjwt\.verify\s*\(.*public_?key.*["']HS\d+["']`), jwtConfusionMessage},
+	},
+	"typescript": {
+		{jwtNoneAlgorithmPattern, jwtNoneMessage},
+		{jwtMixedAlgorithmsPattern, jwtConfusionMessage},
+		{regexp.MustCompile(`(?i)\bjwt\.verify\s*\(.*public_?key.*["']HS\d+["']`), jwtConfusionMessage},
+	},
+	"java": {
+		// auth0 java-jwt, jjwt and Nimbus unsecured tokens
+		{regexp.MustCompile(`\bAlgorithm\.none\s*\(|\bSignatureAlgorithm\.NONE\b|\bnew\s+PlainJWT\b`), jwtNoneMessage},
+		// jjwt parses unsigned tokens with parseClaimsJwt and parseUnsecuredClaims
+		{regexp.MustCompile(`\.(parseClaimsJwt|parsePlaintextJwt|parseUnsecuredClaims|parseUnsecuredContent)\s*\(`), jwtUnverifiedMessage},
+		{regexp.MustCompile(`(?i)\bAlgorithm\.HMAC\d+\s*\(.*public_?key`), jwtConfusionMessage},
+	},
+	"kotlin": {
+		{regexp.MustCompile(`\bAlgorithm\.none\s*\(|\bSignatureAlgorithm\.NONE\b|\bPlainJWT\s*\(`), jwtNoneMessage},
+		{regexp.MustCompile(`\.(parseClaimsJwt|parsePlaintextJwt|parseUnsecuredClaims|parseUnsecuredContent)\s*\(`), jwtUnverifiedMessage},
+		{regexp.MustCompile(`(?i)\bAlgorithm\.HMAC\d+\s*\(.*public_?key`), jwtConfusionMessage},
+	},
+}
+
+// checkInsecureJWT flags JWT verification that accepts unsigned tokens, skips the
+// signature check, or can be tricked into HMAC verification with a public key (CWE-347)
+func (a *Analyzer) checkInsecureJWT(file string, lines []string, report *Report) {
+	checks := insecureJWTChecks[LanguageForFile(file)]
+
+	for i, line := range lines {
+		for _, check := range checks {
+			// SECURITY: Check for tokens accepted without a trustworthy signature
+			if check.pattern.MatchString(line) {
+				report.AddIssue(Issue{
+					Type:     "security",
+					Severity: "high",
+					Message:  check.message,
+					File:     file,
+					Line:     i + 1,
+					RuleID:   "insecure-jwt",
+				})
+				break
+			}
+		}
+	}
+}
@@ -0,0 +1,150 @@
+package review
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+var (
+	// Python's logging.disable at CRITICAL or above, or with no level, which
+	// defaults to CRITICAL and silences every logger
+	pythonLoggingDisablePattern = regexp.MustCompile(`\blogging\.disable\s*\(\s*(\)|logging\.(CRITICAL|FATAL)\b|(CRITICAL|FATAL)\b|sys\.maxsize\b|[5-9]\d+\s*\))`)
+	// A Python logger switched off, e.g. logging.getLogger("audit").disabled = True
+	pythonLoggerDisabledPattern = regexp.MustCompile(`\.disabled\s*=\s*True\b`)
+	// A Python except clause
+	pythonExceptPattern = regexp.MustCompile(`^(\s*)except\b.*:\s*$`)
+	// A JavaScript catch block or promise handler with an empty body
+	jsEmptyCatchPattern = regexp.MustCompile(`\bcatch\s*(\([^)]*\))?\s*\{\s*\}|\.catch\s*\(\s*(\(\s*\w*\s*\)|\w+)\s*=>\s*\{\s*\}\s*\)`)
+	// A config setting, capturing its key and value, in properties, dotenv, INI, TOML, YAML or JSON
+	configKeyValuePattern = regexp.MustCompile(`^(\s*)["']?([\w.$-]+)["']?\s*[:=]\s*(.*?)\s*,?\s*$`)
+)
+
+// loggingKeyOff reports whether a config key sets a log level, such as
+// logging.level.root, LOG_LEVEL or log4j.rootLogger, and its value turns logging off
+func loggingKeyOff(key, value string) bool {
+	key = strings.ToLower(key)
+	if !strings.Contains(key, "log") || !(strings.Contains(key, "level") || strings.HasSuffix(key, "logger")) {
+		return false
+	}
+	// log4j 1.x writes the level before the appenders, e.g. OFF, stdout
+	level, _, _ := strings.Cut(value, ",")
+	level = strings.ToLower(strings.Trim(strings.TrimSpace(level), `"'`))
+	return level == "off" || level == "none"
+}
+
+// checkLoggingLevelOff flags config files that set a log level to OFF or NONE.
+// YAML keys are read with their parents, so logging: level: root: OFF is the key
+// logging.level.root.
+func (a *Analyzer) checkLoggingLevelOff(file string, code []string, report *Report) {
+	ext := strings.ToLower(filepath.Ext(file))
+	yaml := ext == ".yml" || ext == ".yaml"
+
+	// parents holds the indentation and key of each YAML mapping enclosing the line
+	type parent struct {
+		indent int
+		key    string
+	}
+	var parents []parent
+
+	for i, line := range code {
+		m := configKeyValuePattern.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		key, value := m[2], m[3]
+		if yaml {
+			indent := len(m[1])
+			for len(parents) > 0 && parents[len(parents)-1].indent >= indent {
+				parents = parents[:len(parents)-1]
+			}
+			if value == "" {
+				parents = append(parents, parent{indent, key})
+				continue
+			}
+			for j := len(parents) - 1; j >= 0; j-- {
+				key = parents[j].key + "." + key
+			}
+		}
+
+		// SECURITY: Check for logging turned off
+		if loggingKeyOff(key, value) {
+			report.AddIssue(Issue{
+				Type:     "security",
+				Severity: "medium",
+				Message:  fmt.Sprintf("Logging turned off by %s - failed logins and other security events go unrecorded, set a level such as WARN instead", key),
+				File:     file,
+				Line:     i + 1,
+				RuleID:   "logging-disabled",
+			})
+		}
+	}
+}
+
+// checkSuppressedLogging flags code that silences logging or swallows exceptions
+// without logging them, which leaves no trail of attacks or failures. Java and
+// Kotlin report empty catch blocks as empty-catch instead.
+func (a *Analyzer) checkSuppressedLogging(file string, lines []string, report *Report) {
+	language := LanguageForFile(file)
+
+	for i, line := range lines {
+		switch language {
+		case "python":
+			// SECURITY: Check for logging disabled in code
+			if pythonLoggingDisablePattern.MatchString(line) || pythonLoggerDisabledPattern.MatchString(line) {
+				report.AddIssue(Issue{
+					Type:     "security",
+					Severity: "medium",
+					Message:  "Logging disabled in code - security events are no longer recorded, raise the level of noisy loggers instead",
+					File:     file,
+					Line:     i + 1,
+					RuleID:   "logging-disabled",
+				})
+			}
+
+			// Check for except clauses whose only statement is pass
+			if m := pythonExceptPattern.FindStringSubmatch(line); m != nil && pythonOnlyPass(lines, i, len(m[1])) {
+				report.AddIssue(Issue{
+					Type:     "quality",
+					Severity: "low",
+					Message:  "Exception swallowed with pass - log it so failures and attacks leave a trace",
+					File:     file,
+					Line:     i + 1,
+					RuleID:   "swallowed-exception",
+				})
+			}
+		case "javascript", "typescript":
+			// Check for empty catch blocks and promise handlers
+			if jsEmptyCatchPattern.MatchString(line) {
+				report.AddIssue(Issue{
+					Type:     "quality",
+					Severity: "low",
+					Message:  "Error swallowed by an empty catch - log it so failures and attacks leave a trace",
+					File:     file,
+					Line:     i + 1,
+					RuleID:   "swallowed-exception",
+				})
+			}
+		}
+	}
+}
+
+// pythonOnlyPass reports whether the block opened on lines[start], indented by
+// indent, holds nothing but pass
+func pythonOnlyPass(lines []string, start, indent int) bool {
+	statements := 0
+	sawPass := false
+	for _, line := range lines[start+1:] {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" {
+			continue
+		}
+		if len(line)-len(strings.TrimLeft(line, " \t")) <= indent {
+			break
+		}
+		statements++
+		sawPass = sawPass || trimmed == "pass"
+	}
+	return statements == 1 && sawPass
+}
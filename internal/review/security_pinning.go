@@ -0,0 +1,95 @@
+package review
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	// Dart badCertificateCallback that accepts every certificate, e.g. (cert, host, port) => true
+	dartTrustAllCallbackPattern = regexp.MustCompile(`badCertificateCallback\s*=\s*\([^)]*\)\s*(=>\s*true\b|\{\s*return\s+true\s*;\s*\})`)
+	// Android X509TrustManager.checkServerTrusted overrides, in Java or Kotlin
+	checkServerTrustedPattern = regexp.MustCompile(`\b(void|fun)\s+checkServerTrusted\s*\(`)
+	// iOS URLSession authentication challenge delegate methods
+	urlSessionChallengePattern = regexp.MustCompile(`\bfunc\s+urlSession\s*\(.*\bdidReceive\s+\w+\s*:\s*URLAuthenticationChallenge`)
+	// Answering a challenge with the server's own trust, which accepts it as valid
+	swiftTrustCredentialPattern = regexp.MustCompile(`URLCredential\s*\(\s*trust\s*:`)
+	// Evaluating the server trust, or comparing it to pinned keys or certificates
+	swiftTrustEvaluationPattern = regexp.MustCompile(`\bSecTrustEvaluate|\bSecTrustCopy|\bSecCertificateCopyData\b|(?i)\bpinned`)
+)
+
+// checkTrustAllCertificates flags TLS certificate validation replaced by an
+// implementation that trusts every certificate, which defeats any pinning and
+// lets anyone on the network intercept traffic (CWE-295)
+func (a *Analyzer) checkTrustAllCertificates(file string, lines []string, report *Report) {
+	for i, line := range lines {
+		var message string
+		switch LanguageForFile(file) {
+		case "dart":
+			if dartTrustAllCallbackPattern.MatchString(line) {
+				message = "badCertificateCallback returns true - every certificate is accepted, validate or pin the certificate instead"
+			}
+		case "java", "kotlin":
+			if body, ok := functionBody(lines, i); ok && checkServerTrustedPattern.MatchString(line) && emptyFunctionBody(body) {
+				message = "Empty checkServerTrusted implementation - every certificate is trusted, use the platform trust manager or a network security config pin"
+			}
+		case "swift":
+			if urlSessionChallengePattern.MatchString(line) {
+				if body, _ := functionBody(lines, i); swiftTrustCredentialPattern.MatchString(body) && !swiftTrustEvaluationPattern.MatchString(body) {
+					message = "urlSession(_:didReceive:completionHandler:) trusts the server without evaluating it - call SecTrustEvaluateWithError or compare against pinned keys"
+				}
+			}
+		}
+
+		// SECURITY: Check for certificate validation that trusts everything
+		if message != "" {
+			report.AddIssue(Issue{
+				Type:     "security",
+				Severity: "high",
+				Message:  message,
+				File:     file,
+				Line:     i + 1,
+				RuleID:   "trust-all-certificates",
+			})
+		}
+	}
+}
+
+// functionBody returns the text between the braces of the function declared on
+// lines[start], joined across lines. It reports false for declarations without
+// a body, such as interface methods.
+func functionBody(lines []string, start int) (string, bool) {
+	var body strings.Builder
+	depth := 0
+	for _, line := range lines[start:] {
+		for _, c := range asyncScopeStringPattern.ReplaceAllString(line, `""`) {
+			switch {
+			case c == ';' && depth == 0:
+				return "", false
+			case c == '{':
+				depth++
+				if depth == 1 {
+					continue
+				}
+			case c == '}':
+				depth--
+				if depth == 0 {
+					return body.String(), true
+				}
+			}
+			if depth > 0 {
+				body.WriteRune(c)
+			}
+		}
+		if depth > 0 {
+			body.WriteByte('\n')
+		}
+	}
+	return "", false
+}
+
+// emptyFunctionBody reports whether a function body does nothing but return
+func emptyFunctionBody(body string) bool {
+	body = strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(body), ";"))
+	return body == "" || body == "return"
+}
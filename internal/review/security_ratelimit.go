@@ -0,0 +1,60 @@
+package review
+
+import "regexp"
+
+// authRateLimitCheck describes how one framework declares login endpoints and rate limiters
+type authRateLimitCheck struct {
+	// route matches a line that declares an authentication endpoint
+	route *regexp.Regexp
+	// limiter matches rate limiting anywhere in the file
+	limiter *regexp.Regexp
+	// severity is low where limiting usually lives in another file, medium otherwise
+	severity string
+	message  string
+}
+
+var (
+	// Express login routes and their rate limiting middleware
+	expressAuthRateLimit = authRateLimitCheck{
+		route:    regexp.MustCompile("(?i)\\b(app|router|server)\\.(post|put|all)\\s*\\(\\s*['\"`][^'\"`]*(log-?in|sign-?in|auth|session|token|password)"),
+		limiter:  regexp.MustCompile(`(?i)rate-?limit|slow-?down|limiter|throttl|brute`),
+		severity: "medium",
+		message:  "Login route without rate limiting - add express-rate-limit or similar to slow brute-force attempts (heuristic)",
+	}
+	// Django and Flask login views and their throttling
+	pythonAuthRateLimit = authRateLimitCheck{
+		route:    regexp.MustCompile(`(?i)^\s*def\s+(log_?in|sign_?in|authenticate|obtain_token)\w*\s*\(|^\s*class\s+\w*(login|signin|tokenobtain)\w*\s*\(|@\w+\.(route|post)\(\s*['"][^'"]*(login|signin|sign-in|token)`),
+		limiter:  regexp.MustCompile(`(?i)throttl|ratelimit|rate_limit|limiter|axes`),
+		severity: "low",
+		message:  "Login view without throttling - add throttle_classes, django-ratelimit or Flask-Limiter to slow brute-force attempts (heuristic)",
+	}
+	// Rails session routes and controllers, and Rack::Attack or rate_limit
+	railsAuthRateLimit = authRateLimitCheck{
+		route:    regexp.MustCompile(`(?i)^\s*(post|match)\s+['"][^'"]*(login|sign_?in|session)|^\s*class\s+\w*Sessions?Controller\b`),
+		limiter:  regexp.MustCompile(`(?i)rack::attack|throttl|rate_limit`),
+		severity: "low",
+		message:  "Login endpoint without rate limiting - add Rack::Attack or rate_limit to slow brute-force attempts (heuristic)",
+	}
+)
+
+// checkAuthRateLimiting flags authentication endpoints in files that never mention
+// rate limiting. Limiting is often configured elsewhere, so findings are low confidence.
+func (a *Analyzer) checkAuthRateLimiting(file string, contentStr string, lines []string, check authRateLimitCheck, report *Report) {
+	// SECURITY: Check for login endpoints without brute-force protection
+	if check.limiter.MatchString(contentStr) {
+		return
+	}
+
+	for i, line := range lines {
+		if check.route.MatchString(line) {
+			report.AddIssue(Issue{
+				Type:     "security",
+				Severity: check.severity,
+				Message:  check.message,
+				File:     file,
+				Line:     i + 1,
+				RuleID:   "auth-no-rate-limit",
+			})
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package review
+
+import "regexp"
+
+// hardcodedSaltPatterns match key derivation and password hashing calls whose salt
+// argument is a string literal, keyed by language. Salts from os.urandom,
+// crypto.randomBytes, bcrypt.gensalt and the like are not literals and do not match.
+var hardcodedSaltPatterns = map[string]*regexp.Regexp{
+	"python":     regexp.MustCompile(`hashlib\.pbkdf2_hmac\s*\(\s*[^,]+,\s*[^,]+,\s*[bB]?["']|\b(hashlib\.scrypt|PBKDF2HMAC|Scrypt|PBKDF2)\s*\(.*\bsalt\s*=\s*[bB]?["']|bcrypt\.hashpw\s*\(\s*[^,]+,\s*[bB]?["']`),
+	"javascript": regexp.MustCompile("crypto\\.(pbkdf2|scrypt)(Sync)?\\s*\\(\\s*[^,]+,\\s*[\"'`]|bcrypt(js)?\\.hash(Sync)?\\s*\\(\\s*[^,]+,\\s*[\"'`]"),
+	"typescript": regexp.MustCompile("crypto\\.(pbkdf2|scrypt)(Sync)?\\s*\\(\\s*[^,]+,\\s*[\"'`]|bcrypt(js)?\\.hash(Sync)?\\s*\\(\\s*[^,]+,\\s*[\"'`]"),
+	"go":         regexp.MustCompile(`\b(pbkdf2|scrypt|argon2)\.(Key|IDKey)\s*\(\s*[^,]+,\s*(\[\]byte\(\s*)?"`),
+	"ruby":       regexp.MustCompile(`OpenSSL::PKCS5\.pbkdf2_hmac\w*\s*\(\s*[^,]+,\s*["']|BCrypt::Engine\.hash_secret\s*\(\s*[^,]+,\s*["']|OpenSSL::KDF\.\w+\s*\(.*\bsalt:\s*["']`),
+	"php":        regexp.MustCompile(`hash_pbkdf2\s*\(\s*[^,]+,\s*[^,]+,\s*["']|\bcrypt\s*\(\s*[^,]+,\s*["']|['"]salt['"]\s*=>\s*["']`),
+	"java":       regexp.MustCompile(`new\s+PBEKeySpec\s*\(\s*[^,]+,\s*"[^"]*"\s*\.getBytes|new\s+PBEParameterSpec\s*\(\s*"[^"]*"\s*\.getBytes`),
+	"kotlin":     regexp.MustCompile(`PBEKeySpec\s*\(\s*[^,]+,\s*"[^"]*"\s*\.(toByteArray|getBytes)|PBEParameterSpec\s*\(\s*"[^"]*"\s*\.(toByteArray|getBytes)`),
+}
+
+var (
+	// Salt and pepper variables and keys set to a string literal, e.g. SALT = "s3cr3t"
+	saltConstantPattern = regexp.MustCompile("(?i)\\b\\w*(salt|pepper)\\w*[\"']?\\s*(:=|=>|=|:)\\s*[bB]?[\"'`]([^\"'`]{2,})[\"'`]")
+	// Hashing calls with a literal concatenated onto the password, i.e. a hardcoded pepper
+	pepperConcatPattern = regexp.MustCompile("(?i)(hash|digest|sha\\d*|md5|bcrypt|pbkdf2|scrypt|argon2)\\w*\\s*\\(.*(passw\\w*(\\(\\))?\\s*(\\+|\\.)\\s*[bB]?[\"'`][^\"'`]+[\"'`]|[\"'`][^\"'`]+[\"'`]\\s*(\\+|\\.)\\s*\\$?passw)")
+	// Literal values that are sizes or round counts rather than salts
+	saltSizePattern = regexp.MustCompile(`^\d+$`)
+)
+
+// checkHardcodedSalts flags password hashing with a fixed salt or pepper. A salt
+// shared by every password lets precomputed tables crack them all at once (CWE-760).
+func (a *Analyzer) checkHardcodedSalts(file string, lines []string, report *Report) {
+	pattern := hardcodedSaltPatterns[LanguageForFile(file)]
+
+	for i, line := range lines {
+		hardcoded := pattern != nil && pattern.MatchString(line) || pepperConcatPattern.MatchString(line)
+		if m := saltConstantPattern.FindStringSubmatch(line); m != nil && !saltSizePattern.MatchString(m[3]) {
+			hardcoded = true
+		}
+
+		// SECURITY: Check for salts and peppers fixed in the source
+		if hardcoded {
+			report.AddIssue(Issue{
+				Type:     "security",
+				Severity: "high",
+				Message:  "Hardcoded salt or pepper - generate a random salt per password (os.urandom, crypto.randomBytes, bcrypt.gensalt) and load peppers from a secret store (CWE-760)",
+				File:     file,
+				Line:     i + 1,
+				RuleID:   "hardcoded-salt",
+			})
+		}
+	}
+}
@@ -0,0 +1,37 @@
+package review
+
+import "regexp"
+
+// insecureTransportPatterns match RPC and socket setup that disables TLS or certificate
+// verification, keyed by language. Node's rejectUnauthorized is reported by the
+// ssl-verification-disabled rule of the JavaScript and TypeScript analyzers.
+var insecureTransportPatterns = map[string]*regexp.Regexp{
+	"go":         regexp.MustCompile(`grpc\.WithInsecure\s*\(|insecure\.NewCredentials\s*\(|InsecureSkipVerify\s*:\s*true`),
+	"python":     regexp.MustCompile(`\b(verify_)?ssl\s*=\s*False\b|ssl\.CERT_NONE|check_hostname\s*=\s*False|ssl\._create_unverified_context\s*\(|grpc\.insecure_channel\s*\(|\.add_insecure_port\s*\(`),
+	"javascript": regexp.MustCompile(`credentials\.createInsecure\s*\(|ServerCredentials\.createInsecure\s*\(|checkServerIdentity\s*:\s*\(\)\s*=>\s*(undefined|null)\b`),
+	"shell":      regexp.MustCompile(`\bcurl\b.*\s(-k|--insecure)\b|\bwget\b.*--no-check-certificate`),
+	"typescript": regexp.MustCompile(`credentials\.createInsecure\s*\(|ServerCredentials\.createInsecure\s*\(|checkServerIdentity\s*:\s*\(\)\s*=>\s*(undefined|null)\b`),
+}
+
+// checkInsecureTransport flags gRPC channels, TLS configs and socket wrappers set up without
+// transport security or certificate verification
+func (a *Analyzer) checkInsecureTransport(file string, lines []string, report *Report) {
+	pattern, ok := insecureTransportPatterns[LanguageForFile(file)]
+	if !ok {
+		return
+	}
+
+	for i, line := range lines {
+		// SECURITY: Check for plaintext RPC and disabled certificate verification
+		if pattern.MatchString(line) {
+			report.AddIssue(Issue{
+				Type:     "security",
+				Severity: "high",
+				Message:  "Insecure transport - TLS or certificate verification disabled, vulnerable to man-in-the-middle attacks",
+				File:     file,
+				Line:     i + 1,
+				RuleID:   "insecure-transport",
+			})
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package review
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	// Client-supplied upload filenames across PHP, Flask/Django, multer/express-fileupload and Rails.
+	// A bare .filename is only client-supplied on an upload, see uploadVariablePattern.
+	uploadClientNamePattern = regexp.MustCompile(`\$_FILES\[[^\]]+\]\s*\[\s*["']name["']\s*\]|\.originalname\b|\.original_filename\b|req\.files\.[\w\[\]'"]+\.name\b|request\.FILES\[[^\]]+\]\.name\b|request\.files(\[[^\]]+\]|\.get\s*\([^)]*\))\.filename\b|\breq\.file\.filename\b`)
+	// Variables holding an upload, e.g. f = request.files['doc'] or file: UploadFile,
+	// capturing the name whose .filename is client-supplied
+	uploadVariablePattern = regexp.MustCompile(`\b(\w+)\s*=\s*(flask\.)?request\.files\b|\b(\w+)\s*:\s*UploadFile\b|\bfor\s+(\w+)\s+in\s+request\.files\.getlist\s*\(`)
+	// Calls that persist or move an uploaded file to disk
+	uploadSavePattern = regexp.MustCompile(`move_uploaded_file\s*\(|\.save\s*\(|\.mv\s*\(|fs\.(writeFile|writeFileSync|rename|renameSync|createWriteStream)\s*\(|File\.(open|write|join)\s*\(|FileUtils\.(mv|cp)|os\.path\.join\s*\(|path\.(join|resolve)\s*\(`)
+	// Wrappers that strip directory components or replace the client name entirely
+	uploadSanitizerPattern = regexp.MustCompile(`basename\s*\(|secure_filename\s*\(|get_valid_filename\s*\(|sanitize|uuid|random_bytes\s*\(|uniqid\s*\(|path\.basename\s*\(|File\.basename\s*\(`)
+	// Uploaded content being executed or included: the argument must come from the
+	// upload itself, so require('./routes/upload') is an ordinary import
+	uploadExecPattern = regexp.MustCompile(`\b(include|require|include_once|require_once|system|exec|shell_exec|passthru|popen|execSync|spawn)\s*\(.*(\$_FILES|\btmp_name\b|\breq\.files?\b|\.originalname\b|\.original_filename\b|\brequest\.(files|FILES)\b)|\bchmod\s*\(.*(\$_FILES|\btmp_name\b|\breq\.files?\b|\brequest\.(files|FILES)\b).*(0?7[0-7][0-7]|\+x)`)
+	// Evidence that the upload handler validates the file type somewhere in the file
+	uploadAllowlistPattern = regexp.MustCompile(`(?i)allowed|allowlist|whitelist|mime|content_?type|content-type|extension|pathinfo|endswith|filefilter|getimagesize|finfo|imghdr|file_type`)
+)
+
+// checkUploadHandling flags unsafe handling of uploaded files: client filenames used as
+// paths, uploads executed or included, and upload handlers without a type allowlist
+func (a *Analyzer) checkUploadHandling(file string, contentStr string, lines []string, report *Report) {
+	firstSaveLine := 0
+
+	// .filename is the client's name only on an upload; elsewhere, such as
+	// self.filename, it is the program's own
+	var uploadNames []string
+	for _, line := range lines {
+		for _, m := range uploadVariablePattern.FindAllStringSubmatch(line, -1) {
+			uploadNames = append(uploadNames, regexp.QuoteMeta(m[1]+m[3]+m[4]))
+		}
+	}
+	var uploadFilenamePattern *regexp.Regexp
+	if len(uploadNames) > 0 {
+		uploadFilenamePattern = regexp.MustCompile(`(^|[^\w.])(` + strings.Join(uploadNames, "|") + `)\.filename\b`)
+	}
+	clientName := func(line string) bool {
+		return uploadClientNamePattern.MatchString(line) || uploadFilenamePattern != nil && uploadFilenamePattern.MatchString(line)
+	}
+
+	for i, line := range lines {
+		saves := uploadSavePattern.MatchString(line)
+		if saves && firstSaveLine == 0 && (strings.Contains(line, "$_FILES") || clientName(line)) {
+			firstSaveLine = i + 1
+		}
+
+		// SECURITY: Check for client-provided filenames used to build the destination path
+		if saves && clientName(line) && !uploadSanitizerPattern.MatchString(line) {
+			report.AddIssue(Issue{
+				Type:     "security",
+				Severity: "high",
+				Message:  "Uploaded file saved using the client-provided filename - potential path traversal, generate or sanitize the name",
+				File:     file,
+				Line:     i + 1,
+				RuleID:   "upload-client-filename",
+			})
+		}
+
+		// SECURITY: Check for uploaded files being executed or included
+		if uploadExecPattern.MatchString(line) {
+			report.AddIssue(Issue{
+				Type:     "security",
+				Severity: "high",
+				Message:  "Uploaded file is executed or included - potential remote code execution",
+				File:     file,
+				Line:     i + 1,
+				RuleID:   "upload-executed",
+			})
+		}
+	}
+
+	// SECURITY: Check for upload handlers that never validate extension or content type
+	if firstSaveLine > 0 && !uploadAllowlistPattern.MatchString(contentStr) {
+		report.AddIssue(Issue{
+			Type:     "security",
+			Severity: "medium",
+			Message:  "File upload without an extension/content-type allowlist - restrict accepted file types",
+			File:     file,
+			Line:     firstSaveLine,
+			RuleID:   "upload-no-allowlist",
+		})
+	}
+}
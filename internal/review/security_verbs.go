@@ -0,0 +1,89 @@
+package review
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	// Express routes registered for every method, capturing the path. Catch-all
+	// paths are 404 and CORS handlers, which are meant to see every method.
+	expressAllMethodsPattern = regexp.MustCompile("\\b(app|router|server)\\.all\\s*\\(\\s*['\"`]([^'\"`]*)['\"`]")
+	// A Flask route's methods list, capturing its contents
+	flaskRouteMethodsPattern = regexp.MustCompile(`\.route\s*\(.*\bmethods\s*=\s*[\[(]([^\])]*)[\])]`)
+	// A Spring @RequestMapping, which accepts every method unless given method =
+	springRequestMappingPattern   = regexp.MustCompile(`@RequestMapping\b`)
+	springMethodConstraintPattern = regexp.MustCompile(`\bmethod\s*=`)
+	// The declaration an annotation applies to, when it is a type rather than a handler
+	typeDeclarationPattern = regexp.MustCompile(`\b(class|interface|object)\s+\w`)
+	// A Rails route matched for every method
+	railsMatchAllPattern = regexp.MustCompile(`^\s*match\s+.*\bvia:\s*:all\b|^\s*match\s+.*:via\s*=>\s*:all\b`)
+	// The quoted HTTP methods in a methods list
+	httpMethodPattern = regexp.MustCompile(`["'](\w+)["']`)
+)
+
+// checkAnyHTTPMethod flags routes that accept every HTTP method where a few are
+// meant (CWE-650): access rules written for GET and POST do not cover HEAD, PUT
+// or a made-up verb, which the handler still serves. This is a heuristic, so
+// findings are low severity advisories.
+func (a *Analyzer) checkAnyHTTPMethod(file string, lines []string, report *Report) {
+	language := LanguageForFile(file)
+
+	for i, line := range lines {
+		message := ""
+		switch language {
+		case "javascript", "typescript":
+			if m := expressAllMethodsPattern.FindStringSubmatch(line); m != nil && m[2] != "*" && m[2] != "/*" {
+				message = "Route registered with all() accepts every HTTP method - register only the methods it serves, e.g. app.get()"
+			}
+		case "python":
+			if m := flaskRouteMethodsPattern.FindStringSubmatch(line); m != nil && acceptsEveryMethod(m[1]) {
+				message = "Route accepts GET and every write method - split reads from writes or list only the methods it serves"
+			}
+		case "java", "kotlin":
+			if springRequestMappingPattern.MatchString(line) && !springMethodConstraintPattern.MatchString(line) && !annotatesType(lines, i) {
+				message = "@RequestMapping without method = accepts every HTTP method - use @GetMapping, @PostMapping or set method"
+			}
+		case "ruby":
+			if railsMatchAllPattern.MatchString(line) {
+				message = "Route matched with via: :all accepts every HTTP method - use get, post or list the methods in via:"
+			}
+		}
+		if message == "" {
+			continue
+		}
+
+		// SECURITY: Check for routes without HTTP method restrictions (verb tampering)
+		report.AddIssue(Issue{
+			Type:     "security",
+			Severity: "low",
+			Message:  message + " (heuristic)",
+			File:     file,
+			Line:     i + 1,
+			RuleID:   "any-http-method",
+		})
+	}
+}
+
+// acceptsEveryMethod reports whether a methods list holds GET along with POST,
+// PUT and DELETE, which a route serving reads has no need for
+func acceptsEveryMethod(list string) bool {
+	methods := map[string]bool{}
+	for _, m := range httpMethodPattern.FindAllStringSubmatch(list, -1) {
+		methods[strings.ToUpper(m[1])] = true
+	}
+	return methods["GET"] && methods["POST"] && methods["PUT"] && methods["DELETE"]
+}
+
+// annotatesType reports whether the annotation on lines[start] applies to a
+// class or interface, where @RequestMapping only sets the path prefix
+func annotatesType(lines []string, start int) bool {
+	for _, line := range lines[start+1:] {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "@") {
+			continue
+		}
+		return typeDeclarationPattern.MatchString(trimmed)
+	}
+	return false
+}
@@ -0,0 +1,96 @@
+package review
+
+import (
+	"regexp"
+	"strings"
+)
+
+// xxeCheck pairs an XML parser configuration pattern with the message reported
+// for it. requires limits the check to files using the parser's library, and
+// hardened, when set, marks a file that disables external entities elsewhere.
+type xxeCheck struct {
+	pattern  *regexp.Regexp
+	requires *regexp.Regexp
+	hardened *regexp.Regexp
+	message  string
+}
+
+// insecureXMLChecks are keyed by language. The Java analyzer checks its parser
+// factories itself.
+var insecureXMLChecks = map[string][]xxeCheck{
+	"python": {
+		{
+			pattern: regexp.MustCompile(`\bresolve_entities\s*=\s*True\b`),
+			message: "lxml parser with resolve_entities=True - external entities are expanded (XXE), set resolve_entities=False",
+		},
+		{
+			pattern:  regexp.MustCompile(`\bXMLParser\s*\(`),
+			requires: regexp.MustCompile(`\bimport\s+lxml\b|\bfrom\s+lxml\b`),
+			hardened: regexp.MustCompile(`\bresolve_entities\s*=\s*False\b`),
+			message:  "lxml XMLParser without resolve_entities=False - external entities may be expanded (XXE), pass resolve_entities=False and no_network=True",
+		},
+		{
+			pattern:  regexp.MustCompile(`\bXMLParser\s*\(`),
+			requires: regexp.MustCompile(`\bimport\s+xml\.etree\b|\bfrom\s+xml\.etree\b`),
+			hardened: regexp.MustCompile(`\bdefusedxml\b`),
+			message:  "xml.etree XMLParser on untrusted input - it is open to entity expansion attacks, parse with defusedxml.ElementTree instead",
+		},
+	},
+	"php": {
+		{
+			pattern: regexp.MustCompile(`\bLIBXML_NOENT\b`),
+			message: "LIBXML_NOENT substitutes entities - external entities in the document are loaded (XXE), drop the flag",
+		},
+		{
+			pattern:  regexp.MustCompile(`\bsimplexml_load_(?:string|file)\s*\(|->loadXML\s*\(|\bXMLReader::(?:open|XML)\s*\(`),
+			hardened: regexp.MustCompile(`\blibxml_disable_entity_loader\s*\(\s*true\b`),
+			message:  "XML parsed without libxml_disable_entity_loader(true) - on PHP before 8.0 external entities are loaded (XXE), disable the entity loader first",
+		},
+	},
+	"csharp": {
+		{
+			pattern: regexp.MustCompile(`\bDtdProcessing\s*=\s*DtdProcessing\.Parse\b|\bProhibitDtd\s*=\s*false\b`),
+			message: "XML reader with DTD processing enabled - external entities are resolved (XXE), set DtdProcessing = DtdProcessing.Prohibit",
+		},
+		{
+			pattern: regexp.MustCompile(`\bXmlResolver\s*=\s*new\s+XmlUrlResolver\b`),
+			message: "XmlUrlResolver set on an XML parser - external entities and DTDs are fetched (XXE), set XmlResolver = null",
+		},
+	},
+}
+
+// checkInsecureXML flags XML parsers configured to load external entities
+// (XXE, CWE-611), which lets a crafted document read local files or make
+// requests from the server
+func (a *Analyzer) checkInsecureXML(file string, lines []string, report *Report) {
+	checks := insecureXMLChecks[LanguageForFile(file)]
+	code := strings.Join(lines, "\n")
+
+	var active []xxeCheck
+	for _, check := range checks {
+		if check.requires != nil && !check.requires.MatchString(code) {
+			continue
+		}
+		if check.hardened != nil && check.hardened.MatchString(code) {
+			continue
+		}
+		active = append(active, check)
+	}
+
+	for i, line := range lines {
+		for _, check := range active {
+			// SECURITY: Check for XML parsers that resolve external entities
+			if check.pattern.MatchString(line) {
+				report.AddIssue(Issue{
+					Type:     "security",
+					Severity: "high",
+					Message:  check.message,
+					File:     file,
+					Line:     i + 1,
+					RuleID:   "xxe",
+				})
+				break
+			}
+		}
+	}
+}
@@ -0,0 +1,217 @@
+package review
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// SelectionStep is one decision made while selecting a file for analysis
+type SelectionStep struct {
+	Check  string `json:"check"`
+	Passed bool   `json:"passed"`
+	Detail string `json:"detail"`
+}
+
+// FileSelection explains whether and how a file is analyzed
+type FileSelection struct {
+	File string `json:"file"`
+	// Mode is "diff" or "full-scan"
+	Mode string `json:"mode"`
+	// Selected is true when the file ends up in the report's changed files
+	Selected bool `json:"selected"`
+	// Analyzer is the quality analyzer that handles the file, empty if none
+	Analyzer string `json:"analyzer,omitempty"`
+	// SecurityScanned is true when the security checks read the file
+	SecurityScanned bool `json:"security_scanned"`
+	// Plugins lists the configured plugins that run on the file
+	Plugins []string        `json:"plugins,omitempty"`
+	Steps   []SelectionStep `json:"steps"`
+}
+
+// Verdict summarizes the selection in one sentence
+func (s *FileSelection) Verdict() string {
+	if !s.Selected {
+		return "not analyzed"
+	}
+	parts := []string{}
+	if s.Analyzer != "" {
+		parts = append(parts, "quality checked by the "+s.Analyzer+" analyzer")
+	}
+	if s.SecurityScanned {
+		parts = append(parts, "security scanned")
+	}
+	if len(s.Plugins) > 0 {
+		parts = append(parts, "checked by plugins "+strings.Join(s.Plugins, ", "))
+	}
+	if len(parts) == 0 {
+		return "selected, but no check handles this file"
+	}
+	return strings.Join(parts, ", ")
+}
+
+// ExplainFile walks the same decisions a review run makes for a single file:
+// whether it is part of the diff (or full scan), which ignore pattern excludes it,
+// whether security scanning skips it, and which analyzer and plugins handle it
+func (a *Analyzer) ExplainFile(targetBranch, file string, fullScan bool) (*FileSelection, error) {
+	file = filepath.ToSlash(filepath.Clean(file))
+	selection := &FileSelection{File: file, Mode: "diff"}
+	step := func(check string, passed bool, format string, args ...any) {
+		selection.Steps = append(selection.Steps, SelectionStep{Check: check, Passed: passed, Detail: fmt.Sprintf(format, args...)})
+	}
+
+	if info, err := os.Stat(filepath.Join(a.repoPath, file)); err != nil {
+		if fullScan {
+			step("exists", false, "not found in the repository")
+			return selection, nil
+		}
+		// Deleted files still show up in the diff, so keep going
+		step("exists", false, "not found in the repository; analyzers skip files they cannot read")
+	} else if info.IsDir() {
+		step("exists", false, "is a directory; only files are analyzed")
+		return selection, nil
+	} else {
+		step("exists", true, "found (%d bytes)", info.Size())
+	}
+
+	if fullScan {
+		selection.Mode = "full-scan"
+		if !isFullScanFile(file) {
+			step("full scan", false, "extension %q is not collected by a full scan (%s)", filepath.Ext(file), strings.Join(fullScanExtensions, " "))
+			return selection, nil
+		}
+		if slices.Contains(strings.Split(file, "/"), ".git") {
+			step("full scan", false, "inside a .git directory")
+			return selection, nil
+		}
+		if repo := a.nestedRepository(file); repo != "" && !a.includeSubmodules {
+			step("full scan", false, "inside submodule or nested repository %s (skipped unless submodules are included)", repo)
+			return selection, nil
+		}
+		if ignored := a.gitIgnoredPaths(); ignored[file] || slices.ContainsFunc(parentDirs(file), func(dir string) bool { return ignored[dir] }) {
+			step("full scan", false, "ignored by git (.gitignore or another exclude file)")
+			return selection, nil
+		}
+		step("full scan", true, "extension %q is collected by a full scan", filepath.Ext(file))
+	} else {
+		files, err := a.diffFiles(targetBranch)
+		if err != nil {
+			return nil, err
+		}
+		if !slices.Contains(files, file) {
+			step("git diff", false, "not changed %s", a.target.description)
+			return selection, nil
+		}
+		step("git diff", true, "changed %s", a.target.description)
+	}
+
+	if pattern, ignored := a.matchIgnorePattern(file); ignored {
+		step("ignore patterns", false, "matched %q from %s", pattern.Pattern, pattern.Source)
+		return selection, nil
+	}
+	step("ignore patterns", true, "no ignore pattern matches (%d checked)", len(a.ignorePatterns))
+	if len(a.includeRules) > 0 {
+		if !a.matchIncludePattern(file) {
+			step("include patterns", false, "matches none of the %d include patterns", len(a.includeRules))
+			return selection, nil
+		}
+		step("include patterns", true, "matches an include pattern")
+	}
+	selection.Selected = true
+
+	steps, verdict := a.selectFile(file)
+	selection.Steps = append(selection.Steps, steps...)
+	selection.SecurityScanned = verdict.security
+	if verdict.check != nil {
+		selection.Analyzer = verdict.analyzer
+	}
+	selection.Plugins = verdict.plugins
+
+	return selection, nil
+}
+
+// fileVerdict is what a run does with one of the report's changed files
+type fileVerdict struct {
+	// security is true when the security checks scan the file
+	security     bool
+	securitySkip string
+	// analyzer and check are the quality analyzer that runs on the file; check
+	// is nil when none does, with qualitySkip saying why if the file was passed over
+	analyzer    string
+	check       func(string, *Report)
+	qualitySkip string
+	// reportSkip is true when the skip is also reported as a file-skipped issue
+	reportSkip bool
+	plugins    []string
+}
+
+// selectFile decides which checks run on a selected file, explaining each
+// decision. Review runs, why and dry runs all go through it, so they agree.
+func (a *Analyzer) selectFile(file string) ([]SelectionStep, fileVerdict) {
+	var steps []SelectionStep
+	step := func(check string, passed bool, format string, args ...any) {
+		steps = append(steps, SelectionStep{Check: check, Passed: passed, Detail: fmt.Sprintf(format, args...)})
+	}
+	verdict := fileVerdict{}
+	unreadable := a.unreadableReason(file)
+
+	switch {
+	case !a.runsCategory(CategorySecurity):
+		step("security scan", false, "the security category is not selected")
+	case securitySkipReason(file) != "":
+		verdict.securitySkip = securitySkipReason(file)
+		step("security scan", false, "skipped: %s", verdict.securitySkip)
+	case unreadable != "":
+		verdict.securitySkip = unreadable
+		step("security scan", false, "skipped: %s", unreadable)
+	default:
+		verdict.security = true
+		step("security scan", true, "will be scanned for secrets")
+	}
+
+	name, check := a.qualityCheckFor(file)
+	switch {
+	case !a.runsCategory(CategoryQuality) && !a.runsCategory(CategoryPerformance):
+		step("analyzer", false, "the quality and performance categories are not selected")
+	case check == nil:
+		verdict.qualitySkip = "unsupported file type"
+		step("analyzer", false, "no analyzer handles %q files", filepath.Base(file))
+	case unreadable != "":
+		verdict.qualitySkip = unreadable
+		verdict.reportSkip = unreadable == binaryFileReason
+		step("analyzer", false, "%s analyzer skips it: %s", name, unreadable)
+	case a.generatedReason(file) != "":
+		verdict.qualitySkip = a.generatedReason(file)
+		verdict.reportSkip = true
+		step("analyzer", false, "%s analyzer skips it as generated or minified: %s", name, verdict.qualitySkip)
+	default:
+		verdict.analyzer, verdict.check = name, check
+		step("analyzer", true, "handled by the %s analyzer", name)
+	}
+
+	if a.runsCategory(CategoryQuality) {
+		for _, plugin := range a.plugins {
+			if plugin.matches(file) {
+				verdict.plugins = append(verdict.plugins, plugin.Name)
+			}
+		}
+		if len(a.plugins) > 0 {
+			step("plugins", len(verdict.plugins) > 0, "%d of %d plugins match", len(verdict.plugins), len(a.plugins))
+		}
+	}
+
+	return steps, verdict
+}
+
+// parentDirs returns the directories containing file, each with a trailing "/"
+func parentDirs(file string) []string {
+	dirs := []string{}
+	for i, r := range file {
+		if r == '/' {
+			dirs = append(dirs, file[:i+1])
+		}
+	}
+	return dirs
+}
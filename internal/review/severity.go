@@ -0,0 +1,46 @@
+package review
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Severity levels, from most to least severe
+const (
+	SeverityCritical = "critical"
+	SeverityHigh     = "high"
+	SeverityMedium   = "medium"
+	SeverityLow      = "low"
+	SeverityInfo     = "info"
+)
+
+// Severities lists the severity levels from most to least severe
+var Severities = []string{SeverityCritical, SeverityHigh, SeverityMedium, SeverityLow, SeverityInfo}
+
+// severityRank orders severities from least to most severe
+var severityRank = map[string]int{
+	SeverityInfo:     1,
+	SeverityLow:      2,
+	SeverityMedium:   3,
+	SeverityHigh:     4,
+	SeverityCritical: 5,
+}
+
+// ValidSeverity reports whether s is a known severity level
+func ValidSeverity(s string) bool {
+	_, ok := severityRank[s]
+	return ok
+}
+
+// validSeveritiesText lists the severities for error messages
+func validSeveritiesText() string {
+	return strings.Join(Severities[:len(Severities)-1], ", ") + " or " + Severities[len(Severities)-1]
+}
+
+// checkSeverity returns an error for unknown severity strings
+func checkSeverity(s string) error {
+	if !ValidSeverity(s) {
+		return fmt.Errorf("unknown severity %q (expected %s)", s, validSeveritiesText())
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+package review
+
+import (
+	"regexp"
+	"strings"
+)
+
+// suppressionPattern matches an autoreview-ignore comment, capturing the optional
+// comma-separated issue types or rule IDs it is limited to, e.g.
+// "// autoreview-ignore: security" or "# autoreview-ignore: sql-injection, eval"
+var suppressionPattern = regexp.MustCompile(`(?://|#|/\*|--)\s*autoreview-ignore\b(?:\s*:\s*([\w-]+(?:\s*,\s*[\w-]+)*))?`)
+
+// suppression is an autoreview-ignore directive found on a line
+type suppression struct {
+	// targets are the issue types and rule IDs silenced; empty silences everything
+	targets map[string]bool
+	// standalone is set when the comment is the whole line, so it also covers the next line
+	standalone bool
+}
+
+// silences reports whether the directive covers issue
+func (s suppression) silences(issue Issue) bool {
+	return len(s.targets) == 0 || s.targets[issue.Type] || (issue.RuleID != "" && s.targets[issue.RuleID])
+}
+
+// parseSuppression returns the autoreview-ignore directive on line, if any
+func parseSuppression(line string) (suppression, bool) {
+	loc := suppressionPattern.FindStringSubmatchIndex(line)
+	if loc == nil {
+		return suppression{}, false
+	}
+	s := suppression{standalone: strings.TrimSpace(line[:loc[0]]) == ""}
+	if loc[2] >= 0 {
+		s.targets = map[string]bool{}
+		for _, target := range strings.Split(line[loc[2]:loc[3]], ",") {
+			s.targets[strings.ToLower(strings.TrimSpace(target))] = true
+		}
+	}
+	return s, true
+}
+
+// applySuppressions drops issues silenced by an autoreview-ignore comment at the
+// end of their line, or on a comment line of its own just above it. Issues
+// without a line number are reported for the whole file and are kept.
+func (a *Analyzer) applySuppressions(report *Report) {
+	files := map[string][]string{}
+	linesOf := func(file string) []string {
+		lines, ok := files[file]
+		if !ok {
+			if content, err := a.readFile(file); err == nil {
+				lines = strings.Split(string(content), "\n")
+			}
+			files[file] = lines
+		}
+		return lines
+	}
+
+	before := len(report.Issues)
+	report.FilterIssues(func(issue Issue) bool {
+		if issue.Line < 1 {
+			return true
+		}
+		lines := linesOf(issue.File)
+		if issue.Line > len(lines) {
+			return true
+		}
+		if s, ok := parseSuppression(lines[issue.Line-1]); ok && s.silences(issue) {
+			return false
+		}
+		if issue.Line > 1 {
+			if s, ok := parseSuppression(lines[issue.Line-2]); ok && s.standalone && s.silences(issue) {
+				return false
+			}
+		}
+		return true
+	})
+	if suppressed := before - len(report.Issues); suppressed > 0 {
+		a.log.Infof("Suppressed %d issues with autoreview-ignore comments", suppressed)
+	}
+}
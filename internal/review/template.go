@@ -0,0 +1,121 @@
+package review
+
+import (
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"text/template"
+)
+
+// ReportTemplate renders a report with a user-supplied text/template. The
+// template is executed with the *Report, so it can range over .Issues and read
+// .Summary, plus the helper functions in templateFuncs.
+type ReportTemplate struct {
+	tmpl *template.Template
+}
+
+// IssueGroup is a set of issues sharing the value of the field they were grouped by
+type IssueGroup struct {
+	Key    string
+	Issues []Issue
+}
+
+// templateFuncs are the helpers available to report templates
+var templateFuncs = template.FuncMap{
+	// atLeast keeps the issues at or above a severity, e.g. {{range atLeast "high" .Issues}}
+	"atLeast": func(severity string, issues []Issue) ([]Issue, error) {
+		if err := checkSeverity(severity); err != nil {
+			return nil, err
+		}
+		return filterIssues(issues, func(issue Issue) bool { return severityRank[issue.Severity] >= severityRank[severity] }), nil
+	},
+	// severity keeps the issues of exactly one severity
+	"severity": func(severity string, issues []Issue) ([]Issue, error) {
+		if err := checkSeverity(severity); err != nil {
+			return nil, err
+		}
+		return filterIssues(issues, func(issue Issue) bool { return issue.Severity == severity }), nil
+	},
+	"groupBy": groupIssues,
+	"upper":   strings.ToUpper,
+	"lower":   strings.ToLower,
+	"join":    strings.Join,
+}
+
+// issueGroupFields are the fields groupBy accepts and how to read them
+var issueGroupFields = map[string]func(Issue) string{
+	"file":     func(issue Issue) string { return issue.File },
+	"severity": func(issue Issue) string { return issue.Severity },
+	"type":     func(issue Issue) string { return issue.Type },
+	"rule":     func(issue Issue) string { return issue.RuleID },
+	"category": func(issue Issue) string { return issue.Category },
+	"effort":   func(issue Issue) string { return issue.Effort },
+}
+
+// ParseReportTemplate loads a template file. The template is checked by rendering
+// an empty report, so misspelled fields are reported here rather than after a run.
+func ParseReportTemplate(path string) (*ReportTemplate, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read output template: %w", err)
+	}
+	tmpl, err := template.New(filepath.Base(path)).Funcs(templateFuncs).Parse(string(content))
+	if err != nil {
+		return nil, fmt.Errorf("invalid output template: %w", err)
+	}
+	t := &ReportTemplate{tmpl: tmpl}
+	if err := t.Execute(io.Discard, NewReport()); err != nil {
+		return nil, fmt.Errorf("invalid output template: %w", err)
+	}
+	return t, nil
+}
+
+// Execute renders report with the template
+func (t *ReportTemplate) Execute(w io.Writer, report *Report) error {
+	return t.tmpl.Execute(w, report)
+}
+
+// groupIssues groups issues by file, severity, type, rule, category or effort.
+// Severity groups run from most to least severe, other groups are sorted by key.
+func groupIssues(field string, issues []Issue) ([]IssueGroup, error) {
+	key, ok := issueGroupFields[field]
+	if !ok {
+		return nil, fmt.Errorf("cannot group issues by %q (expected one of %s)", field, strings.Join(slices.Sorted(maps.Keys(issueGroupFields)), ", "))
+	}
+
+	var groups []IssueGroup
+	index := map[string]int{}
+	for _, issue := range issues {
+		k := key(issue)
+		i, seen := index[k]
+		if !seen {
+			i = len(groups)
+			index[k] = i
+			groups = append(groups, IssueGroup{Key: k})
+		}
+		groups[i].Issues = append(groups[i].Issues, issue)
+	}
+
+	slices.SortFunc(groups, func(a, b IssueGroup) int {
+		if field == "severity" {
+			return severityRank[b.Key] - severityRank[a.Key]
+		}
+		return strings.Compare(a.Key, b.Key)
+	})
+	return groups, nil
+}
+
+// filterIssues returns the issues keep returns true for
+func filterIssues(issues []Issue, keep func(Issue) bool) []Issue {
+	kept := []Issue{}
+	for _, issue := range issues {
+		if keep(issue) {
+			kept = append(kept, issue)
+		}
+	}
+	return kept
+}
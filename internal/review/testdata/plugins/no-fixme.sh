@@ -0,0 +1,33 @@
+#!/bin/sh
+# Example AutoReview plugin: reports FIXME comments on changed lines.
+# See docs/PLUGINS.md for the protocol.
+
+request=$(cat)
+
+case "$request" in
+*'"type":"handshake"'*)
+	echo '{"protocol_version":1,"name":"no-fixme"}'
+	exit 0
+	;;
+esac
+
+path=$(printf '%s' "$request" | sed -n 's/.*"path":"\([^"]*\)".*/\1/p')
+ranges=$(printf '%s' "$request" | sed -n 's/.*"changed_lines":\[\([^]]*\)\].*/\1/p' |
+	sed 's/},{/\n/g' | sed 's/[^0-9,]//g')
+
+# in_changed_range LINE succeeds when LINE was changed (or on a full scan)
+in_changed_range() {
+	[ -z "$ranges" ] && return 0
+	for range in $ranges; do
+		start=${range%,*}
+		end=${range#*,}
+		[ "$1" -ge "$start" ] && [ "$1" -le "$end" ] && return 0
+	done
+	return 1
+}
+
+grep -n 'FIXME' "$path" | while IFS=: read -r line _; do
+	if in_changed_range "$line"; then
+		printf '{"rule":"fixme","type":"quality","severity":"medium","message":"FIXME left in code","line":%s}\n' "$line"
+	fi
+done
@@ -0,0 +1,249 @@
+package review
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// TicketTracker looks up issue tracker tickets referenced from TODO comments
+type TicketTracker interface {
+	// Name identifies the tracker in messages, e.g. "jira"
+	Name() string
+	// References returns the ticket keys mentioned in a TODO comment
+	References(comment string) []string
+	// Closed reports whether the ticket has been resolved
+	Closed(ctx context.Context, key string) (bool, error)
+}
+
+// ticketLookupTimeout bounds a single tracker request
+const ticketLookupTimeout = 10 * time.Second
+
+var (
+	// TODO and FIXME markers that may carry a ticket reference
+	todoMarkerPattern = regexp.MustCompile(`\b(TODO|FIXME)\b`)
+	// Jira keys such as PROJ-123
+	jiraKeyPattern = regexp.MustCompile(`\b[A-Z][A-Z0-9]+-\d+\b`)
+	// GitHub issue references such as #42 or owner/repo#42
+	githubIssuePattern = regexp.MustCompile(`(?:^|[\s(\[])((?:[\w.-]+/[\w.-]+)?#\d+)\b`)
+)
+
+// JiraTracker resolves Jira issue keys through the Jira REST API
+type JiraTracker struct {
+	// BaseURL is the Jira site, e.g. https://example.atlassian.net
+	BaseURL string
+	// Email and Token authenticate with basic auth; both empty sends no credentials
+	Email  string
+	Token  string
+	Client *http.Client
+}
+
+func (j *JiraTracker) Name() string { return "jira" }
+
+func (j *JiraTracker) References(comment string) []string {
+	return jiraKeyPattern.FindAllString(comment, -1)
+}
+
+// Closed treats every status in Jira's "done" category as closed
+func (j *JiraTracker) Closed(ctx context.Context, key string) (bool, error) {
+	endpoint := strings.TrimSuffix(j.BaseURL, "/") + "/rest/api/2/issue/" + url.PathEscape(key) + "?fields=status"
+	var issue struct {
+		Fields struct {
+			Status struct {
+				StatusCategory struct {
+					Key string `json:"key"`
+				} `json:"statusCategory"`
+			} `json:"status"`
+		} `json:"fields"`
+	}
+	err := getTicketJSON(ctx, j.Client, endpoint, func(req *http.Request) {
+		if j.Email != "" || j.Token != "" {
+			req.SetBasicAuth(j.Email, j.Token)
+		}
+	}, &issue)
+	if err != nil {
+		return false, err
+	}
+	return issue.Fields.Status.StatusCategory.Key == "done", nil
+}
+
+// GitHubTracker resolves GitHub issue references through the GitHub REST API
+type GitHubTracker struct {
+	// APIURL defaults to https://api.github.com
+	APIURL string
+	// Repo is the owner/name that bare #123 references belong to
+	Repo   string
+	Token  string
+	Client *http.Client
+}
+
+func (g *GitHubTracker) Name() string { return "github" }
+
+// References returns keys as owner/repo#number, qualifying bare #number with Repo
+func (g *GitHubTracker) References(comment string) []string {
+	keys := []string{}
+	for _, match := range githubIssuePattern.FindAllStringSubmatch(comment, -1) {
+		key := match[1]
+		if strings.HasPrefix(key, "#") {
+			if g.Repo == "" {
+				continue
+			}
+			key = g.Repo + key
+		}
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+func (g *GitHubTracker) Closed(ctx context.Context, key string) (bool, error) {
+	repo, number, ok := strings.Cut(key, "#")
+	if _, err := strconv.Atoi(number); !ok || err != nil {
+		return false, fmt.Errorf("invalid GitHub issue reference %q", key)
+	}
+
+	apiURL := g.APIURL
+	if apiURL == "" {
+		apiURL = "https://api.github.com"
+	}
+	endpoint := strings.TrimSuffix(apiURL, "/") + "/repos/" + repo + "/issues/" + number
+	var issue struct {
+		State string `json:"state"`
+	}
+	err := getTicketJSON(ctx, g.Client, endpoint, func(req *http.Request) {
+		req.Header.Set("Accept", "application/vnd.github+json")
+		if g.Token != "" {
+			req.Header.Set("Authorization", "Bearer "+g.Token)
+		}
+	}, &issue)
+	if err != nil {
+		return false, err
+	}
+	return issue.State == "closed", nil
+}
+
+// getTicketJSON fetches endpoint and decodes the JSON response into v
+func getTicketJSON(ctx context.Context, client *http.Client, endpoint string, prepare func(*http.Request), v any) error {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	ctx, cancel := context.WithTimeout(ctx, ticketLookupTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return err
+	}
+	prepare(req)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s returned %s", endpoint, resp.Status)
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
+// TicketTrackersFromEnv configures trackers from the environment: Jira from
+// JIRA_BASE_URL, JIRA_EMAIL and JIRA_API_TOKEN, GitHub Issues from
+// GITHUB_REPOSITORY and GITHUB_TOKEN (both set in GitHub Actions)
+func TicketTrackersFromEnv() []TicketTracker {
+	trackers := []TicketTracker{}
+	if base := os.Getenv("JIRA_BASE_URL"); base != "" {
+		trackers = append(trackers, &JiraTracker{
+			BaseURL: base,
+			Email:   os.Getenv("JIRA_EMAIL"),
+			Token:   os.Getenv("JIRA_API_TOKEN"),
+		})
+	}
+	if repo := os.Getenv("GITHUB_REPOSITORY"); repo != "" {
+		trackers = append(trackers, &GitHubTracker{
+			APIURL: os.Getenv("GITHUB_API_URL"),
+			Repo:   repo,
+			Token:  os.Getenv("GITHUB_TOKEN"),
+		})
+	}
+	return trackers
+}
+
+// SetTicketTrackers enables the closed-ticket TODO check with these trackers
+func (a *Analyzer) SetTicketTrackers(trackers []TicketTracker) {
+	a.ticketTrackers = append([]TicketTracker(nil), trackers...)
+}
+
+// checkTodoTickets escalates TODO comments whose referenced ticket is closed.
+// Each ticket is looked up once per run; a tracker that cannot be reached is
+// skipped for the rest of the run instead of failing the review.
+func (a *Analyzer) checkTodoTickets(report *Report) {
+	if len(a.ticketTrackers) == 0 {
+		return
+	}
+
+	closed := map[string]bool{}
+	looked := map[string]bool{}
+	unreachable := map[string]bool{}
+	escalated := map[string]bool{}
+
+	for _, file := range report.ChangedFiles {
+		content, err := a.readFile(file)
+		if err != nil {
+			continue
+		}
+
+		for i, line := range strings.Split(string(content), "\n") {
+			if !todoMarkerPattern.MatchString(line) {
+				continue
+			}
+
+			for _, tracker := range a.ticketTrackers {
+				if unreachable[tracker.Name()] {
+					continue
+				}
+				for _, key := range tracker.References(line) {
+					cacheKey := tracker.Name() + ":" + key
+					if !looked[cacheKey] {
+						isClosed, err := tracker.Closed(a.ctx, key)
+						if err != nil {
+							a.log.Warnf("Skipping %s ticket checks: %v", tracker.Name(), err)
+							unreachable[tracker.Name()] = true
+							break
+						}
+						looked[cacheKey] = true
+						closed[cacheKey] = isClosed
+					}
+					if !closed[cacheKey] {
+						continue
+					}
+
+					// QUALITY: Check for TODOs left behind after their ticket was closed
+					report.AddIssue(Issue{
+						Type:     "quality",
+						Severity: "medium",
+						Message:  fmt.Sprintf("TODO references %s ticket %s, which is closed - resolve the TODO or reopen the ticket", tracker.Name(), key),
+						File:     file,
+						Line:     i + 1,
+						RuleID:   "todo-closed-ticket",
+					})
+					escalated[fmt.Sprintf("%s:%d", file, i+1)] = true
+				}
+			}
+		}
+	}
+
+	// The closed-ticket finding replaces the plain TODO finding on the same line
+	if len(escalated) > 0 {
+		report.FilterIssues(func(issue Issue) bool {
+			return issue.RuleID != "todo-comment" || !escalated[fmt.Sprintf("%s:%d", issue.File, issue.Line)]
+		})
+	}
+}
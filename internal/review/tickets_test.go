@@ -0,0 +1,119 @@
+package review
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+// newJiraServer serves issue lookups, reporting the keys in closed as done
+func newJiraServer(t *testing.T, closed map[string]bool, hits *atomic.Int32) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		key := strings.TrimPrefix(r.URL.Path, "/rest/api/2/issue/")
+		category := "indeterminate"
+		if closed[key] {
+			category = "done"
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"key":"` + key + `","fields":{"status":{"statusCategory":{"key":"` + category + `"}}}}`))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestCheckTodoTickets_ClosedTicketEscalated(t *testing.T) {
+	tmpDir := t.TempDir()
+	createTestFile(t, tmpDir, "app.py", `# TODO(PROJ-1): drop the legacy path
+# TODO PROJ-2 cache this
+def handler():
+    pass  # FIXME PROJ-1 again
+`)
+
+	var hits atomic.Int32
+	server := newJiraServer(t, map[string]bool{"PROJ-1": true}, &hits)
+
+	report, err := Run(context.Background(), Options{
+		RepoPath:       tmpDir,
+		FullScan:       true,
+		TicketTrackers: []TicketTracker{&JiraTracker{BaseURL: server.URL}},
+	})
+	if err != nil {
+		t.Fatalf("Run returned error: %v", err)
+	}
+
+	escalated := map[int]bool{}
+	for _, issue := range report.Issues {
+		if issue.RuleID == "todo-closed-ticket" {
+			if issue.Severity != "medium" || !contains(issue.Message, "PROJ-1") {
+				t.Errorf("Unexpected closed-ticket issue: %+v", issue)
+			}
+			escalated[issue.Line] = true
+		}
+		if issue.RuleID == "todo-comment" && issue.Line == 1 {
+			t.Errorf("Expected the plain TODO finding on line 1 to be replaced, got %+v", issue)
+		}
+	}
+	if !escalated[1] || !escalated[4] || escalated[2] {
+		t.Errorf("Expected lines 1 and 4 escalated and the open PROJ-2 left alone, got %v", escalated)
+	}
+	if got := hits.Load(); got != 2 {
+		t.Errorf("Expected one lookup per ticket, got %d requests", got)
+	}
+}
+
+func TestCheckTodoTickets_UnreachableTrackerSkipped(t *testing.T) {
+	tmpDir := t.TempDir()
+	createTestFile(t, tmpDir, "app.py", "# TODO PROJ-1 remove\n# TODO PROJ-2 remove\n")
+
+	var hits atomic.Int32
+	server := newJiraServer(t, nil, &hits)
+	server.Close()
+
+	report, err := Run(context.Background(), Options{
+		RepoPath:       tmpDir,
+		FullScan:       true,
+		TicketTrackers: []TicketTracker{&JiraTracker{BaseURL: server.URL}},
+	})
+	if err != nil {
+		t.Fatalf("Expected an unreachable tracker not to fail the run, got %v", err)
+	}
+	if hasIssue(report, "quality", "medium", "closed") {
+		t.Errorf("Expected no closed-ticket findings when the tracker is unreachable")
+	}
+	if !hasIssue(report, "quality", "info", "TODO/FIXME comment found") {
+		t.Errorf("Expected the plain TODO findings to remain")
+	}
+}
+
+func TestGitHubTracker(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		state := "open"
+		if r.URL.Path == "/repos/acme/app/issues/7" {
+			state = "closed"
+		}
+		w.Write([]byte(`{"state":"` + state + `"}`))
+	}))
+	defer server.Close()
+
+	tracker := &GitHubTracker{APIURL: server.URL, Repo: "acme/app", Token: "secret"}
+	refs := tracker.References("// TODO(#7): see also other/lib#12")
+	if strings.Join(refs, ",") != "acme/app#7,other/lib#12" {
+		t.Fatalf("Unexpected references: %v", refs)
+	}
+
+	for key, want := range map[string]bool{"acme/app#7": true, "other/lib#12": false} {
+		closed, err := tracker.Closed(context.Background(), key)
+		if err != nil || closed != want {
+			t.Errorf("Closed(%s) = %v, %v; want %v", key, closed, err, want)
+		}
+	}
+}
@@ -0,0 +1,120 @@
+package review
+
+import (
+	"bytes"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// isFullScanFile reports whether a full scan collects the file, by extension and ignoring
+// case, or by name for Jenkinsfiles
+func isFullScanFile(file string) bool {
+	return slices.Contains(fullScanExtensions, strings.ToLower(filepath.Ext(file))) || isJenkinsfile(file) || isManifestFile(file)
+}
+
+// fullScanFiles walks the repository for the files a full scan collects. Paths
+// are slash-separated and relative to the repository root. .git directories,
+// paths git ignores and directories excluded by an ignore pattern are pruned;
+// other ignore patterns are applied by the caller.
+func (a *Analyzer) fullScanFiles() ([]string, error) {
+	a.log.Infof("Analyzing full codebase")
+	a.log.Infof("Searching for files with extensions: %v", fullScanExtensions)
+
+	gitIgnored := a.gitIgnoredPaths()
+
+	files := []string{}
+	err := filepath.WalkDir(a.repoPath, func(path string, d fs.DirEntry, err error) error {
+		if ctxErr := a.ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+		if err != nil {
+			if path == a.repoPath {
+				return err
+			}
+			// An unreadable subdirectory should not abort the whole scan
+			a.log.Warnf("Skipping %s: %v", path, err)
+			return nil
+		}
+
+		rel, err := filepath.Rel(a.repoPath, path)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if rel == "." {
+			return nil
+		}
+
+		if d.IsDir() {
+			if d.Name() == ".git" || gitIgnored[rel+"/"] {
+				return filepath.SkipDir
+			}
+			if pattern, ignored := a.matchIgnorePattern(rel + "/"); ignored && strings.HasSuffix(pattern.Pattern, "/") {
+				return filepath.SkipDir
+			}
+			if !a.includeSubmodules && isRepositoryRoot(path) {
+				a.log.Debugf("Skipping submodule or nested repository: %s", rel)
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if d.Type().IsRegular() && isFullScanFile(rel) && !gitIgnored[rel] {
+			files = append(files, rel)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to walk %s: %w", a.repoPath, err)
+	}
+	return files, nil
+}
+
+// SetIncludeSubmodules controls whether full scans descend into git submodules
+// and nested repositories, which are skipped by default
+func (a *Analyzer) SetIncludeSubmodules(include bool) {
+	a.includeSubmodules = include
+}
+
+// isRepositoryRoot reports whether dir has its own .git: a directory for nested
+// repositories, a file pointing at the superproject's modules for submodules
+func isRepositoryRoot(dir string) bool {
+	_, err := os.Lstat(filepath.Join(dir, ".git"))
+	return err == nil
+}
+
+// nestedRepository returns the submodule or nested repository containing file, or ""
+func (a *Analyzer) nestedRepository(file string) string {
+	for _, dir := range parentDirs(file) {
+		if isRepositoryRoot(filepath.Join(a.repoPath, dir)) {
+			return strings.TrimSuffix(dir, "/")
+		}
+	}
+	return ""
+}
+
+// gitIgnoredPaths returns the untracked paths git ignores, with directories
+// ending in "/". Outside a git repository nothing is ignored.
+func (a *Analyzer) gitIgnoredPaths() map[string]bool {
+	ignored := map[string]bool{}
+
+	cmd := exec.CommandContext(a.ctx, "git", "ls-files", "-z", "--others", "--ignored", "--exclude-standard", "--directory")
+	cmd.Dir = a.repoPath
+	output, err := cmd.Output()
+	if err != nil {
+		a.log.Debugf("Not honoring .gitignore: %v", err)
+		return ignored
+	}
+
+	for _, path := range bytes.Split(output, []byte{0}) {
+		if len(path) > 0 {
+			ignored[string(path)] = true
+		}
+	}
+	return ignored
+}
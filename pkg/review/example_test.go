@@ -0,0 +1,44 @@
+package review_test
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"os"
+
+	"github.com/BrandonThomas84/code-review-automation/pkg/review"
+)
+
+func ExampleRun() {
+	report, err := review.Run(context.Background(), review.Options{
+		RepoPath:     ".",
+		TargetBranch: "main",
+		MinSeverity:  "medium",
+		IgnorePatterns: []review.IgnorePattern{
+			{Pattern: "testdata/", Source: "embedding service"},
+		},
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if err := review.Render(os.Stdout, "json", report); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func ExampleRegisterRenderer() {
+	review.RegisterRenderer("count", func(w io.Writer, report *review.Report) error {
+		_, err := fmt.Fprintf(w, "%d issues\n", report.Summary.TotalIssues)
+		return err
+	})
+
+	report := &review.Report{}
+	report.AddIssue(review.Issue{Type: "quality", Severity: "low", Message: "TODO/FIXME comment found", File: "main.py", Line: 3})
+
+	if err := review.Render(os.Stdout, "count", report); err != nil {
+		log.Fatal(err)
+	}
+	// Output: 1 issues
+}
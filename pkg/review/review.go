@@ -0,0 +1,235 @@
+// Package review is the supported Go API for embedding AutoReview in other programs.
+//
+// It exposes the same analysis pipeline the code-review CLI uses: build an
+// Options value, call Run, and render the resulting Report with any of the
+// registered output formats.
+//
+//	report, err := review.Run(ctx, review.Options{
+//		RepoPath:     "/path/to/repo",
+//		TargetBranch: "main",
+//		MinSeverity:  "medium",
+//	})
+//	if err != nil {
+//		return err
+//	}
+//	return review.Render(os.Stdout, "json", report)
+//
+// The JSON encoding of Report and Issue is a stable contract: fields may be
+// added, but existing field names and meanings will not change.
+package review
+
+import (
+	"context"
+	"io"
+
+	"github.com/BrandonThomas84/code-review-automation/internal/review"
+)
+
+type (
+	// Options configures a single review run.
+	Options = review.Options
+
+	// Report is the result of a review run. Its JSON encoding has the shape
+	//
+	//	{"timestamp": ..., "changed_files": [...], "issues": [...], "summary": {...}}
+	Report = review.Report
+
+	// Issue is a single finding. Type is one of the categories in IssueTypes and
+	// Severity is one of Severities. Line is omitted when the finding
+	// applies to the whole file. RuleID names the check that produced the
+	// finding, e.g. "line-length"; plugin findings use "<plugin>/<rule>".
+	// Confidence is one of Confidences; heuristic checks report medium or low.
+	// CWE names the weakness a security finding detects, e.g. "CWE-89".
+	Issue = review.Issue
+
+	// Commit identifies the HEAD commit a Report was generated for.
+	Commit = review.Commit
+
+	// LogLevel controls how much progress a run logs, see Options.LogLevel.
+	LogLevel = review.LogLevel
+
+	// Logger writes leveled progress messages.
+	Logger = review.Logger
+
+	// Summary holds the per-severity, per-file and per-rule issue counts of a Report.
+	Summary = review.Summary
+
+	// FileCount is the number of issues in a file, see Summary.TopFiles.
+	FileCount = review.FileCount
+
+	// IgnorePattern excludes matching paths from analysis. Source is a free-form
+	// label recorded for diagnostics.
+	IgnorePattern = review.IgnorePattern
+
+	// Plugin is an external executable that reports additional issues. See
+	// docs/PLUGINS.md for the stdin/stdout contract.
+	Plugin = review.Plugin
+
+	// TicketTracker looks up tickets referenced from TODO comments. Set
+	// Options.TicketTrackers to flag TODOs whose ticket has been closed.
+	TicketTracker = review.TicketTracker
+
+	// JiraTracker is a TicketTracker for Jira issue keys such as PROJ-123.
+	JiraTracker = review.JiraTracker
+
+	// GitHubTracker is a TicketTracker for GitHub issue references such as #42.
+	GitHubTracker = review.GitHubTracker
+
+	// Renderer writes a Report in a particular output format.
+	Renderer = review.Renderer
+
+	// FileSelection explains whether and how a file is analyzed, step by step.
+	FileSelection = review.FileSelection
+
+	// SelectionStep is one decision recorded in a FileSelection.
+	SelectionStep = review.SelectionStep
+
+	// ScanPlan previews the files a run would analyze, as printed by --dry-run.
+	ScanPlan = review.ScanPlan
+
+	// PlannedFile is one candidate file in a ScanPlan.
+	PlannedFile = review.PlannedFile
+
+	// PlanSummary holds the file counts of a ScanPlan.
+	PlanSummary = review.PlanSummary
+
+	// OptionsError is returned by Run, Explain and Plan when Options fail validation.
+	OptionsError = review.OptionsError
+
+	// SetupError is returned when the environment keeps a run from starting:
+	// the repository path is missing, git is unavailable or the target branch
+	// cannot be resolved.
+	SetupError = review.SetupError
+
+	// ReportDiff lists the findings added, removed and unchanged between two reports.
+	ReportDiff = review.ReportDiff
+
+	// DiffSummary holds the finding deltas of a ReportDiff.
+	DiffSummary = review.DiffSummary
+
+	// ReportTemplate renders a Report with a user-supplied text/template.
+	ReportTemplate = review.ReportTemplate
+
+	// IssueGroup is one group returned by the groupBy template function.
+	IssueGroup = review.IssueGroup
+)
+
+// Log levels for Options.LogLevel, from quietest to most verbose.
+const (
+	LogQuiet = review.LogQuiet
+	LogWarn  = review.LogWarn
+	LogInfo  = review.LogInfo
+	LogDebug = review.LogDebug
+)
+
+// Diff sources for Options.DiffSource, which review uncommitted changes instead
+// of comparing HEAD with the target branch.
+const (
+	DiffStaged  = review.DiffStaged
+	DiffWorking = review.DiffWorking
+)
+
+// Run analyzes the repository described by opts and returns the report.
+// Cancelling ctx aborts any git commands that are still running.
+func Run(ctx context.Context, opts Options) (*Report, error) {
+	return review.Run(ctx, opts)
+}
+
+// Explain reports how a run with opts would treat file: whether it is in the
+// diff or full scan, which ignore pattern excludes it, whether security
+// scanning skips it, and which analyzer and plugins handle it.
+func Explain(ctx context.Context, opts Options, file string) (*FileSelection, error) {
+	return review.Explain(ctx, opts, file)
+}
+
+// Plan resolves the files a run with opts would analyze, and which analyzer,
+// security scan and plugins would handle each, without reading file contents.
+func Plan(ctx context.Context, opts Options) (*ScanPlan, error) {
+	return review.Plan(ctx, opts)
+}
+
+// NewLogger returns a logger writing messages up to level to w.
+func NewLogger(level LogLevel, w io.Writer) *Logger {
+	return review.NewLogger(level, w)
+}
+
+// TicketTrackersFromEnv returns the trackers configured by JIRA_BASE_URL,
+// JIRA_EMAIL and JIRA_API_TOKEN, and by GITHUB_REPOSITORY and GITHUB_TOKEN.
+func TicketTrackersFromEnv() []TicketTracker {
+	return review.TicketTrackersFromEnv()
+}
+
+// IgnorePatterns returns the ignore patterns a run with opts would apply,
+// including those discovered in the repository's .autoreview-ignore file.
+func IgnorePatterns(opts Options) []IgnorePattern {
+	return review.NewAnalyzerFromOptions(opts).IgnorePatterns()
+}
+
+// Languages returns the language names LanguageDisabledRules can be keyed by.
+func Languages() []string {
+	return review.Languages()
+}
+
+// Severities returns the severity levels from most to least severe:
+// critical, high, medium, low and info.
+func Severities() []string {
+	return append([]string(nil), review.Severities...)
+}
+
+// Confidences returns the confidence levels from most to least certain:
+// high, medium and low.
+func Confidences() []string {
+	return append([]string(nil), review.Confidences...)
+}
+
+// Categories returns the check categories Options.Only can select.
+func Categories() []string {
+	return append([]string(nil), review.Categories...)
+}
+
+// IssueTypes returns the issue categories the analyzers report.
+func IssueTypes() []string {
+	return append([]string(nil), review.IssueTypes...)
+}
+
+// RegisterRenderer makes a renderer available under the given format name,
+// replacing any renderer previously registered with that name.
+func RegisterRenderer(name string, renderer Renderer) {
+	review.RegisterRenderer(name, renderer)
+}
+
+// Formats returns the names of all registered output formats.
+func Formats() []string {
+	return review.RendererNames()
+}
+
+// Render writes report to w using the renderer registered for format.
+func Render(w io.Writer, format string, report *Report) error {
+	return report.Render(w, format)
+}
+
+// ParseReportTemplate loads a text/template file for rendering reports. The
+// template is executed with the *Report and may use the helper functions
+// atLeast, severity, groupBy, upper, lower and join.
+func ParseReportTemplate(path string) (*ReportTemplate, error) {
+	return review.ParseReportTemplate(path)
+}
+
+// LoadReport reads a report previously saved as JSON by the CLI or OutputJSON.
+func LoadReport(path string) (*Report, error) {
+	return review.LoadReport(path)
+}
+
+// WritePRComment writes a Markdown pull request comment listing the findings
+// current introduced over base and how many it resolved. A nil base lists
+// every finding in current.
+func WritePRComment(w io.Writer, current, base *Report) error {
+	return review.WritePRComment(w, current, base)
+}
+
+// DiffReports compares two reports. Findings are matched by rule ID (or type),
+// file and message, so findings that only moved to another line or changed
+// severity are unchanged.
+func DiffReports(previous, current *Report) *ReportDiff {
+	return current.Diff(previous)
+}
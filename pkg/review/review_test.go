@@ -0,0 +1,70 @@
+package review
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRun_RequiresTargetBranch(t *testing.T) {
+	_, err := Run(context.Background(), Options{RepoPath: t.TempDir()})
+	if err == nil || !strings.Contains(err.Error(), "target branch") {
+		t.Errorf("Expected target branch error, got %v", err)
+	}
+}
+
+func TestRun_RejectsInvalidMinSeverity(t *testing.T) {
+	_, err := Run(context.Background(), Options{RepoPath: t.TempDir(), FullScan: true, MinSeverity: "severe"})
+	if err == nil || !strings.Contains(err.Error(), "severity") {
+		t.Errorf("Expected min severity error, got %v", err)
+	}
+}
+
+func TestRender_UnknownFormat(t *testing.T) {
+	var sb strings.Builder
+	if err := Render(&sb, "nope", &Report{}); err == nil {
+		t.Error("Expected error for unknown format")
+	}
+}
+
+func TestFormats_IncludesBuiltins(t *testing.T) {
+	formats := strings.Join(Formats(), ",")
+	for _, name := range []string{"json", "oneline", "text"} {
+		if !strings.Contains(formats, name) {
+			t.Errorf("Expected %q in formats %s", name, formats)
+		}
+	}
+}
+
+func TestRun_MinSeverityFiltersIssues(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "app.py"), []byte("print(x)\nresult = eval(data)\n"), 0644); err != nil {
+		t.Fatalf("Failed to write fixture: %v", err)
+	}
+
+	report, err := Run(context.Background(), Options{RepoPath: dir, FullScan: true, MinSeverity: "high"})
+	if err != nil {
+		t.Fatalf("Run returned error: %v", err)
+	}
+
+	if report.Summary.TotalIssues == 0 {
+		t.Fatal("Expected the eval() finding to be reported")
+	}
+	for _, issue := range report.Issues {
+		if issue.Severity != "high" {
+			t.Errorf("Expected only high severity issues, got %s: %s", issue.Severity, issue.Message)
+		}
+	}
+}
+
+func TestRun_UnknownLanguageInRules(t *testing.T) {
+	_, err := Run(context.Background(), Options{
+		FullScan:              true,
+		LanguageDisabledRules: map[string][]string{"cobol": {"line-length"}},
+	})
+	if err == nil || !strings.Contains(err.Error(), "cobol") {
+		t.Errorf("Expected an unknown language error, got %v", err)
+	}
+}